@@ -58,6 +58,14 @@ type tsdbSelectQuery struct {
 	Filter    map[string]map[string]string `json:"filter"`
 }
 
+type tsdbJoinQuery struct {
+	Join      []string            `json:"join"`
+	TimeRange tsdbQueryRange      `json:"range"`
+	Where     map[string][]string `json:"where"`
+	Output    map[string]string   `json:"output"`
+	OrderBy   string              `json:"order-by"`
+}
+
 type tsdbAggregateAllQuery struct {
 	Metrics map[string]string `json:"aggregate"`
 	Output  map[string]string `json:"output"`
@@ -286,3 +294,91 @@ func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
 	d.fillInQuery(qi, humanLabel, humanDesc, string(bodyWriter.Bytes()), interval.StartUnixNano(), interval.EndUnixNano())
 }
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, joining the series of every host into rows,
+// e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu
+// WHERE
+// 		(hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// 		AND time >= '$HOUR_START'
+// 		AND time < '$HOUR_END'
+// ORDER BY time
+//
+// Resultsets:
+// raw-scan-1
+// raw-scan-8
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+	startTimestamp := interval.StartUnixNano()
+	endTimestamp := interval.EndUnixNano()
+
+	var query tsdbJoinQuery
+	for _, name := range devops.GetAllCPUMetrics() {
+		query.Join = append(query.Join, "cpu."+name)
+	}
+
+	query.Where = make(map[string][]string)
+	query.Where["hostname"] = hostnames
+	query.TimeRange.From = startTimestamp
+	query.TimeRange.To = endTimestamp
+	query.Output = make(map[string]string)
+	query.Output["format"] = "csv"
+	query.OrderBy = "time"
+
+	bodyWriter := new(bytes.Buffer)
+	body, err := json.Marshal(query)
+	if err != nil {
+		panic(err)
+	}
+	bodyWriter.Write(body)
+
+	humanLabel := devops.GetRawScanLabel("Akumuli", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, string(bodyWriter.Bytes()), interval.StartUnixNano(), interval.EndUnixNano())
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day
+// ORDER BY day
+//
+// Resultsets:
+// downsample
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	startTimestamp := d.Interval.StartUnixNano()
+	endTimestamp := d.Interval.EndUnixNano()
+
+	var query tsdbGroupAggregateQuery
+	query.GroupAggregate.Func = append(query.GroupAggregate.Func, "mean")
+	query.GroupAggregate.Step = "1d"
+	for _, name := range devops.GetAllCPUMetrics() {
+		query.GroupAggregate.Name = append(query.GroupAggregate.Name, "cpu."+name)
+	}
+
+	query.TimeRange.From = startTimestamp
+	query.TimeRange.To = endTimestamp
+	query.Output = make(map[string]string)
+	query.Output["format"] = "csv"
+	query.OrderBy = "time"
+
+	bodyWriter := new(bytes.Buffer)
+	body, err := json.Marshal(query)
+	if err != nil {
+		panic(err)
+	}
+	bodyWriter.Write(body)
+
+	humanLabel := devops.GetDownsampleLabel("Akumuli")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, string(bodyWriter.Bytes()), startTimestamp, endTimestamp)
+}
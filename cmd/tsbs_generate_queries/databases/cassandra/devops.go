@@ -131,7 +131,7 @@ func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
 func (d *Devops) LastPointPerHost(qi query.Query) {
 	humanLabel := "Cassandra last row per host"
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
-	d.fillInQuery(qi, humanLabel, humanDesc, "", devops.GetAllCPUMetrics(), d.Interval, nil)
+	d.fillInQuery(qi, humanLabel, humanDesc, "", devops.GetAllCPUMetrics(), d.Interval.TimeInterval, nil)
 	q := qi.(*query.Cassandra)
 	q.ForEveryN = []byte("hostname,1")
 }
@@ -162,3 +162,34 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	q.GroupByDuration = time.Hour
 	q.WhereClause = []byte("usage_user,>,90.0")
 }
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	tagSets := [][]string{d.getHostWhere(nHosts)}
+
+	humanLabel := devops.GetRawScanLabel("Cassandra", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "", devops.GetAllCPUMetrics(), interval, tagSets)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	humanLabel := devops.GetDownsampleLabel("Cassandra")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "avg", devops.GetAllCPUMetrics(), d.Interval.TimeInterval, nil)
+	q := qi.(*query.Cassandra)
+	q.GroupByDuration = devops.DownsampleBucket
+}
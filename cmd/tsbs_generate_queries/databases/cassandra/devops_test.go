@@ -82,7 +82,7 @@ func TestDevopsFillInQuery(t *testing.T) {
 		t.Errorf("empty query has non-zero length tagset")
 	}
 
-	d.fillInQuery(q, humanLabel, humanDesc, aggType, fields, d.Interval, tags)
+	d.fillInQuery(q, humanLabel, humanDesc, aggType, fields, d.Interval.TimeInterval, tags)
 
 	if got := string(q.HumanLabel); got != humanLabel {
 		t.Errorf("filled query mislabeled: got %s want %s", got, humanLabel)
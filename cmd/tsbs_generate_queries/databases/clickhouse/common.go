@@ -4,6 +4,10 @@ import (
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/pkg/query"
 )
@@ -42,3 +46,67 @@ func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryG
 
 	return devops, nil
 }
+
+// NewIoT creates a new iot use case query generator.
+func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := iot.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	iot := &IoT{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return iot, nil
+}
+
+// NewK8s creates a new k8s use case query generator.
+func (g *BaseGenerator) NewK8s(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := k8s.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k8s := &K8s{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return k8s, nil
+}
+
+// NewFinance creates a new finance use case query generator.
+func (g *BaseGenerator) NewFinance(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := finance.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	finance := &Finance{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return finance, nil
+}
+
+// NewSmartMeter creates a new smart-meter use case query generator.
+func (g *BaseGenerator) NewSmartMeter(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := smartmeter.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	smartMeter := &SmartMeter{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return smartMeter, nil
+}
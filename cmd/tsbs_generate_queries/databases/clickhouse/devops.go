@@ -327,3 +327,373 @@ func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange t
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
 	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
 }
+
+// getHostnameGroupClauses returns the select and group by clauses of the column
+// an aggregate subquery over 'cpu' groups the hosts by, and the join resolving
+// the hostname of that column.
+func (d *Devops) getHostnameGroupClauses() (selectField, groupField, joinClause string) {
+	if d.UseTags {
+		return "tags_id AS id", "id", "ANY INNER JOIN tags USING (id)"
+	}
+	return "hostname", "hostname", ""
+}
+
+// PercentileCPU selects the percentiles of the user CPU usage per host per hour for half a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT hour, hostname, quantile(0.5)(usage_user), ..., quantile(0.99)(usage_user)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname
+// ORDER BY hour, hostname
+//
+// Resultsets:
+// percentile-cpu
+func (d *Devops) PercentileCPU(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.PercentileCPUDuration)
+	percentiles := devops.GetCPUPercentiles()
+
+	selectClauses := make([]string, len(percentiles))
+	pctClauses := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		pctClauses[i] = devops.GetPercentileName(p) + "_usage_user"
+		selectClauses[i] = fmt.Sprintf("quantile(%g)(usage_user) AS %s", p, pctClauses[i])
+	}
+	selectField, groupField, joinClause := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            hour,
+            hostname,
+            %s
+        FROM
+        (
+            SELECT
+                toStartOfHour(created_at) AS hour,
+                %s,
+                %s
+            FROM cpu
+            WHERE (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY
+                hour,
+                %s
+        ) AS cpu_pct
+        %s
+        ORDER BY
+            hour ASC,
+            hostname
+        `,
+		strings.Join(pctClauses, ", "),                      // main SELECT %s
+		selectField,                                         // cpu_pct SELECT %s,
+		strings.Join(selectClauses, ", "),                   // cpu_pct SELECT %s
+		interval.Start().Format(clickhouseTimeStringFormat), // cpu_pct time >= '%s'
+		interval.End().Format(clickhouseTimeStringFormat),   // cpu_pct time < '%s'
+		groupField,                                          // cpu_pct GROUP BY %s
+		joinClause) // JOIN clause
+
+	humanLabel := devops.GetPercentileCPULabel("ClickHouse")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// TopKHostsByCPU selects the hosts with the highest mean user CPU usage in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT hostname, avg(usage_user) AS mean_usage_user
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hostname
+// ORDER BY mean_usage_user DESC
+// LIMIT $LIMIT
+//
+// Resultsets:
+// topk-hosts
+func (d *Devops) TopKHostsByCPU(qi query.Query, limit int) {
+	interval := d.Interval.MustRandWindow(devops.TopKHostsDuration)
+	selectField, groupField, joinClause := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            hostname,
+            mean_usage_user
+        FROM
+        (
+            SELECT
+                %s,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY %s
+        ) AS cpu_avg
+        %s
+        ORDER BY mean_usage_user DESC
+        LIMIT %d
+        `,
+		selectField,
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat),
+		groupField,
+		joinClause,
+		limit)
+
+	humanLabel := devops.GetTopKHostsLabel("ClickHouse", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// CounterRate computes the per-second rate of the counters of a measurement per host per minute,
+// treating a decreasing counter as reset to zero,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, hostname, avg(runningDifference(counter1) / runningDifference(time)), ...
+// FROM (SELECT * FROM measurement WHERE time >= '$HOUR_START' AND time < '$HOUR_END' ORDER BY hostname, time)
+// GROUP BY minute, hostname
+// ORDER BY minute, hostname
+//
+// Resultsets:
+// rate-net
+// rate-diskio
+func (d *Devops) CounterRate(qi query.Query, measurement string) {
+	metrics, err := devops.GetCounterMetrics(measurement)
+	panicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.CounterRateDuration)
+
+	rateClauses := make([]string, len(metrics))
+	avgClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		rateClauses[i] = fmt.Sprintf("if((runningDifference(tags_id) = 0) AND (runningDifference(created_at) > 0), "+
+			"if(runningDifference(%[1]s) >= 0, runningDifference(%[1]s), %[1]s) / runningDifference(created_at), NULL) AS rate_%[1]s", m)
+		avgClauses[i] = fmt.Sprintf("avg(rate_%[1]s) AS rate_%[1]s", m)
+	}
+	selectField, _, joinClause := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            minute,
+            hostname,
+            %s
+        FROM
+        (
+            SELECT
+                toStartOfMinute(created_at) AS minute,
+                %s,
+                %s
+            FROM
+            (
+                SELECT *
+                FROM %s
+                WHERE (created_at >= '%s') AND (created_at < '%s')
+                ORDER BY
+                    tags_id,
+                    created_at
+            )
+        ) AS %s_rate
+        %s
+        GROUP BY
+            minute,
+            hostname
+        ORDER BY
+            minute ASC,
+            hostname
+        `,
+		strings.Join(avgClauses, ", "),                      // main SELECT %s
+		selectField,                                         // rate SELECT %s,
+		strings.Join(rateClauses, ", "),                     // rate SELECT %s
+		measurement,                                         // FROM %s
+		interval.Start().Format(clickhouseTimeStringFormat), // time >= '%s'
+		interval.End().Format(clickhouseTimeStringFormat),   // time < '%s'
+		measurement,                                         // AS %s_rate
+		joinClause) // JOIN clause
+
+	humanLabel := devops.GetCounterRateLabel("ClickHouse", measurement)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, measurement, sql)
+}
+
+// HighCPUAndMem finds the 5 minute buckets in which a host has both a high mean CPU usage
+// and a high mean memory usage, joining the 'cpu' and 'mem' tables,
+// e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), avg(mem.used_percent)
+// FROM cpu JOIN mem USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname
+// HAVING avg(cpu.usage_user) > 90.0 AND avg(mem.used_percent) > 80.0
+// ORDER BY bucket, hostname
+//
+// Resultsets:
+// high-cpu-mem
+func (d *Devops) HighCPUAndMem(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUMemDuration)
+	selectField, groupField, joinClause := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            bucket,
+            hostname,
+            mean_usage_user,
+            mean_used_percent
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                %[1]s,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '%[3]s') AND (created_at < '%[4]s')
+            GROUP BY
+                bucket,
+                %[2]s
+        ) AS cpu_avg
+        ANY INNER JOIN
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                %[1]s,
+                avg(used_percent) AS mean_used_percent
+            FROM mem
+            WHERE (created_at >= '%[3]s') AND (created_at < '%[4]s')
+            GROUP BY
+                bucket,
+                %[2]s
+        ) AS mem_avg USING (bucket, %[2]s)
+        %[5]s
+        WHERE (mean_usage_user > %.1[6]f) AND (mean_used_percent > %.1[7]f)
+        ORDER BY
+            bucket ASC,
+            hostname
+        `,
+		selectField,
+		groupField,
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat),
+		joinClause,
+		devops.HighCPUThreshold,
+		devops.HighMemThreshold)
+
+	humanLabel := devops.GetHighCPUMemLabel("ClickHouse")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// CPUNginxCorrelation lines up the mean CPU usage of every host with the number of
+// requests its nginx served per 5 minutes, joining the 'cpu' and 'nginx' tables,
+// e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), max(nginx.requests) - min(nginx.requests)
+// FROM cpu JOIN nginx USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname
+// ORDER BY hostname, bucket
+//
+// Resultsets:
+// cpu-nginx
+func (d *Devops) CPUNginxCorrelation(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.CPUNginxDuration)
+	selectField, groupField, joinClause := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            bucket,
+            hostname,
+            mean_usage_user,
+            requests
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                %[1]s,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '%[3]s') AND (created_at < '%[4]s')
+            GROUP BY
+                bucket,
+                %[2]s
+        ) AS cpu_avg
+        ANY INNER JOIN
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                %[1]s,
+                max(requests) - min(requests) AS requests
+            FROM nginx
+            WHERE (created_at >= '%[3]s') AND (created_at < '%[4]s')
+            GROUP BY
+                bucket,
+                %[2]s
+        ) AS nginx_requests USING (bucket, %[2]s)
+        %[5]s
+        ORDER BY
+            hostname ASC,
+            bucket
+        `,
+		selectField,
+		groupField,
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat),
+		joinClause)
+
+	humanLabel := devops.GetCPUNginxLabel("ClickHouse")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// RawScan selects all the CPU metrics of nHosts random hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT created_at, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND created_at >= '$HOUR_START' AND created_at < '$HOUR_END'
+// ORDER BY created_at
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	selectField, _, _ := d.getHostnameGroupClauses()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            created_at,
+            %s,
+            %s
+        FROM cpu
+        WHERE %s AND (created_at >= '%s') AND (created_at < '%s')
+        ORDER BY created_at
+        `,
+		selectField,
+		strings.Join(devops.GetAllCPUMetrics(), ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := devops.GetRawScanLabel("ClickHouse", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// DownsampleCPU averages all the CPU metrics of all hosts per day over the entire
+// dataset, e.g. in pseudo-SQL:
+//
+// SELECT day, avg(usage_user), ..., avg(usage_guest_nice)
+// FROM cpu
+// WHERE created_at >= '$DATASET_START' AND created_at < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectClausesAggMetrics("avg", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`
+        SELECT
+            toStartOfDay(created_at) AS day,
+            %s
+        FROM cpu
+        WHERE (created_at >= '%s') AND (created_at < '%s')
+        GROUP BY day
+        ORDER BY day
+        `,
+		strings.Join(selectClauses, ", "),
+		d.Interval.Start().Format(clickhouseTimeStringFormat),
+		d.Interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := devops.GetDownsampleLabel("ClickHouse")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
@@ -527,3 +527,359 @@ func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, sqlQuery st
 		t.Errorf("incorrect query:\ngot\n%s\nwant\n%s", got, sqlQuery)
 	}
 }
+
+func TestPercentileCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "ClickHouse p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "ClickHouse p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            hour,
+            hostname,
+            p50_usage_user, p95_usage_user, p99_usage_user
+        FROM
+        (
+            SELECT
+                toStartOfHour(created_at) AS hour,
+                hostname,
+                quantile(0.5)(usage_user) AS p50_usage_user, quantile(0.95)(usage_user) AS p95_usage_user, quantile(0.99)(usage_user) AS p99_usage_user
+            FROM cpu
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+            GROUP BY
+                hour,
+                hostname
+        ) AS cpu_pct
+        
+        ORDER BY
+            hour ASC,
+            hostname
+        `,
+		},
+		{
+			desc:               "use tags",
+			devopsUseTags:      true,
+			expectedHumanLabel: "ClickHouse p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "ClickHouse p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h: 1970-01-01T00:54:10Z",
+			expectedQuery: `
+        SELECT
+            hour,
+            hostname,
+            p50_usage_user, p95_usage_user, p99_usage_user
+        FROM
+        (
+            SELECT
+                toStartOfHour(created_at) AS hour,
+                tags_id AS id,
+                quantile(0.5)(usage_user) AS p50_usage_user, quantile(0.95)(usage_user) AS p95_usage_user, quantile(0.99)(usage_user) AS p99_usage_user
+            FROM cpu
+            WHERE (created_at >= '1970-01-01 00:54:10') AND (created_at < '1970-01-01 12:54:10')
+            GROUP BY
+                hour,
+                id
+        ) AS cpu_pct
+        ANY INNER JOIN tags USING (id)
+        ORDER BY
+            hour ASC,
+            hostname
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.PercentileCPU(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.PercentileCPUDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestTopKHostsByCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "use tags",
+			input:              10,
+			devopsUseTags:      true,
+			expectedHumanLabel: "ClickHouse top 10 hosts by mean CPU usage, random 1h0m0s",
+			expectedHumanDesc:  "ClickHouse top 10 hosts by mean CPU usage, random 1h0m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            hostname,
+            mean_usage_user
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 01:16:22')
+            GROUP BY id
+        ) AS cpu_avg
+        ANY INNER JOIN tags USING (id)
+        ORDER BY mean_usage_user DESC
+        LIMIT 10
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.TopKHostsByCPU(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.TopKHostsDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestCounterRate(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "use tags",
+			devopsUseTags:      true,
+			expectedHumanLabel: "ClickHouse per-second rate of diskio counters per host, all hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "ClickHouse per-second rate of diskio counters per host, all hosts, random 1h0m0s by 1m: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            minute,
+            hostname,
+            avg(rate_read_bytes) AS rate_read_bytes, avg(rate_write_bytes) AS rate_write_bytes
+        FROM
+        (
+            SELECT
+                toStartOfMinute(created_at) AS minute,
+                tags_id AS id,
+                if((runningDifference(tags_id) = 0) AND (runningDifference(created_at) > 0), ` +
+				"if(runningDifference(read_bytes) >= 0, runningDifference(read_bytes), read_bytes) / runningDifference(created_at), NULL) AS rate_read_bytes, " +
+				"if((runningDifference(tags_id) = 0) AND (runningDifference(created_at) > 0), " +
+				`if(runningDifference(write_bytes) >= 0, runningDifference(write_bytes), write_bytes) / runningDifference(created_at), NULL) AS rate_write_bytes
+            FROM
+            (
+                SELECT *
+                FROM diskio
+                WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 01:16:22')
+                ORDER BY
+                    tags_id,
+                    created_at
+            )
+        ) AS diskio_rate
+        ANY INNER JOIN tags USING (id)
+        GROUP BY
+            minute,
+            hostname
+        ORDER BY
+            minute ASC,
+            hostname
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.CounterRate(q, devops.DiskIOTableName)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.CounterRateDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestHighCPUAndMem(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "use tags",
+			devopsUseTags:      true,
+			expectedHumanLabel: "ClickHouse CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "ClickHouse CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            bucket,
+            hostname,
+            mean_usage_user,
+            mean_used_percent
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                tags_id AS id,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+            GROUP BY
+                bucket,
+                id
+        ) AS cpu_avg
+        ANY INNER JOIN
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                tags_id AS id,
+                avg(used_percent) AS mean_used_percent
+            FROM mem
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+            GROUP BY
+                bucket,
+                id
+        ) AS mem_avg USING (bucket, id)
+        ANY INNER JOIN tags USING (id)
+        WHERE (mean_usage_user > 90.0) AND (mean_used_percent > 80.0)
+        ORDER BY
+            bucket ASC,
+            hostname
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUAndMem(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.HighCPUMemDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestCPUNginxCorrelation(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "no tags",
+			expectedHumanLabel: "ClickHouse mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "ClickHouse mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            bucket,
+            hostname,
+            mean_usage_user,
+            requests
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                hostname,
+                avg(usage_user) AS mean_usage_user
+            FROM cpu
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+            GROUP BY
+                bucket,
+                hostname
+        ) AS cpu_avg
+        ANY INNER JOIN
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS bucket,
+                hostname,
+                max(requests) - min(requests) AS requests
+            FROM nginx
+            WHERE (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+            GROUP BY
+                bucket,
+                hostname
+        ) AS nginx_requests USING (bucket, hostname)
+        
+        ORDER BY
+            hostname ASC,
+            bucket
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.CPUNginxCorrelation(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.CPUNginxDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestRawScan(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "no tags",
+			input:              1,
+			expectedHumanLabel: "ClickHouse all CPU metrics, raw points, random    1 hosts, random 12h0m0s",
+			expectedHumanDesc:  "ClickHouse all CPU metrics, raw points, random    1 hosts, random 12h0m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: `
+        SELECT
+            created_at,
+            hostname,
+            usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, usage_steal, usage_guest, usage_guest_nice
+        FROM cpu
+        WHERE (hostname = 'host_9') AND (created_at >= '1970-01-01 00:16:22') AND (created_at < '1970-01-01 12:16:22')
+        ORDER BY created_at
+        `,
+		},
+		{
+			desc:               "use tags",
+			input:              2,
+			devopsUseTags:      true,
+			expectedHumanLabel: "ClickHouse all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "ClickHouse all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T00:47:30Z",
+			expectedQuery: `
+        SELECT
+            created_at,
+            tags_id AS id,
+            usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, usage_steal, usage_guest, usage_guest_nice
+        FROM cpu
+        WHERE tags_id IN (SELECT id FROM tags WHERE hostname IN ('host_5','host_9')) AND (created_at >= '1970-01-01 00:47:30') AND (created_at < '1970-01-01 12:47:30')
+        ORDER BY created_at
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.RawScan(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.RawScanDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestDownsampleCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "entire dataset",
+			expectedHumanLabel: "ClickHouse mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "ClickHouse mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedQuery: `
+        SELECT
+            toStartOfDay(created_at) AS day,
+            avg(usage_user) AS avg_usage_user, avg(usage_system) AS avg_usage_system, avg(usage_idle) AS avg_usage_idle, avg(usage_nice) AS avg_usage_nice, avg(usage_iowait) AS avg_usage_iowait, avg(usage_irq) AS avg_usage_irq, avg(usage_softirq) AS avg_usage_softirq, avg(usage_steal) AS avg_usage_steal, avg(usage_guest) AS avg_usage_guest, avg(usage_guest_nice) AS avg_usage_guest_nice
+        FROM cpu
+        WHERE (created_at >= '1970-01-01 00:00:00') AND (created_at < '1970-01-04 00:00:00')
+        GROUP BY day
+        ORDER BY day
+        `,
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.DownsampleCPU(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(3 * devops.DownsampleBucket)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
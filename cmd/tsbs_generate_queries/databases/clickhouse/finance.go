@@ -0,0 +1,140 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Finance produces ClickHouse-specific queries for all the finance query types.
+// The created_at column only has a precision of seconds, so the order of the
+// events within a second is taken from the time column, which holds the full
+// timestamp as a string that sorts in time order.
+type Finance struct {
+	*BaseGenerator
+	*finance.Core
+}
+
+// OHLC calculates the open, high, low and close price and the traded volume
+// per minute of a random symbol in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, first(price) AS open, max(price) AS high, min(price) AS low, last(price) AS close, sum(size) AS volume
+// FROM trade
+// WHERE symbol = '$SYMBOL' AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute
+// ORDER BY minute
+//
+// Resultsets:
+// ohlc-1m
+func (f *Finance) OHLC(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.OHLCDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            toStartOfMinute(created_at) AS minute,
+            argMin(price, time) AS open,
+            max(price) AS high,
+            min(price) AS low,
+            argMax(price, time) AS close,
+            sum(size) AS volume
+        FROM trade
+        WHERE tags_id IN (SELECT id FROM tags WHERE symbol = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+        GROUP BY minute
+        ORDER BY minute ASC
+        `,
+		f.GetRandomSymbol(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse OHLC bars per minute"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradeTableName, sql)
+}
+
+// VWAP calculates the volume weighted average price of every symbol of a random
+// exchange in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT symbol, sum(price * size) / sum(size) AS vwap, sum(size) AS volume
+// FROM trade
+// WHERE exchange = '$EXCHANGE' AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY symbol
+// ORDER BY symbol
+//
+// Resultsets:
+// vwap
+func (f *Finance) VWAP(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.VWAPDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            symbol,
+            vwap,
+            volume
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                sum(price * size) / sum(size) AS vwap,
+                sum(size) AS volume
+            FROM trade
+            WHERE tags_id IN (SELECT id FROM tags WHERE exchange = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+        ) AS symbol_vwap
+        ANY INNER JOIN tags USING (id)
+        ORDER BY symbol ASC
+        `,
+		f.GetRandomExchange(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse VWAP per symbol"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradeTableName, sql)
+}
+
+// LastQuote fetches the last quote of every symbol of a random exchange,
+// e.g. in pseudo-SQL:
+//
+// SELECT DISTINCT ON (symbol) symbol, time, bid_price, bid_size, ask_price, ask_size
+// FROM quote
+// WHERE exchange = '$EXCHANGE'
+// ORDER BY symbol, time DESC
+//
+// Resultsets:
+// last-quote
+func (f *Finance) LastQuote(qi query.Query) {
+	exchange := f.GetRandomExchange()
+
+	sql := fmt.Sprintf(`
+        SELECT
+            symbol,
+            time,
+            bid_price,
+            bid_size,
+            ask_price,
+            ask_size
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(time) AS time,
+                argMax(bid_price, time) AS bid_price,
+                argMax(bid_size, time) AS bid_size,
+                argMax(ask_price, time) AS ask_price,
+                argMax(ask_size, time) AS ask_size
+            FROM quote
+            WHERE tags_id IN (SELECT id FROM tags WHERE exchange = '%s')
+            GROUP BY id
+        ) AS last_quote
+        ANY INNER JOIN tags USING (id)
+        ORDER BY symbol ASC
+        `,
+		exchange)
+
+	humanLabel := "ClickHouse last quote per symbol"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, exchange)
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.QuoteTableName, sql)
+}
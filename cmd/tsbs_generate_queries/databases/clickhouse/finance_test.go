@@ -0,0 +1,117 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestFinanceQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(*Finance, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc: "ohlc",
+			fn: func(f *Finance, q query.Query) {
+				f.OHLC(q)
+			},
+			expectedHumanLabel: "ClickHouse OHLC bars per minute",
+			expectedHumanDesc:  "ClickHouse OHLC bars per minute: 1970-01-02T02:16:22Z",
+			expectedQuery: `
+        SELECT
+            toStartOfMinute(created_at) AS minute,
+            argMin(price, time) AS open,
+            max(price) AS high,
+            min(price) AS low,
+            argMax(price, time) AS close,
+            sum(size) AS volume
+        FROM trade
+        WHERE tags_id IN (SELECT id FROM tags WHERE symbol = 'ENDU') AND (created_at >= '1970-01-02 02:16:22') AND (created_at < '1970-01-02 03:16:22')
+        GROUP BY minute
+        ORDER BY minute ASC
+        `,
+		},
+		{
+			desc: "vwap",
+			fn: func(f *Finance, q query.Query) {
+				f.VWAP(q)
+			},
+			expectedHumanLabel: "ClickHouse VWAP per symbol",
+			expectedHumanDesc:  "ClickHouse VWAP per symbol: 1970-01-02T02:16:22Z",
+			expectedQuery: `
+        SELECT
+            symbol,
+            vwap,
+            volume
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                sum(price * size) / sum(size) AS vwap,
+                sum(size) AS volume
+            FROM trade
+            WHERE tags_id IN (SELECT id FROM tags WHERE exchange = 'XETRA') AND (created_at >= '1970-01-02 02:16:22') AND (created_at < '1970-01-02 03:16:22')
+            GROUP BY id
+        ) AS symbol_vwap
+        ANY INNER JOIN tags USING (id)
+        ORDER BY symbol ASC
+        `,
+		},
+		{
+			desc: "last quote",
+			fn: func(f *Finance, q query.Query) {
+				f.LastQuote(q)
+			},
+			expectedHumanLabel: "ClickHouse last quote per symbol",
+			expectedHumanDesc:  "ClickHouse last quote per symbol: HKEX",
+			expectedQuery: `
+        SELECT
+            symbol,
+            time,
+            bid_price,
+            bid_size,
+            ask_price,
+            ask_size
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(time) AS time,
+                argMax(bid_price, time) AS bid_price,
+                argMax(bid_size, time) AS bid_size,
+                argMax(ask_price, time) AS ask_price,
+                argMax(ask_size, time) AS ask_size
+            FROM quote
+            WHERE tags_id IN (SELECT id FROM tags WHERE exchange = 'HKEX')
+            GROUP BY id
+        ) AS last_quote
+        ANY INNER JOIN tags USING (id)
+        ORDER BY symbol ASC
+        `,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			g, err := b.NewFinance(s, e, 10)
+			if err != nil {
+				t.Fatalf("Error while creating finance generator")
+			}
+			f := g.(*Finance)
+			q := f.GenerateEmptyQuery()
+			c.fn(f, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// IoT produces ClickHouse-specific queries for all the iot query types. The
+// truck tags are only stored in the tags table, so the readings and
+// diagnostics are always aggregated per tags_id first and joined with the
+// tags afterwards. The driving sessions and breakdowns are found with array
+// functions on the ten minute buckets of every truck.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// getTrucksWhereString gets multiple random trucks and creates a WHERE SQL
+// statement selecting their tags ids.
+func (i *IoT) getTrucksWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	panicIfErr(err)
+	return fmt.Sprintf("tags_id IN (SELECT id FROM tags WHERE name IN ('%s'))", strings.Join(names, "', '"))
+}
+
+// getFleetWhereString creates a WHERE SQL statement selecting the tags ids of
+// the named trucks of a random fleet.
+func (i *IoT) getFleetWhereString() string {
+	return fmt.Sprintf("tags_id IN (SELECT id FROM tags WHERE fleet = '%s' AND name IS NOT NULL)", i.GetRandomFleet())
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := fmt.Sprintf(`
+        SELECT
+            name,
+            driver,
+            last_longitude AS longitude,
+            last_latitude AS latitude
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(longitude, created_at) AS last_longitude,
+                argMax(latitude, created_at) AS last_latitude
+            FROM readings
+            WHERE %s
+            GROUP BY id
+        ) AS last_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		i.getTrucksWhereString(nTrucks))
+
+	humanLabel := "ClickHouse last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	sql := fmt.Sprintf(`
+        SELECT
+            name,
+            driver,
+            last_longitude AS longitude,
+            last_latitude AS latitude
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(longitude, created_at) AS last_longitude,
+                argMax(latitude, created_at) AS last_latitude
+            FROM readings
+            WHERE %s
+            GROUP BY id
+        ) AS last_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		i.getFleetWhereString())
+
+	humanLabel := "ClickHouse last location per truck"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	sql := fmt.Sprintf(`
+        SELECT
+            name,
+            driver,
+            last_fuel_state AS fuel_state
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(fuel_state, created_at) AS last_fuel_state
+            FROM diagnostics
+            WHERE %s
+            GROUP BY id
+            HAVING last_fuel_state < 0.1
+        ) AS last_diagnostics
+        ANY INNER JOIN tags USING (id)
+        `,
+		i.getFleetWhereString())
+
+	humanLabel := "ClickHouse trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	sql := fmt.Sprintf(`
+        SELECT
+            name,
+            driver,
+            last_current_load AS current_load,
+            load_capacity
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(current_load, created_at) AS last_current_load
+            FROM diagnostics
+            WHERE %s
+            GROUP BY id
+        ) AS last_diagnostics
+        ANY INNER JOIN tags USING (id)
+        WHERE last_current_load / load_capacity > 0.9
+        `,
+		i.getFleetWhereString())
+
+	humanLabel := "ClickHouse trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`
+        SELECT
+            name,
+            driver
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                avg(velocity) AS mean_velocity
+            FROM readings
+            WHERE %s AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+            HAVING mean_velocity < 1
+        ) AS stationary_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		i.getFleetWhereString(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := i.drivingSessionsSQL(interval.Start(), interval.End(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "ClickHouse trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := i.drivingSessionsSQL(interval.Start(), interval.End(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "ClickHouse trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// drivingSessionsSQL selects the trucks of a random fleet that were driving
+// for more than the given number of ten minute periods between start and end.
+func (i *IoT) drivingSessionsSQL(start, end time.Time, periods int) string {
+	return fmt.Sprintf(`
+        SELECT
+            name,
+            driver
+        FROM
+        (
+            SELECT
+                id,
+                count() AS driving_periods
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                WHERE %s AND (created_at >= '%s') AND (created_at < '%s')
+                GROUP BY id, ten_minutes
+                HAVING mean_velocity > 1
+            )
+            GROUP BY id
+            HAVING driving_periods > %d
+        ) AS driving_sessions
+        ANY INNER JOIN tags USING (id)
+        `,
+		i.getFleetWhereString(),
+		start.Format(clickhouseTimeStringFormat),
+		end.Format(clickhouseTimeStringFormat),
+		periods)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *IoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := `
+        SELECT
+            fleet,
+            avg(fuel_consumption) AS avg_fuel_consumption,
+            avg(nominal_fuel_consumption) AS projected_fuel_consumption
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                fuel_consumption
+            FROM readings
+            WHERE velocity > 1
+        ) AS driving_readings
+        ANY INNER JOIN tags USING (id)
+        WHERE (fleet IS NOT NULL) AND (nominal_fuel_consumption IS NOT NULL) AND (name IS NOT NULL)
+        GROUP BY fleet
+        `
+
+	humanLabel := "ClickHouse average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := `
+        SELECT
+            fleet,
+            name,
+            driver,
+            avg(hours) AS avg_daily_hours
+        FROM
+        (
+            SELECT
+                id,
+                day,
+                count() / 6 AS hours
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfDay(created_at) AS day,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                GROUP BY id, day, ten_minutes
+                HAVING mean_velocity > 1
+            )
+            GROUP BY id, day
+        ) AS daily_hours
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, name, driver
+        `
+
+	humanLabel := "ClickHouse average driver driving duration per day"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+// The ten minute buckets of every truck are sorted into an array, of which
+// only the buckets changing from stopped to driving or back are kept. Every
+// bucket starting to drive and the next change make up a session.
+func (i *IoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := `
+        SELECT
+            name,
+            toStartOfDay(session.1) AS day,
+            avg(dateDiff('minute', session.1, session.2)) AS duration_minutes
+        FROM
+        (
+            SELECT
+                id,
+                arraySort(groupArray((ten_minutes, mean_velocity > 5))) AS periods,
+                arrayFilter((p, n) -> (n = 1) OR (p.2 != periods[n - 1].2), periods, arrayEnumerate(periods)) AS changes,
+                arrayMap(n -> (changes[n].1, changes[n + 1].1), arrayFilter(n -> changes[n].2 AND (n < length(changes)), arrayEnumerate(changes))) AS sessions
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                GROUP BY id, ten_minutes
+            )
+            GROUP BY id
+        ) AS truck_sessions
+        ARRAY JOIN sessions AS session
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY name, day
+        ORDER BY name, day
+        `
+
+	humanLabel := "ClickHouse average driver driving session without stopping per day"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	sql := `
+        SELECT
+            fleet,
+            model,
+            load_capacity,
+            avg(avg_load / load_capacity) AS avg_load_percentage
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                avg(current_load) AS avg_load
+            FROM diagnostics
+            GROUP BY id
+        ) AS truck_load
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, model, load_capacity
+        `
+
+	humanLabel := "ClickHouse average load per truck model per fleet"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	sql := `
+        SELECT
+            fleet,
+            model,
+            day,
+            sum(active_periods) / 144 AS daily_activity
+        FROM
+        (
+            SELECT
+                id,
+                day,
+                count() AS active_periods
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfDay(created_at) AS day,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(status) AS mean_status
+                FROM diagnostics
+                GROUP BY id, day, ten_minutes
+                HAVING mean_status < 1
+            )
+            GROUP BY id, day
+        ) AS truck_activity
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, model, day
+        ORDER BY day
+        `
+
+	humanLabel := "ClickHouse daily truck activity per fleet per model"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+// A breakdown is a ten minute bucket with a breakdown ratio of at least 0.5
+// following one with a lower ratio, counted on the sorted array of buckets of
+// every truck.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := `
+        SELECT
+            model,
+            sum(truck_breakdowns) AS breakdowns
+        FROM
+        (
+            SELECT
+                id,
+                arrayMap(p -> p.2, arraySort(groupArray((ten_minutes, breakdown_ratio)))) AS ratios,
+                arrayCount((r, n) -> (n > 1) AND (ratios[n - 1] < 0.5) AND (r >= 0.5), ratios, arrayEnumerate(ratios)) AS truck_breakdowns
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(if(status = 0, 1, 0)) AS breakdown_ratio
+                FROM diagnostics
+                GROUP BY id, ten_minutes
+            )
+            GROUP BY id
+        ) AS truck_ratios
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY model
+        `
+
+	humanLabel := "ClickHouse truck breakdown frequency per model"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
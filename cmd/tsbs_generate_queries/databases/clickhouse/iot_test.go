@@ -0,0 +1,444 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIoTQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(*IoT, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc: "last loc by truck",
+			fn: func(i *IoT, q query.Query) {
+				i.LastLocByTruck(q, 2)
+			},
+			expectedHumanLabel: "ClickHouse last location by specific truck",
+			expectedHumanDesc:  "ClickHouse last location by specific truck: random    2 trucks",
+			expectedQuery: `
+        SELECT
+            name,
+            driver,
+            last_longitude AS longitude,
+            last_latitude AS latitude
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(longitude, created_at) AS last_longitude,
+                argMax(latitude, created_at) AS last_latitude
+            FROM readings
+            WHERE tags_id IN (SELECT id FROM tags WHERE name IN ('truck_5', 'truck_9'))
+            GROUP BY id
+        ) AS last_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "last loc per truck",
+			fn: func(i *IoT, q query.Query) {
+				i.LastLocPerTruck(q)
+			},
+			expectedHumanLabel: "ClickHouse last location per truck",
+			expectedHumanDesc:  "ClickHouse last location per truck",
+			expectedQuery: `
+        SELECT
+            name,
+            driver,
+            last_longitude AS longitude,
+            last_latitude AS latitude
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(longitude, created_at) AS last_longitude,
+                argMax(latitude, created_at) AS last_latitude
+            FROM readings
+            WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'South' AND name IS NOT NULL)
+            GROUP BY id
+        ) AS last_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "trucks with low fuel",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLowFuel(q)
+			},
+			expectedHumanLabel: "ClickHouse trucks with low fuel",
+			expectedHumanDesc:  "ClickHouse trucks with low fuel: under 10 percent",
+			expectedQuery: `
+        SELECT
+            name,
+            driver,
+            last_fuel_state AS fuel_state
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(fuel_state, created_at) AS last_fuel_state
+            FROM diagnostics
+            WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'South' AND name IS NOT NULL)
+            GROUP BY id
+            HAVING last_fuel_state < 0.1
+        ) AS last_diagnostics
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "trucks with high load",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithHighLoad(q)
+			},
+			expectedHumanLabel: "ClickHouse trucks with high load",
+			expectedHumanDesc:  "ClickHouse trucks with high load: over 90 percent",
+			expectedQuery: `
+        SELECT
+            name,
+            driver,
+            last_current_load AS current_load,
+            load_capacity
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                argMax(current_load, created_at) AS last_current_load
+            FROM diagnostics
+            WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'South' AND name IS NOT NULL)
+            GROUP BY id
+        ) AS last_diagnostics
+        ANY INNER JOIN tags USING (id)
+        WHERE last_current_load / load_capacity > 0.9
+        `,
+		},
+		{
+			desc: "stationary trucks",
+			fn: func(i *IoT, q query.Query) {
+				i.StationaryTrucks(q)
+			},
+			expectedHumanLabel: "ClickHouse stationary trucks",
+			expectedHumanDesc:  "ClickHouse stationary trucks: with low avg velocity in last 10 minutes",
+			expectedQuery: `
+        SELECT
+            name,
+            driver
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                avg(velocity) AS mean_velocity
+            FROM readings
+            WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'West' AND name IS NOT NULL) AND (created_at >= '1970-01-01 23:36:22') AND (created_at < '1970-01-01 23:46:22')
+            GROUP BY id
+            HAVING mean_velocity < 1
+        ) AS stationary_readings
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "trucks with long driving sessions",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLongDrivingSessions(q)
+			},
+			expectedHumanLabel: "ClickHouse trucks with longer driving sessions",
+			expectedHumanDesc:  "ClickHouse trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedQuery: `
+        SELECT
+            name,
+            driver
+        FROM
+        (
+            SELECT
+                id,
+                count() AS driving_periods
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'West' AND name IS NOT NULL) AND (created_at >= '1970-01-01 06:16:22') AND (created_at < '1970-01-01 10:16:22')
+                GROUP BY id, ten_minutes
+                HAVING mean_velocity > 1
+            )
+            GROUP BY id
+            HAVING driving_periods > 22
+        ) AS driving_sessions
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "trucks with long daily sessions",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLongDailySessions(q)
+			},
+			expectedHumanLabel: "ClickHouse trucks with longer daily sessions",
+			expectedHumanDesc:  "ClickHouse trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
+			expectedQuery: `
+        SELECT
+            name,
+            driver
+        FROM
+        (
+            SELECT
+                id,
+                count() AS driving_periods
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                WHERE tags_id IN (SELECT id FROM tags WHERE fleet = 'West' AND name IS NOT NULL) AND (created_at >= '1970-01-01 18:16:22') AND (created_at < '1970-01-02 18:16:22')
+                GROUP BY id, ten_minutes
+                HAVING mean_velocity > 1
+            )
+            GROUP BY id
+            HAVING driving_periods > 60
+        ) AS driving_sessions
+        ANY INNER JOIN tags USING (id)
+        `,
+		},
+		{
+			desc: "avg vs projected fuel consumption",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgVsProjectedFuelConsumption(q)
+			},
+			expectedHumanLabel: "ClickHouse average vs projected fuel consumption per fleet",
+			expectedHumanDesc:  "ClickHouse average vs projected fuel consumption per fleet",
+			expectedQuery: `
+        SELECT
+            fleet,
+            avg(fuel_consumption) AS avg_fuel_consumption,
+            avg(nominal_fuel_consumption) AS projected_fuel_consumption
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                fuel_consumption
+            FROM readings
+            WHERE velocity > 1
+        ) AS driving_readings
+        ANY INNER JOIN tags USING (id)
+        WHERE (fleet IS NOT NULL) AND (nominal_fuel_consumption IS NOT NULL) AND (name IS NOT NULL)
+        GROUP BY fleet
+        `,
+		},
+		{
+			desc: "avg daily driving duration",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgDailyDrivingDuration(q)
+			},
+			expectedHumanLabel: "ClickHouse average driver driving duration per day",
+			expectedHumanDesc:  "ClickHouse average driver driving duration per day",
+			expectedQuery: `
+        SELECT
+            fleet,
+            name,
+            driver,
+            avg(hours) AS avg_daily_hours
+        FROM
+        (
+            SELECT
+                id,
+                day,
+                count() / 6 AS hours
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfDay(created_at) AS day,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                GROUP BY id, day, ten_minutes
+                HAVING mean_velocity > 1
+            )
+            GROUP BY id, day
+        ) AS daily_hours
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, name, driver
+        `,
+		},
+		{
+			desc: "avg daily driving session",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgDailyDrivingSession(q)
+			},
+			expectedHumanLabel: "ClickHouse average driver driving session without stopping per day",
+			expectedHumanDesc:  "ClickHouse average driver driving session without stopping per day",
+			expectedQuery: `
+        SELECT
+            name,
+            toStartOfDay(session.1) AS day,
+            avg(dateDiff('minute', session.1, session.2)) AS duration_minutes
+        FROM
+        (
+            SELECT
+                id,
+                arraySort(groupArray((ten_minutes, mean_velocity > 5))) AS periods,
+                arrayFilter((p, n) -> (n = 1) OR (p.2 != periods[n - 1].2), periods, arrayEnumerate(periods)) AS changes,
+                arrayMap(n -> (changes[n].1, changes[n + 1].1), arrayFilter(n -> changes[n].2 AND (n < length(changes)), arrayEnumerate(changes))) AS sessions
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(velocity) AS mean_velocity
+                FROM readings
+                GROUP BY id, ten_minutes
+            )
+            GROUP BY id
+        ) AS truck_sessions
+        ARRAY JOIN sessions AS session
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY name, day
+        ORDER BY name, day
+        `,
+		},
+		{
+			desc: "avg load",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgLoad(q)
+			},
+			expectedHumanLabel: "ClickHouse average load per truck model per fleet",
+			expectedHumanDesc:  "ClickHouse average load per truck model per fleet",
+			expectedQuery: `
+        SELECT
+            fleet,
+            model,
+            load_capacity,
+            avg(avg_load / load_capacity) AS avg_load_percentage
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                avg(current_load) AS avg_load
+            FROM diagnostics
+            GROUP BY id
+        ) AS truck_load
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, model, load_capacity
+        `,
+		},
+		{
+			desc: "daily truck activity",
+			fn: func(i *IoT, q query.Query) {
+				i.DailyTruckActivity(q)
+			},
+			expectedHumanLabel: "ClickHouse daily truck activity per fleet per model",
+			expectedHumanDesc:  "ClickHouse daily truck activity per fleet per model",
+			expectedQuery: `
+        SELECT
+            fleet,
+            model,
+            day,
+            sum(active_periods) / 144 AS daily_activity
+        FROM
+        (
+            SELECT
+                id,
+                day,
+                count() AS active_periods
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfDay(created_at) AS day,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(status) AS mean_status
+                FROM diagnostics
+                GROUP BY id, day, ten_minutes
+                HAVING mean_status < 1
+            )
+            GROUP BY id, day
+        ) AS truck_activity
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY fleet, model, day
+        ORDER BY day
+        `,
+		},
+		{
+			desc: "truck breakdown frequency",
+			fn: func(i *IoT, q query.Query) {
+				i.TruckBreakdownFrequency(q)
+			},
+			expectedHumanLabel: "ClickHouse truck breakdown frequency per model",
+			expectedHumanDesc:  "ClickHouse truck breakdown frequency per model",
+			expectedQuery: `
+        SELECT
+            model,
+            sum(truck_breakdowns) AS breakdowns
+        FROM
+        (
+            SELECT
+                id,
+                arrayMap(p -> p.2, arraySort(groupArray((ten_minutes, breakdown_ratio)))) AS ratios,
+                arrayCount((r, n) -> (n > 1) AND (ratios[n - 1] < 0.5) AND (r >= 0.5), ratios, arrayEnumerate(ratios)) AS truck_breakdowns
+            FROM
+            (
+                SELECT
+                    tags_id AS id,
+                    toStartOfTenMinutes(created_at) AS ten_minutes,
+                    avg(if(status = 0, 1, 0)) AS breakdown_ratio
+                FROM diagnostics
+                GROUP BY id, ten_minutes
+            )
+            GROUP BY id
+        ) AS truck_ratios
+        ANY INNER JOIN tags USING (id)
+        WHERE name IS NOT NULL
+        GROUP BY model
+        `,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			g, err := b.NewIoT(s, e, 10)
+			if err != nil {
+				t.Fatalf("Error while creating iot generator")
+			}
+			i := g.(*IoT)
+			q := i.GenerateEmptyQuery()
+			c.fn(i, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+		})
+	}
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	cases := []struct {
+		minutesPerHour float64
+		duration       time.Duration
+		result         int
+	}{
+		{minutesPerHour: 5, duration: 4 * time.Hour, result: 22},
+		{minutesPerHour: 35, duration: 24 * time.Hour, result: 60},
+	}
+
+	for _, c := range cases {
+		if got := tenMinutePeriods(c.minutesPerHour, c.duration); got != c.result {
+			t.Errorf("incorrect result for %.2f minutes per hour, duration %s: got %d want %d", c.minutesPerHour, c.duration.String(), got, c.result)
+		}
+	}
+}
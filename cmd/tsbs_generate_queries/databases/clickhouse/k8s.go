@@ -0,0 +1,160 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces ClickHouse-specific queries for all the k8s query types.
+// Only the pod tag can be stored in the measurement tables, so all the
+// queries look up the other tags in the separate tags table.
+type K8s struct {
+	*BaseGenerator
+	*k8s.Core
+}
+
+// TopPodsByCPU finds the pods of a namespace which used the most CPU time in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT pod, max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds
+// FROM container
+// WHERE namespace = '$NAMESPACE' AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY pod
+// ORDER BY cpu_seconds DESC
+// LIMIT $LIMIT
+//
+// Resultsets:
+// top-pods-cpu
+func (k *K8s) TopPodsByCPU(qi query.Query, limit int) {
+	interval := k.Interval.MustRandWindow(k8s.TopPodsDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            pod,
+            cpu_seconds
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds
+            FROM container
+            WHERE tags_id IN (SELECT id FROM tags WHERE namespace = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+        ) AS pod_cpu
+        ANY INNER JOIN tags USING (id)
+        ORDER BY cpu_seconds DESC
+        LIMIT %d
+        `,
+		k.GetRandomNamespace(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat),
+		limit)
+
+	humanLabel := fmt.Sprintf("ClickHouse top %d pods by CPU usage per namespace", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerTableName, sql)
+}
+
+// RestartsPerDeployment counts the container restarts per deployment of a namespace in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT deployment, sum(restarts) AS restarts
+// FROM (
+//     SELECT deployment, pod, max(container_status_restarts_total) - min(container_status_restarts_total) AS restarts
+//     FROM kube_pod
+//     WHERE namespace = '$NAMESPACE' AND time >= '$DAY_START' AND time < '$DAY_END'
+//     GROUP BY deployment, pod
+// )
+// GROUP BY deployment
+// ORDER BY restarts DESC, deployment
+//
+// Resultsets:
+// restarts-per-deployment
+func (k *K8s) RestartsPerDeployment(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.RestartsDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            deployment,
+            sum(restarts) AS restarts
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(container_status_restarts_total) - min(container_status_restarts_total) AS restarts
+            FROM kube_pod
+            WHERE tags_id IN (SELECT id FROM tags WHERE namespace = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+        ) AS pod_restarts
+        ANY INNER JOIN tags USING (id)
+        GROUP BY deployment
+        ORDER BY
+            restarts DESC,
+            deployment ASC
+        `,
+		k.GetRandomNamespace(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse container restarts per deployment"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.KubePodTableName, sql)
+}
+
+// NodeSaturation calculates the CPU cores and memory used on each node per 5 minutes in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT five_minutes, node, sum(cpu_seconds) / 300 AS cpu_cores, sum(memory_bytes) AS memory_bytes
+// FROM (
+//     SELECT five_minutes, node, pod,
+//         max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds,
+//         avg(memory_working_set_bytes) AS memory_bytes
+//     FROM container
+//     WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+//     GROUP BY five_minutes, node, pod
+// )
+// GROUP BY five_minutes, node
+// ORDER BY five_minutes, node
+//
+// Resultsets:
+// node-saturation
+func (k *K8s) NodeSaturation(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.NodeSaturationDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            five_minutes,
+            node,
+            sum(cpu_seconds) / %d AS cpu_cores,
+            sum(memory_bytes) AS memory_bytes
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS five_minutes,
+                tags_id AS id,
+                max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds,
+                avg(memory_working_set_bytes) AS memory_bytes
+            FROM container
+            WHERE (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY
+                five_minutes,
+                id
+        ) AS pod_usage
+        ANY INNER JOIN tags USING (id)
+        GROUP BY
+            five_minutes,
+            node
+        ORDER BY
+            five_minutes ASC,
+            node ASC
+        `,
+		int(k8s.NodeSaturationBucket.Seconds()),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse node CPU and memory saturation"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerTableName, sql)
+}
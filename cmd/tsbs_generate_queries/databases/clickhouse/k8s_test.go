@@ -0,0 +1,126 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestK8sQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(*K8s, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc: "top pods by cpu",
+			fn: func(k *K8s, q query.Query) {
+				k.TopPodsByCPU(q, k8s.TopPodsLimit)
+			},
+			expectedHumanLabel: "ClickHouse top 10 pods by CPU usage per namespace",
+			expectedHumanDesc:  "ClickHouse top 10 pods by CPU usage per namespace: 1970-01-02T02:16:22Z",
+			expectedQuery: `
+        SELECT
+            pod,
+            cpu_seconds
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds
+            FROM container
+            WHERE tags_id IN (SELECT id FROM tags WHERE namespace = 'ingress') AND (created_at >= '1970-01-02 02:16:22') AND (created_at < '1970-01-02 03:16:22')
+            GROUP BY id
+        ) AS pod_cpu
+        ANY INNER JOIN tags USING (id)
+        ORDER BY cpu_seconds DESC
+        LIMIT 10
+        `,
+		},
+		{
+			desc: "restarts per deployment",
+			fn: func(k *K8s, q query.Query) {
+				k.RestartsPerDeployment(q)
+			},
+			expectedHumanLabel: "ClickHouse container restarts per deployment",
+			expectedHumanDesc:  "ClickHouse container restarts per deployment: 1970-01-01T18:16:22Z",
+			expectedQuery: `
+        SELECT
+            deployment,
+            sum(restarts) AS restarts
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(container_status_restarts_total) - min(container_status_restarts_total) AS restarts
+            FROM kube_pod
+            WHERE tags_id IN (SELECT id FROM tags WHERE namespace = 'ingress') AND (created_at >= '1970-01-01 18:16:22') AND (created_at < '1970-01-02 18:16:22')
+            GROUP BY id
+        ) AS pod_restarts
+        ANY INNER JOIN tags USING (id)
+        GROUP BY deployment
+        ORDER BY
+            restarts DESC,
+            deployment ASC
+        `,
+		},
+		{
+			desc: "node saturation",
+			fn: func(k *K8s, q query.Query) {
+				k.NodeSaturation(q)
+			},
+			expectedHumanLabel: "ClickHouse node CPU and memory saturation",
+			expectedHumanDesc:  "ClickHouse node CPU and memory saturation: 1970-01-02T02:16:22Z",
+			expectedQuery: `
+        SELECT
+            five_minutes,
+            node,
+            sum(cpu_seconds) / 300 AS cpu_cores,
+            sum(memory_bytes) AS memory_bytes
+        FROM
+        (
+            SELECT
+                toStartOfFiveMinute(created_at) AS five_minutes,
+                tags_id AS id,
+                max(cpu_usage_seconds_total) - min(cpu_usage_seconds_total) AS cpu_seconds,
+                avg(memory_working_set_bytes) AS memory_bytes
+            FROM container
+            WHERE (created_at >= '1970-01-02 02:16:22') AND (created_at < '1970-01-02 03:16:22')
+            GROUP BY
+                five_minutes,
+                id
+        ) AS pod_usage
+        ANY INNER JOIN tags USING (id)
+        GROUP BY
+            five_minutes,
+            node
+        ORDER BY
+            five_minutes ASC,
+            node ASC
+        `,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			g, err := b.NewK8s(s, e, 10)
+			if err != nil {
+				t.Fatalf("Error while creating k8s generator")
+			}
+			k := g.(*K8s)
+			q := k.GenerateEmptyQuery()
+			c.fn(k, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// SmartMeter produces ClickHouse-specific queries for all the smart-meter query types.
+type SmartMeter struct {
+	*BaseGenerator
+	*smartmeter.Core
+}
+
+// DailyConsumptionPerFeeder calculates the energy consumed by the meters of
+// each feeder of a random region in a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT feeder, sum(consumption) AS consumption_kwh
+// FROM (
+//     SELECT feeder, meter_id, max(energy_import_kwh) - min(energy_import_kwh) AS consumption
+//     FROM meter
+//     WHERE region = '$REGION' AND time >= '$DAY_START' AND time < '$DAY_END'
+//     GROUP BY feeder, meter_id
+// )
+// GROUP BY feeder
+// ORDER BY feeder
+//
+// Resultsets:
+// daily-consumption-per-feeder
+func (s *SmartMeter) DailyConsumptionPerFeeder(qi query.Query) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            feeder,
+            sum(consumption) AS consumption_kwh
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(energy_import_kwh) - min(energy_import_kwh) AS consumption
+            FROM meter
+            WHERE tags_id IN (SELECT id FROM tags WHERE region = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+        ) AS meter_consumption
+        ANY INNER JOIN tags USING (id)
+        GROUP BY feeder
+        ORDER BY feeder ASC
+        `,
+		s.GetRandomRegion(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat))
+
+	humanLabel := "ClickHouse daily consumption per feeder"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	s.fillInQuery(qi, humanLabel, humanDesc, smartmeter.TableName, sql)
+}
+
+// TopConsumers finds the meters of a random region which consumed the most
+// energy in a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT meter_id, max(energy_import_kwh) - min(energy_import_kwh) AS consumption_kwh
+// FROM meter
+// WHERE region = '$REGION' AND time >= '$DAY_START' AND time < '$DAY_END'
+// GROUP BY meter_id
+// ORDER BY consumption_kwh DESC
+// LIMIT $LIMIT
+//
+// Resultsets:
+// top-consumers
+func (s *SmartMeter) TopConsumers(qi query.Query, limit int) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+
+	sql := fmt.Sprintf(`
+        SELECT
+            meter_id,
+            consumption_kwh
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(energy_import_kwh) - min(energy_import_kwh) AS consumption_kwh
+            FROM meter
+            WHERE tags_id IN (SELECT id FROM tags WHERE region = '%s') AND (created_at >= '%s') AND (created_at < '%s')
+            GROUP BY id
+            ORDER BY consumption_kwh DESC
+            LIMIT %d
+        ) AS meter_consumption
+        ANY INNER JOIN tags USING (id)
+        ORDER BY consumption_kwh DESC
+        `,
+		s.GetRandomRegion(),
+		interval.Start().Format(clickhouseTimeStringFormat),
+		interval.End().Format(clickhouseTimeStringFormat),
+		limit)
+
+	humanLabel := fmt.Sprintf("ClickHouse top %d consumers per region", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	s.fillInQuery(qi, humanLabel, humanDesc, smartmeter.TableName, sql)
+}
@@ -0,0 +1,90 @@
+package clickhouse
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestSmartMeterQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(*SmartMeter, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc: "daily consumption per feeder",
+			fn: func(s *SmartMeter, q query.Query) {
+				s.DailyConsumptionPerFeeder(q)
+			},
+			expectedHumanLabel: "ClickHouse daily consumption per feeder",
+			expectedHumanDesc:  "ClickHouse daily consumption per feeder: 1970-01-01T18:16:22Z",
+			expectedQuery: `
+        SELECT
+            feeder,
+            sum(consumption) AS consumption_kwh
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(energy_import_kwh) - min(energy_import_kwh) AS consumption
+            FROM meter
+            WHERE tags_id IN (SELECT id FROM tags WHERE region = 'south') AND (created_at >= '1970-01-01 18:16:22') AND (created_at < '1970-01-02 18:16:22')
+            GROUP BY id
+        ) AS meter_consumption
+        ANY INNER JOIN tags USING (id)
+        GROUP BY feeder
+        ORDER BY feeder ASC
+        `,
+		},
+		{
+			desc: "top consumers",
+			fn: func(s *SmartMeter, q query.Query) {
+				s.TopConsumers(q, smartmeter.TopConsumersLimit)
+			},
+			expectedHumanLabel: "ClickHouse top 10 consumers per region",
+			expectedHumanDesc:  "ClickHouse top 10 consumers per region: 1970-01-01T18:16:22Z",
+			expectedQuery: `
+        SELECT
+            meter_id,
+            consumption_kwh
+        FROM
+        (
+            SELECT
+                tags_id AS id,
+                max(energy_import_kwh) - min(energy_import_kwh) AS consumption_kwh
+            FROM meter
+            WHERE tags_id IN (SELECT id FROM tags WHERE region = 'south') AND (created_at >= '1970-01-01 18:16:22') AND (created_at < '1970-01-02 18:16:22')
+            GROUP BY id
+            ORDER BY consumption_kwh DESC
+            LIMIT 10
+        ) AS meter_consumption
+        ANY INNER JOIN tags USING (id)
+        ORDER BY consumption_kwh DESC
+        `,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			g, err := b.NewSmartMeter(s, e, 10)
+			if err != nil {
+				t.Fatalf("Error while creating smart-meter generator")
+			}
+			sm := g.(*SmartMeter)
+			q := sm.GenerateEmptyQuery()
+			c.fn(sm, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+		})
+	}
+}
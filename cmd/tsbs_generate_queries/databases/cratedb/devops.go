@@ -68,7 +68,7 @@ func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
 
 	humanLabel := devops.GetMaxAllLabel("CrateDB", nHosts)
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
 
 // GroupByTimeAndPrimaryTag selects the AVG of metrics in the group `cpu` per device
@@ -100,7 +100,7 @@ func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
 
 	humanLabel := devops.GetDoubleGroupByLabel("CrateDB", numMetrics)
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
 
 // GroupByOrderByLimit populates a query.Query that has a time WHERE clause,
@@ -123,7 +123,7 @@ func (d *Devops) GroupByOrderByLimit(qi query.Query) {
 
 	humanLabel := "CrateDB max cpu over last 5 min-intervals (random end)"
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
 
 // LastPointPerHost finds the last row for every host in the dataset
@@ -141,7 +141,7 @@ func (d *Devops) LastPointPerHost(qi query.Query) {
 
 	humanLabel := "CrateDB last row per host"
 	humanDesc := humanLabel
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
 
 // HighCPUForHosts populates a query that gets CPU metrics when the CPU has
@@ -171,7 +171,7 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	humanLabel, err := devops.GetHighCPULabel("CrateDB", nHosts)
 	panicIfErr(err)
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
 
 // GroupByTime selects the MAX for metrics under 'cpu', per minute for N random
@@ -212,5 +212,203 @@ func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange t
 		"CrateDB %d cpu metric(s), random %4d hosts, random %s by 1m",
 		numMetrics, nHosts, timeRange)
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
-	d.fillInQuery(qi, humanLabel, humanDesc, sql)
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// PercentileCPU selects the percentiles of the user CPU usage per host per
+// hour for half a day
+//
+// Queries:
+// percentile-cpu
+func (d *Devops) PercentileCPU(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.PercentileCPUDuration)
+	percentiles := devops.GetCPUPercentiles()
+	selectClauses := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		selectClauses[i] = fmt.Sprintf("percentile(usage_user, %g) AS %s_usage_user",
+			p, devops.GetPercentileName(p))
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT
+			date_trunc('hour', ts) AS hour,
+			%[1]s AS host,
+			%[2]s
+		FROM cpu
+		WHERE ts >= %[3]d
+		  AND ts < %[4]d
+		GROUP BY hour, %[1]s
+		ORDER BY hour, host`,
+		hostnameField,
+		strings.Join(selectClauses, ", "),
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := devops.GetPercentileCPULabel("CrateDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// TopKHostsByCPU selects the hosts with the highest mean user CPU usage in
+// a time window
+//
+// Queries:
+// topk-hosts
+func (d *Devops) TopKHostsByCPU(qi query.Query, limit int) {
+	interval := d.Interval.MustRandWindow(devops.TopKHostsDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT
+			%[1]s AS host,
+			avg(usage_user) AS mean_usage_user
+		FROM cpu
+		WHERE ts >= %[2]d
+		  AND ts < %[3]d
+		GROUP BY %[1]s
+		ORDER BY mean_usage_user DESC
+		LIMIT %[4]d`,
+		hostnameField,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis(),
+		limit)
+
+	humanLabel := devops.GetTopKHostsLabel("CrateDB", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// HighCPUAndMem finds the 5 minute buckets in which a host has both a high
+// mean CPU usage and a high mean memory usage, joining the 'cpu' and 'mem'
+// tables
+//
+// Queries:
+// high-cpu-mem
+func (d *Devops) HighCPUAndMem(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUMemDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT c.bucket, c.host, c.mean_usage_user, m.mean_used_percent
+		FROM
+		  (
+			SELECT date_bin('%[1]d seconds'::INTERVAL, ts, 0) AS bucket, %[2]s AS host, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE ts >= %[3]d
+			  AND ts < %[4]d
+			GROUP BY bucket, host
+		  ) c
+		  JOIN
+		  (
+			SELECT date_bin('%[1]d seconds'::INTERVAL, ts, 0) AS bucket, %[2]s AS host, avg(used_percent) AS mean_used_percent
+			FROM mem
+			WHERE ts >= %[3]d
+			  AND ts < %[4]d
+			GROUP BY bucket, host
+		  ) m ON c.bucket = m.bucket AND c.host = m.host
+		WHERE c.mean_usage_user > %.1[5]f
+		  AND m.mean_used_percent > %.1[6]f
+		ORDER BY c.bucket, c.host`,
+		int(devops.JoinBucket.Seconds()),
+		hostnameField,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis(),
+		devops.HighCPUThreshold,
+		devops.HighMemThreshold)
+
+	humanLabel := devops.GetHighCPUMemLabel("CrateDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// CPUNginxCorrelation lines up the mean CPU usage of every host with the
+// number of requests its nginx served per 5 minutes, joining the 'cpu' and
+// 'nginx' tables
+//
+// Queries:
+// cpu-nginx
+func (d *Devops) CPUNginxCorrelation(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.CPUNginxDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT c.bucket, c.host, c.mean_usage_user, n.requests
+		FROM
+		  (
+			SELECT date_bin('%[1]d seconds'::INTERVAL, ts, 0) AS bucket, %[2]s AS host, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE ts >= %[3]d
+			  AND ts < %[4]d
+			GROUP BY bucket, host
+		  ) c
+		  JOIN
+		  (
+			SELECT date_bin('%[1]d seconds'::INTERVAL, ts, 0) AS bucket, %[2]s AS host, max(requests) - min(requests) AS requests
+			FROM nginx
+			WHERE ts >= %[3]d
+			  AND ts < %[4]d
+			GROUP BY bucket, host
+		  ) n ON c.bucket = n.bucket AND c.host = n.host
+		ORDER BY c.host, c.bucket`,
+		int(devops.JoinBucket.Seconds()),
+		hostnameField,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := devops.GetCPUNginxLabel("CrateDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// RawScan selects all the metrics under 'cpu' of N random hosts over a random
+// 12 hour window, without aggregating them
+//
+// Queries:
+// raw-scan-1
+// raw-scan-8
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	hosts, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+
+	sql := fmt.Sprintf(`
+		SELECT ts, %s, %s
+		FROM cpu
+		WHERE %s IN ('%s')
+		  AND ts >= %d
+		  AND ts < %d
+		ORDER BY ts`,
+		hostnameField,
+		strings.Join(devops.GetAllCPUMetrics(), ", "),
+		hostnameField,
+		strings.Join(hosts, "', '"),
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis())
+
+	humanLabel := devops.GetRawScanLabel("CrateDB", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all
+// hosts over the entire dataset
+//
+// Queries:
+// downsample
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectAggClauses("avg", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`
+		SELECT
+			date_trunc('day', ts) AS day,
+			%s
+		FROM cpu
+		WHERE ts >= %d
+		  AND ts < %d
+		GROUP BY day
+		ORDER BY day`,
+		strings.Join(selectClauses, ", "),
+		d.Interval.StartUnixMillis(),
+		d.Interval.EndUnixMillis())
+
+	humanLabel := devops.GetDownsampleLabel("CrateDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, "cpu", sql)
 }
@@ -259,3 +259,178 @@ func TestDevopsGroupByTimeQuery(t *testing.T) {
 			got.SqlQuery, want.SqlQuery)
 	}
 }
+
+func TestDevopsPercentileCPUQuery(t *testing.T) {
+	rand.Seed(100)
+
+	start := time.Date(2006, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2006, 1, 10, 20, 0, 0, 0, time.UTC)
+	d := assertNewDevops(t, start, end)
+
+	want := &query.CrateDB{
+		Table: []byte("cpu"),
+		SqlQuery: []byte(`
+		SELECT
+			date_trunc('hour', ts) AS hour,
+			tags['hostname'] AS host,
+			percentile(usage_user, 0.5) AS p50_usage_user, percentile(usage_user, 0.95) AS p95_usage_user, percentile(usage_user, 0.99) AS p99_usage_user
+		FROM cpu
+		WHERE ts >= 1136357713823
+		  AND ts < 1136400913823
+		GROUP BY hour, tags['hostname']
+		ORDER BY hour, host`),
+	}
+
+	got := &query.CrateDB{}
+	d.PercentileCPU(got)
+
+	if !reflect.DeepEqual(want.SqlQuery, got.SqlQuery) {
+		t.Errorf("incorrect sql query:\ngot: %s\n want:\n %s",
+			got.SqlQuery, want.SqlQuery)
+	}
+	if !reflect.DeepEqual(want.Table, got.Table) {
+		t.Errorf("incorrect table:\ngot: %s\n want:\n %s",
+			got.Table, want.Table)
+	}
+}
+
+func TestDevopsTopKHostsByCPUQuery(t *testing.T) {
+	rand.Seed(100)
+
+	start := time.Date(2006, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2006, 1, 10, 20, 0, 0, 0, time.UTC)
+	d := assertNewDevops(t, start, end)
+
+	want := &query.CrateDB{
+		Table: []byte("cpu"),
+		SqlQuery: []byte(`
+		SELECT
+			tags['hostname'] AS host,
+			avg(usage_user) AS mean_usage_user
+		FROM cpu
+		WHERE ts >= 1136447713823
+		  AND ts < 1136451313823
+		GROUP BY tags['hostname']
+		ORDER BY mean_usage_user DESC
+		LIMIT 10`),
+	}
+
+	got := &query.CrateDB{}
+	d.TopKHostsByCPU(got, 10)
+
+	if !reflect.DeepEqual(want.SqlQuery, got.SqlQuery) {
+		t.Errorf("incorrect sql query:\ngot: %s\n want:\n %s",
+			got.SqlQuery, want.SqlQuery)
+	}
+	if !reflect.DeepEqual(want.Table, got.Table) {
+		t.Errorf("incorrect table:\ngot: %s\n want:\n %s",
+			got.Table, want.Table)
+	}
+}
+
+func TestDevopsHighCPUAndMemQuery(t *testing.T) {
+	rand.Seed(100)
+
+	start := time.Date(2006, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2006, 1, 10, 20, 0, 0, 0, time.UTC)
+	d := assertNewDevops(t, start, end)
+
+	want := &query.CrateDB{
+		Table: []byte("cpu"),
+		SqlQuery: []byte(`
+		SELECT c.bucket, c.host, c.mean_usage_user, m.mean_used_percent
+		FROM
+		  (
+			SELECT date_bin('300 seconds'::INTERVAL, ts, 0) AS bucket, tags['hostname'] AS host, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE ts >= 1136357713823
+			  AND ts < 1136400913823
+			GROUP BY bucket, host
+		  ) c
+		  JOIN
+		  (
+			SELECT date_bin('300 seconds'::INTERVAL, ts, 0) AS bucket, tags['hostname'] AS host, avg(used_percent) AS mean_used_percent
+			FROM mem
+			WHERE ts >= 1136357713823
+			  AND ts < 1136400913823
+			GROUP BY bucket, host
+		  ) m ON c.bucket = m.bucket AND c.host = m.host
+		WHERE c.mean_usage_user > 90.0
+		  AND m.mean_used_percent > 80.0
+		ORDER BY c.bucket, c.host`),
+	}
+
+	got := &query.CrateDB{}
+	d.HighCPUAndMem(got)
+
+	if !reflect.DeepEqual(want.SqlQuery, got.SqlQuery) {
+		t.Errorf("incorrect sql query:\ngot: %s\n want:\n %s",
+			got.SqlQuery, want.SqlQuery)
+	}
+	if !reflect.DeepEqual(want.Table, got.Table) {
+		t.Errorf("incorrect table:\ngot: %s\n want:\n %s",
+			got.Table, want.Table)
+	}
+}
+
+func TestDevopsRawScanQuery(t *testing.T) {
+	rand.Seed(100)
+
+	start := time.Date(2006, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2006, 1, 10, 20, 0, 0, 0, time.UTC)
+	d := assertNewDevops(t, start, end)
+
+	want := &query.CrateDB{
+		Table: []byte("cpu"),
+		SqlQuery: []byte(`
+		SELECT ts, tags['hostname'], usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, usage_steal, usage_guest, usage_guest_nice
+		FROM cpu
+		WHERE tags['hostname'] IN ('host_8', 'host_0')
+		  AND ts >= 1136357713823
+		  AND ts < 1136400913823
+		ORDER BY ts`),
+	}
+
+	got := &query.CrateDB{}
+	d.RawScan(got, 2)
+
+	if !reflect.DeepEqual(want.SqlQuery, got.SqlQuery) {
+		t.Errorf("incorrect sql query:\ngot: %s\n want:\n %s",
+			got.SqlQuery, want.SqlQuery)
+	}
+	if !reflect.DeepEqual(want.Table, got.Table) {
+		t.Errorf("incorrect table:\ngot: %s\n want:\n %s",
+			got.Table, want.Table)
+	}
+}
+
+func TestDevopsDownsampleCPUQuery(t *testing.T) {
+	start := time.Date(2006, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2006, 1, 10, 20, 0, 0, 0, time.UTC)
+	d := assertNewDevops(t, start, end)
+
+	want := &query.CrateDB{
+		Table: []byte("cpu"),
+		SqlQuery: []byte(`
+		SELECT
+			date_trunc('day', ts) AS day,
+			avg(usage_user) AS avg_usage_user, avg(usage_system) AS avg_usage_system, avg(usage_idle) AS avg_usage_idle, avg(usage_nice) AS avg_usage_nice, avg(usage_iowait) AS avg_usage_iowait, avg(usage_irq) AS avg_usage_irq, avg(usage_softirq) AS avg_usage_softirq, avg(usage_steal) AS avg_usage_steal, avg(usage_guest) AS avg_usage_guest, avg(usage_guest_nice) AS avg_usage_guest_nice
+		FROM cpu
+		WHERE ts >= 1136109600000
+		  AND ts < 1136923200000
+		GROUP BY day
+		ORDER BY day`),
+	}
+
+	got := &query.CrateDB{}
+	d.DownsampleCPU(got)
+
+	if !reflect.DeepEqual(want.SqlQuery, got.SqlQuery) {
+		t.Errorf("incorrect sql query:\ngot: %s\n want:\n %s",
+			got.SqlQuery, want.SqlQuery)
+	}
+	if !reflect.DeepEqual(want.Table, got.Table) {
+		t.Errorf("incorrect table:\ngot: %s\n want:\n %s",
+			got.Table, want.Table)
+	}
+}
@@ -0,0 +1,324 @@
+package cratedb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// IoT produces CrateDB-specific queries for all the iot query types. The
+// truck tags are children of the tags object column of the readings and
+// diagnostics tables. Ten minute buckets use date_bin, so CrateDB 4.7 or
+// newer is required.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+const (
+	nameField       = "tags['name']"
+	tenMinuteBucket = "date_bin('10 minutes'::INTERVAL, ts, 0)"
+)
+
+// getTrucksWhereString gets multiple random trucks and creates a WHERE SQL
+// statement for their names.
+func (i *IoT) getTrucksWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	panicIfErr(err)
+	return fmt.Sprintf("%s IN ('%s')", nameField, strings.Join(names, "', '"))
+}
+
+// getFleetWhereString creates a WHERE SQL statement for the named trucks of
+// a random fleet.
+func (i *IoT) getFleetWhereString() string {
+	return fmt.Sprintf("tags['fleet'] = '%s' AND %s IS NOT NULL", i.GetRandomFleet(), nameField)
+}
+
+// lastRowSQL selects the columns of the last row of every truck in the table
+// matching the filter, keeping only the rows matching the condition if any.
+func lastRowSQL(table, columns, filter, condition string) string {
+	if condition != "" {
+		condition = "\n\t\t  AND " + condition
+	}
+	return fmt.Sprintf(`
+		SELECT t.name, t.driver, %[2]s
+		FROM
+		  (
+			SELECT %[3]s AS name, tags['driver'] AS driver, max(ts) AS max_ts
+			FROM %[1]s
+			WHERE %[4]s
+			GROUP BY name, driver
+		  ) t, %[1]s r
+		WHERE r.ts = t.max_ts
+		  AND r.%[3]s = t.name%[5]s`,
+		table, columns, nameField, filter, condition)
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := lastRowSQL(iot.ReadingsTableName, "r.longitude, r.latitude", i.getTrucksWhereString(nTrucks), "")
+
+	humanLabel := "CrateDB last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	sql := lastRowSQL(iot.ReadingsTableName, "r.longitude, r.latitude", i.getFleetWhereString(), "")
+
+	humanLabel := "CrateDB last location per truck"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	sql := lastRowSQL(iot.DiagnosticsTableName, "r.fuel_state", i.getFleetWhereString(), "r.fuel_state < 0.1")
+
+	humanLabel := "CrateDB trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	sql := lastRowSQL(iot.DiagnosticsTableName,
+		"r.current_load, r.tags['load_capacity'] AS load_capacity",
+		i.getFleetWhereString(),
+		"r.current_load / r.tags['load_capacity'] > 0.9")
+
+	humanLabel := "CrateDB trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`
+		SELECT %s AS name, tags['driver'] AS driver
+		FROM readings
+		WHERE ts >= %d
+		  AND ts < %d
+		  AND %s
+		GROUP BY name, driver
+		HAVING avg(velocity) < 1`,
+		nameField,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis(),
+		i.getFleetWhereString())
+
+	humanLabel := "CrateDB stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := i.drivingSessionsSQL(interval.StartUnixMillis(), interval.EndUnixMillis(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "CrateDB trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := i.drivingSessionsSQL(interval.StartUnixMillis(), interval.EndUnixMillis(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "CrateDB trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// drivingSessionsSQL selects the trucks of a random fleet that were driving
+// for more than the given number of ten minute periods between start and end,
+// given in unix milliseconds.
+func (i *IoT) drivingSessionsSQL(start, end int64, periods int) string {
+	return fmt.Sprintf(`
+		SELECT name, driver
+		FROM
+		  (
+			SELECT %s AS ten_minutes, %s AS name, tags['driver'] AS driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE ts >= %d
+			  AND ts < %d
+			  AND %s
+			GROUP BY ten_minutes, name, driver
+		  ) t
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count(*) > %d`,
+		tenMinuteBucket,
+		nameField,
+		start,
+		end,
+		i.getFleetWhereString(),
+		periods)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *IoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT
+			tags['fleet'] AS fleet,
+			avg(fuel_consumption) AS avg_fuel_consumption,
+			avg(tags['nominal_fuel_consumption']) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1
+		  AND tags['fleet'] IS NOT NULL
+		  AND tags['nominal_fuel_consumption'] IS NOT NULL
+		  AND %s IS NOT NULL
+		GROUP BY fleet`,
+		nameField)
+
+	humanLabel := "CrateDB average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT fleet, name, driver, avg(hours) AS avg_daily_hours
+		FROM
+		  (
+			SELECT date_trunc('day', ten_minutes) AS day, fleet, name, driver, count(*) / 6.0 AS hours
+			FROM
+			  (
+				SELECT %s AS ten_minutes, tags['fleet'] AS fleet, %s AS name, tags['driver'] AS driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE %[2]s IS NOT NULL
+				GROUP BY ten_minutes, fleet, name, driver
+			  ) t
+			WHERE mean_velocity > 1
+			GROUP BY day, fleet, name, driver
+		  ) d
+		GROUP BY fleet, name, driver`,
+		tenMinuteBucket,
+		nameField)
+
+	humanLabel := "CrateDB average driver driving duration per day"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+// Only the ten minute buckets changing from stopped to driving or back are
+// kept, and every bucket starting to drive lasts until the next change.
+func (i *IoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT name, date_trunc('day', start) AS day, avg(stop::bigint - start::bigint) / 60000.0 AS duration_minutes
+		FROM
+		  (
+			SELECT name, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY name ORDER BY ten_minutes) AS stop, driving
+			FROM
+			  (
+				SELECT name, ten_minutes, driving, lag(driving) OVER (PARTITION BY name ORDER BY ten_minutes) AS prev_driving
+				FROM
+				  (
+					SELECT %s AS ten_minutes, %s AS name, avg(velocity) > 5 AS driving
+					FROM readings
+					WHERE %[2]s IS NOT NULL
+					GROUP BY ten_minutes, name
+				  ) t
+			  ) p
+			WHERE prev_driving IS NULL
+			   OR driving <> prev_driving
+		  ) s
+		WHERE driving
+		GROUP BY name, day
+		ORDER BY name, day`,
+		tenMinuteBucket,
+		nameField)
+
+	humanLabel := "CrateDB average driver driving session without stopping per day"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT fleet, model, load_capacity, avg(avg_load / load_capacity) AS avg_load_percentage
+		FROM
+		  (
+			SELECT %s AS name, tags['fleet'] AS fleet, tags['model'] AS model, tags['load_capacity'] AS load_capacity, avg(current_load) AS avg_load
+			FROM diagnostics
+			WHERE %[1]s IS NOT NULL
+			GROUP BY name, fleet, model, load_capacity
+		  ) t
+		GROUP BY fleet, model, load_capacity`,
+		nameField)
+
+	humanLabel := "CrateDB average load per truck model per fleet"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT fleet, model, day, count(*) / 144.0 AS daily_activity
+		FROM
+		  (
+			SELECT date_trunc('day', ts) AS day, %s AS ten_minutes, %s AS name, tags['fleet'] AS fleet, tags['model'] AS model, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE %[2]s IS NOT NULL
+			GROUP BY day, ten_minutes, name, fleet, model
+		  ) t
+		WHERE mean_status < 1
+		GROUP BY fleet, model, day
+		ORDER BY day`,
+		tenMinuteBucket,
+		nameField)
+
+	humanLabel := "CrateDB daily truck activity per fleet per model"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := fmt.Sprintf(`
+		SELECT model, count(*) AS breakdowns
+		FROM
+		  (
+			SELECT model, breakdown_ratio, lead(breakdown_ratio) OVER (PARTITION BY name ORDER BY ten_minutes) AS next_breakdown_ratio
+			FROM
+			  (
+				SELECT %s AS ten_minutes, %s AS name, tags['model'] AS model, avg(CASE WHEN status = 0 THEN 1.0 ELSE 0.0 END) AS breakdown_ratio
+				FROM diagnostics
+				WHERE %[2]s IS NOT NULL
+				GROUP BY ten_minutes, name, model
+			  ) t
+		  ) b
+		WHERE breakdown_ratio < 0.5
+		  AND next_breakdown_ratio >= 0.5
+		GROUP BY model`,
+		tenMinuteBucket,
+		nameField)
+
+	humanLabel := "CrateDB truck breakdown frequency per model"
+	humanDesc := humanLabel
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
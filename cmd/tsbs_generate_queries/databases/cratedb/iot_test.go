@@ -0,0 +1,354 @@
+package cratedb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIoTQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(*IoT, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedTable      string
+		expectedQuery      string
+	}{
+		{
+			desc: "last loc by truck",
+			fn: func(i *IoT, q query.Query) {
+				i.LastLocByTruck(q, 2)
+			},
+			expectedHumanLabel: "CrateDB last location by specific truck",
+			expectedHumanDesc:  "CrateDB last location by specific truck: random    2 trucks",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT t.name, t.driver, r.longitude, r.latitude
+		FROM
+		  (
+			SELECT tags['name'] AS name, tags['driver'] AS driver, max(ts) AS max_ts
+			FROM readings
+			WHERE tags['name'] IN ('truck_5', 'truck_9')
+			GROUP BY name, driver
+		  ) t, readings r
+		WHERE r.ts = t.max_ts
+		  AND r.tags['name'] = t.name`,
+		},
+		{
+			desc: "last loc per truck",
+			fn: func(i *IoT, q query.Query) {
+				i.LastLocPerTruck(q)
+			},
+			expectedHumanLabel: "CrateDB last location per truck",
+			expectedHumanDesc:  "CrateDB last location per truck",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT t.name, t.driver, r.longitude, r.latitude
+		FROM
+		  (
+			SELECT tags['name'] AS name, tags['driver'] AS driver, max(ts) AS max_ts
+			FROM readings
+			WHERE tags['fleet'] = 'South' AND tags['name'] IS NOT NULL
+			GROUP BY name, driver
+		  ) t, readings r
+		WHERE r.ts = t.max_ts
+		  AND r.tags['name'] = t.name`,
+		},
+		{
+			desc: "trucks with low fuel",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLowFuel(q)
+			},
+			expectedHumanLabel: "CrateDB trucks with low fuel",
+			expectedHumanDesc:  "CrateDB trucks with low fuel: under 10 percent",
+			expectedTable:      "diagnostics",
+			expectedQuery: `
+		SELECT t.name, t.driver, r.fuel_state
+		FROM
+		  (
+			SELECT tags['name'] AS name, tags['driver'] AS driver, max(ts) AS max_ts
+			FROM diagnostics
+			WHERE tags['fleet'] = 'South' AND tags['name'] IS NOT NULL
+			GROUP BY name, driver
+		  ) t, diagnostics r
+		WHERE r.ts = t.max_ts
+		  AND r.tags['name'] = t.name
+		  AND r.fuel_state < 0.1`,
+		},
+		{
+			desc: "trucks with high load",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithHighLoad(q)
+			},
+			expectedHumanLabel: "CrateDB trucks with high load",
+			expectedHumanDesc:  "CrateDB trucks with high load: over 90 percent",
+			expectedTable:      "diagnostics",
+			expectedQuery: `
+		SELECT t.name, t.driver, r.current_load, r.tags['load_capacity'] AS load_capacity
+		FROM
+		  (
+			SELECT tags['name'] AS name, tags['driver'] AS driver, max(ts) AS max_ts
+			FROM diagnostics
+			WHERE tags['fleet'] = 'South' AND tags['name'] IS NOT NULL
+			GROUP BY name, driver
+		  ) t, diagnostics r
+		WHERE r.ts = t.max_ts
+		  AND r.tags['name'] = t.name
+		  AND r.current_load / r.tags['load_capacity'] > 0.9`,
+		},
+		{
+			desc: "stationary trucks",
+			fn: func(i *IoT, q query.Query) {
+				i.StationaryTrucks(q)
+			},
+			expectedHumanLabel: "CrateDB stationary trucks",
+			expectedHumanDesc:  "CrateDB stationary trucks: with low avg velocity in last 10 minutes",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT tags['name'] AS name, tags['driver'] AS driver
+		FROM readings
+		WHERE ts >= 84982646
+		  AND ts < 85582646
+		  AND tags['fleet'] = 'West' AND tags['name'] IS NOT NULL
+		GROUP BY name, driver
+		HAVING avg(velocity) < 1`,
+		},
+		{
+			desc: "trucks with long driving sessions",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLongDrivingSessions(q)
+			},
+			expectedHumanLabel: "CrateDB trucks with longer driving sessions",
+			expectedHumanDesc:  "CrateDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT name, driver
+		FROM
+		  (
+			SELECT date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['name'] AS name, tags['driver'] AS driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE ts >= 22582646
+			  AND ts < 36982646
+			  AND tags['fleet'] = 'West' AND tags['name'] IS NOT NULL
+			GROUP BY ten_minutes, name, driver
+		  ) t
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count(*) > 22`,
+		},
+		{
+			desc: "trucks with long daily sessions",
+			fn: func(i *IoT, q query.Query) {
+				i.TrucksWithLongDailySessions(q)
+			},
+			expectedHumanLabel: "CrateDB trucks with longer daily sessions",
+			expectedHumanDesc:  "CrateDB trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT name, driver
+		FROM
+		  (
+			SELECT date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['name'] AS name, tags['driver'] AS driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE ts >= 65782646
+			  AND ts < 152182646
+			  AND tags['fleet'] = 'West' AND tags['name'] IS NOT NULL
+			GROUP BY ten_minutes, name, driver
+		  ) t
+		WHERE mean_velocity > 1
+		GROUP BY name, driver
+		HAVING count(*) > 60`,
+		},
+		{
+			desc: "avg vs projected fuel consumption",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgVsProjectedFuelConsumption(q)
+			},
+			expectedHumanLabel: "CrateDB average vs projected fuel consumption per fleet",
+			expectedHumanDesc:  "CrateDB average vs projected fuel consumption per fleet",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT
+			tags['fleet'] AS fleet,
+			avg(fuel_consumption) AS avg_fuel_consumption,
+			avg(tags['nominal_fuel_consumption']) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1
+		  AND tags['fleet'] IS NOT NULL
+		  AND tags['nominal_fuel_consumption'] IS NOT NULL
+		  AND tags['name'] IS NOT NULL
+		GROUP BY fleet`,
+		},
+		{
+			desc: "avg daily driving duration",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgDailyDrivingDuration(q)
+			},
+			expectedHumanLabel: "CrateDB average driver driving duration per day",
+			expectedHumanDesc:  "CrateDB average driver driving duration per day",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT fleet, name, driver, avg(hours) AS avg_daily_hours
+		FROM
+		  (
+			SELECT date_trunc('day', ten_minutes) AS day, fleet, name, driver, count(*) / 6.0 AS hours
+			FROM
+			  (
+				SELECT date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['fleet'] AS fleet, tags['name'] AS name, tags['driver'] AS driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE tags['name'] IS NOT NULL
+				GROUP BY ten_minutes, fleet, name, driver
+			  ) t
+			WHERE mean_velocity > 1
+			GROUP BY day, fleet, name, driver
+		  ) d
+		GROUP BY fleet, name, driver`,
+		},
+		{
+			desc: "avg daily driving session",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgDailyDrivingSession(q)
+			},
+			expectedHumanLabel: "CrateDB average driver driving session without stopping per day",
+			expectedHumanDesc:  "CrateDB average driver driving session without stopping per day",
+			expectedTable:      "readings",
+			expectedQuery: `
+		SELECT name, date_trunc('day', start) AS day, avg(stop::bigint - start::bigint) / 60000.0 AS duration_minutes
+		FROM
+		  (
+			SELECT name, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY name ORDER BY ten_minutes) AS stop, driving
+			FROM
+			  (
+				SELECT name, ten_minutes, driving, lag(driving) OVER (PARTITION BY name ORDER BY ten_minutes) AS prev_driving
+				FROM
+				  (
+					SELECT date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['name'] AS name, avg(velocity) > 5 AS driving
+					FROM readings
+					WHERE tags['name'] IS NOT NULL
+					GROUP BY ten_minutes, name
+				  ) t
+			  ) p
+			WHERE prev_driving IS NULL
+			   OR driving <> prev_driving
+		  ) s
+		WHERE driving
+		GROUP BY name, day
+		ORDER BY name, day`,
+		},
+		{
+			desc: "avg load",
+			fn: func(i *IoT, q query.Query) {
+				i.AvgLoad(q)
+			},
+			expectedHumanLabel: "CrateDB average load per truck model per fleet",
+			expectedHumanDesc:  "CrateDB average load per truck model per fleet",
+			expectedTable:      "diagnostics",
+			expectedQuery: `
+		SELECT fleet, model, load_capacity, avg(avg_load / load_capacity) AS avg_load_percentage
+		FROM
+		  (
+			SELECT tags['name'] AS name, tags['fleet'] AS fleet, tags['model'] AS model, tags['load_capacity'] AS load_capacity, avg(current_load) AS avg_load
+			FROM diagnostics
+			WHERE tags['name'] IS NOT NULL
+			GROUP BY name, fleet, model, load_capacity
+		  ) t
+		GROUP BY fleet, model, load_capacity`,
+		},
+		{
+			desc: "daily truck activity",
+			fn: func(i *IoT, q query.Query) {
+				i.DailyTruckActivity(q)
+			},
+			expectedHumanLabel: "CrateDB daily truck activity per fleet per model",
+			expectedHumanDesc:  "CrateDB daily truck activity per fleet per model",
+			expectedTable:      "diagnostics",
+			expectedQuery: `
+		SELECT fleet, model, day, count(*) / 144.0 AS daily_activity
+		FROM
+		  (
+			SELECT date_trunc('day', ts) AS day, date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['name'] AS name, tags['fleet'] AS fleet, tags['model'] AS model, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE tags['name'] IS NOT NULL
+			GROUP BY day, ten_minutes, name, fleet, model
+		  ) t
+		WHERE mean_status < 1
+		GROUP BY fleet, model, day
+		ORDER BY day`,
+		},
+		{
+			desc: "truck breakdown frequency",
+			fn: func(i *IoT, q query.Query) {
+				i.TruckBreakdownFrequency(q)
+			},
+			expectedHumanLabel: "CrateDB truck breakdown frequency per model",
+			expectedHumanDesc:  "CrateDB truck breakdown frequency per model",
+			expectedTable:      "diagnostics",
+			expectedQuery: `
+		SELECT model, count(*) AS breakdowns
+		FROM
+		  (
+			SELECT model, breakdown_ratio, lead(breakdown_ratio) OVER (PARTITION BY name ORDER BY ten_minutes) AS next_breakdown_ratio
+			FROM
+			  (
+				SELECT date_bin('10 minutes'::INTERVAL, ts, 0) AS ten_minutes, tags['name'] AS name, tags['model'] AS model, avg(CASE WHEN status = 0 THEN 1.0 ELSE 0.0 END) AS breakdown_ratio
+				FROM diagnostics
+				WHERE tags['name'] IS NOT NULL
+				GROUP BY ten_minutes, name, model
+			  ) t
+		  ) b
+		WHERE breakdown_ratio < 0.5
+		  AND next_breakdown_ratio >= 0.5
+		GROUP BY model`,
+		},
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			g, err := b.NewIoT(start, end, testScale)
+			if err != nil {
+				t.Fatalf("error while creating iot generator")
+			}
+			i := g.(*IoT)
+			q := i.GenerateEmptyQuery()
+			c.fn(i, q)
+
+			got := q.(*query.CrateDB)
+			if string(got.HumanLabel) != c.expectedHumanLabel {
+				t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got.HumanLabel, c.expectedHumanLabel)
+			}
+			if string(got.HumanDescription) != c.expectedHumanDesc {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got.HumanDescription, c.expectedHumanDesc)
+			}
+			if string(got.Table) != c.expectedTable {
+				t.Errorf("incorrect table:\ngot\n%s\nwant\n%s", got.Table, c.expectedTable)
+			}
+			if string(got.SqlQuery) != c.expectedQuery {
+				t.Errorf("incorrect sql query:\ngot\n%s\nwant\n%s", got.SqlQuery, c.expectedQuery)
+			}
+		})
+	}
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	cases := []struct {
+		minutesPerHour float64
+		duration       time.Duration
+		result         int
+	}{
+		{minutesPerHour: 5, duration: 4 * time.Hour, result: 22},
+		{minutesPerHour: 35, duration: 24 * time.Hour, result: 60},
+	}
+
+	for _, c := range cases {
+		if got := tenMinutePeriods(c.minutesPerHour, c.duration); got != c.result {
+			t.Errorf("incorrect result for %.2f minutes per hour, duration %s: got %d want %d", c.minutesPerHour, c.duration.String(), got, c.result)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// BaseGenerator contains settings specific for the Elasticsearch database.
+type BaseGenerator struct {
+	// Index is the data stream the documents were loaded into.
+	Index string
+}
+
+// GenerateEmptyQuery returns an empty query.HTTP.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewHTTP()
+}
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
+// fillInQuery fills the query struct with a search request of the data
+// stream, the body being the query DSL encoded as JSON.
+func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc string, search map[string]interface{}) {
+	body, err := json.Marshal(search)
+	databases.PanicIfErr(err)
+
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("POST")
+	q.Path = []byte(fmt.Sprintf("/%s/_search", g.Index))
+	q.Body = body
+}
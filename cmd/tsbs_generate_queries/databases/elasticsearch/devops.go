@@ -0,0 +1,262 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	iutils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Devops produces Elasticsearch query DSL searches for all the devops query
+// types. The tags and fields of the documents are the tags.* and fields.*
+// properties written by the loader.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// obj is a JSON object of the query DSL.
+type obj = map[string]interface{}
+
+// getFilters returns a bool query matching all the given filters, which
+// are not scored.
+func getFilters(filters ...obj) obj {
+	return obj{"bool": obj{"filter": filters}}
+}
+
+func getMeasurementFilter() obj {
+	return obj{"term": obj{"measurement": "cpu"}}
+}
+
+func getTimeFilter(interval *iutils.TimeInterval) obj {
+	return obj{"range": obj{"@timestamp": obj{"gte": interval.StartString(), "lt": interval.EndString()}}}
+}
+
+func (d *Devops) getHostFilter(nHosts int) obj {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return obj{"terms": obj{"tags.hostname": hostnames}}
+}
+
+// getDateHistogram returns a date_histogram aggregation with buckets of the
+// given interval, e.g. 1m.
+func getDateHistogram(interval string, aggs obj) obj {
+	return obj{
+		"date_histogram": obj{"field": "@timestamp", "fixed_interval": interval},
+		"aggs":           aggs,
+	}
+}
+
+// getMetricAggs returns an aggregation named <fn>_<metric> of every metric,
+// e.g. max_usage_user.
+func getMetricAggs(fn string, metrics []string) obj {
+	aggs := obj{}
+	for _, m := range metrics {
+		aggs[fn+"_"+m] = obj{fn: obj{"field": "fields." + m}}
+	}
+	return aggs
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+
+	humanLabel := fmt.Sprintf("Elasticsearch %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size":  0,
+		"query": getFilters(getMeasurementFilter(), getTimeFilter(interval), d.getHostFilter(nHosts)),
+		"aggs": obj{
+			"minute": getDateHistogram("1m", getMetricAggs("max", metrics)),
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	aggs := getMetricAggs("max", []string{"usage_user"})
+	aggs["last_5"] = obj{"bucket_sort": obj{
+		"sort": []obj{{"_key": obj{"order": "desc"}}},
+		"size": 5,
+	}}
+	humanLabel := "Elasticsearch max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size": 0,
+		"query": getFilters(
+			getMeasurementFilter(),
+			obj{"range": obj{"@timestamp": obj{"lt": interval.EndString()}}},
+		),
+		"aggs": obj{
+			"minute": getDateHistogram("1m", aggs),
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+
+	humanLabel := devops.GetDoubleGroupByLabel("Elasticsearch", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size":  0,
+		"query": getFilters(getMeasurementFilter(), getTimeFilter(interval)),
+		"aggs": obj{
+			"hour": getDateHistogram("1h", obj{
+				"hostname": obj{
+					"terms": obj{"field": "tags.hostname", "size": d.Scale, "order": obj{"_key": "asc"}},
+					"aggs":  getMetricAggs("avg", metrics),
+				},
+			}),
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+
+	humanLabel := devops.GetMaxAllLabel("Elasticsearch", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size":  0,
+		"query": getFilters(getMeasurementFilter(), getTimeFilter(interval), d.getHostFilter(nHosts)),
+		"aggs": obj{
+			"hour": getDateHistogram("1h", getMetricAggs("max", devops.GetAllCPUMetrics())),
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	humanLabel := "Elasticsearch last row per host"
+	humanDesc := humanLabel + ": cpu"
+	search := obj{
+		"size":  0,
+		"query": getFilters(getMeasurementFilter()),
+		"aggs": obj{
+			"hostname": obj{
+				"terms": obj{"field": "tags.hostname", "size": d.Scale},
+				"aggs": obj{
+					"last": obj{"top_hits": obj{
+						"size": 1,
+						"sort": []obj{{"@timestamp": obj{"order": "desc"}}},
+					}},
+				},
+			},
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+//
+// Elasticsearch returns at most 10000 hits from a search.
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	filters := []obj{
+		getMeasurementFilter(),
+		getTimeFilter(interval),
+		{"range": obj{"fields.usage_user": obj{"gt": 90.0}}},
+	}
+	if nHosts != 0 {
+		filters = append(filters, d.getHostFilter(nHosts))
+	}
+
+	humanLabel, err := devops.GetHighCPULabel("Elasticsearch", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size":  10000,
+		"query": getFilters(filters...),
+		"sort":  []obj{{"@timestamp": obj{"order": "asc"}}},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+//
+// Elasticsearch returns at most 10000 hits from a search.
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	humanLabel := devops.GetRawScanLabel("Elasticsearch", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	search := obj{
+		"size":  10000,
+		"query": getFilters(getMeasurementFilter(), getTimeFilter(interval), d.getHostFilter(nHosts)),
+		"sort":  []obj{{"@timestamp": obj{"order": "asc"}}},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	humanLabel := devops.GetDownsampleLabel("Elasticsearch")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	search := obj{
+		"size":  0,
+		"query": getFilters(getMeasurementFilter(), getTimeFilter(d.Interval.TimeInterval)),
+		"aggs": obj{
+			"day": getDateHistogram("1d", getMetricAggs("avg", devops.GetAllCPUMetrics())),
+		},
+	}
+	d.fillInQuery(qi, humanLabel, humanDesc, search)
+}
@@ -0,0 +1,139 @@
+package elasticsearch
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestDevopsQueries(t *testing.T) {
+	const cpuFilter = `{"term":{"measurement":"cpu"}}`
+	cases := []struct {
+		desc               string
+		fn                 func(d *Devops, q query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedBody       string
+	}{
+		{
+			desc:               "GroupByTime",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanLabel: "Elasticsearch 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Elasticsearch 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedBody: `{"aggs":{"minute":{"aggs":{"max_usage_system":{"max":{"field":"fields.usage_system"}},"max_usage_user":{"max":{"field":"fields.usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1m"}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-02T02:16:22Z","lt":"1970-01-02T03:16:22Z"}}},` +
+				`{"terms":{"tags.hostname":["host_9","host_3"]}}]}},"size":0}`,
+		},
+		{
+			desc:               "GroupByOrderByLimit",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanLabel: "Elasticsearch max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "Elasticsearch max cpu over last 5 min-intervals (random end): 1970-01-01T11:37:12Z",
+			expectedBody: `{"aggs":{"minute":{"aggs":{"last_5":{"bucket_sort":{"size":5,"sort":[{"_key":{"order":"desc"}}]}},"max_usage_user":{"max":{"field":"fields.usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1m"}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"lt":"1970-01-01T12:37:12Z"}}}]}},"size":0}`,
+		},
+		{
+			desc:               "GroupByTimeAndPrimaryTag",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanLabel: "Elasticsearch mean of 2 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "Elasticsearch mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-02T02:17:45Z",
+			expectedBody: `{"aggs":{"hour":{"aggs":{"hostname":{"aggs":{"avg_usage_system":{"avg":{"field":"fields.usage_system"}},"avg_usage_user":{"avg":{"field":"fields.usage_user"}}},` +
+				`"terms":{"field":"tags.hostname","order":{"_key":"asc"},"size":10}}},"date_histogram":{"field":"@timestamp","fixed_interval":"1h"}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-02T02:17:45Z","lt":"1970-01-02T14:17:45Z"}}}]}},"size":0}`,
+		},
+		{
+			desc:               "MaxAllCPU",
+			fn:                 func(d *Devops, q query.Query) { d.MaxAllCPU(q, 2) },
+			expectedHumanLabel: "Elasticsearch max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h",
+			expectedHumanDesc:  "Elasticsearch max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h: 1970-01-01T21:23:08Z",
+			expectedBody: `{"aggs":{"hour":{"aggs":{"max_usage_guest":{"max":{"field":"fields.usage_guest"}},"max_usage_guest_nice":{"max":{"field":"fields.usage_guest_nice"}},` +
+				`"max_usage_idle":{"max":{"field":"fields.usage_idle"}},"max_usage_iowait":{"max":{"field":"fields.usage_iowait"}},"max_usage_irq":{"max":{"field":"fields.usage_irq"}},` +
+				`"max_usage_nice":{"max":{"field":"fields.usage_nice"}},"max_usage_softirq":{"max":{"field":"fields.usage_softirq"}},"max_usage_steal":{"max":{"field":"fields.usage_steal"}},` +
+				`"max_usage_system":{"max":{"field":"fields.usage_system"}},"max_usage_user":{"max":{"field":"fields.usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1h"}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-01T21:23:08Z","lt":"1970-01-02T05:23:08Z"}}},` +
+				`{"terms":{"tags.hostname":["host_5","host_1"]}}]}},"size":0}`,
+		},
+		{
+			desc:               "LastPointPerHost",
+			fn:                 func(d *Devops, q query.Query) { d.LastPointPerHost(q) },
+			expectedHumanLabel: "Elasticsearch last row per host",
+			expectedHumanDesc:  "Elasticsearch last row per host: cpu",
+			expectedBody: `{"aggs":{"hostname":{"aggs":{"last":{"top_hits":{"size":1,"sort":[{"@timestamp":{"order":"desc"}}]}}},"terms":{"field":"tags.hostname","size":10}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `]}},"size":0}`,
+		},
+		{
+			desc:               "HighCPUForHosts all hosts",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanLabel: "Elasticsearch CPU over threshold, all hosts",
+			expectedHumanDesc:  "Elasticsearch CPU over threshold, all hosts: 1970-01-02T06:57:58Z",
+			expectedBody: `{"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-02T06:57:58Z","lt":"1970-01-02T18:57:58Z"}}},` +
+				`{"range":{"fields.usage_user":{"gt":90}}}]}},"size":10000,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			desc:               "HighCPUForHosts 1 host",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 1) },
+			expectedHumanLabel: "Elasticsearch CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "Elasticsearch CPU over threshold, 1 host(s): 1970-01-01T01:23:03Z",
+			expectedBody: `{"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-01T01:23:03Z","lt":"1970-01-01T13:23:03Z"}}},` +
+				`{"range":{"fields.usage_user":{"gt":90}}},{"terms":{"tags.hostname":["host_2"]}}]}},"size":10000,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			desc:               "RawScan",
+			fn:                 func(d *Devops, q query.Query) { d.RawScan(q, 2) },
+			expectedHumanLabel: "Elasticsearch all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "Elasticsearch all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T03:08:59Z",
+			expectedBody: `{"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-01T03:08:59Z","lt":"1970-01-01T15:08:59Z"}}},` +
+				`{"terms":{"tags.hostname":["host_5","host_8"]}}]}},"size":10000,"sort":[{"@timestamp":{"order":"asc"}}]}`,
+		},
+		{
+			desc:               "DownsampleCPU",
+			fn:                 func(d *Devops, q query.Query) { d.DownsampleCPU(q) },
+			expectedHumanLabel: "Elasticsearch mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "Elasticsearch mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedBody: `{"aggs":{"day":{"aggs":{"avg_usage_guest":{"avg":{"field":"fields.usage_guest"}},"avg_usage_guest_nice":{"avg":{"field":"fields.usage_guest_nice"}},"avg_usage_idle":{"avg":{"field":"fields.usage_idle"}},` +
+				`"avg_usage_iowait":{"avg":{"field":"fields.usage_iowait"}},"avg_usage_irq":{"avg":{"field":"fields.usage_irq"}},"avg_usage_nice":{"avg":{"field":"fields.usage_nice"}},"avg_usage_softirq":{"avg":{"field":"fields.usage_softirq"}},` +
+				`"avg_usage_steal":{"avg":{"field":"fields.usage_steal"}},"avg_usage_system":{"avg":{"field":"fields.usage_system"}},"avg_usage_user":{"avg":{"field":"fields.usage_user"}}},` +
+				`"date_histogram":{"field":"@timestamp","fixed_interval":"1d"}}},` +
+				`"query":{"bool":{"filter":[` + cpuFilter + `,{"range":{"@timestamp":{"gte":"1970-01-01T00:00:00Z","lt":"1970-01-03T00:00:00Z"}}}]}},"size":0}`,
+		},
+	}
+
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{Index: "benchmark"}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			qi := d.GenerateEmptyQuery()
+			c.fn(d, qi)
+			q := qi.(*query.HTTP)
+
+			if got := string(q.HumanLabel); got != c.expectedHumanLabel {
+				t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, c.expectedHumanLabel)
+			}
+			if got := string(q.HumanDescription); got != c.expectedHumanDesc {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, c.expectedHumanDesc)
+			}
+			if got := string(q.Method); got != "POST" {
+				t.Errorf("incorrect method:\ngot\n%s\nwant POST", got)
+			}
+			if got := string(q.Path); got != "/benchmark/_search" {
+				t.Errorf("incorrect path:\ngot\n%s\nwant /benchmark/_search", got)
+			}
+			if got := string(q.Body); got != c.expectedBody {
+				t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got, c.expectedBody)
+			}
+		})
+	}
+}
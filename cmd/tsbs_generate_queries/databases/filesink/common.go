@@ -0,0 +1,43 @@
+package filesink
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/filesink"
+)
+
+// BaseGenerator contains settings specific for the file sink.
+type BaseGenerator struct{}
+
+// GenerateEmptyQuery returns an empty query.FileSink.
+func (g *BaseGenerator) GenerateEmptyQuery() query.Query {
+	return query.NewFileSink()
+}
+
+// NewDevops creates a new devops use case query generator.
+func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := devops.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &Devops{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
+// fillInQuery fills the query struct with the JSON encoded plan.
+func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc string, plan *filesink.Plan) {
+	encoded, err := json.Marshal(plan)
+	databases.PanicIfErr(err)
+
+	q := qi.(*query.FileSink)
+	q.HumanLabel = []byte(humanLabel)
+	q.HumanDescription = []byte(humanDesc)
+	q.Plan = encoded
+}
@@ -0,0 +1,201 @@
+package filesink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/filesink"
+)
+
+// Devops produces file sink plans for all the devops query types.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+func (d *Devops) getHostFilter(nHosts int) *filesink.In {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return &filesink.In{Column: "hostname", Values: hostnames}
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+
+	humanLabel := fmt.Sprintf("FileSink %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:     "cpu",
+		Start:     interval.StartUnixNano(),
+		End:       interval.EndUnixNano(),
+		In:        d.getHostFilter(nHosts),
+		Columns:   metrics,
+		Aggregate: filesink.AggregateMax,
+		Interval:  int64(time.Minute),
+	})
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	humanLabel := "FileSink max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:      "cpu",
+		End:        interval.EndUnixNano(),
+		Columns:    []string{"usage_user"},
+		Aggregate:  filesink.AggregateMax,
+		Interval:   int64(time.Minute),
+		Descending: true,
+		Limit:      5,
+	})
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+
+	humanLabel := devops.GetDoubleGroupByLabel("FileSink", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:     "cpu",
+		Start:     interval.StartUnixNano(),
+		End:       interval.EndUnixNano(),
+		Columns:   metrics,
+		Aggregate: filesink.AggregateMean,
+		Interval:  int64(time.Hour),
+		GroupBy:   []string{"hostname"},
+	})
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+
+	humanLabel := devops.GetMaxAllLabel("FileSink", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:     "cpu",
+		Start:     interval.StartUnixNano(),
+		End:       interval.EndUnixNano(),
+		In:        d.getHostFilter(nHosts),
+		Columns:   devops.GetAllCPUMetrics(),
+		Aggregate: filesink.AggregateMax,
+		Interval:  int64(time.Hour),
+	})
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	humanLabel := "FileSink last row per host"
+	humanDesc := humanLabel + ": cpu"
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:     "cpu",
+		Columns:   devops.GetAllCPUMetrics(),
+		Aggregate: filesink.AggregateLast,
+		GroupBy:   []string{"hostname"},
+	})
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	plan := &filesink.Plan{
+		Table:   "cpu",
+		Start:   interval.StartUnixNano(),
+		End:     interval.EndUnixNano(),
+		Above:   &filesink.Above{Column: "usage_user", Value: 90.0},
+		Columns: devops.GetAllCPUMetrics(),
+		GroupBy: []string{"hostname"},
+	}
+	if nHosts != 0 {
+		plan.In = d.getHostFilter(nHosts)
+	}
+
+	humanLabel, err := devops.GetHighCPULabel("FileSink", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, plan)
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	humanLabel := devops.GetRawScanLabel("FileSink", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:   "cpu",
+		Start:   interval.StartUnixNano(),
+		End:     interval.EndUnixNano(),
+		In:      d.getHostFilter(nHosts),
+		Columns: devops.GetAllCPUMetrics(),
+		GroupBy: []string{"hostname"},
+	})
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	humanLabel := devops.GetDownsampleLabel("FileSink")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, &filesink.Plan{
+		Table:     "cpu",
+		Start:     d.Interval.StartUnixNano(),
+		End:       d.Interval.EndUnixNano(),
+		Columns:   devops.GetAllCPUMetrics(),
+		Aggregate: filesink.AggregateMean,
+		Interval:  int64(devops.DownsampleBucket),
+	})
+}
@@ -0,0 +1,118 @@
+package filesink
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestDevopsQueries(t *testing.T) {
+	const allCPU = `"columns":["usage_user","usage_system","usage_idle","usage_nice","usage_iowait","usage_irq","usage_softirq","usage_steal","usage_guest","usage_guest_nice"]`
+	cases := []struct {
+		desc               string
+		fn                 func(d *Devops, q query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedPlan       string
+	}{
+		{
+			desc:               "GroupByTime",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanLabel: "FileSink 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "FileSink 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedPlan: `{"table":"cpu","start":94582646325489,"end":98182646325489,"in":{"column":"hostname","values":["host_9","host_3"]},` +
+				`"columns":["usage_user","usage_system"],"aggregate":"max","interval":60000000000}`,
+		},
+		{
+			desc:               "GroupByOrderByLimit",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanLabel: "FileSink max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "FileSink max cpu over last 5 min-intervals (random end): 1970-01-01T11:37:12Z",
+			expectedPlan:       `{"table":"cpu","end":45432342805883,"columns":["usage_user"],"aggregate":"max","interval":60000000000,"descending":true,"limit":5}`,
+		},
+		{
+			desc:               "GroupByTimeAndPrimaryTag",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanLabel: "FileSink mean of 2 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "FileSink mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-02T02:17:45Z",
+			expectedPlan: `{"table":"cpu","start":94665311177487,"end":137865311177487,"columns":["usage_user","usage_system"],` +
+				`"aggregate":"mean","interval":3600000000000,"group_by":["hostname"]}`,
+		},
+		{
+			desc:               "MaxAllCPU",
+			fn:                 func(d *Devops, q query.Query) { d.MaxAllCPU(q, 2) },
+			expectedHumanLabel: "FileSink max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h",
+			expectedHumanDesc:  "FileSink max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h: 1970-01-01T21:23:08Z",
+			expectedPlan: `{"table":"cpu","start":76988303546563,"end":105788303546563,"in":{"column":"hostname","values":["host_5","host_1"]},` +
+				allCPU + `,"aggregate":"max","interval":3600000000000}`,
+		},
+		{
+			desc:               "LastPointPerHost",
+			fn:                 func(d *Devops, q query.Query) { d.LastPointPerHost(q) },
+			expectedHumanLabel: "FileSink last row per host",
+			expectedHumanDesc:  "FileSink last row per host: cpu",
+			expectedPlan:       `{"table":"cpu",` + allCPU + `,"aggregate":"last","group_by":["hostname"]}`,
+		},
+		{
+			desc:               "HighCPUForHosts all hosts",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanLabel: "FileSink CPU over threshold, all hosts",
+			expectedHumanDesc:  "FileSink CPU over threshold, all hosts: 1970-01-02T06:57:58Z",
+			expectedPlan: `{"table":"cpu","start":111478487617828,"end":154678487617828,"above":{"column":"usage_user","value":90},` +
+				allCPU + `,"group_by":["hostname"]}`,
+		},
+		{
+			desc:               "HighCPUForHosts 1 host",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 1) },
+			expectedHumanLabel: "FileSink CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "FileSink CPU over threshold, 1 host(s): 1970-01-01T01:23:03Z",
+			expectedPlan: `{"table":"cpu","start":4983975214056,"end":48183975214056,"in":{"column":"hostname","values":["host_2"]},` +
+				`"above":{"column":"usage_user","value":90},` + allCPU + `,"group_by":["hostname"]}`,
+		},
+		{
+			desc:               "RawScan",
+			fn:                 func(d *Devops, q query.Query) { d.RawScan(q, 2) },
+			expectedHumanLabel: "FileSink all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "FileSink all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T03:08:59Z",
+			expectedPlan: `{"table":"cpu","start":11339080812606,"end":54539080812606,"in":{"column":"hostname","values":["host_5","host_8"]},` +
+				allCPU + `,"group_by":["hostname"]}`,
+		},
+		{
+			desc:               "DownsampleCPU",
+			fn:                 func(d *Devops, q query.Query) { d.DownsampleCPU(q) },
+			expectedHumanLabel: "FileSink mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "FileSink mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedPlan:       `{"table":"cpu","end":172800000000000,` + allCPU + `,"aggregate":"mean","interval":86400000000000}`,
+		},
+	}
+
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			qi := d.GenerateEmptyQuery()
+			c.fn(d, qi)
+			q := qi.(*query.FileSink)
+
+			if got := string(q.HumanLabel); got != c.expectedHumanLabel {
+				t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, c.expectedHumanLabel)
+			}
+			if got := string(q.HumanDescription); got != c.expectedHumanDesc {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, c.expectedHumanDesc)
+			}
+			if got := string(q.Plan); got != c.expectedPlan {
+				t.Errorf("incorrect plan:\ngot\n%s\nwant\n%s", got, c.expectedPlan)
+			}
+		})
+	}
+}
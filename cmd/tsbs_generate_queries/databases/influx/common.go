@@ -7,12 +7,21 @@ import (
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
+// errFluxUseCase is returned for the use cases without Flux queries.
+const errFluxUseCase = "Flux queries are only implemented for the devops use case"
+
 // BaseGenerator contains settings specific for Influx database.
 type BaseGenerator struct {
+	// UseFlux generates Flux queries for the InfluxDB v2 API instead of InfluxQL.
+	UseFlux bool
+	// Bucket is the InfluxDB v2 bucket the Flux queries read from.
+	Bucket string
 }
 
 // GenerateEmptyQuery returns an empty query.HTTP.
@@ -33,6 +42,18 @@ func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, influ
 	q.Body = nil
 }
 
+// fillInFluxQuery fills the query struct with a Flux query for the
+// InfluxDB v2 query API, which expects the query as the request body.
+func (g *BaseGenerator) fillInFluxQuery(qi query.Query, humanLabel, humanDesc, flux string) {
+	q := qi.(*query.HTTP)
+	q.HumanLabel = []byte(humanLabel)
+	q.RawQuery = []byte(flux)
+	q.HumanDescription = []byte(humanDesc)
+	q.Method = []byte("POST")
+	q.Path = []byte("/api/v2/query")
+	q.Body = []byte(flux)
+}
+
 // NewDevops creates a new devops use case query generator.
 func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
 	core, err := devops.NewCore(start, end, scale)
@@ -41,6 +62,13 @@ func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryG
 		return nil, err
 	}
 
+	if g.UseFlux {
+		return &FluxDevops{
+			BaseGenerator: g,
+			Core:          core,
+		}, nil
+	}
+
 	devops := &Devops{
 		BaseGenerator: g,
 		Core:          core,
@@ -51,6 +79,10 @@ func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryG
 
 // NewIoT creates a new iot use case query generator.
 func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	if g.UseFlux {
+		return nil, fmt.Errorf(errFluxUseCase)
+	}
+
 	core, err := iot.NewCore(start, end, scale)
 
 	if err != nil {
@@ -64,3 +96,43 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return devops, nil
 }
+
+// NewK8s creates a new k8s use case query generator.
+func (g *BaseGenerator) NewK8s(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	if g.UseFlux {
+		return nil, fmt.Errorf(errFluxUseCase)
+	}
+
+	core, err := k8s.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k8s := &K8s{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return k8s, nil
+}
+
+// NewSmartMeter creates a new smart-meter use case query generator.
+func (g *BaseGenerator) NewSmartMeter(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	if g.UseFlux {
+		return nil, fmt.Errorf(errFluxUseCase)
+	}
+
+	core, err := smartmeter.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	smartMeter := &SmartMeter{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return smartMeter, nil
+}
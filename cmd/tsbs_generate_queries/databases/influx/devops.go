@@ -147,3 +147,136 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	influxql := fmt.Sprintf("SELECT * from cpu where usage_user > 90.0 %s and time >= '%s' and time < '%s'", hostWhereClause, interval.StartString(), interval.EndString())
 	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
 }
+
+// PercentileCPU selects the percentiles of the user CPU usage per host per hour for half a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT percentile(usage_user, 50), ..., percentile(usage_user, 99)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) PercentileCPU(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.PercentileCPUDuration)
+	percentiles := devops.GetCPUPercentiles()
+	selectClauses := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		selectClauses[i] = fmt.Sprintf("percentile(usage_user, %g) as %s_usage_user", p*100, devops.GetPercentileName(p))
+	}
+
+	humanLabel := devops.GetPercentileCPULabel("Influx")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := fmt.Sprintf("SELECT %s from cpu where time >= '%s' and time < '%s' group by time(1h),hostname", strings.Join(selectClauses, ", "), interval.StartString(), interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// TopKHostsByCPU selects the hosts with the highest mean user CPU usage in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT hostname, avg(usage_user) AS mean_usage_user
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hostname ORDER BY mean_usage_user DESC
+// LIMIT $LIMIT
+func (d *Devops) TopKHostsByCPU(qi query.Query, limit int) {
+	interval := d.Interval.MustRandWindow(devops.TopKHostsDuration)
+
+	humanLabel := devops.GetTopKHostsLabel("Influx", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := fmt.Sprintf("SELECT top(mean_usage_user, hostname, %d) from (SELECT mean(usage_user) as mean_usage_user from cpu where time >= '%s' and time < '%s' group by hostname)", limit, interval.StartString(), interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// CounterRate computes the per-second rate of the counters of a measurement per host per minute,
+// dropping the negative rates of counter resets, e.g. in pseudo-SQL:
+//
+// SELECT non_negative_derivative(max(counter1), 1s), ..., non_negative_derivative(max(counterN), 1s)
+// FROM measurement
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute, hostname ORDER BY minute, hostname
+func (d *Devops) CounterRate(qi query.Query, measurement string) {
+	metrics, err := devops.GetCounterMetrics(measurement)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.CounterRateDuration)
+	selectClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		selectClauses[i] = fmt.Sprintf("non_negative_derivative(max(%[1]s), 1s) as rate_%[1]s", m)
+	}
+
+	humanLabel := devops.GetCounterRateLabel("Influx", measurement)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := fmt.Sprintf("SELECT %s from %s where time >= '%s' and time < '%s' group by time(1m),hostname", strings.Join(selectClauses, ", "), measurement, interval.StartString(), interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// HighCPUAndMem finds the 5 minute buckets in which a host has both a high mean CPU usage
+// and a high mean memory usage. InfluxQL cannot join measurements, so the buckets over
+// each threshold are selected by one statement per measurement and matched by the client,
+// e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), avg(mem.used_percent)
+// FROM cpu JOIN mem USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname
+// HAVING avg(cpu.usage_user) > 90.0 AND avg(mem.used_percent) > 80.0
+// ORDER BY bucket, hostname
+func (d *Devops) HighCPUAndMem(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUMemDuration)
+	statementFmt := "SELECT %[1]s from (SELECT mean(%[2]s) as %[1]s from %[3]s where time >= '%[4]s' and time < '%[5]s' group by time(%[6]dm),hostname) where %[1]s > %.1[7]f group by hostname"
+
+	humanLabel := devops.GetHighCPUMemLabel("Influx")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := strings.Join([]string{
+		fmt.Sprintf(statementFmt, "mean_usage_user", "usage_user", devops.TableName, interval.StartString(), interval.EndString(), int(devops.JoinBucket.Minutes()), devops.HighCPUThreshold),
+		fmt.Sprintf(statementFmt, "mean_used_percent", "used_percent", devops.MemTableName, interval.StartString(), interval.EndString(), int(devops.JoinBucket.Minutes()), devops.HighMemThreshold),
+	}, "; ")
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// CPUNginxCorrelation lines up the mean CPU usage of every host with the number of
+// requests its nginx served per 5 minutes, reading the 'cpu' and 'nginx' measurements
+// in the same statement, e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), max(nginx.requests) - min(nginx.requests)
+// FROM cpu JOIN nginx USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname ORDER BY hostname, bucket
+func (d *Devops) CPUNginxCorrelation(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.CPUNginxDuration)
+
+	humanLabel := devops.GetCPUNginxLabel("Influx")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := fmt.Sprintf("SELECT mean(usage_user) as mean_usage_user, spread(requests) as requests from cpu, nginx where time >= '%s' and time < '%s' group by time(%dm),hostname", interval.StartString(), interval.EndString(), int(devops.JoinBucket.Minutes()))
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	whereHosts := d.getHostWhereString(nHosts)
+
+	humanLabel := devops.GetRawScanLabel("Influx", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	influxql := fmt.Sprintf("SELECT hostname,%s from cpu where %s and time >= '%s' and time < '%s'", strings.Join(devops.GetAllCPUMetrics(), ","), whereHosts, interval.StartString(), interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// DownsampleCPU selects the MEAN of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT MEAN(metric1), ..., MEAN(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectClausesAggMetrics("mean", devops.GetAllCPUMetrics())
+
+	humanLabel := devops.GetDownsampleLabel("Influx")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	influxql := fmt.Sprintf("SELECT %s from cpu where time >= '%s' and time < '%s' group by time(1d)", strings.Join(selectClauses, ","), d.Interval.StartString(), d.Interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
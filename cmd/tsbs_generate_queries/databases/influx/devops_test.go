@@ -475,3 +475,191 @@ func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, path string
 		t.Errorf("body not nil, got %+v", influxql.Body)
 	}
 }
+
+func TestPercentileCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "Influx p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "Influx p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h: 1970-01-01T00:16:22Z",
+			expectedQuery: "SELECT percentile(usage_user, 50) as p50_usage_user, percentile(usage_user, 95) as p95_usage_user, " +
+				"percentile(usage_user, 99) as p99_usage_user " +
+				"from cpu " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T12:16:22Z' " +
+				"group by time(1h),hostname",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.PercentileCPU(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.PercentileCPUDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestTopKHostsByCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "10 hosts",
+			input:              10,
+			expectedHumanLabel: "Influx top 10 hosts by mean CPU usage, random 1h0m0s",
+			expectedHumanDesc:  "Influx top 10 hosts by mean CPU usage, random 1h0m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: "SELECT top(mean_usage_user, hostname, 10) " +
+				"from (SELECT mean(usage_user) as mean_usage_user from cpu " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T01:16:22Z' " +
+				"group by hostname)",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.TopKHostsByCPU(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.TopKHostsDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestCounterRate(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "net",
+			expectedHumanLabel: "Influx per-second rate of net counters per host, all hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Influx per-second rate of net counters per host, all hosts, random 1h0m0s by 1m: 1970-01-01T00:16:22Z",
+			expectedQuery: "SELECT non_negative_derivative(max(bytes_sent), 1s) as rate_bytes_sent, " +
+				"non_negative_derivative(max(bytes_recv), 1s) as rate_bytes_recv " +
+				"from net " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T01:16:22Z' " +
+				"group by time(1m),hostname",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.CounterRate(q, devops.NetTableName)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.CounterRateDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestHighCPUAndMem(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "Influx CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "Influx CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: "SELECT mean_usage_user from (SELECT mean(usage_user) as mean_usage_user from cpu " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T12:16:22Z' group by time(5m),hostname) " +
+				"where mean_usage_user > 90.0 group by hostname; " +
+				"SELECT mean_used_percent from (SELECT mean(used_percent) as mean_used_percent from mem " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T12:16:22Z' group by time(5m),hostname) " +
+				"where mean_used_percent > 80.0 group by hostname",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.HighCPUAndMem(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.HighCPUMemDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestCPUNginxCorrelation(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "Influx mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "Influx mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s: 1970-01-01T00:16:22Z",
+			expectedQuery: "SELECT mean(usage_user) as mean_usage_user, spread(requests) as requests from cpu, nginx " +
+				"where time >= '1970-01-01T00:16:22Z' and time < '1970-01-01T12:16:22Z' " +
+				"group by time(5m),hostname",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.CPUNginxCorrelation(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.CPUNginxDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestRawScan(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:    "zero hosts",
+			input:   0,
+			fail:    true,
+			failMsg: "number of hosts cannot be < 1; got 0",
+		},
+		{
+			desc:               "2 hosts",
+			input:              2,
+			expectedHumanLabel: "Influx all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "Influx all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T00:54:10Z",
+			expectedQuery: "SELECT hostname,usage_user,usage_system,usage_idle,usage_nice,usage_iowait," +
+				"usage_irq,usage_softirq,usage_steal,usage_guest,usage_guest_nice " +
+				"from cpu " +
+				"where (hostname = 'host_3' or hostname = 'host_5') and " +
+				"time >= '1970-01-01T00:54:10Z' and time < '1970-01-01T12:54:10Z'",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.RawScan(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.RawScanDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestDownsampleCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "Influx mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "Influx mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedQuery: "SELECT mean(usage_user),mean(usage_system),mean(usage_idle),mean(usage_nice),mean(usage_iowait)," +
+				"mean(usage_irq),mean(usage_softirq),mean(usage_steal),mean(usage_guest),mean(usage_guest_nice) " +
+				"from cpu " +
+				"where time >= '1970-01-01T00:00:00Z' and time < '1970-01-04T00:00:00Z' " +
+				"group by time(1d)",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.DownsampleCPU(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(3 * devops.DownsampleBucket)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
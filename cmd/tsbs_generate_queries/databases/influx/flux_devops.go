@@ -0,0 +1,190 @@
+package influx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// FluxDevops produces Flux queries for the InfluxDB v2 API for all the
+// devops query types.
+type FluxDevops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// getFromRange returns the start of every Flux query, reading the given
+// time range of the 'cpu' measurement from the bucket.
+func (d *FluxDevops) getFromRange(start, stop string) string {
+	return fmt.Sprintf(`from(bucket: "%s") `+
+		`|> range(start: %s, stop: %s) `+
+		`|> filter(fn: (r) => r._measurement == "cpu")`, d.Bucket, start, stop)
+}
+
+// getFilterWithValues creates a filter matching any of the given values of
+// a column, e.g. filter(fn: (r) => r.hostname == "host_1" or r.hostname == "host_2").
+func (d *FluxDevops) getFilterWithValues(column string, values []string) string {
+	clauses := make([]string, len(values))
+	for i, v := range values {
+		clauses[i] = fmt.Sprintf(`r.%s == "%s"`, column, v)
+	}
+	return fmt.Sprintf("filter(fn: (r) => %s)", strings.Join(clauses, " or "))
+}
+
+func (d *FluxDevops) getHostFilterString(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return d.getFilterWithValues("hostname", hostnames)
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *FluxDevops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+
+	humanLabel := fmt.Sprintf("Influx Flux %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s |> %s |> %s |> group(columns: ["_field"]) |> aggregateWindow(every: 1m, fn: max, createEmpty: false)`,
+		d.getFromRange(interval.StartString(), interval.EndString()),
+		d.getFilterWithValues("_field", metrics),
+		d.getHostFilterString(nHosts))
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// GroupByOrderByLimit benchmarks a query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+//
+// Flux requires a start for every range, so the range starts at the
+// beginning of the dataset.
+func (d *FluxDevops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	humanLabel := "Influx Flux max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s |> filter(fn: (r) => r._field == "usage_user") |> group(columns: ["_field"]) `+
+		`|> aggregateWindow(every: 1m, fn: max, createEmpty: false) |> sort(columns: ["_time"], desc: true) |> limit(n: 5)`,
+		d.getFromRange(d.Interval.StartString(), interval.EndString()))
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *FluxDevops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+
+	humanLabel := devops.GetDoubleGroupByLabel("Influx Flux", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s |> %s |> group(columns: ["_field", "hostname"]) |> aggregateWindow(every: 1h, fn: mean, createEmpty: false)`,
+		d.getFromRange(interval.StartString(), interval.EndString()),
+		d.getFilterWithValues("_field", metrics))
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *FluxDevops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+	hostFilter := d.getHostFilterString(nHosts)
+
+	humanLabel := devops.GetMaxAllLabel("Influx Flux", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s |> %s |> group(columns: ["_field"]) |> aggregateWindow(every: 1h, fn: max, createEmpty: false)`,
+		d.getFromRange(interval.StartString(), interval.EndString()),
+		hostFilter)
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+func (d *FluxDevops) LastPointPerHost(qi query.Query) {
+	humanLabel := "Influx Flux last row per host"
+	humanDesc := humanLabel + ": cpu"
+	flux := fmt.Sprintf(`from(bucket: "%s") |> range(start: 0) |> filter(fn: (r) => r._measurement == "cpu") `+
+		`|> group(columns: ["hostname", "_field"]) |> last()`, d.Bucket)
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *FluxDevops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	var hostFilter string
+	if nHosts != 0 {
+		hostFilter = fmt.Sprintf(" |> %s", d.getHostFilterString(nHosts))
+	}
+
+	humanLabel, err := devops.GetHighCPULabel("Influx Flux", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s%s |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value") |> filter(fn: (r) => r.usage_user > 90.0)`,
+		d.getFromRange(interval.StartString(), interval.EndString()),
+		hostFilter)
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *FluxDevops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	hostFilter := d.getHostFilterString(nHosts)
+
+	humanLabel := devops.GetRawScanLabel("Influx Flux", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	flux := fmt.Sprintf(`%s |> %s |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value") |> sort(columns: ["_time"])`,
+		d.getFromRange(interval.StartString(), interval.EndString()),
+		hostFilter)
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
+
+// DownsampleCPU selects the MEAN of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT MEAN(metric1), ..., MEAN(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *FluxDevops) DownsampleCPU(qi query.Query) {
+	humanLabel := devops.GetDownsampleLabel("Influx Flux")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	flux := fmt.Sprintf(`%s |> group(columns: ["_field"]) |> aggregateWindow(every: 1d, fn: mean, createEmpty: false)`,
+		d.getFromRange(d.Interval.StartString(), d.Interval.EndString()))
+	d.fillInFluxQuery(qi, humanLabel, humanDesc, flux)
+}
@@ -0,0 +1,148 @@
+package influx
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestFluxDevopsQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(d *FluxDevops, q query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc:               "GroupByTime",
+			fn:                 func(d *FluxDevops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanLabel: "Influx Flux 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Influx Flux 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-02T02:16:22Z, stop: 1970-01-02T03:16:22Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> filter(fn: (r) => r._field == "usage_user" or r._field == "usage_system") ` +
+				`|> filter(fn: (r) => r.hostname == "host_9" or r.hostname == "host_3") ` +
+				`|> group(columns: ["_field"]) |> aggregateWindow(every: 1m, fn: max, createEmpty: false)`,
+		},
+		{
+			desc:               "GroupByOrderByLimit",
+			fn:                 func(d *FluxDevops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanLabel: "Influx Flux max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "Influx Flux max cpu over last 5 min-intervals (random end): 1970-01-01T11:37:12Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-01T00:00:00Z, stop: 1970-01-01T12:37:12Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") |> filter(fn: (r) => r._field == "usage_user") ` +
+				`|> group(columns: ["_field"]) |> aggregateWindow(every: 1m, fn: max, createEmpty: false) ` +
+				`|> sort(columns: ["_time"], desc: true) |> limit(n: 5)`,
+		},
+		{
+			desc:               "GroupByTimeAndPrimaryTag",
+			fn:                 func(d *FluxDevops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanLabel: "Influx Flux mean of 2 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "Influx Flux mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-02T02:17:45Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-02T02:17:45Z, stop: 1970-01-02T14:17:45Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> filter(fn: (r) => r._field == "usage_user" or r._field == "usage_system") ` +
+				`|> group(columns: ["_field", "hostname"]) |> aggregateWindow(every: 1h, fn: mean, createEmpty: false)`,
+		},
+		{
+			desc:               "MaxAllCPU",
+			fn:                 func(d *FluxDevops, q query.Query) { d.MaxAllCPU(q, 2) },
+			expectedHumanLabel: "Influx Flux max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h",
+			expectedHumanDesc:  "Influx Flux max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h: 1970-01-01T21:23:08Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-01T21:23:08Z, stop: 1970-01-02T05:23:08Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> filter(fn: (r) => r.hostname == "host_5" or r.hostname == "host_1") ` +
+				`|> group(columns: ["_field"]) |> aggregateWindow(every: 1h, fn: max, createEmpty: false)`,
+		},
+		{
+			desc:               "LastPointPerHost",
+			fn:                 func(d *FluxDevops, q query.Query) { d.LastPointPerHost(q) },
+			expectedHumanLabel: "Influx Flux last row per host",
+			expectedHumanDesc:  "Influx Flux last row per host: cpu",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 0) |> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> group(columns: ["hostname", "_field"]) |> last()`,
+		},
+		{
+			desc:               "HighCPUForHosts all hosts",
+			fn:                 func(d *FluxDevops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanLabel: "Influx Flux CPU over threshold, all hosts",
+			expectedHumanDesc:  "Influx Flux CPU over threshold, all hosts: 1970-01-02T06:57:58Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-02T06:57:58Z, stop: 1970-01-02T18:57:58Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value") |> filter(fn: (r) => r.usage_user > 90.0)`,
+		},
+		{
+			desc:               "HighCPUForHosts 1 host",
+			fn:                 func(d *FluxDevops, q query.Query) { d.HighCPUForHosts(q, 1) },
+			expectedHumanLabel: "Influx Flux CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "Influx Flux CPU over threshold, 1 host(s): 1970-01-01T01:23:03Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-01T01:23:03Z, stop: 1970-01-01T13:23:03Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") |> filter(fn: (r) => r.hostname == "host_2") ` +
+				`|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value") |> filter(fn: (r) => r.usage_user > 90.0)`,
+		},
+		{
+			desc:               "RawScan",
+			fn:                 func(d *FluxDevops, q query.Query) { d.RawScan(q, 2) },
+			expectedHumanLabel: "Influx Flux all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "Influx Flux all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T03:08:59Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-01T03:08:59Z, stop: 1970-01-01T15:08:59Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") |> filter(fn: (r) => r.hostname == "host_5" or r.hostname == "host_8") ` +
+				`|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value") |> sort(columns: ["_time"])`,
+		},
+		{
+			desc:               "DownsampleCPU",
+			fn:                 func(d *FluxDevops, q query.Query) { d.DownsampleCPU(q) },
+			expectedHumanLabel: "Influx Flux mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "Influx Flux mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedQuery: `from(bucket: "benchmark") |> range(start: 1970-01-01T00:00:00Z, stop: 1970-01-03T00:00:00Z) ` +
+				`|> filter(fn: (r) => r._measurement == "cpu") ` +
+				`|> group(columns: ["_field"]) |> aggregateWindow(every: 1d, fn: mean, createEmpty: false)`,
+		},
+	}
+
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{UseFlux: true, Bucket: "benchmark"}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*FluxDevops)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			qi := d.GenerateEmptyQuery()
+			c.fn(d, qi)
+			q := qi.(*query.HTTP)
+
+			if got := string(q.HumanLabel); got != c.expectedHumanLabel {
+				t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, c.expectedHumanLabel)
+			}
+			if got := string(q.HumanDescription); got != c.expectedHumanDesc {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, c.expectedHumanDesc)
+			}
+			if got := string(q.Method); got != "POST" {
+				t.Errorf("incorrect method:\ngot\n%s\nwant POST", got)
+			}
+			if got := string(q.Path); got != "/api/v2/query" {
+				t.Errorf("incorrect path:\ngot\n%s\nwant /api/v2/query", got)
+			}
+			if got := string(q.Body); got != c.expectedQuery {
+				t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got, c.expectedQuery)
+			}
+		})
+	}
+}
+
+func TestFluxOnlyDevops(t *testing.T) {
+	b := BaseGenerator{UseFlux: true, Bucket: "benchmark"}
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	if _, err := b.NewIoT(s, e, 10); err == nil || err.Error() != errFluxUseCase {
+		t.Errorf("expected error %q for iot use case, got %v", errFluxUseCase, err)
+	}
+}
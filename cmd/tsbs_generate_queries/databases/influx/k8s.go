@@ -0,0 +1,76 @@
+package influx
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces Influx-specific queries for all the k8s query types.
+type K8s struct {
+	*k8s.Core
+	*BaseGenerator
+}
+
+// TopPodsByCPU finds the pods of a namespace which used the most CPU time in a time window.
+func (k *K8s) TopPodsByCPU(qi query.Query, limit int) {
+	interval := k.Interval.MustRandWindow(k8s.TopPodsDuration)
+	influxql := fmt.Sprintf(`SELECT top("cpu_seconds", "pod", %d) 
+		FROM (SELECT spread("cpu_usage_seconds_total") AS "cpu_seconds" 
+			FROM "container" 
+			WHERE "namespace" = '%s' AND time >= '%s' AND time < '%s' 
+			GROUP BY "pod")`,
+		limit,
+		k.GetRandomNamespace(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := fmt.Sprintf("Influx top %d pods by CPU usage per namespace", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// RestartsPerDeployment counts the container restarts per deployment of a namespace in a time window.
+func (k *K8s) RestartsPerDeployment(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.RestartsDuration)
+	influxql := fmt.Sprintf(`SELECT sum("restarts") AS "restarts" 
+		FROM (SELECT spread("container_status_restarts_total") AS "restarts" 
+			FROM "kube_pod" 
+			WHERE "namespace" = '%s' AND time >= '%s' AND time < '%s' 
+			GROUP BY "deployment", "pod") 
+		GROUP BY "deployment"`,
+		k.GetRandomNamespace(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx container restarts per deployment"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// NodeSaturation calculates the CPU cores and memory used on each node per 5 minutes in a time window.
+func (k *K8s) NodeSaturation(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.NodeSaturationDuration)
+	influxql := fmt.Sprintf(`SELECT sum("cpu_seconds") / %d AS "cpu_cores", sum("memory_bytes") AS "memory_bytes" 
+		FROM (SELECT spread("cpu_usage_seconds_total") AS "cpu_seconds", mean("memory_working_set_bytes") AS "memory_bytes" 
+			FROM "container" 
+			WHERE time >= '%s' AND time < '%s' 
+			GROUP BY time(%s), "node", "pod") 
+		WHERE time >= '%s' AND time < '%s' 
+		GROUP BY time(%s), "node"`,
+		int(k8s.NodeSaturationBucket.Seconds()),
+		interval.StartString(),
+		interval.EndString(),
+		k8s.NodeSaturationBucket,
+		interval.StartString(),
+		interval.EndString(),
+		k8s.NodeSaturationBucket)
+
+	humanLabel := "Influx node CPU and memory saturation"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
@@ -0,0 +1,54 @@
+package influx
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// SmartMeter produces Influx-specific queries for all the smart-meter query types.
+type SmartMeter struct {
+	*smartmeter.Core
+	*BaseGenerator
+}
+
+// DailyConsumptionPerFeeder calculates the energy consumed by the meters of
+// each feeder of a random region in a day.
+func (s *SmartMeter) DailyConsumptionPerFeeder(qi query.Query) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+	influxql := fmt.Sprintf(`SELECT sum("consumption") AS "consumption_kwh" 
+		FROM (SELECT spread("energy_import_kwh") AS "consumption" 
+			FROM "meter" 
+			WHERE "region" = '%s' AND time >= '%s' AND time < '%s' 
+			GROUP BY "feeder", "meter_id") 
+		GROUP BY "feeder"`,
+		s.GetRandomRegion(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx daily consumption per feeder"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	s.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// TopConsumers finds the meters of a random region which consumed the most
+// energy in a day.
+func (s *SmartMeter) TopConsumers(qi query.Query, limit int) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+	influxql := fmt.Sprintf(`SELECT top("consumption_kwh", "meter_id", %d) 
+		FROM (SELECT spread("energy_import_kwh") AS "consumption_kwh" 
+			FROM "meter" 
+			WHERE "region" = '%s' AND time >= '%s' AND time < '%s' 
+			GROUP BY "meter_id")`,
+		limit,
+		s.GetRandomRegion(),
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := fmt.Sprintf("Influx top %d consumers per region", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	s.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
@@ -0,0 +1,320 @@
+package mongo
+
+import (
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func init() {
+	// needed for serializing the time bounds and sorts of the mongo query to gob
+	gob.Register(time.Time{})
+	gob.Register(bson.D{})
+}
+
+// TimeseriesDevops produces Mongo-specific queries for the devops use case,
+// for the data stored in a native time-series collection. The tags and the
+// measurement of a reading are in its meta field and the metrics are top
+// level fields, e.g.
+//
+//	{"time": ISODate(...), "meta": {"measurement": "cpu", "hostname": "host_0", ...}, "usage_user": 58.0, ...}
+type TimeseriesDevops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// timeBucket truncates the time of the readings to the given unit.
+func timeBucket(unit string) bson.M {
+	return bson.M{"$dateTrunc": bson.M{"date": "$time", "unit": unit}}
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *TimeseriesDevops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	panicIfErr(err)
+
+	group := bson.M{"_id": timeBucket("minute")}
+	for _, metric := range metrics {
+		group["max_"+metric] = bson.M{"$max": "$" + metric}
+	}
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"meta.hostname":    bson.M{"$in": hostnames},
+				"time": bson.M{
+					"$gte": interval.Start(),
+					"$lt":  interval.End(),
+				},
+			},
+		},
+		{"$group": group},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	humanLabel := []byte(fmt.Sprintf("Mongo [TIMESERIES] %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange))
+	q := qi.(*query.Mongo)
+	q.HumanLabel = humanLabel
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *TimeseriesDevops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+
+	group := bson.M{"_id": timeBucket("hour")}
+	for _, metric := range devops.GetAllCPUMetrics() {
+		group["max_"+metric] = bson.M{"$max": "$" + metric}
+	}
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"meta.hostname":    bson.M{"$in": hostnames},
+				"time": bson.M{
+					"$gte": interval.Start(),
+					"$lt":  interval.End(),
+				},
+			},
+		},
+		{"$group": group},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	humanLabel := devops.GetMaxAllLabel("Mongo [TIMESERIES]", nHosts)
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *TimeseriesDevops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	panicIfErr(err)
+
+	group := bson.M{
+		"_id": bson.M{
+			"time":     timeBucket("hour"),
+			"hostname": "$meta.hostname",
+		},
+	}
+	for _, metric := range metrics {
+		group["avg_"+metric] = bson.M{"$avg": "$" + metric}
+	}
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"time": bson.M{
+					"$gte": interval.Start(),
+					"$lt":  interval.End(),
+				},
+			},
+		},
+		{"$group": group},
+		{"$sort": bson.D{{Name: "_id.time", Value: 1}, {Name: "_id.hostname", Value: 1}}},
+	}
+
+	humanLabel := devops.GetDoubleGroupByLabel("Mongo [TIMESERIES]", numMetrics)
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *TimeseriesDevops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	match := bson.M{
+		"meta.measurement": "cpu",
+		"time": bson.M{
+			"$gte": interval.Start(),
+			"$lt":  interval.End(),
+		},
+		"usage_user": bson.M{"$gt": 90.0},
+	}
+	if nHosts > 0 {
+		hostnames, err := d.GetRandomHosts(nHosts)
+		panicIfErr(err)
+		match["meta.hostname"] = bson.M{"$in": hostnames}
+	}
+	pipelineQuery := []bson.M{
+		{"$match": match},
+		{"$project": bson.M{"_id": 0}},
+	}
+
+	humanLabel, err := devops.GetHighCPULabel("Mongo [TIMESERIES]", nHosts)
+	panicIfErr(err)
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// LastPointPerHost finds the last row for every host in the dataset. The
+// readings are sorted by host and time, following the index on the meta
+// field, and the first one of every host is kept.
+func (d *TimeseriesDevops) LastPointPerHost(qi query.Query) {
+	pipelineQuery := []bson.M{
+		{"$match": bson.M{"meta.measurement": "cpu"}},
+		{"$sort": bson.D{{Name: "meta.hostname", Value: 1}, {Name: "time", Value: -1}}},
+		{
+			"$group": bson.M{
+				"_id":    "$meta.hostname",
+				"result": bson.M{"$first": "$$ROOT"},
+			},
+		},
+	}
+
+	humanLabel := "Mongo [TIMESERIES] last row per host"
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(humanLabel)
+}
+
+// GroupByOrderByLimit populates a query.Query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT date_trunc('minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *TimeseriesDevops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"time":             bson.M{"$lt": interval.End()},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":       timeBucket("minute"),
+				"max_value": bson.M{"$max": "$usage_user"},
+			},
+		},
+		{"$sort": bson.M{"_id": -1}},
+		{"$limit": 5},
+	}
+
+	humanLabel := "Mongo [TIMESERIES] max cpu over last 5 min-intervals (random end)"
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s", humanLabel, interval.EndString()))
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *TimeseriesDevops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"meta.hostname":    bson.M{"$in": hostnames},
+				"time": bson.M{
+					"$gte": interval.Start(),
+					"$lt":  interval.End(),
+				},
+			},
+		},
+		{"$sort": bson.M{"time": 1}},
+		{"$project": bson.M{"_id": 0}},
+	}
+
+	humanLabel := devops.GetRawScanLabel("Mongo [TIMESERIES]", nHosts)
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *TimeseriesDevops) DownsampleCPU(qi query.Query) {
+	group := bson.M{"_id": timeBucket("day")}
+	for _, metric := range devops.GetAllCPUMetrics() {
+		group["avg_"+metric] = bson.M{"$avg": "$" + metric}
+	}
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"meta.measurement": "cpu",
+				"time": bson.M{
+					"$gte": d.Interval.Start(),
+					"$lt":  d.Interval.End(),
+				},
+			},
+		},
+		{"$group": group},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	humanLabel := devops.GetDownsampleLabel("Mongo [TIMESERIES]")
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, d.Interval.StartString(), q.CollectionName))
+}
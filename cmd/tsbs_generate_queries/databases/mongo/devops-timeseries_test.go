@@ -0,0 +1,106 @@
+package mongo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func newTimeseriesDevops(t *testing.T) *TimeseriesDevops {
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{UseTimeseries: true}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	return dq.(*TimeseriesDevops)
+}
+
+func TestTimeseriesDevopsGroupByTime(t *testing.T) {
+	rand.Seed(123)
+	d := newTimeseriesDevops(t)
+	q := d.GenerateEmptyQuery()
+	d.GroupByTime(q, 2, 2, time.Hour)
+	mq := q.(*query.Mongo)
+
+	if got, want := string(mq.HumanLabel), "Mongo [TIMESERIES] 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m"; got != want {
+		t.Errorf("incorrect label:\ngot\n%s\nwant\n%s", got, want)
+	}
+	if len(mq.BsonDoc) != 3 {
+		t.Fatalf("incorrect number of stages: got %d want 3", len(mq.BsonDoc))
+	}
+	match := mq.BsonDoc[0]["$match"].(bson.M)
+	if match["meta.measurement"] != "cpu" {
+		t.Errorf("incorrect measurement filter: %v", match)
+	}
+	if hosts := match["meta.hostname"].(bson.M)["$in"].([]string); len(hosts) != 2 {
+		t.Errorf("incorrect host filter: %v", hosts)
+	}
+	bounds := match["time"].(bson.M)
+	start, end := bounds["$gte"].(time.Time), bounds["$lt"].(time.Time)
+	if end.Sub(start) != time.Hour {
+		t.Errorf("incorrect time range: %v to %v", start, end)
+	}
+	group := mq.BsonDoc[1]["$group"].(bson.M)
+	if !reflect.DeepEqual(group["max_usage_user"], bson.M{"$max": "$usage_user"}) ||
+		!reflect.DeepEqual(group["max_usage_system"], bson.M{"$max": "$usage_system"}) {
+		t.Errorf("incorrect group: %v", group)
+	}
+	if !reflect.DeepEqual(group["_id"], timeBucket("minute")) {
+		t.Errorf("incorrect group key: %v", group["_id"])
+	}
+}
+
+// TestTimeseriesDevopsGob checks that the queries survive the encoding used
+// for the query files, including their times and ordered sorts.
+func TestTimeseriesDevopsGob(t *testing.T) {
+	rand.Seed(123)
+	d := newTimeseriesDevops(t)
+	fillers := []func(query.Query){
+		func(q query.Query) { d.GroupByTime(q, 1, 1, time.Hour) },
+		func(q query.Query) { d.MaxAllCPU(q, 8) },
+		func(q query.Query) { d.GroupByTimeAndPrimaryTag(q, devops.GetCPUMetricsLen()) },
+		func(q query.Query) { d.HighCPUForHosts(q, 0) },
+		func(q query.Query) { d.LastPointPerHost(q) },
+		func(q query.Query) { d.GroupByOrderByLimit(q) },
+		func(q query.Query) { d.RawScan(q, 8) },
+		func(q query.Query) { d.DownsampleCPU(q) },
+	}
+	for _, fill := range fillers {
+		q := d.GenerateEmptyQuery()
+		fill(q)
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(q); err != nil {
+			t.Fatalf("%s: could not encode: %v", q.HumanLabelName(), err)
+		}
+		decoded := query.NewMongo()
+		if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+			t.Fatalf("%s: could not decode: %v", q.HumanLabelName(), err)
+		}
+		want, got := bsonRoundTrip(t, q.(*query.Mongo).BsonDoc), bsonRoundTrip(t, decoded.BsonDoc)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: pipeline changed by the encoding", q.HumanLabelName())
+		}
+	}
+}
+
+// bsonRoundTrip returns a pipeline as sent to the server and read back.
+func bsonRoundTrip(t *testing.T, pipeline []bson.M) bson.M {
+	b, err := bson.Marshal(bson.M{"pipeline": pipeline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bson.M
+	if err := bson.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
@@ -508,3 +508,110 @@ func (d *Devops) GroupByOrderByLimit(qi query.Query) {
 	q.CollectionName = []byte("point_data")
 	q.HumanDescription = []byte(fmt.Sprintf("%s: %s", humanLabel, interval.EndString()))
 }
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+	docs := getTimeFilterDocs(interval)
+
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"measurement": "cpu",
+				"tags.hostname": bson.M{
+					"$in": hostnames,
+				},
+				"key_id": bson.M{
+					"$in": docs,
+				},
+			},
+		},
+		{
+			"$project": bson.M{
+				"_id":    0,
+				"events": 1,
+				"key_id": 1,
+				"tags":   "$tags.hostname",
+			},
+		},
+	}
+	pipelineQuery = append(pipelineQuery, getTimeFilterPipeline(interval)...)
+	pipelineQuery = append(pipelineQuery, bson.M{"$sort": bson.M{"events.timestamp_ns": 1}})
+
+	humanLabel := devops.GetRawScanLabel("Mongo", nHosts)
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	interval := d.Interval.TimeInterval
+	docs := getTimeFilterDocs(interval)
+	bucketNano := devops.DownsampleBucket.Nanoseconds()
+
+	pipelineQuery := []bson.M{
+		{
+			"$match": bson.M{
+				"measurement": "cpu",
+				"key_id": bson.M{
+					"$in": docs,
+				},
+			},
+		},
+		{
+			"$project": bson.M{
+				"_id":    0,
+				"events": 1,
+				"key_id": 1,
+				"tags":   "$tags.hostname",
+			},
+		},
+	}
+	pipelineQuery = append(pipelineQuery, getTimeFilterPipeline(interval)...)
+	pipelineQuery = append(pipelineQuery, bson.M{
+		"$project": bson.M{
+			"time_bucket": bson.M{
+				"$subtract": []interface{}{
+					"$events.timestamp_ns",
+					bson.M{"$mod": []interface{}{"$events.timestamp_ns", bucketNano}},
+				},
+			},
+			"events": 1,
+		},
+	})
+
+	group := bson.M{
+		"$group": bson.M{
+			"_id": "$time_bucket",
+		},
+	}
+	resultMap := group["$group"].(bson.M)
+	for _, metric := range devops.GetAllCPUMetrics() {
+		resultMap["avg_"+metric] = bson.M{"$avg": "$events." + metric}
+	}
+	pipelineQuery = append(pipelineQuery, group)
+	pipelineQuery = append(pipelineQuery, bson.M{"$sort": bson.M{"_id": 1}})
+
+	humanLabel := devops.GetDownsampleLabel("Mongo")
+	q := qi.(*query.Mongo)
+	q.HumanLabel = []byte(humanLabel)
+	q.BsonDoc = pipelineQuery
+	q.CollectionName = []byte("point_data")
+	q.HumanDescription = []byte(fmt.Sprintf("%s: %s (%s)", humanLabel, interval.StartString(), q.CollectionName))
+}
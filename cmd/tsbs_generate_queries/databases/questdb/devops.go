@@ -0,0 +1,332 @@
+package questdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TODO: Remove the need for this by continuing to bubble up errors
+func panicIfErr(err error) {
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+// Devops produces QuestDB-specific queries for all the devops query types.
+// The tags are SYMBOL columns of every table and the rows are bucketed with
+// SAMPLE BY on the designated timestamp column.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// getSelectAggClauses builds specified aggregate function clauses for
+// a set of column idents.
+//
+// For instance:
+//
+//	max(cpu_time) AS max_cpu_time
+func (d *Devops) getSelectAggClauses(aggFunc string, idents []string) []string {
+	selectAggClauses := make([]string, len(idents))
+	for i, ident := range idents {
+		selectAggClauses[i] =
+			fmt.Sprintf("%[1]s(%[2]s) AS %[1]s_%[2]s", aggFunc, ident)
+	}
+	return selectAggClauses
+}
+
+// getHostWhereWithHostnames creates a WHERE SQL statement for the given hostnames.
+func (d *Devops) getHostWhereWithHostnames(hostnames []string) string {
+	return fmt.Sprintf("hostname IN ('%s')", strings.Join(hostnames, "', '"))
+}
+
+// getHostWhereString gets multiple random hostnames and creates a WHERE SQL statement for these hostnames.
+func (d *Devops) getHostWhereString(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	panicIfErr(err)
+	return d.getHostWhereWithHostnames(hostnames)
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for N random
+// hosts
+//
+// Queries:
+// cpu-max-all-1
+// cpu-max-all-8
+func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+	selectClauses := d.getSelectAggClauses("max", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp AS hour, %s
+		FROM cpu
+		WHERE %s
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1h`,
+		strings.Join(selectClauses, ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt))
+
+	humanLabel := devops.GetMaxAllLabel("QuestDB", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of metrics in the group `cpu` per device
+// per hour for a day
+//
+// Queries:
+// double-groupby-1
+// double-groupby-5
+// double-groupby-all
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	panicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	selectClauses := d.getSelectAggClauses("avg", metrics)
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp AS hour, hostname, %s
+		FROM cpu
+		WHERE timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1h`,
+		strings.Join(selectClauses, ", "),
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt))
+
+	humanLabel := devops.GetDoubleGroupByLabel("QuestDB", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByOrderByLimit populates a query.Query that has a time WHERE clause,
+// that groups by a truncated date, orders by that date, and takes a limit:
+//
+// Queries:
+// groupby-orderby-limit
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp AS minute, max(usage_user)
+		FROM cpu
+		WHERE timestamp < '%s'
+		SAMPLE BY 1m
+		ORDER BY minute DESC
+		LIMIT 5`,
+		interval.End().Format(timeFmt))
+
+	humanLabel := "QuestDB max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset
+//
+// Queries:
+// lastpoint
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	sql := `
+		SELECT *
+		FROM cpu
+		LATEST ON timestamp PARTITION BY hostname`
+
+	humanLabel := "QuestDB last row per host"
+	humanDesc := humanLabel
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has
+// high usage between a time period for a number of hosts (if 0, it will
+// search all hosts)
+//
+// Queries:
+// high-cpu-1
+// high-cpu-all
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	var hostWhereClause string
+	if nHosts > 0 {
+		hostWhereClause = fmt.Sprintf("\n		  AND %s", d.getHostWhereString(nHosts))
+	}
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT *
+		FROM cpu
+		WHERE usage_user > 90.0
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'%s`,
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt),
+		hostWhereClause)
+
+	humanLabel, err := devops.GetHighCPULabel("QuestDB", nHosts)
+	panicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByTime selects the MAX for metrics under 'cpu', per minute for N random
+// hosts
+//
+// Resultsets:
+// single-groupby-1-1-12
+// single-groupby-1-1-1
+// single-groupby-1-8-1
+// single-groupby-5-1-12
+// single-groupby-5-1-1
+// single-groupby-5-8-1
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	panicIfErr(err)
+	selectClauses := d.getSelectAggClauses("max", metrics)
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp AS minute, %s
+		FROM cpu
+		WHERE %s
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1m`,
+		strings.Join(selectClauses, ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt))
+
+	humanLabel := fmt.Sprintf(
+		"QuestDB %d cpu metric(s), random %4d hosts, random %s by 1m",
+		numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// HighCPUAndMem finds the 5 minute buckets in which a host has both a high
+// mean CPU usage and a high mean memory usage, joining the 'cpu' and 'mem'
+// tables
+//
+// Queries:
+// high-cpu-mem
+func (d *Devops) HighCPUAndMem(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUMemDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT c.bucket, c.hostname, c.mean_usage_user, m.mean_used_percent
+		FROM (
+			SELECT timestamp AS bucket, hostname, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE timestamp >= '%[1]s'
+			  AND timestamp < '%[2]s'
+			SAMPLE BY %[3]dm
+		) c
+		JOIN (
+			SELECT timestamp AS bucket, hostname, avg(used_percent) AS mean_used_percent
+			FROM mem
+			WHERE timestamp >= '%[1]s'
+			  AND timestamp < '%[2]s'
+			SAMPLE BY %[3]dm
+		) m ON c.hostname = m.hostname AND c.bucket = m.bucket
+		WHERE c.mean_usage_user > %.1[4]f
+		  AND m.mean_used_percent > %.1[5]f
+		ORDER BY c.bucket, c.hostname`,
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt),
+		int(devops.JoinBucket.Minutes()),
+		devops.HighCPUThreshold,
+		devops.HighMemThreshold)
+
+	humanLabel := devops.GetHighCPUMemLabel("QuestDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// CPUNginxCorrelation lines up the mean CPU usage of every host with the
+// number of requests its nginx served per 5 minutes, joining the 'cpu' and
+// 'nginx' tables
+//
+// Queries:
+// cpu-nginx
+func (d *Devops) CPUNginxCorrelation(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.CPUNginxDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT c.bucket, c.hostname, c.mean_usage_user, n.requests
+		FROM (
+			SELECT timestamp AS bucket, hostname, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE timestamp >= '%[1]s'
+			  AND timestamp < '%[2]s'
+			SAMPLE BY %[3]dm
+		) c
+		JOIN (
+			SELECT timestamp AS bucket, hostname, max(requests) - min(requests) AS requests
+			FROM nginx
+			WHERE timestamp >= '%[1]s'
+			  AND timestamp < '%[2]s'
+			SAMPLE BY %[3]dm
+		) n ON c.hostname = n.hostname AND c.bucket = n.bucket
+		ORDER BY c.hostname, c.bucket`,
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt),
+		int(devops.JoinBucket.Minutes()))
+
+	humanLabel := devops.GetCPUNginxLabel("QuestDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// RawScan selects all the metrics under 'cpu' of N random hosts over a random
+// 12 hour window, without aggregating them
+//
+// Queries:
+// raw-scan-1
+// raw-scan-8
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp, hostname, %s
+		FROM cpu
+		WHERE %s
+		  AND timestamp >= '%s'
+		  AND timestamp < '%s'
+		ORDER BY timestamp`,
+		strings.Join(devops.GetAllCPUMetrics(), ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt))
+
+	humanLabel := devops.GetRawScanLabel("QuestDB", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all
+// hosts over the entire dataset
+//
+// Queries:
+// downsample
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectAggClauses("avg", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`
+		SELECT timestamp AS day, %s
+		FROM cpu
+		WHERE timestamp >= '%s'
+		  AND timestamp < '%s'
+		SAMPLE BY 1d`,
+		strings.Join(selectClauses, ", "),
+		d.Interval.Start().Format(timeFmt),
+		d.Interval.End().Format(timeFmt))
+
+	humanLabel := devops.GetDownsampleLabel("QuestDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
@@ -0,0 +1,304 @@
+package questdb
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const testScale = 10
+
+type testCase struct {
+	desc               string
+	generate           func(query.Query)
+	expectedHumanLabel string
+	expectedHumanDesc  string
+	expectedTable      string
+	expectedSQLQuery   string
+}
+
+func runTestCases(t *testing.T, b *BaseGenerator, cases []testCase) {
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			q := b.GenerateEmptyQuery()
+			c.generate(q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedTable, c.expectedSQLQuery)
+		})
+	}
+}
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, table, sqlQuery string) {
+	tsq, ok := q.(*query.TimescaleDB)
+
+	if !ok {
+		t.Fatal("Filled query is not *query.TimescaleDB type")
+	}
+
+	if got := string(tsq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+
+	if got := string(tsq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+
+	if got := string(tsq.Hypertable); got != table {
+		t.Errorf("incorrect table:\ngot\n%s\nwant\n%s", got, table)
+	}
+
+	if got := string(tsq.SqlQuery); got != sqlQuery {
+		t.Errorf("incorrect SQL query:\ndiff\n%s\ngot\n%s\nwant\n%s", diff.CharacterDiff(got, sqlQuery), got, sqlQuery)
+	}
+}
+
+func newTestDevops(t *testing.T, b *BaseGenerator) *Devops {
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+	dq, err := b.NewDevops(start, end, testScale)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	return dq.(*Devops)
+}
+
+func TestDevopsGetSelectAggClauses(t *testing.T) {
+	d := newTestDevops(t, &BaseGenerator{})
+	cases := []struct {
+		desc    string
+		agg     string
+		metrics []string
+		want    string
+	}{
+		{
+			desc:    "single metric - max",
+			agg:     "max",
+			metrics: []string{"foo"},
+			want:    "max(foo) AS max_foo",
+		},
+		{
+			desc:    "multiple metric - avg",
+			agg:     "avg",
+			metrics: []string{"foo", "bar"},
+			want:    "avg(foo) AS avg_foo, avg(bar) AS avg_bar",
+		},
+	}
+
+	for _, c := range cases {
+		got := strings.Join(d.getSelectAggClauses(c.agg, c.metrics), ", ")
+		if got != c.want {
+			t.Errorf("%s: incorrect output: got %s want %s", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestDevopsGetHostWhereWithHostnames(t *testing.T) {
+	d := newTestDevops(t, &BaseGenerator{})
+	cases := []struct {
+		desc      string
+		hostnames []string
+		want      string
+	}{
+		{
+			desc:      "single host",
+			hostnames: []string{"foo1"},
+			want:      "hostname IN ('foo1')",
+		},
+		{
+			desc:      "multi host",
+			hostnames: []string{"foo1", "foo2"},
+			want:      "hostname IN ('foo1', 'foo2')",
+		},
+	}
+
+	for _, c := range cases {
+		if got := d.getHostWhereWithHostnames(c.hostnames); got != c.want {
+			t.Errorf("%s: incorrect output: got %s want %s", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestDevopsQueries(t *testing.T) {
+	b := &BaseGenerator{}
+	d := newTestDevops(t, b)
+
+	cases := []testCase{
+		{
+			desc:               "max all cpu",
+			generate:           func(q query.Query) { d.MaxAllCPU(q, 2) },
+			expectedHumanLabel: "QuestDB max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h",
+			expectedHumanDesc:  "QuestDB max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h: 1970-01-01T02:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp AS hour, max(usage_user) AS max_usage_user, max(usage_system) AS max_usage_system, max(usage_idle) AS max_usage_idle, max(usage_nice) AS max_usage_nice, max(usage_iowait) AS max_usage_iowait, max(usage_irq) AS max_usage_irq, max(usage_softirq) AS max_usage_softirq, max(usage_steal) AS max_usage_steal, max(usage_guest) AS max_usage_guest, max(usage_guest_nice) AS max_usage_guest_nice
+		FROM cpu
+		WHERE hostname IN ('host_9', 'host_3')
+		  AND timestamp >= '1970-01-01T02:16:22.646325Z'
+		  AND timestamp < '1970-01-01T10:16:22.646325Z'
+		SAMPLE BY 1h`,
+		},
+		{
+			desc:               "double groupby",
+			generate:           func(q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanLabel: "QuestDB mean of 2 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "QuestDB mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-01T06:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp AS hour, hostname, avg(usage_user) AS avg_usage_user, avg(usage_system) AS avg_usage_system
+		FROM cpu
+		WHERE timestamp >= '1970-01-01T06:16:22.646325Z'
+		  AND timestamp < '1970-01-01T18:16:22.646325Z'
+		SAMPLE BY 1h`,
+		},
+		{
+			desc:               "groupby orderby limit",
+			generate:           d.GroupByOrderByLimit,
+			expectedHumanLabel: "QuestDB max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "QuestDB max cpu over last 5 min-intervals (random end): 1970-01-02T03:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp AS minute, max(usage_user)
+		FROM cpu
+		WHERE timestamp < '1970-01-02T03:16:22.646325Z'
+		SAMPLE BY 1m
+		ORDER BY minute DESC
+		LIMIT 5`,
+		},
+		{
+			desc:               "lastpoint",
+			generate:           d.LastPointPerHost,
+			expectedHumanLabel: "QuestDB last row per host",
+			expectedHumanDesc:  "QuestDB last row per host",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT *
+		FROM cpu
+		LATEST ON timestamp PARTITION BY hostname`,
+		},
+		{
+			desc:               "high cpu all hosts",
+			generate:           func(q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanLabel: "QuestDB CPU over threshold, all hosts",
+			expectedHumanDesc:  "QuestDB CPU over threshold, all hosts: 1970-01-01T06:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT *
+		FROM cpu
+		WHERE usage_user > 90.0
+		  AND timestamp >= '1970-01-01T06:16:22.646325Z'
+		  AND timestamp < '1970-01-01T18:16:22.646325Z'`,
+		},
+		{
+			desc:               "high cpu 2 hosts",
+			generate:           func(q query.Query) { d.HighCPUForHosts(q, 2) },
+			expectedHumanLabel: "QuestDB CPU over threshold, 2 host(s)",
+			expectedHumanDesc:  "QuestDB CPU over threshold, 2 host(s): 1970-01-02T05:47:30Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT *
+		FROM cpu
+		WHERE usage_user > 90.0
+		  AND timestamp >= '1970-01-02T05:47:30.894865Z'
+		  AND timestamp < '1970-01-02T17:47:30.894865Z'
+		  AND hostname IN ('host_5', 'host_9')`,
+		},
+		{
+			desc:               "single groupby",
+			generate:           func(q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanLabel: "QuestDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "QuestDB 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp AS minute, max(usage_user) AS max_usage_user, max(usage_system) AS max_usage_system
+		FROM cpu
+		WHERE hostname IN ('host_9', 'host_3')
+		  AND timestamp >= '1970-01-02T02:16:22.646325Z'
+		  AND timestamp < '1970-01-02T03:16:22.646325Z'
+		SAMPLE BY 1m`,
+		},
+		{
+			desc:               "high cpu and mem",
+			generate:           d.HighCPUAndMem,
+			expectedHumanLabel: "QuestDB CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "QuestDB CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s: 1970-01-01T06:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT c.bucket, c.hostname, c.mean_usage_user, m.mean_used_percent
+		FROM (
+			SELECT timestamp AS bucket, hostname, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE timestamp >= '1970-01-01T06:16:22.646325Z'
+			  AND timestamp < '1970-01-01T18:16:22.646325Z'
+			SAMPLE BY 5m
+		) c
+		JOIN (
+			SELECT timestamp AS bucket, hostname, avg(used_percent) AS mean_used_percent
+			FROM mem
+			WHERE timestamp >= '1970-01-01T06:16:22.646325Z'
+			  AND timestamp < '1970-01-01T18:16:22.646325Z'
+			SAMPLE BY 5m
+		) m ON c.hostname = m.hostname AND c.bucket = m.bucket
+		WHERE c.mean_usage_user > 90.0
+		  AND m.mean_used_percent > 80.0
+		ORDER BY c.bucket, c.hostname`,
+		},
+		{
+			desc:               "cpu nginx correlation",
+			generate:           d.CPUNginxCorrelation,
+			expectedHumanLabel: "QuestDB mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s",
+			expectedHumanDesc:  "QuestDB mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s: 1970-01-01T06:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT c.bucket, c.hostname, c.mean_usage_user, n.requests
+		FROM (
+			SELECT timestamp AS bucket, hostname, avg(usage_user) AS mean_usage_user
+			FROM cpu
+			WHERE timestamp >= '1970-01-01T06:16:22.646325Z'
+			  AND timestamp < '1970-01-01T18:16:22.646325Z'
+			SAMPLE BY 5m
+		) c
+		JOIN (
+			SELECT timestamp AS bucket, hostname, max(requests) - min(requests) AS requests
+			FROM nginx
+			WHERE timestamp >= '1970-01-01T06:16:22.646325Z'
+			  AND timestamp < '1970-01-01T18:16:22.646325Z'
+			SAMPLE BY 5m
+		) n ON c.hostname = n.hostname AND c.bucket = n.bucket
+		ORDER BY c.hostname, c.bucket`,
+		},
+		{
+			desc:               "raw scan",
+			generate:           func(q query.Query) { d.RawScan(q, 2) },
+			expectedHumanLabel: "QuestDB all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "QuestDB all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T06:16:22Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp, hostname, usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, usage_steal, usage_guest, usage_guest_nice
+		FROM cpu
+		WHERE hostname IN ('host_9', 'host_3')
+		  AND timestamp >= '1970-01-01T06:16:22.646325Z'
+		  AND timestamp < '1970-01-01T18:16:22.646325Z'
+		ORDER BY timestamp`,
+		},
+		{
+			desc:               "downsample",
+			generate:           d.DownsampleCPU,
+			expectedHumanLabel: "QuestDB mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "QuestDB mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedTable:      "cpu",
+			expectedSQLQuery: `
+		SELECT timestamp AS day, avg(usage_user) AS avg_usage_user, avg(usage_system) AS avg_usage_system, avg(usage_idle) AS avg_usage_idle, avg(usage_nice) AS avg_usage_nice, avg(usage_iowait) AS avg_usage_iowait, avg(usage_irq) AS avg_usage_irq, avg(usage_softirq) AS avg_usage_softirq, avg(usage_steal) AS avg_usage_steal, avg(usage_guest) AS avg_usage_guest, avg(usage_guest_nice) AS avg_usage_guest_nice
+		FROM cpu
+		WHERE timestamp >= '1970-01-01T00:00:00.000000Z'
+		  AND timestamp < '1970-01-03T00:00:00.000000Z'
+		SAMPLE BY 1d`,
+		},
+	}
+
+	runTestCases(t, b, cases)
+}
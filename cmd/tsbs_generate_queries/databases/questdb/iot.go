@@ -0,0 +1,283 @@
+package questdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// IoT produces QuestDB-specific queries for all the iot query types. The
+// truck tags are columns of the readings and diagnostics tables, so no
+// joins are needed. Nested buckets use timestamp_floor, as SAMPLE BY only
+// applies to tables with a designated timestamp.
+type IoT struct {
+	*iot.Core
+	*BaseGenerator
+}
+
+// getTruckWhereString gets multiple random trucks and creates a WHERE SQL statement for their names.
+func (i *IoT) getTruckWhereString(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	panicIfErr(err)
+	return fmt.Sprintf("name IN ('%s')", strings.Join(names, "', '"))
+}
+
+// LastLocByTruck finds the truck location for nTrucks.
+func (i *IoT) LastLocByTruck(qi query.Query, nTrucks int) {
+	sql := fmt.Sprintf(`SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE %s
+		LATEST ON timestamp PARTITION BY name`,
+		i.getTruckWhereString(nTrucks))
+
+	humanLabel := "QuestDB last location by specific truck"
+	humanDesc := fmt.Sprintf("%s: random %4d trucks", humanLabel, nTrucks)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// LastLocPerTruck finds all the truck locations along with truck and driver names.
+func (i *IoT) LastLocPerTruck(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE name IS NOT NULL
+		AND fleet = '%s'
+		LATEST ON timestamp PARTITION BY name`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB last location per truck"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%).
+func (i *IoT) TrucksWithLowFuel(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, fuel_state
+		FROM (
+			SELECT *
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE fuel_state < 0.1`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB trucks with low fuel"
+	humanDesc := fmt.Sprintf("%s: under 10 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%.
+func (i *IoT) TrucksWithHighLoad(qi query.Query) {
+	sql := fmt.Sprintf(`SELECT name, driver, current_load, load_capacity
+		FROM (
+			SELECT *
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = '%s'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE current_load / load_capacity > 0.9`,
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB trucks with high load"
+	humanDesc := fmt.Sprintf("%s: over 90 percent", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window.
+func (i *IoT) StationaryTrucks(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.StationaryDuration)
+	sql := fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE timestamp >= '%s' AND timestamp < '%s'
+			AND name IS NOT NULL
+			AND fleet = '%s'
+			GROUP BY name, driver)
+		WHERE mean_velocity < 1`,
+		interval.Start().Format(timeFmt),
+		interval.End().Format(timeFmt),
+		i.GetRandomFleet())
+
+	humanLabel := "QuestDB stationary trucks"
+	humanDesc := fmt.Sprintf("%s: with low avg velocity in last 10 minutes", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.LongDrivingSessionDuration)
+	sql := i.drivingSessionsSQL(interval.Start(), interval.End(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+		tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+
+	humanLabel := "QuestDB trucks with longer driving sessions"
+	humanDesc := fmt.Sprintf("%s: stopped less than 20 mins in 4 hour period", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.DailyDrivingDuration)
+	sql := i.drivingSessionsSQL(interval.Start(), interval.End(),
+		// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+		tenMinutePeriods(35, iot.DailyDrivingDuration))
+
+	humanLabel := "QuestDB trucks with longer daily sessions"
+	humanDesc := fmt.Sprintf("%s: drove more than 10 hours in the last 24 hours", humanLabel)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// drivingSessionsSQL selects the trucks of a random fleet that were driving
+// for more than the given number of ten minute periods between start and end.
+func (i *IoT) drivingSessionsSQL(start, end time.Time, periods int) string {
+	return fmt.Sprintf(`SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS driving_periods
+			FROM (
+				SELECT timestamp_floor('10m', timestamp) AS ten_minutes, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '%s' AND timestamp < '%s'
+				AND name IS NOT NULL
+				AND fleet = '%s'
+				GROUP BY ten_minutes, name, driver)
+			WHERE mean_velocity > 1
+			GROUP BY name, driver)
+		WHERE driving_periods > %d`,
+		start.Format(timeFmt),
+		end.Format(timeFmt),
+		i.GetRandomFleet(),
+		periods)
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel consumption per fleet.
+func (i *IoT) AvgVsProjectedFuelConsumption(qi query.Query) {
+	sql := `SELECT fleet, avg(fuel_consumption) AS avg_fuel_consumption,
+		avg(nominal_fuel_consumption) AS projected_fuel_consumption
+		FROM readings
+		WHERE velocity > 1
+		AND fleet IS NOT NULL
+		AND nominal_fuel_consumption IS NOT NULL
+		AND name IS NOT NULL
+		GROUP BY fleet`
+
+	humanLabel := "QuestDB average vs projected fuel consumption per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver.
+func (i *IoT) AvgDailyDrivingDuration(qi query.Query) {
+	sql := `SELECT fleet, name, driver, avg(hours) AS avg_daily_hours
+		FROM (
+			SELECT timestamp_floor('d', ten_minutes) AS day, fleet, name, driver, count() / 6 AS hours
+			FROM (
+				SELECT timestamp_floor('10m', timestamp) AS ten_minutes, fleet, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE name IS NOT NULL
+				GROUP BY ten_minutes, fleet, name, driver)
+			WHERE mean_velocity > 1
+			GROUP BY day, fleet, name, driver)
+		GROUP BY fleet, name, driver`
+
+	humanLabel := "QuestDB average driver driving duration per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgDailyDrivingSession finds the average driving session without stopping per driver per day.
+func (i *IoT) AvgDailyDrivingSession(qi query.Query) {
+	sql := `SELECT name, timestamp_floor('d', start) AS day, avg(datediff('m', start, stop)) AS duration_minutes
+		FROM (
+			SELECT name, ten_minutes AS start, lead(ten_minutes) OVER (PARTITION BY name ORDER BY ten_minutes) AS stop, mean_velocity
+			FROM (
+				SELECT name, ten_minutes, mean_velocity, lag(mean_velocity) OVER (PARTITION BY name ORDER BY ten_minutes) AS prev_velocity
+				FROM (
+					SELECT timestamp_floor('10m', timestamp) AS ten_minutes, name, avg(velocity) AS mean_velocity
+					FROM readings
+					WHERE name IS NOT NULL
+					GROUP BY ten_minutes, name))
+			WHERE (mean_velocity > 5) <> (prev_velocity > 5))
+		WHERE mean_velocity > 5
+		GROUP BY name, day
+		ORDER BY name, day`
+
+	humanLabel := "QuestDB average driver driving session without stopping per day"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// AvgLoad finds the average load per truck model per fleet.
+func (i *IoT) AvgLoad(qi query.Query) {
+	sql := `SELECT fleet, model, load_capacity, avg(avg_load / load_capacity) AS avg_load_percentage
+		FROM (
+			SELECT name, fleet, model, load_capacity, avg(current_load) AS avg_load
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			GROUP BY name, fleet, model, load_capacity)
+		GROUP BY fleet, model, load_capacity`
+
+	humanLabel := "QuestDB average load per truck model per fleet"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model.
+func (i *IoT) DailyTruckActivity(qi query.Query) {
+	sql := `SELECT fleet, model, day, count() / 144.0 AS daily_activity
+		FROM (
+			SELECT timestamp_floor('d', timestamp) AS day, timestamp_floor('10m', timestamp) AS ten_minutes, name, fleet, model, avg(status) AS mean_status
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			GROUP BY day, ten_minutes, name, fleet, model)
+		WHERE mean_status < 1
+		GROUP BY fleet, model, day
+		ORDER BY day`
+
+	humanLabel := "QuestDB daily truck activity per fleet per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// TruckBreakdownFrequency calculates the amount of times a truck model broke down in the last period.
+func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
+	sql := `SELECT model, count() AS breakdowns
+		FROM (
+			SELECT model, breakdown_ratio, lead(breakdown_ratio) OVER (PARTITION BY name ORDER BY ten_minutes) AS next_breakdown_ratio
+			FROM (
+				SELECT timestamp_floor('10m', timestamp) AS ten_minutes, name, model, avg(CASE WHEN status = 0 THEN 1.0 ELSE 0.0 END) AS breakdown_ratio
+				FROM diagnostics
+				WHERE name IS NOT NULL
+				GROUP BY ten_minutes, name, model))
+		WHERE breakdown_ratio < 0.5 AND next_breakdown_ratio >= 0.5
+		GROUP BY model`
+
+	humanLabel := "QuestDB truck breakdown frequency per model"
+	humanDesc := humanLabel
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
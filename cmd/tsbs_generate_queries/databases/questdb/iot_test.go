@@ -0,0 +1,164 @@
+package questdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIoTQueries(t *testing.T) {
+	b := &BaseGenerator{}
+	start := time.Unix(0, 0)
+	end := start.Add(48 * time.Hour)
+	ig, err := b.NewIoT(start, end, testScale)
+	if err != nil {
+		t.Fatalf("Error while creating iot generator")
+	}
+	i := ig.(*IoT)
+
+	cases := []testCase{
+		{
+			desc:               "last loc by truck",
+			generate:           func(q query.Query) { i.LastLocByTruck(q, 2) },
+			expectedHumanLabel: "QuestDB last location by specific truck",
+			expectedHumanDesc:  "QuestDB last location by specific truck: random    2 trucks",
+			expectedTable:      "readings",
+			expectedSQLQuery: `SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE name IN ('truck_5', 'truck_9')
+		LATEST ON timestamp PARTITION BY name`,
+		},
+		{
+			desc:               "last loc per truck",
+			generate:           i.LastLocPerTruck,
+			expectedHumanLabel: "QuestDB last location per truck",
+			expectedHumanDesc:  "QuestDB last location per truck",
+			expectedTable:      "readings",
+			expectedSQLQuery: `SELECT name, driver, longitude, latitude
+		FROM readings
+		WHERE name IS NOT NULL
+		AND fleet = 'South'
+		LATEST ON timestamp PARTITION BY name`,
+		},
+		{
+			desc:               "trucks with low fuel",
+			generate:           i.TrucksWithLowFuel,
+			expectedHumanLabel: "QuestDB trucks with low fuel",
+			expectedHumanDesc:  "QuestDB trucks with low fuel: under 10 percent",
+			expectedTable:      "diagnostics",
+			expectedSQLQuery: `SELECT name, driver, fuel_state
+		FROM (
+			SELECT *
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = 'South'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE fuel_state < 0.1`,
+		},
+		{
+			desc:               "trucks with high load",
+			generate:           i.TrucksWithHighLoad,
+			expectedHumanLabel: "QuestDB trucks with high load",
+			expectedHumanDesc:  "QuestDB trucks with high load: over 90 percent",
+			expectedTable:      "diagnostics",
+			expectedSQLQuery: `SELECT name, driver, current_load, load_capacity
+		FROM (
+			SELECT *
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			AND fleet = 'South'
+			LATEST ON timestamp PARTITION BY name)
+		WHERE current_load / load_capacity > 0.9`,
+		},
+		{
+			desc:               "stationary trucks",
+			generate:           i.StationaryTrucks,
+			expectedHumanLabel: "QuestDB stationary trucks",
+			expectedHumanDesc:  "QuestDB stationary trucks: with low avg velocity in last 10 minutes",
+			expectedTable:      "readings",
+			expectedSQLQuery: `SELECT name, driver
+		FROM (
+			SELECT name, driver, avg(velocity) AS mean_velocity
+			FROM readings
+			WHERE timestamp >= '1970-01-01T23:36:22.646325Z' AND timestamp < '1970-01-01T23:46:22.646325Z'
+			AND name IS NOT NULL
+			AND fleet = 'West'
+			GROUP BY name, driver)
+		WHERE mean_velocity < 1`,
+		},
+		{
+			desc:               "trucks with long driving sessions",
+			generate:           i.TrucksWithLongDrivingSessions,
+			expectedHumanLabel: "QuestDB trucks with longer driving sessions",
+			expectedHumanDesc:  "QuestDB trucks with longer driving sessions: stopped less than 20 mins in 4 hour period",
+			expectedTable:      "readings",
+			expectedSQLQuery: `SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS driving_periods
+			FROM (
+				SELECT timestamp_floor('10m', timestamp) AS ten_minutes, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '1970-01-01T06:16:22.646325Z' AND timestamp < '1970-01-01T10:16:22.646325Z'
+				AND name IS NOT NULL
+				AND fleet = 'West'
+				GROUP BY ten_minutes, name, driver)
+			WHERE mean_velocity > 1
+			GROUP BY name, driver)
+		WHERE driving_periods > 22`,
+		},
+		{
+			desc:               "trucks with long daily sessions",
+			generate:           i.TrucksWithLongDailySessions,
+			expectedHumanLabel: "QuestDB trucks with longer daily sessions",
+			expectedHumanDesc:  "QuestDB trucks with longer daily sessions: drove more than 10 hours in the last 24 hours",
+			expectedTable:      "readings",
+			expectedSQLQuery: `SELECT name, driver
+		FROM (
+			SELECT name, driver, count() AS driving_periods
+			FROM (
+				SELECT timestamp_floor('10m', timestamp) AS ten_minutes, name, driver, avg(velocity) AS mean_velocity
+				FROM readings
+				WHERE timestamp >= '1970-01-01T18:16:22.646325Z' AND timestamp < '1970-01-02T18:16:22.646325Z'
+				AND name IS NOT NULL
+				AND fleet = 'West'
+				GROUP BY ten_minutes, name, driver)
+			WHERE mean_velocity > 1
+			GROUP BY name, driver)
+		WHERE driving_periods > 60`,
+		},
+		{
+			desc:               "avg load",
+			generate:           i.AvgLoad,
+			expectedHumanLabel: "QuestDB average load per truck model per fleet",
+			expectedHumanDesc:  "QuestDB average load per truck model per fleet",
+			expectedTable:      "diagnostics",
+			expectedSQLQuery: `SELECT fleet, model, load_capacity, avg(avg_load / load_capacity) AS avg_load_percentage
+		FROM (
+			SELECT name, fleet, model, load_capacity, avg(current_load) AS avg_load
+			FROM diagnostics
+			WHERE name IS NOT NULL
+			GROUP BY name, fleet, model, load_capacity)
+		GROUP BY fleet, model, load_capacity`,
+		},
+	}
+
+	runTestCases(t, b, cases)
+}
+
+func TestTenMinutePeriods(t *testing.T) {
+	cases := []struct {
+		minutesPerHour float64
+		duration       time.Duration
+		result         int
+	}{
+		{minutesPerHour: 5, duration: 4 * time.Hour, result: 22},
+		{minutesPerHour: 35, duration: 24 * time.Hour, result: 60},
+	}
+
+	for _, c := range cases {
+		if got := tenMinutePeriods(c.minutesPerHour, c.duration); got != c.result {
+			t.Errorf("incorrect result for %.2f minutes per hour, duration %s: got %d want %d", c.minutesPerHour, c.duration.String(), got, c.result)
+		}
+	}
+}
@@ -168,3 +168,32 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	siriql := fmt.Sprintf("select filter(> 90) from `usage_user` %s between '%s' and '%s'", whereHosts, interval.StartString(), interval.EndString())
 	d.fillInQuery(qi, humanLabel, humanDesc, siriql)
 }
+
+// RawScan selects the raw points of all metrics in the group `cpu` of nhosts hosts
+// over a random 12 hour window, e.g. in pseudo-SQL:
+//
+// select * from (`groupHost1` | ...) & `cpu` between 'time1' and 'time2'
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	whereMetrics := "`cpu`"
+	whereHosts := d.getHostWhereString(nHosts)
+
+	humanLabel := devops.GetRawScanLabel("SiriDB", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	siriql := fmt.Sprintf("select * from %s & %s between '%s' and '%s'", whereHosts, whereMetrics, interval.StartString(), interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, siriql)
+}
+
+// DownsampleCPU selects the AVG of all metrics in the group `cpu` per day over the
+// entire dataset, e.g. in pseudo-SQL:
+//
+// select mean(1d) from `cpu` between 'time1' and 'time2'
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	whereMetrics := "`cpu`"
+
+	humanLabel := devops.GetDownsampleLabel("SiriDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	siriql := fmt.Sprintf("select mean(1d) from %s between '%s' and '%s'", whereMetrics, d.Interval.StartString(), d.Interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, siriql)
+}
@@ -321,6 +321,57 @@ func TestHighCPUForHosts(t *testing.T) {
 	runTestCases(t, testFunc, start, end, cases)
 }
 
+func TestRawScan(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:    "zero hosts",
+			input:   0,
+			fail:    true,
+			failMsg: "number of hosts cannot be < 1; got 0",
+		},
+		{
+			desc:               "2 hosts",
+			input:              2,
+			expectedHumanLabel: "SiriDB all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "SiriDB all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T00:54:10Z",
+			expectedQuery:      "select * from (`host_3`|`host_5`) & `cpu` between '1970-01-01T00:54:10Z' and '1970-01-01T12:54:10Z'",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.RawScan(q, c.input)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(devops.RawScanDuration).Add(time.Hour)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
+func TestDownsampleCPU(t *testing.T) {
+	cases := []testCase{
+		{
+			desc:               "happy path",
+			expectedHumanLabel: "SiriDB mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "SiriDB mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedQuery:      "select mean(1d) from `cpu` between '1970-01-01T00:00:00Z' and '1970-01-04T00:00:00Z'",
+		},
+	}
+
+	testFunc := func(d *Devops, c testCase) query.Query {
+		q := d.GenerateEmptyQuery()
+		d.DownsampleCPU(q)
+		return q
+	}
+
+	start := time.Unix(0, 0)
+	end := start.Add(3 * devops.DownsampleBucket)
+
+	runTestCases(t, testFunc, start, end, cases)
+}
+
 func TestDevopsFillInQuery(t *testing.T) {
 	humanLabel := "this is my label"
 	humanDesc := "and now my description"
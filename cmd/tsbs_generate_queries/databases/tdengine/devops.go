@@ -0,0 +1,204 @@
+package tdengine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Devops produces TDengine-specific queries for all the devops query types.
+// Every measurement is a super table with the host tags as its tags, rows
+// are bucketed with INTERVAL and grouped per host with PARTITION BY. The
+// database has nanosecond precision, so times are given as Unix nanoseconds.
+type Devops struct {
+	*BaseGenerator
+	*devops.Core
+}
+
+// getSelectAggClauses builds specified aggregate function clauses for
+// a set of column idents, e.g. max(usage_user) AS max_usage_user
+func (d *Devops) getSelectAggClauses(aggFunc string, idents []string) []string {
+	selectAggClauses := make([]string, len(idents))
+	for i, ident := range idents {
+		selectAggClauses[i] = fmt.Sprintf("%[1]s(%[2]s) AS %[1]s_%[2]s", aggFunc, ident)
+	}
+	return selectAggClauses
+}
+
+// getHostWhereString gets multiple random hostnames and creates a WHERE SQL
+// statement for these hostnames.
+func (d *Devops) getHostWhereString(nHosts int) string {
+	hostnames, err := d.GetRandomHosts(nHosts)
+	databases.PanicIfErr(err)
+	return fmt.Sprintf("hostname IN ('%s')", strings.Join(hostnames, "', '"))
+}
+
+// MaxAllCPU selects the MAX of all metrics under 'cpu' per hour for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT MAX(metric1), ..., MAX(metricN)
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour ORDER BY hour
+func (d *Devops) MaxAllCPU(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.MaxAllDuration)
+	selectClauses := d.getSelectAggClauses("max", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`SELECT _wstart AS hour, %s FROM cpu WHERE %s AND ts >= %d AND ts < %d INTERVAL(1h)`,
+		strings.Join(selectClauses, ", "),
+		d.getHostWhereString(nHosts),
+		interval.StartUnixNano(),
+		interval.EndUnixNano())
+
+	humanLabel := devops.GetMaxAllLabel("TDengine", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByTimeAndPrimaryTag selects the AVG of numMetrics metrics under 'cpu' per device per hour for a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) GroupByTimeAndPrimaryTag(qi query.Query, numMetrics int) {
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.DoubleGroupByDuration)
+	selectClauses := make([]string, numMetrics)
+	for i, m := range metrics {
+		selectClauses[i] = fmt.Sprintf("avg(%[1]s) AS mean_%[1]s", m)
+	}
+
+	sql := fmt.Sprintf(`SELECT _wstart AS hour, hostname, %s FROM cpu WHERE ts >= %d AND ts < %d PARTITION BY hostname INTERVAL(1h) ORDER BY hour, hostname`,
+		strings.Join(selectClauses, ", "),
+		interval.StartUnixNano(),
+		interval.EndUnixNano())
+
+	humanLabel := devops.GetDoubleGroupByLabel("TDengine", numMetrics)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByOrderByLimit populates a query.Query that has a time WHERE clause, that groups by a truncated date, orders by that date, and takes a limit:
+// SELECT time_bucket('1 minute', time) AS t, MAX(cpu) FROM cpu
+// WHERE time < '$TIME'
+// GROUP BY t ORDER BY t DESC
+// LIMIT $LIMIT
+func (d *Devops) GroupByOrderByLimit(qi query.Query) {
+	interval := d.Interval.MustRandWindow(time.Hour)
+
+	sql := fmt.Sprintf(`SELECT _wstart AS minute, max(usage_user) AS max_usage_user FROM cpu WHERE ts < %d INTERVAL(1m) ORDER BY minute DESC LIMIT 5`,
+		interval.EndUnixNano())
+
+	humanLabel := "TDengine max cpu over last 5 min-intervals (random end)"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.EndString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// HighCPUForHosts populates a query that gets CPU metrics when the CPU has high
+// usage between a time period for a number of hosts (if 0, it will search all hosts),
+// e.g. in pseudo-SQL:
+//
+// SELECT * FROM cpu
+// WHERE usage_user > 90.0
+// AND time >= '$TIME_START' AND time < '$TIME_END'
+// AND (hostname = '$HOST' OR hostname = '$HOST2'...)
+func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUDuration)
+	hostWhereClause := ""
+	if nHosts > 0 {
+		hostWhereClause = " AND " + d.getHostWhereString(nHosts)
+	}
+
+	sql := fmt.Sprintf(`SELECT * FROM cpu WHERE usage_user > 90.0 AND ts >= %d AND ts < %d%s`,
+		interval.StartUnixNano(),
+		interval.EndUnixNano(),
+		hostWhereClause)
+
+	humanLabel, err := devops.GetHighCPULabel("TDengine", nHosts)
+	databases.PanicIfErr(err)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// LastPointPerHost finds the last row for every host in the dataset, served
+// from the last row cache of the sub-tables if it is enabled.
+func (d *Devops) LastPointPerHost(qi query.Query) {
+	sql := `SELECT hostname, LAST_ROW(*) FROM cpu PARTITION BY hostname ORDER BY hostname`
+
+	humanLabel := "TDengine last row per host"
+	humanDesc := humanLabel
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// GroupByTime selects the MAX for numMetrics metrics under 'cpu',
+// per minute for nhosts hosts,
+// e.g. in pseudo-SQL:
+//
+// SELECT minute, max(metric1), ..., max(metricN)
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute ORDER BY minute ASC
+func (d *Devops) GroupByTime(qi query.Query, nHosts, numMetrics int, timeRange time.Duration) {
+	interval := d.Interval.MustRandWindow(timeRange)
+	metrics, err := devops.GetCPUMetricsSlice(numMetrics)
+	databases.PanicIfErr(err)
+	selectClauses := d.getSelectAggClauses("max", metrics)
+
+	sql := fmt.Sprintf(`SELECT _wstart AS minute, %s FROM cpu WHERE %s AND ts >= %d AND ts < %d INTERVAL(1m)`,
+		strings.Join(selectClauses, ", "),
+		d.getHostWhereString(nHosts),
+		interval.StartUnixNano(),
+		interval.EndUnixNano())
+
+	humanLabel := fmt.Sprintf("TDengine %d cpu metric(s), random %4d hosts, random %s by 1m", numMetrics, nHosts, timeRange)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	sql := fmt.Sprintf(`SELECT ts, hostname, %s FROM cpu WHERE %s AND ts >= %d AND ts < %d ORDER BY ts`,
+		strings.Join(devops.GetAllCPUMetrics(), ", "),
+		d.getHostWhereString(nHosts),
+		interval.StartUnixNano(),
+		interval.EndUnixNano())
+
+	humanLabel := devops.GetRawScanLabel("TDengine", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectAggClauses("avg", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`SELECT _wstart AS day, %s FROM cpu WHERE ts >= %d AND ts < %d INTERVAL(1d)`,
+		strings.Join(selectClauses, ", "),
+		d.Interval.StartUnixNano(),
+		d.Interval.EndUnixNano())
+
+	humanLabel := devops.GetDownsampleLabel("TDengine")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
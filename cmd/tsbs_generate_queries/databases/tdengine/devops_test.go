@@ -0,0 +1,126 @@
+package tdengine
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestDevopsQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fn                 func(d *Devops, q query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedSQLQuery   string
+	}{
+		{
+			desc:               "GroupByTime",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTime(q, 2, 2, time.Hour) },
+			expectedHumanLabel: "TDengine 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TDengine 2 cpu metric(s), random    2 hosts, random 1h0m0s by 1m: 1970-01-02T02:16:22Z",
+			expectedSQLQuery: "SELECT _wstart AS minute, max(usage_user) AS max_usage_user, max(usage_system) AS max_usage_system " +
+				"FROM cpu WHERE hostname IN ('host_9', 'host_3') AND ts >= 94582646325489 AND ts < 98182646325489 INTERVAL(1m)",
+		},
+		{
+			desc:               "GroupByOrderByLimit",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByOrderByLimit(q) },
+			expectedHumanLabel: "TDengine max cpu over last 5 min-intervals (random end)",
+			expectedHumanDesc:  "TDengine max cpu over last 5 min-intervals (random end): 1970-01-01T12:37:12Z",
+			expectedSQLQuery:   "SELECT _wstart AS minute, max(usage_user) AS max_usage_user FROM cpu WHERE ts < 45432342805883 INTERVAL(1m) ORDER BY minute DESC LIMIT 5",
+		},
+		{
+			desc:               "GroupByTimeAndPrimaryTag",
+			fn:                 func(d *Devops, q query.Query) { d.GroupByTimeAndPrimaryTag(q, 2) },
+			expectedHumanLabel: "TDengine mean of 2 metrics, all hosts, random 12h0m0s by 1h",
+			expectedHumanDesc:  "TDengine mean of 2 metrics, all hosts, random 12h0m0s by 1h: 1970-01-02T02:17:45Z",
+			expectedSQLQuery: "SELECT _wstart AS hour, hostname, avg(usage_user) AS mean_usage_user, avg(usage_system) AS mean_usage_system " +
+				"FROM cpu WHERE ts >= 94665311177487 AND ts < 137865311177487 PARTITION BY hostname INTERVAL(1h) ORDER BY hour, hostname",
+		},
+		{
+			desc:               "MaxAllCPU",
+			fn:                 func(d *Devops, q query.Query) { d.MaxAllCPU(q, 2) },
+			expectedHumanLabel: "TDengine max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h",
+			expectedHumanDesc:  "TDengine max of all CPU metrics, random    2 hosts, random 8h0m0s by 1h: 1970-01-01T21:23:08Z",
+			expectedSQLQuery: "SELECT _wstart AS hour, max(usage_user) AS max_usage_user, max(usage_system) AS max_usage_system, " +
+				"max(usage_idle) AS max_usage_idle, max(usage_nice) AS max_usage_nice, max(usage_iowait) AS max_usage_iowait, " +
+				"max(usage_irq) AS max_usage_irq, max(usage_softirq) AS max_usage_softirq, max(usage_steal) AS max_usage_steal, " +
+				"max(usage_guest) AS max_usage_guest, max(usage_guest_nice) AS max_usage_guest_nice " +
+				"FROM cpu WHERE hostname IN ('host_5', 'host_1') AND ts >= 76988303546563 AND ts < 105788303546563 INTERVAL(1h)",
+		},
+		{
+			desc:               "LastPointPerHost",
+			fn:                 func(d *Devops, q query.Query) { d.LastPointPerHost(q) },
+			expectedHumanLabel: "TDengine last row per host",
+			expectedHumanDesc:  "TDengine last row per host",
+			expectedSQLQuery:   "SELECT hostname, LAST_ROW(*) FROM cpu PARTITION BY hostname ORDER BY hostname",
+		},
+		{
+			desc:               "HighCPUForHosts all hosts",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 0) },
+			expectedHumanLabel: "TDengine CPU over threshold, all hosts",
+			expectedHumanDesc:  "TDengine CPU over threshold, all hosts: 1970-01-02T06:57:58Z",
+			expectedSQLQuery:   "SELECT * FROM cpu WHERE usage_user > 90.0 AND ts >= 111478487617828 AND ts < 154678487617828",
+		},
+		{
+			desc:               "HighCPUForHosts 1 host",
+			fn:                 func(d *Devops, q query.Query) { d.HighCPUForHosts(q, 1) },
+			expectedHumanLabel: "TDengine CPU over threshold, 1 host(s)",
+			expectedHumanDesc:  "TDengine CPU over threshold, 1 host(s): 1970-01-01T01:23:03Z",
+			expectedSQLQuery:   "SELECT * FROM cpu WHERE usage_user > 90.0 AND ts >= 4983975214056 AND ts < 48183975214056 AND hostname IN ('host_2')",
+		},
+		{
+			desc:               "RawScan",
+			fn:                 func(d *Devops, q query.Query) { d.RawScan(q, 2) },
+			expectedHumanLabel: "TDengine all CPU metrics, raw points, random    2 hosts, random 12h0m0s",
+			expectedHumanDesc:  "TDengine all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T03:08:59Z",
+			expectedSQLQuery: "SELECT ts, hostname, usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, " +
+				"usage_steal, usage_guest, usage_guest_nice " +
+				"FROM cpu WHERE hostname IN ('host_5', 'host_8') AND ts >= 11339080812606 AND ts < 54539080812606 ORDER BY ts",
+		},
+		{
+			desc:               "DownsampleCPU",
+			fn:                 func(d *Devops, q query.Query) { d.DownsampleCPU(q) },
+			expectedHumanLabel: "TDengine mean of all CPU metrics, all hosts, entire dataset by 24h0m0s",
+			expectedHumanDesc:  "TDengine mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z",
+			expectedSQLQuery: "SELECT _wstart AS day, avg(usage_user) AS avg_usage_user, avg(usage_system) AS avg_usage_system, " +
+				"avg(usage_idle) AS avg_usage_idle, avg(usage_nice) AS avg_usage_nice, avg(usage_iowait) AS avg_usage_iowait, " +
+				"avg(usage_irq) AS avg_usage_irq, avg(usage_softirq) AS avg_usage_softirq, avg(usage_steal) AS avg_usage_steal, " +
+				"avg(usage_guest) AS avg_usage_guest, avg(usage_guest_nice) AS avg_usage_guest_nice " +
+				"FROM cpu WHERE ts >= 0 AND ts < 172800000000000 INTERVAL(1d)",
+		},
+	}
+
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	b := BaseGenerator{}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			qi := d.GenerateEmptyQuery()
+			c.fn(d, qi)
+			q := qi.(*query.TimescaleDB)
+
+			if got := string(q.HumanLabel); got != c.expectedHumanLabel {
+				t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, c.expectedHumanLabel)
+			}
+			if got := string(q.HumanDescription); got != c.expectedHumanDesc {
+				t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, c.expectedHumanDesc)
+			}
+			if got := string(q.Hypertable); got != "cpu" {
+				t.Errorf("incorrect table: got %s want cpu", got)
+			}
+			if got := string(q.SqlQuery); got != c.expectedSQLQuery {
+				t.Errorf("incorrect SQL query:\ngot\n%s\nwant\n%s", got, c.expectedSQLQuery)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package timescaledb
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/pkg/query"
 )
@@ -32,6 +36,20 @@ func (g *BaseGenerator) fillInQuery(qi query.Query, humanLabel, humanDesc, table
 	q.SqlQuery = []byte(sql)
 }
 
+// columnSelect returns the expression selecting a tag column from the tags table.
+func (g *BaseGenerator) columnSelect(column string) string {
+	if g.UseJSON {
+		return fmt.Sprintf("tagset->>'%[1]s'", column)
+	}
+
+	return column
+}
+
+// withAlias returns the expression selecting a tag column aliased to its name.
+func (g *BaseGenerator) withAlias(column string) string {
+	return fmt.Sprintf("%s AS %s", g.columnSelect(column), column)
+}
+
 // NewDevops creates a new devops use case query generator.
 func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryGenerator, error) {
 	core, err := devops.NewCore(start, end, scale)
@@ -63,3 +81,51 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return iot, nil
 }
+
+// NewK8s creates a new k8s use case query generator.
+func (g *BaseGenerator) NewK8s(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := k8s.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k8s := &K8s{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return k8s, nil
+}
+
+// NewFinance creates a new finance use case query generator.
+func (g *BaseGenerator) NewFinance(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := finance.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	finance := &Finance{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return finance, nil
+}
+
+// NewSmartMeter creates a new smart-meter use case query generator.
+func (g *BaseGenerator) NewSmartMeter(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := smartmeter.NewCore(start, end, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	smartMeter := &SmartMeter{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return smartMeter, nil
+}
@@ -255,3 +255,288 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
 	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
 }
+
+// getHostnameGroupClauses returns the column the named CTE over 'cpu' groups the
+// hosts by, the hostname field selected from it and the join resolving that field.
+func (d *Devops) getHostnameGroupClauses(cte string) (groupField, hostnameField, joinStr string) {
+	if !d.UseJSON && !d.UseTags {
+		return "hostname", "hostname", ""
+	}
+	hostnameField = "tags.hostname"
+	if d.UseJSON {
+		hostnameField = "tags.tagset->>'hostname'"
+	}
+	return "tags_id", hostnameField, fmt.Sprintf("JOIN tags ON %s.tags_id = tags.id", cte)
+}
+
+// PercentileCPU selects the percentiles of the user CPU usage per host per hour for half a day,
+// e.g. in pseudo-SQL:
+//
+// SELECT hour, hostname, percentile_cont(0.5) WITHIN GROUP (ORDER BY usage_user), ...
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hour, hostname ORDER BY hour, hostname
+func (d *Devops) PercentileCPU(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.PercentileCPUDuration)
+	percentiles := devops.GetCPUPercentiles()
+
+	selectClauses := make([]string, len(percentiles))
+	pctClauses := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		pctClauses[i] = devops.GetPercentileName(p) + "_usage_user"
+		selectClauses[i] = fmt.Sprintf("percentile_cont(%g) WITHIN GROUP (ORDER BY usage_user) as %s", p, pctClauses[i])
+	}
+	groupField, hostnameField, joinStr := d.getHostnameGroupClauses("cpu_pct")
+
+	sql := fmt.Sprintf(`
+        WITH cpu_pct AS (
+          SELECT %s as hour, %s,
+          %s
+          FROM cpu
+          WHERE time >= '%s' AND time < '%s'
+          GROUP BY hour, %s
+        )
+        SELECT hour, %s, %s
+        FROM cpu_pct
+        %s
+        ORDER BY hour, %s`,
+		d.getTimeBucket(oneHour), groupField,
+		strings.Join(selectClauses, ", "),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		groupField,
+		hostnameField, strings.Join(pctClauses, ", "),
+		joinStr, hostnameField)
+
+	humanLabel := devops.GetPercentileCPULabel("TimescaleDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// TopKHostsByCPU selects the hosts with the highest mean user CPU usage in a time window,
+// e.g. in pseudo-SQL:
+//
+// SELECT hostname, avg(usage_user) AS mean_usage_user
+// FROM cpu
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY hostname ORDER BY mean_usage_user DESC
+// LIMIT $LIMIT
+func (d *Devops) TopKHostsByCPU(qi query.Query, limit int) {
+	interval := d.Interval.MustRandWindow(devops.TopKHostsDuration)
+	groupField, hostnameField, joinStr := d.getHostnameGroupClauses("cpu_avg")
+
+	sql := fmt.Sprintf(`
+        WITH cpu_avg AS (
+          SELECT %s, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '%s' AND time < '%s'
+          GROUP BY %s
+        )
+        SELECT %s, mean_usage_user
+        FROM cpu_avg
+        %s
+        ORDER BY mean_usage_user DESC
+        LIMIT %d`,
+		groupField,
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		groupField,
+		hostnameField,
+		joinStr,
+		limit)
+
+	humanLabel := devops.GetTopKHostsLabel("TimescaleDB", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// CounterRate computes the per-second rate of the counters of a measurement per host per minute,
+// treating a decreasing counter as reset to zero, e.g. in pseudo-SQL:
+//
+// SELECT minute, hostname, avg((counter1 - lag(counter1)) / (time - lag(time))), ...
+// FROM measurement
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY minute, hostname ORDER BY minute, hostname
+func (d *Devops) CounterRate(qi query.Query, measurement string) {
+	metrics, err := devops.GetCounterMetrics(measurement)
+	panicIfErr(err)
+	interval := d.Interval.MustRandWindow(devops.CounterRateDuration)
+
+	rateClauses := make([]string, len(metrics))
+	avgClauses := make([]string, len(metrics))
+	for i, m := range metrics {
+		rateClauses[i] = fmt.Sprintf("CASE WHEN %[1]s >= lag(%[1]s) OVER w THEN %[1]s - lag(%[1]s) OVER w ELSE %[1]s END "+
+			"/ extract(epoch FROM time - lag(time) OVER w) as rate_%[1]s", m)
+		avgClauses[i] = fmt.Sprintf("avg(rate_%[1]s) as rate_%[1]s", m)
+	}
+	cte := measurement + "_rate"
+	groupField, hostnameField, joinStr := d.getHostnameGroupClauses(cte)
+
+	sql := fmt.Sprintf(`
+        WITH %s AS (
+          SELECT time, %s,
+          %s
+          FROM %s
+          WHERE time >= '%s' AND time < '%s'
+          WINDOW w AS (PARTITION BY %s ORDER BY time)
+        )
+        SELECT %s AS minute, %s, %s
+        FROM %s
+        %s
+        GROUP BY minute, %s
+        ORDER BY minute, %s`,
+		cte, groupField,
+		strings.Join(rateClauses, ", "),
+		measurement,
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		groupField,
+		d.getTimeBucket(oneMinute), hostnameField, strings.Join(avgClauses, ", "),
+		cte,
+		joinStr,
+		hostnameField,
+		hostnameField)
+
+	humanLabel := devops.GetCounterRateLabel("TimescaleDB", measurement)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, measurement, sql)
+}
+
+// HighCPUAndMem finds the 5 minute buckets in which a host has both a high mean CPU usage
+// and a high mean memory usage, joining the 'cpu' and 'mem' tables,
+// e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), avg(mem.used_percent)
+// FROM cpu JOIN mem USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname
+// HAVING avg(cpu.usage_user) > 90.0 AND avg(mem.used_percent) > 80.0
+// ORDER BY bucket, hostname
+func (d *Devops) HighCPUAndMem(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.HighCPUMemDuration)
+	bucket := d.getTimeBucket(int(devops.JoinBucket.Seconds()))
+	groupField, hostnameField, joinStr := d.getHostnameGroupClauses("cpu_avg")
+
+	sql := fmt.Sprintf(`
+        WITH cpu_avg AS (
+          SELECT %[1]s as bucket, %[2]s, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '%[3]s' AND time < '%[4]s'
+          GROUP BY bucket, %[2]s
+        ), mem_avg AS (
+          SELECT %[1]s as bucket, %[2]s, avg(used_percent) as mean_used_percent
+          FROM mem
+          WHERE time >= '%[3]s' AND time < '%[4]s'
+          GROUP BY bucket, %[2]s
+        )
+        SELECT bucket, %[5]s, mean_usage_user, mean_used_percent
+        FROM cpu_avg
+        JOIN mem_avg USING (bucket, %[2]s)
+        %[6]s
+        WHERE mean_usage_user > %.1[7]f AND mean_used_percent > %.1[8]f
+        ORDER BY bucket, %[5]s`,
+		bucket, groupField,
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		hostnameField,
+		joinStr,
+		devops.HighCPUThreshold,
+		devops.HighMemThreshold)
+
+	humanLabel := devops.GetHighCPUMemLabel("TimescaleDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// CPUNginxCorrelation lines up the mean CPU usage of every host with the number of
+// requests its nginx served per 5 minutes, joining the 'cpu' and 'nginx' tables,
+// e.g. in pseudo-SQL:
+//
+// SELECT bucket, hostname, avg(cpu.usage_user), max(nginx.requests) - min(nginx.requests)
+// FROM cpu JOIN nginx USING (bucket, hostname)
+// WHERE time >= '$HOUR_START' AND time < '$HOUR_END'
+// GROUP BY bucket, hostname ORDER BY hostname, bucket
+func (d *Devops) CPUNginxCorrelation(qi query.Query) {
+	interval := d.Interval.MustRandWindow(devops.CPUNginxDuration)
+	bucket := d.getTimeBucket(int(devops.JoinBucket.Seconds()))
+	groupField, hostnameField, joinStr := d.getHostnameGroupClauses("cpu_avg")
+
+	sql := fmt.Sprintf(`
+        WITH cpu_avg AS (
+          SELECT %[1]s as bucket, %[2]s, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '%[3]s' AND time < '%[4]s'
+          GROUP BY bucket, %[2]s
+        ), nginx_requests AS (
+          SELECT %[1]s as bucket, %[2]s, max(requests) - min(requests) as requests
+          FROM nginx
+          WHERE time >= '%[3]s' AND time < '%[4]s'
+          GROUP BY bucket, %[2]s
+        )
+        SELECT bucket, %[5]s, mean_usage_user, requests
+        FROM cpu_avg
+        JOIN nginx_requests USING (bucket, %[2]s)
+        %[6]s
+        ORDER BY %[5]s, bucket`,
+		bucket, groupField,
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		hostnameField,
+		joinStr)
+
+	humanLabel := devops.GetCPUNginxLabel("TimescaleDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// RawScan selects all the CPU metrics of nHosts random hosts over a random 12 hour
+// window without aggregating them, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, usage_user, ..., usage_guest_nice
+// FROM cpu
+// WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+	groupField, _, _ := d.getHostnameGroupClauses(devops.TableName)
+
+	sql := fmt.Sprintf(`SELECT time, %s, %s
+        FROM cpu
+        WHERE %s AND time >= '%s' AND time < '%s'
+        ORDER BY time`,
+		groupField,
+		strings.Join(devops.GetAllCPUMetrics(), ", "),
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := devops.GetRawScanLabel("TimescaleDB", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// DownsampleCPU averages all the CPU metrics of all hosts per day over the entire
+// dataset, e.g. in pseudo-SQL:
+//
+// SELECT day, avg(usage_user), ..., avg(usage_guest_nice)
+// FROM cpu
+// WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	selectClauses := d.getSelectClausesAggMetrics("avg", devops.GetAllCPUMetrics())
+
+	sql := fmt.Sprintf(`SELECT %s AS day,
+        %s
+        FROM cpu
+        WHERE time >= '%s' AND time < '%s'
+        GROUP BY day ORDER BY day`,
+		d.getTimeBucket(int(devops.DownsampleBucket.Seconds())),
+		strings.Join(selectClauses, ", "),
+		d.Interval.Start().Format(goTimeFmt),
+		d.Interval.End().Format(goTimeFmt))
+
+	humanLabel := devops.GetDownsampleLabel("TimescaleDB")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
@@ -534,3 +534,293 @@ func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, hypertable,
 		t.Errorf("incorrect SQL query:\ndiff\n%s\ngot\n%s\nwant\n%s", diff.CharacterDiff(got, sqlQuery), got, sqlQuery)
 	}
 }
+
+func TestPercentileCPU(t *testing.T) {
+	cases := []struct {
+		desc             string
+		useJSON          bool
+		useTags          bool
+		expectedSQLQuery string
+	}{
+		{
+			desc: "no JSON or tags",
+			expectedSQLQuery: `
+        WITH cpu_pct AS (
+          SELECT time_bucket('3600 seconds', time) as hour, hostname,
+          percentile_cont(0.5) WITHIN GROUP (ORDER BY usage_user) as p50_usage_user, ` +
+				"percentile_cont(0.95) WITHIN GROUP (ORDER BY usage_user) as p95_usage_user, " +
+				`percentile_cont(0.99) WITHIN GROUP (ORDER BY usage_user) as p99_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY hour, hostname
+        )
+        SELECT hour, hostname, p50_usage_user, p95_usage_user, p99_usage_user
+        FROM cpu_pct
+        
+        ORDER BY hour, hostname`,
+		},
+		{
+			desc:    "use tags",
+			useTags: true,
+			expectedSQLQuery: `
+        WITH cpu_pct AS (
+          SELECT time_bucket('3600 seconds', time) as hour, tags_id,
+          percentile_cont(0.5) WITHIN GROUP (ORDER BY usage_user) as p50_usage_user, ` +
+				"percentile_cont(0.95) WITHIN GROUP (ORDER BY usage_user) as p95_usage_user, " +
+				`percentile_cont(0.99) WITHIN GROUP (ORDER BY usage_user) as p99_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY hour, tags_id
+        )
+        SELECT hour, tags.hostname, p50_usage_user, p95_usage_user, p99_usage_user
+        FROM cpu_pct
+        JOIN tags ON cpu_pct.tags_id = tags.id
+        ORDER BY hour, tags.hostname`,
+		},
+		{
+			desc:    "use JSON",
+			useJSON: true,
+			expectedSQLQuery: `
+        WITH cpu_pct AS (
+          SELECT time_bucket('3600 seconds', time) as hour, tags_id,
+          percentile_cont(0.5) WITHIN GROUP (ORDER BY usage_user) as p50_usage_user, ` +
+				"percentile_cont(0.95) WITHIN GROUP (ORDER BY usage_user) as p95_usage_user, " +
+				`percentile_cont(0.99) WITHIN GROUP (ORDER BY usage_user) as p99_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY hour, tags_id
+        )
+        SELECT hour, tags.tagset->>'hostname', p50_usage_user, p95_usage_user, p99_usage_user
+        FROM cpu_pct
+        JOIN tags ON cpu_pct.tags_id = tags.id
+        ORDER BY hour, tags.tagset->>'hostname'`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			s := time.Unix(0, 0)
+			e := s.Add(devops.PercentileCPUDuration).Add(time.Hour)
+			b := BaseGenerator{
+				UseJSON:       c.useJSON,
+				UseTags:       c.useTags,
+				UseTimeBucket: true,
+			}
+			dq, err := b.NewDevops(s, e, 10)
+			if err != nil {
+				t.Fatalf("Error while creating devops generator")
+			}
+			d := dq.(*Devops)
+
+			q := d.GenerateEmptyQuery()
+			d.PercentileCPU(q)
+			humanLabel := "TimescaleDB p50, p95, p99 of CPU usage per host, all hosts, random 12h0m0s by 1h"
+			verifyQuery(t, q, humanLabel, humanLabel+": 1970-01-01T00:16:22Z", "cpu", c.expectedSQLQuery)
+		})
+	}
+}
+
+func TestTopKHostsByCPU(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB top 10 hosts by mean CPU usage, random 1h0m0s"
+	expectedHumanDesc := "TimescaleDB top 10 hosts by mean CPU usage, random 1h0m0s: 1970-01-01T00:16:22Z"
+	expectedHypertable := "cpu"
+	expectedSQLQuery := `
+        WITH cpu_avg AS (
+          SELECT tags_id, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+          GROUP BY tags_id
+        )
+        SELECT tags.hostname, mean_usage_user
+        FROM cpu_avg
+        JOIN tags ON cpu_avg.tags_id = tags.id
+        ORDER BY mean_usage_user DESC
+        LIMIT 10`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.TopKHostsDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		UseTags: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.TopKHostsByCPU(q, 10)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedHypertable, expectedSQLQuery)
+}
+
+func TestCounterRate(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB per-second rate of net counters per host, all hosts, random 1h0m0s by 1m"
+	expectedHumanDesc := "TimescaleDB per-second rate of net counters per host, all hosts, random 1h0m0s by 1m: 1970-01-01T00:16:22Z"
+	expectedHypertable := "net"
+	expectedSQLQuery := `
+        WITH net_rate AS (
+          SELECT time, tags_id,
+          CASE WHEN bytes_sent >= lag(bytes_sent) OVER w THEN bytes_sent - lag(bytes_sent) OVER w ELSE bytes_sent END ` +
+		"/ extract(epoch FROM time - lag(time) OVER w) as rate_bytes_sent, " +
+		"CASE WHEN bytes_recv >= lag(bytes_recv) OVER w THEN bytes_recv - lag(bytes_recv) OVER w ELSE bytes_recv END " +
+		`/ extract(epoch FROM time - lag(time) OVER w) as rate_bytes_recv
+          FROM net
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+          WINDOW w AS (PARTITION BY tags_id ORDER BY time)
+        )
+        SELECT time_bucket('60 seconds', time) AS minute, tags.hostname, avg(rate_bytes_sent) as rate_bytes_sent, avg(rate_bytes_recv) as rate_bytes_recv
+        FROM net_rate
+        JOIN tags ON net_rate.tags_id = tags.id
+        GROUP BY minute, tags.hostname
+        ORDER BY minute, tags.hostname`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.CounterRateDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		UseTags:       true,
+		UseTimeBucket: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.CounterRate(q, devops.NetTableName)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedHypertable, expectedSQLQuery)
+}
+
+func TestHighCPUAndMem(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s"
+	expectedHumanDesc := expectedHumanLabel + ": 1970-01-01T00:16:22Z"
+	expectedSQLQuery := `
+        WITH cpu_avg AS (
+          SELECT time_bucket('300 seconds', time) as bucket, hostname, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY bucket, hostname
+        ), mem_avg AS (
+          SELECT time_bucket('300 seconds', time) as bucket, hostname, avg(used_percent) as mean_used_percent
+          FROM mem
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY bucket, hostname
+        )
+        SELECT bucket, hostname, mean_usage_user, mean_used_percent
+        FROM cpu_avg
+        JOIN mem_avg USING (bucket, hostname)
+        
+        WHERE mean_usage_user > 90.0 AND mean_used_percent > 80.0
+        ORDER BY bucket, hostname`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.HighCPUMemDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		UseTimeBucket: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.HighCPUAndMem(q)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, "cpu", expectedSQLQuery)
+}
+
+func TestCPUNginxCorrelation(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s"
+	expectedHumanDesc := expectedHumanLabel + ": 1970-01-01T00:16:22Z"
+	expectedSQLQuery := `
+        WITH cpu_avg AS (
+          SELECT time_bucket('300 seconds', time) as bucket, tags_id, avg(usage_user) as mean_usage_user
+          FROM cpu
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY bucket, tags_id
+        ), nginx_requests AS (
+          SELECT time_bucket('300 seconds', time) as bucket, tags_id, max(requests) - min(requests) as requests
+          FROM nginx
+          WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+          GROUP BY bucket, tags_id
+        )
+        SELECT bucket, tags.hostname, mean_usage_user, requests
+        FROM cpu_avg
+        JOIN nginx_requests USING (bucket, tags_id)
+        JOIN tags ON cpu_avg.tags_id = tags.id
+        ORDER BY tags.hostname, bucket`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.CPUNginxDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		UseTags:       true,
+		UseTimeBucket: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.CPUNginxCorrelation(q)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, "cpu", expectedSQLQuery)
+}
+
+func TestRawScan(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB all CPU metrics, raw points, random    1 hosts, random 12h0m0s"
+	expectedHumanDesc := expectedHumanLabel + ": 1970-01-01T00:16:22Z"
+	expectedSQLQuery := `SELECT time, hostname, usage_user, usage_system, usage_idle, usage_nice, usage_iowait, usage_irq, usage_softirq, usage_steal, usage_guest, usage_guest_nice
+        FROM cpu
+        WHERE (hostname = 'host_9') AND time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+        ORDER BY time`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.RawScanDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		UseTimeBucket: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.RawScan(q, 1)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, "cpu", expectedSQLQuery)
+}
+
+func TestDownsampleCPU(t *testing.T) {
+	expectedHumanLabel := "TimescaleDB mean of all CPU metrics, all hosts, entire dataset by 24h0m0s"
+	expectedHumanDesc := expectedHumanLabel + ": 1970-01-01T00:00:00Z"
+	expectedSQLQuery := `SELECT time_bucket('86400 seconds', time) AS day,
+        avg(usage_user) as avg_usage_user, avg(usage_system) as avg_usage_system, avg(usage_idle) as avg_usage_idle, ` +
+		"avg(usage_nice) as avg_usage_nice, avg(usage_iowait) as avg_usage_iowait, avg(usage_irq) as avg_usage_irq, " +
+		"avg(usage_softirq) as avg_usage_softirq, avg(usage_steal) as avg_usage_steal, avg(usage_guest) as avg_usage_guest, " +
+		`avg(usage_guest_nice) as avg_usage_guest_nice
+        FROM cpu
+        WHERE time >= '1970-01-01 00:00:00 +0000' AND time < '1970-01-04 00:00:00 +0000'
+        GROUP BY day ORDER BY day`
+	s := time.Unix(0, 0)
+	e := s.Add(3 * devops.DownsampleBucket)
+
+	b := BaseGenerator{
+		UseTimeBucket: true,
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.DownsampleCPU(q)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, "cpu", expectedSQLQuery)
+}
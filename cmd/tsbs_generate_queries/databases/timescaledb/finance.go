@@ -0,0 +1,83 @@
+package timescaledb
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Finance produces TimescaleDB-specific queries for all the finance query types.
+type Finance struct {
+	*finance.Core
+	*BaseGenerator
+}
+
+// OHLC calculates the open, high, low and close price and the traded volume
+// per minute of a random symbol in a time window.
+func (f *Finance) OHLC(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.OHLCDuration)
+	sql := fmt.Sprintf(`SELECT time_bucket('%d seconds', r.time) AS minute,
+			first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close,
+			sum(r.size) AS volume
+		FROM trade r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE r.time >= '%s' AND r.time < '%s'
+		AND t.%s = '%s'
+		GROUP BY minute
+		ORDER BY minute`,
+		int(finance.OHLCBucket.Seconds()),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		f.columnSelect("symbol"),
+		f.GetRandomSymbol())
+
+	humanLabel := "TimescaleDB OHLC bars per minute"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradeTableName, sql)
+}
+
+// VWAP calculates the volume weighted average price of every symbol of a random
+// exchange in a time window.
+func (f *Finance) VWAP(qi query.Query) {
+	interval := f.Interval.MustRandWindow(finance.VWAPDuration)
+	sql := fmt.Sprintf(`SELECT t.%s, sum(r.price * r.size) / sum(r.size) AS vwap, sum(r.size) AS volume
+		FROM trade r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE r.time >= '%s' AND r.time < '%s'
+		AND t.%s = '%s'
+		GROUP BY 1
+		ORDER BY 1`,
+		f.withAlias("symbol"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		f.columnSelect("exchange"),
+		f.GetRandomExchange())
+
+	humanLabel := "TimescaleDB VWAP per symbol"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.TradeTableName, sql)
+}
+
+// LastQuote fetches the last quote of every symbol of a random exchange.
+func (f *Finance) LastQuote(qi query.Query) {
+	exchange := f.GetRandomExchange()
+	sql := fmt.Sprintf(`SELECT t.%s, q.*
+		FROM tags t INNER JOIN LATERAL
+			(SELECT time, bid_price, bid_size, ask_price, ask_size
+			FROM quote q
+			WHERE q.tags_id = t.id
+			ORDER BY time DESC LIMIT 1) q ON true
+		WHERE t.%s = '%s'
+		ORDER BY 1`,
+		f.withAlias("symbol"),
+		f.columnSelect("exchange"),
+		exchange)
+
+	humanLabel := "TimescaleDB last quote per symbol"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, exchange)
+
+	f.fillInQuery(qi, humanLabel, humanDesc, finance.QuoteTableName, sql)
+}
@@ -0,0 +1,111 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestFinanceQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		useJSON            bool
+		fn                 func(*Finance, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "ohlc",
+			fn: func(f *Finance, q query.Query) {
+				f.OHLC(q)
+			},
+			expectedHumanLabel: "TimescaleDB OHLC bars per minute",
+			expectedHumanDesc:  "TimescaleDB OHLC bars per minute: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradeTableName,
+			expectedSQLQuery: `SELECT time_bucket('60 seconds', r.time) AS minute,
+			first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close,
+			sum(r.size) AS volume
+		FROM trade r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		AND t.symbol = 'ENDU'
+		GROUP BY minute
+		ORDER BY minute`,
+		},
+		{
+			desc:    "ohlc use json",
+			useJSON: true,
+			fn: func(f *Finance, q query.Query) {
+				f.OHLC(q)
+			},
+			expectedHumanLabel: "TimescaleDB OHLC bars per minute",
+			expectedHumanDesc:  "TimescaleDB OHLC bars per minute: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradeTableName,
+			expectedSQLQuery: `SELECT time_bucket('60 seconds', r.time) AS minute,
+			first(r.price, r.time) AS open, max(r.price) AS high, min(r.price) AS low, last(r.price, r.time) AS close,
+			sum(r.size) AS volume
+		FROM trade r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		AND t.tagset->>'symbol' = 'ENDU'
+		GROUP BY minute
+		ORDER BY minute`,
+		},
+		{
+			desc: "vwap",
+			fn: func(f *Finance, q query.Query) {
+				f.VWAP(q)
+			},
+			expectedHumanLabel: "TimescaleDB VWAP per symbol",
+			expectedHumanDesc:  "TimescaleDB VWAP per symbol: 1970-01-02T02:16:22Z",
+			expectedHypertable: finance.TradeTableName,
+			expectedSQLQuery: `SELECT t.symbol AS symbol, sum(r.price * r.size) / sum(r.size) AS vwap, sum(r.size) AS volume
+		FROM trade r
+		INNER JOIN tags t ON r.tags_id = t.id
+		WHERE r.time >= '1970-01-02 02:16:22.646325 +0000' AND r.time < '1970-01-02 03:16:22.646325 +0000'
+		AND t.exchange = 'XETRA'
+		GROUP BY 1
+		ORDER BY 1`,
+		},
+		{
+			desc: "last quote",
+			fn: func(f *Finance, q query.Query) {
+				f.LastQuote(q)
+			},
+			expectedHumanLabel: "TimescaleDB last quote per symbol",
+			expectedHumanDesc:  "TimescaleDB last quote per symbol: HKEX",
+			expectedHypertable: finance.QuoteTableName,
+			expectedSQLQuery: `SELECT t.symbol AS symbol, q.*
+		FROM tags t INNER JOIN LATERAL
+			(SELECT time, bid_price, bid_size, ask_price, ask_size
+			FROM quote q
+			WHERE q.tags_id = t.id
+			ORDER BY time DESC LIMIT 1) q ON true
+		WHERE t.exchange = 'HKEX'
+		ORDER BY 1`,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{UseJSON: c.useJSON}
+			g, err := b.NewFinance(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating finance generator")
+			}
+			f := g.(*Finance)
+			q := f.GenerateEmptyQuery()
+			c.fn(f, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+		})
+	}
+}
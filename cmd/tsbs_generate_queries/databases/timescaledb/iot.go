@@ -29,18 +29,6 @@ func NewIoT(start, end time.Time, scale int, g *BaseGenerator) *IoT {
 	}
 }
 
-func (i *IoT) columnSelect(column string) string {
-	if i.UseJSON {
-		return fmt.Sprintf("tagset->>'%[1]s'", column)
-	}
-
-	return column
-}
-
-func (i *IoT) withAlias(column string) string {
-	return fmt.Sprintf("%s AS %s", i.columnSelect(column), column)
-}
-
 func (i *IoT) getTrucksWhereWithNames(names []string) string {
 	nameClauses := []string{}
 	if i.UseJSON {
@@ -0,0 +1,89 @@
+package timescaledb
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces TimescaleDB-specific queries for all the k8s query types.
+type K8s struct {
+	*k8s.Core
+	*BaseGenerator
+}
+
+// TopPodsByCPU finds the pods of a namespace which used the most CPU time in a time window.
+func (k *K8s) TopPodsByCPU(qi query.Query, limit int) {
+	interval := k.Interval.MustRandWindow(k8s.TopPodsDuration)
+	sql := fmt.Sprintf(`SELECT t.%s, max(c.cpu_usage_seconds_total) - min(c.cpu_usage_seconds_total) AS cpu_seconds
+		FROM container c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE c.time >= '%s' AND c.time < '%s'
+		AND t.%s = '%s'
+		GROUP BY 1
+		ORDER BY cpu_seconds DESC
+		LIMIT %d`,
+		k.withAlias("pod"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		k.columnSelect("namespace"),
+		k.GetRandomNamespace(),
+		limit)
+
+	humanLabel := fmt.Sprintf("TimescaleDB top %d pods by CPU usage per namespace", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerTableName, sql)
+}
+
+// RestartsPerDeployment counts the container restarts per deployment of a namespace in a time window.
+func (k *K8s) RestartsPerDeployment(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.RestartsDuration)
+	sql := fmt.Sprintf(`SELECT deployment, sum(restarts) AS restarts
+		FROM (SELECT t.%s, t.%s, max(p.container_status_restarts_total) - min(p.container_status_restarts_total) AS restarts
+			FROM kube_pod p
+			INNER JOIN tags t ON p.tags_id = t.id
+			WHERE p.time >= '%s' AND p.time < '%s'
+			AND t.%s = '%s'
+			GROUP BY 1, 2) AS pod_restarts
+		GROUP BY deployment
+		ORDER BY restarts DESC, deployment`,
+		k.withAlias("deployment"),
+		k.withAlias("pod"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		k.columnSelect("namespace"),
+		k.GetRandomNamespace())
+
+	humanLabel := "TimescaleDB container restarts per deployment"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.KubePodTableName, sql)
+}
+
+// NodeSaturation calculates the CPU cores and memory used on each node per 5 minutes in a time window.
+func (k *K8s) NodeSaturation(qi query.Query) {
+	interval := k.Interval.MustRandWindow(k8s.NodeSaturationDuration)
+	bucketSeconds := int(k8s.NodeSaturationBucket.Seconds())
+	sql := fmt.Sprintf(`SELECT bucket, node, sum(cpu_seconds) / %d AS cpu_cores, sum(memory_bytes) AS memory_bytes
+		FROM (SELECT time_bucket('%d seconds', c.time) AS bucket, t.%s, c.tags_id,
+				max(c.cpu_usage_seconds_total) - min(c.cpu_usage_seconds_total) AS cpu_seconds,
+				avg(c.memory_working_set_bytes) AS memory_bytes
+			FROM container c
+			INNER JOIN tags t ON c.tags_id = t.id
+			WHERE c.time >= '%s' AND c.time < '%s'
+			GROUP BY 1, 2, 3) AS pod_usage
+		GROUP BY bucket, node
+		ORDER BY bucket, node`,
+		bucketSeconds,
+		bucketSeconds,
+		k.withAlias("node"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB node CPU and memory saturation"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	k.fillInQuery(qi, humanLabel, humanDesc, k8s.ContainerTableName, sql)
+}
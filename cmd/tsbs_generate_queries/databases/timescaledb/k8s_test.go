@@ -0,0 +1,113 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestK8sQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		useJSON            bool
+		fn                 func(*K8s, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "top pods by cpu",
+			fn: func(k *K8s, q query.Query) {
+				k.TopPodsByCPU(q, k8s.TopPodsLimit)
+			},
+			expectedHumanLabel: "TimescaleDB top 10 pods by CPU usage per namespace",
+			expectedHumanDesc:  "TimescaleDB top 10 pods by CPU usage per namespace: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerTableName,
+			expectedSQLQuery: `SELECT t.pod AS pod, max(c.cpu_usage_seconds_total) - min(c.cpu_usage_seconds_total) AS cpu_seconds
+		FROM container c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE c.time >= '1970-01-02 02:16:22.646325 +0000' AND c.time < '1970-01-02 03:16:22.646325 +0000'
+		AND t.namespace = 'ingress'
+		GROUP BY 1
+		ORDER BY cpu_seconds DESC
+		LIMIT 10`,
+		},
+		{
+			desc:    "top pods by cpu use json",
+			useJSON: true,
+			fn: func(k *K8s, q query.Query) {
+				k.TopPodsByCPU(q, k8s.TopPodsLimit)
+			},
+			expectedHumanLabel: "TimescaleDB top 10 pods by CPU usage per namespace",
+			expectedHumanDesc:  "TimescaleDB top 10 pods by CPU usage per namespace: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerTableName,
+			expectedSQLQuery: `SELECT t.tagset->>'pod' AS pod, max(c.cpu_usage_seconds_total) - min(c.cpu_usage_seconds_total) AS cpu_seconds
+		FROM container c
+		INNER JOIN tags t ON c.tags_id = t.id
+		WHERE c.time >= '1970-01-02 02:16:22.646325 +0000' AND c.time < '1970-01-02 03:16:22.646325 +0000'
+		AND t.tagset->>'namespace' = 'ingress'
+		GROUP BY 1
+		ORDER BY cpu_seconds DESC
+		LIMIT 10`,
+		},
+		{
+			desc: "restarts per deployment",
+			fn: func(k *K8s, q query.Query) {
+				k.RestartsPerDeployment(q)
+			},
+			expectedHumanLabel: "TimescaleDB container restarts per deployment",
+			expectedHumanDesc:  "TimescaleDB container restarts per deployment: 1970-01-01T18:16:22Z",
+			expectedHypertable: k8s.KubePodTableName,
+			expectedSQLQuery: `SELECT deployment, sum(restarts) AS restarts
+		FROM (SELECT t.deployment AS deployment, t.pod AS pod, max(p.container_status_restarts_total) - min(p.container_status_restarts_total) AS restarts
+			FROM kube_pod p
+			INNER JOIN tags t ON p.tags_id = t.id
+			WHERE p.time >= '1970-01-01 18:16:22.646325 +0000' AND p.time < '1970-01-02 18:16:22.646325 +0000'
+			AND t.namespace = 'ingress'
+			GROUP BY 1, 2) AS pod_restarts
+		GROUP BY deployment
+		ORDER BY restarts DESC, deployment`,
+		},
+		{
+			desc: "node saturation",
+			fn: func(k *K8s, q query.Query) {
+				k.NodeSaturation(q)
+			},
+			expectedHumanLabel: "TimescaleDB node CPU and memory saturation",
+			expectedHumanDesc:  "TimescaleDB node CPU and memory saturation: 1970-01-02T02:16:22Z",
+			expectedHypertable: k8s.ContainerTableName,
+			expectedSQLQuery: `SELECT bucket, node, sum(cpu_seconds) / 300 AS cpu_cores, sum(memory_bytes) AS memory_bytes
+		FROM (SELECT time_bucket('300 seconds', c.time) AS bucket, t.node AS node, c.tags_id,
+				max(c.cpu_usage_seconds_total) - min(c.cpu_usage_seconds_total) AS cpu_seconds,
+				avg(c.memory_working_set_bytes) AS memory_bytes
+			FROM container c
+			INNER JOIN tags t ON c.tags_id = t.id
+			WHERE c.time >= '1970-01-02 02:16:22.646325 +0000' AND c.time < '1970-01-02 03:16:22.646325 +0000'
+			GROUP BY 1, 2, 3) AS pod_usage
+		GROUP BY bucket, node
+		ORDER BY bucket, node`,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{UseJSON: c.useJSON}
+			g, err := b.NewK8s(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating k8s generator")
+			}
+			k := g.(*K8s)
+			q := k.GenerateEmptyQuery()
+			c.fn(k, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package timescaledb
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// SmartMeter produces TimescaleDB-specific queries for all the smart-meter query types.
+type SmartMeter struct {
+	*smartmeter.Core
+	*BaseGenerator
+}
+
+// DailyConsumptionPerFeeder calculates the energy consumed by the meters of
+// each feeder of a random region in a day.
+func (s *SmartMeter) DailyConsumptionPerFeeder(qi query.Query) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+	sql := fmt.Sprintf(`SELECT feeder, sum(consumption) AS consumption_kwh
+		FROM (SELECT t.%s, max(m.energy_import_kwh) - min(m.energy_import_kwh) AS consumption
+			FROM meter m
+			INNER JOIN tags t ON m.tags_id = t.id
+			WHERE m.time >= '%s' AND m.time < '%s'
+			AND t.%s = '%s'
+			GROUP BY m.tags_id, 1) AS meter_consumption
+		GROUP BY feeder
+		ORDER BY feeder`,
+		s.withAlias("feeder"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		s.columnSelect("region"),
+		s.GetRandomRegion())
+
+	humanLabel := "TimescaleDB daily consumption per feeder"
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	s.fillInQuery(qi, humanLabel, humanDesc, smartmeter.TableName, sql)
+}
+
+// TopConsumers finds the meters of a random region which consumed the most
+// energy in a day.
+func (s *SmartMeter) TopConsumers(qi query.Query, limit int) {
+	interval := s.Interval.MustRandWindow(smartmeter.ConsumptionDuration)
+	sql := fmt.Sprintf(`SELECT t.%s, max(m.energy_import_kwh) - min(m.energy_import_kwh) AS consumption_kwh
+		FROM meter m
+		INNER JOIN tags t ON m.tags_id = t.id
+		WHERE m.time >= '%s' AND m.time < '%s'
+		AND t.%s = '%s'
+		GROUP BY 1
+		ORDER BY consumption_kwh DESC
+		LIMIT %d`,
+		s.withAlias("meter_id"),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		s.columnSelect("region"),
+		s.GetRandomRegion(),
+		limit)
+
+	humanLabel := fmt.Sprintf("TimescaleDB top %d consumers per region", limit)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+
+	s.fillInQuery(qi, humanLabel, humanDesc, smartmeter.TableName, sql)
+}
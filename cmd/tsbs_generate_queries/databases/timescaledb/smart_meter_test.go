@@ -0,0 +1,95 @@
+package timescaledb
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestSmartMeterQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		useJSON            bool
+		fn                 func(*SmartMeter, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "daily consumption per feeder",
+			fn: func(s *SmartMeter, q query.Query) {
+				s.DailyConsumptionPerFeeder(q)
+			},
+			expectedHumanLabel: "TimescaleDB daily consumption per feeder",
+			expectedHumanDesc:  "TimescaleDB daily consumption per feeder: 1970-01-01T18:16:22Z",
+			expectedHypertable: smartmeter.TableName,
+			expectedSQLQuery: `SELECT feeder, sum(consumption) AS consumption_kwh
+		FROM (SELECT t.feeder AS feeder, max(m.energy_import_kwh) - min(m.energy_import_kwh) AS consumption
+			FROM meter m
+			INNER JOIN tags t ON m.tags_id = t.id
+			WHERE m.time >= '1970-01-01 18:16:22.646325 +0000' AND m.time < '1970-01-02 18:16:22.646325 +0000'
+			AND t.region = 'south'
+			GROUP BY m.tags_id, 1) AS meter_consumption
+		GROUP BY feeder
+		ORDER BY feeder`,
+		},
+		{
+			desc:    "daily consumption per feeder use json",
+			useJSON: true,
+			fn: func(s *SmartMeter, q query.Query) {
+				s.DailyConsumptionPerFeeder(q)
+			},
+			expectedHumanLabel: "TimescaleDB daily consumption per feeder",
+			expectedHumanDesc:  "TimescaleDB daily consumption per feeder: 1970-01-01T18:16:22Z",
+			expectedHypertable: smartmeter.TableName,
+			expectedSQLQuery: `SELECT feeder, sum(consumption) AS consumption_kwh
+		FROM (SELECT t.tagset->>'feeder' AS feeder, max(m.energy_import_kwh) - min(m.energy_import_kwh) AS consumption
+			FROM meter m
+			INNER JOIN tags t ON m.tags_id = t.id
+			WHERE m.time >= '1970-01-01 18:16:22.646325 +0000' AND m.time < '1970-01-02 18:16:22.646325 +0000'
+			AND t.tagset->>'region' = 'south'
+			GROUP BY m.tags_id, 1) AS meter_consumption
+		GROUP BY feeder
+		ORDER BY feeder`,
+		},
+		{
+			desc: "top consumers",
+			fn: func(s *SmartMeter, q query.Query) {
+				s.TopConsumers(q, smartmeter.TopConsumersLimit)
+			},
+			expectedHumanLabel: "TimescaleDB top 10 consumers per region",
+			expectedHumanDesc:  "TimescaleDB top 10 consumers per region: 1970-01-01T18:16:22Z",
+			expectedHypertable: smartmeter.TableName,
+			expectedSQLQuery: `SELECT t.meter_id AS meter_id, max(m.energy_import_kwh) - min(m.energy_import_kwh) AS consumption_kwh
+		FROM meter m
+		INNER JOIN tags t ON m.tags_id = t.id
+		WHERE m.time >= '1970-01-01 18:16:22.646325 +0000' AND m.time < '1970-01-02 18:16:22.646325 +0000'
+		AND t.region = 'south'
+		GROUP BY 1
+		ORDER BY consumption_kwh DESC
+		LIMIT 10`,
+		},
+	}
+
+	s := time.Unix(0, 0)
+	e := s.Add(48 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{UseJSON: c.useJSON}
+			g, err := b.NewSmartMeter(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating smart-meter generator")
+			}
+			sm := g.(*SmartMeter)
+			q := sm.GenerateEmptyQuery()
+			c.fn(sm, q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+		})
+	}
+}
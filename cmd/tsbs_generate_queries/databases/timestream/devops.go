@@ -264,3 +264,53 @@ func (d *Devops) HighCPUForHosts(qi query.Query, nHosts int) {
 	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
 	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
 }
+
+// RawScan selects all the metrics under 'cpu' of nhosts hosts over a random 12 hour
+// window without aggregating them, one row per measure, e.g. in pseudo-SQL:
+//
+// SELECT time, hostname, measure_name, measure_value
+// FROM cpu WHERE (hostname = '$HOSTNAME_1' OR ... OR hostname = '$HOSTNAME_N')
+// AND time >= '$HOUR_START' AND time < '$HOUR_END'
+// ORDER BY time
+func (d *Devops) RawScan(qi query.Query, nHosts int) {
+	interval := d.Interval.MustRandWindow(devops.RawScanDuration)
+
+	sql := fmt.Sprintf(`SELECT time, hostname, measure_name, measure_value::double
+		FROM "%s"."cpu"
+		WHERE %s AND time >= '%s' AND time < '%s'
+		ORDER BY time`,
+		d.DBName,
+		d.getHostWhereString(nHosts),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := devops.GetRawScanLabel("Timestream", nHosts)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset, e.g. in pseudo-SQL:
+//
+// SELECT AVG(metric1), ..., AVG(metricN)
+// FROM cpu WHERE time >= '$DATASET_START' AND time < '$DATASET_END'
+// GROUP BY day ORDER BY day
+func (d *Devops) DownsampleCPU(qi query.Query) {
+	metrics := devops.GetAllCPUMetrics()
+	selectClauses := d.getSelectClausesAggMetrics("avg", metrics)
+
+	sql := fmt.Sprintf(`SELECT %s AS day,
+			%s
+		FROM "%s"."cpu"
+		WHERE time >= '%s' AND time < '%s'
+		GROUP BY 1 ORDER BY 1`,
+		d.getTimeBucket(int(devops.DownsampleBucket.Seconds())),
+		strings.Join(selectClauses, ",\n\t\t\t"),
+		d.DBName,
+		d.Interval.Start().Format(goTimeFmt),
+		d.Interval.End().Format(goTimeFmt))
+
+	humanLabel := devops.GetDownsampleLabel("Timestream")
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, d.Interval.StartString())
+	d.fillInQuery(qi, humanLabel, humanDesc, devops.TableName, sql)
+}
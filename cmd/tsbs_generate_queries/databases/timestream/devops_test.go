@@ -468,6 +468,68 @@ func TestHighCPUForHosts(t *testing.T) {
 	}
 }
 
+func TestRawScan(t *testing.T) {
+	expectedHumanLabel := "Timestream all CPU metrics, raw points, random    2 hosts, random 12h0m0s"
+	expectedHumanDesc := "Timestream all CPU metrics, raw points, random    2 hosts, random 12h0m0s: 1970-01-01T00:16:22Z"
+	expectedTable := "cpu"
+	expectedSQLQuery := `SELECT time, hostname, measure_name, measure_value::double
+		FROM "b"."cpu"
+		WHERE (hostname = 'host_9' OR hostname = 'host_3') AND time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 12:16:22.646325 +0000'
+		ORDER BY time`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(devops.RawScanDuration).Add(time.Hour)
+
+	b := BaseGenerator{
+		DBName: "b",
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.RawScan(q, 2)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedTable, expectedSQLQuery)
+}
+
+func TestDownsampleCPU(t *testing.T) {
+	expectedHumanLabel := "Timestream mean of all CPU metrics, all hosts, entire dataset by 24h0m0s"
+	expectedHumanDesc := "Timestream mean of all CPU metrics, all hosts, entire dataset by 24h0m0s: 1970-01-01T00:00:00Z"
+	expectedTable := "cpu"
+	expectedSQLQuery := `SELECT bin(time, 86400s) AS day,
+			avg(case when measure_name = 'usage_user' THEN measure_value::double ELSE NULL END) as avg_usage_user,
+			avg(case when measure_name = 'usage_system' THEN measure_value::double ELSE NULL END) as avg_usage_system,
+			avg(case when measure_name = 'usage_idle' THEN measure_value::double ELSE NULL END) as avg_usage_idle,
+			avg(case when measure_name = 'usage_nice' THEN measure_value::double ELSE NULL END) as avg_usage_nice,
+			avg(case when measure_name = 'usage_iowait' THEN measure_value::double ELSE NULL END) as avg_usage_iowait,
+			avg(case when measure_name = 'usage_irq' THEN measure_value::double ELSE NULL END) as avg_usage_irq,
+			avg(case when measure_name = 'usage_softirq' THEN measure_value::double ELSE NULL END) as avg_usage_softirq,
+			avg(case when measure_name = 'usage_steal' THEN measure_value::double ELSE NULL END) as avg_usage_steal,
+			avg(case when measure_name = 'usage_guest' THEN measure_value::double ELSE NULL END) as avg_usage_guest,
+			avg(case when measure_name = 'usage_guest_nice' THEN measure_value::double ELSE NULL END) as avg_usage_guest_nice
+		FROM "b"."cpu"
+		WHERE time >= '1970-01-01 00:00:00 +0000' AND time < '1970-01-04 00:00:00 +0000'
+		GROUP BY 1 ORDER BY 1`
+	rand.Seed(123) // Setting seed for testing purposes.
+	s := time.Unix(0, 0)
+	e := s.Add(3 * devops.DownsampleBucket)
+
+	b := BaseGenerator{
+		DBName: "b",
+	}
+	dq, err := b.NewDevops(s, e, 10)
+	if err != nil {
+		t.Fatalf("Error while creating devops generator")
+	}
+	d := dq.(*Devops)
+
+	q := d.GenerateEmptyQuery()
+	d.DownsampleCPU(q)
+	verifyQuery(t, q, expectedHumanLabel, expectedHumanDesc, expectedTable, expectedSQLQuery)
+}
+
 func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, table, sqlQuery string) {
 	tsq, ok := q.(*query.Timestream)
 
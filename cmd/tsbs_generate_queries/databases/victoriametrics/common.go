@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	iutils "github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/query"
@@ -31,6 +33,30 @@ func (g *BaseGenerator) NewDevops(start, end time.Time, scale int) (utils.QueryG
 	}, nil
 }
 
+// NewIoT creates a new iot use case query generator.
+func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := iot.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &IoT{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
+// NewK8s creates a new k8s use case query generator.
+func (g *BaseGenerator) NewK8s(start, end time.Time, scale int) (utils.QueryGenerator, error) {
+	core, err := k8s.NewCore(start, end, scale)
+	if err != nil {
+		return nil, err
+	}
+	return &K8s{
+		BaseGenerator: g,
+		Core:          core,
+	}, nil
+}
+
 type queryInfo struct {
 	// prometheus query
 	query string
@@ -103,6 +103,103 @@ func (d *Devops) MaxAllCPU(qq query.Query, nHosts int) {
 	d.fillInQuery(qq, qi)
 }
 
+// PercentileCPU selects the percentiles of the user CPU usage per host per hour for half a day,
+// e.g. in pseudo-MetricsQL:
+//
+// max(
+// 	quantiles_over_time("phi", 0.5, 0.95, 0.99, cpu_usage_user[1h])
+// ) by (hostname, phi)
+func (d *Devops) PercentileCPU(qq query.Query) {
+	percentiles := devops.GetCPUPercentiles()
+	phis := make([]string, len(percentiles))
+	for i, p := range percentiles {
+		phis[i] = fmt.Sprintf("%g", p)
+	}
+	qi := &queryInfo{
+		query:    fmt.Sprintf(`max(quantiles_over_time("phi", %s, cpu_usage_user[1h])) by (hostname, phi)`, strings.Join(phis, ", ")),
+		label:    devops.GetPercentileCPULabel("VictoriaMetrics"),
+		interval: d.Interval.MustRandWindow(devops.PercentileCPUDuration),
+		step:     "3600",
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// TopKHostsByCPU selects the hosts with the highest mean user CPU usage in a time window,
+// e.g. in pseudo-MetricsQL:
+//
+// topk(10,
+// 	avg(
+// 		avg_over_time(cpu_usage_user[1h])
+// 	) by (hostname)
+// )
+func (d *Devops) TopKHostsByCPU(qq query.Query, limit int) {
+	qi := &queryInfo{
+		query:    fmt.Sprintf("topk(%d, avg(avg_over_time(cpu_usage_user[1h])) by (hostname))", limit),
+		label:    devops.GetTopKHostsLabel("VictoriaMetrics", limit),
+		interval: d.Interval.MustRandWindow(devops.TopKHostsDuration),
+		step:     "3600",
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// CounterRate computes the per-second rate of the counters of a measurement per host per minute,
+// e.g. in pseudo-MetricsQL:
+//
+// sum(
+// 	rate({__name__=~"measurement_(counter1|...|counterN)"}[1m])
+// ) by (__name__, hostname)
+func (d *Devops) CounterRate(qq query.Query, measurement string) {
+	metrics, err := devops.GetCounterMetrics(measurement)
+	if err != nil {
+		panic(err.Error())
+	}
+	qi := &queryInfo{
+		query: fmt.Sprintf("sum(rate({__name__=~'%s_(%s)'}[1m])) by (__name__, hostname)",
+			measurement, strings.Join(metrics, "|")),
+		label:    devops.GetCounterRateLabel("VictoriaMetrics", measurement),
+		interval: d.Interval.MustRandWindow(devops.CounterRateDuration),
+		step:     "60",
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// RawScan selects all metrics under 'cpu' of nhosts hosts over a random 12 hour window
+// without aggregating them. The step matches the default interval between the points
+// of the generated data, so every point is returned,
+// e.g. in pseudo-PromQL:
+//
+// {__name__=~"cpu_(metric1|metric2...|metricN)",hostname=~"hostname1|hostname2...|hostnameN"}
+func (d *Devops) RawScan(qq query.Query, nHosts int) {
+	hosts := d.mustGetRandomHosts(nHosts)
+	qi := &queryInfo{
+		query:    getSelectClause(devops.GetAllCPUMetrics(), hosts),
+		label:    devops.GetRawScanLabel("VictoriaMetrics", nHosts),
+		interval: d.Interval.MustRandWindow(devops.RawScanDuration),
+		step:     "10",
+	}
+	d.fillInQuery(qq, qi)
+}
+
+// DownsampleCPU selects the AVG of all metrics under 'cpu' per day for all hosts
+// over the entire dataset,
+// e.g. in pseudo-PromQL:
+//
+// avg(
+// 	avg_over_time(
+// 		{__name__=~"cpu_(metric1|metric2...|metricN)"}[1d]
+// 	)
+// ) by (__name__)
+func (d *Devops) DownsampleCPU(qq query.Query) {
+	selectClause := getSelectClause(devops.GetAllCPUMetrics(), nil)
+	qi := &queryInfo{
+		query:    fmt.Sprintf("avg(avg_over_time(%s[1d])) by (__name__)", selectClause),
+		label:    devops.GetDownsampleLabel("VictoriaMetrics"),
+		interval: d.Interval.TimeInterval,
+		step:     "86400",
+	}
+	d.fillInQuery(qq, qi)
+}
+
 func getHostClause(hostnames []string) string {
 	if len(hostnames) == 0 {
 		return ""
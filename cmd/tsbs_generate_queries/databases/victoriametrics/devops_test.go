@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
@@ -52,6 +53,47 @@ func Test_what(t *testing.T) {
 			expQuery: "max(max_over_time({__name__=~'cpu_(usage_user|usage_system|usage_idle|usage_nice|usage_iowait|usage_irq|usage_softirq|usage_steal|usage_guest|usage_guest_nice)', hostname=~'host_5|host_9|host_3|host_1|host_7'}[1h])) by (__name__)",
 			expStep:  "3600",
 		},
+		"PercentileCPU": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.PercentileCPU(q)
+			},
+			expQuery: `max(quantiles_over_time("phi", 0.5, 0.95, 0.99, cpu_usage_user[1h])) by (hostname, phi)`,
+			expStep:  "3600",
+		},
+		"TopKHostsByCPU": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.TopKHostsByCPU(q, 10)
+			},
+			expQuery: "topk(10, avg(avg_over_time(cpu_usage_user[1h])) by (hostname))",
+			expStep:  "3600",
+		},
+		"CounterRate_net": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.CounterRate(q, devops.NetTableName)
+			},
+			expQuery: "sum(rate({__name__=~'net_(bytes_sent|bytes_recv)'}[1m])) by (__name__, hostname)",
+			expStep:  "60",
+		},
+		"RawScan": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.RawScan(q, 2)
+			},
+			expQuery: "{__name__=~'cpu_(usage_user|usage_system|usage_idle|usage_nice|usage_iowait|usage_irq|usage_softirq|usage_steal|usage_guest|usage_guest_nice)', hostname=~'host_5|host_9'}",
+			expStep:  "10",
+		},
+		"DownsampleCPU": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.DownsampleCPU(q)
+			},
+			expQuery: "avg(avg_over_time({__name__=~'cpu_(usage_user|usage_system|usage_idle|usage_nice|usage_iowait|usage_irq|usage_softirq|usage_steal|usage_guest|usage_guest_nice)'}[1d])) by (__name__)",
+			expStep:  "86400",
+		},
+		"CounterRate_cpu": {
+			fn: func(g *Devops, q *query.HTTP) {
+				g.CounterRate(q, devops.TableName)
+			},
+			expToFail: true,
+		},
 		"GroupByOrderByLimit": {
 			fn: func(g *Devops, q *query.HTTP) {
 				g.GroupByOrderByLimit(q)
@@ -0,0 +1,222 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// IoT produces MetricsQL queries for the iot query types. Every field of the
+// readings and diagnostics is a metric of its own, e.g. readings_velocity,
+// labeled with the truck tags. The queries without a time window of their own
+// look back over the whole dataset.
+type IoT struct {
+	*BaseGenerator
+	*iot.Core
+}
+
+// mustGetTrucksClause gets multiple random trucks and creates a label
+// matcher for their names.
+func (i *IoT) mustGetTrucksClause(nTrucks int) string {
+	names, err := i.GetRandomTrucks(nTrucks)
+	if err != nil {
+		panic(err.Error())
+	}
+	return fmt.Sprintf("name=~'%s'", strings.Join(names, "|"))
+}
+
+// getFleetClause creates a label matcher for the named trucks of a random
+// fleet.
+func (i *IoT) getFleetClause() string {
+	return fmt.Sprintf("fleet='%s', name!=''", i.GetRandomFleet())
+}
+
+// datasetQueryInfo returns the query info of a query looking back over the
+// whole dataset, along with the lookback window of its rollups.
+func (i *IoT) datasetQueryInfo(query func(window string) string, label string) *queryInfo {
+	seconds := int(i.Interval.Duration().Seconds())
+	return &queryInfo{
+		query:    query(fmt.Sprintf("%ds", seconds)),
+		label:    label,
+		interval: i.Interval.TimeInterval,
+		step:     strconv.Itoa(seconds),
+	}
+}
+
+// LastLocByTruck finds the truck location for nTrucks,
+// e.g. in pseudo-MetricsQL:
+//
+//	last_over_time({__name__=~'readings_(latitude|longitude)', name=~'truck_1|...|truck_N'}[dataset]) keep_metric_names
+func (i *IoT) LastLocByTruck(qq query.Query, nTrucks int) {
+	trucks := i.mustGetTrucksClause(nTrucks)
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("last_over_time({__name__=~'readings_(latitude|longitude)', %s}[%s]) keep_metric_names", trucks, window)
+	}, "VictoriaMetrics last location by specific truck")
+	i.fillInQuery(qq, qi)
+}
+
+// LastLocPerTruck finds all the truck locations of a random fleet,
+// e.g. in pseudo-MetricsQL:
+//
+//	last_over_time({__name__=~'readings_(latitude|longitude)', fleet='fleet', name!=''}[dataset]) keep_metric_names
+func (i *IoT) LastLocPerTruck(qq query.Query) {
+	fleet := i.getFleetClause()
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("last_over_time({__name__=~'readings_(latitude|longitude)', %s}[%s]) keep_metric_names", fleet, window)
+	}, "VictoriaMetrics last location per truck")
+	i.fillInQuery(qq, qi)
+}
+
+// TrucksWithLowFuel finds all trucks with low fuel (less than 10%),
+// e.g. in pseudo-MetricsQL:
+//
+//	last_over_time(diagnostics_fuel_state{fleet='fleet', name!=''}[dataset]) < 0.1
+func (i *IoT) TrucksWithLowFuel(qq query.Query) {
+	fleet := i.getFleetClause()
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("last_over_time(diagnostics_fuel_state{%s}[%s]) < 0.1", fleet, window)
+	}, "VictoriaMetrics trucks with low fuel")
+	i.fillInQuery(qq, qi)
+}
+
+// TrucksWithHighLoad finds all trucks that have load over 90%. The load
+// capacity of a truck is a label, so its value is read with label_value,
+// e.g. in pseudo-MetricsQL:
+//
+//	with (load = last_over_time(diagnostics_current_load{fleet='fleet', name!=''}[dataset]))
+//	load / label_value(load, 'load_capacity') > 0.9
+func (i *IoT) TrucksWithHighLoad(qq query.Query) {
+	fleet := i.getFleetClause()
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("with (load = last_over_time(diagnostics_current_load{%s}[%s])) load / label_value(load, 'load_capacity') > 0.9", fleet, window)
+	}, "VictoriaMetrics trucks with high load")
+	i.fillInQuery(qq, qi)
+}
+
+// StationaryTrucks finds all trucks that have low average velocity in a time window,
+// e.g. in pseudo-MetricsQL:
+//
+//	avg_over_time(readings_velocity{fleet='fleet', name!=''}[10m]) < 1
+func (i *IoT) StationaryTrucks(qq query.Query) {
+	qi := &queryInfo{
+		query:    fmt.Sprintf("avg_over_time(readings_velocity{%s}[10m]) < 1", i.getFleetClause()),
+		label:    "VictoriaMetrics stationary trucks",
+		interval: i.Interval.MustRandWindow(iot.StationaryDuration),
+		step:     strconv.Itoa(int(iot.StationaryDuration.Seconds())),
+	}
+	i.fillInQuery(qq, qi)
+}
+
+// TrucksWithLongDrivingSessions finds all trucks that have not stopped at least 20 mins in the last 4 hours.
+func (i *IoT) TrucksWithLongDrivingSessions(qq query.Query) {
+	// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 5 mins per hour.
+	qi := i.drivingSessionsQueryInfo(iot.LongDrivingSessionDuration, tenMinutePeriods(5, iot.LongDrivingSessionDuration))
+	qi.label = "VictoriaMetrics trucks with longer driving sessions"
+	i.fillInQuery(qq, qi)
+}
+
+// TrucksWithLongDailySessions finds all trucks that have driven more than 10 hours in the last 24 hours.
+func (i *IoT) TrucksWithLongDailySessions(qq query.Query) {
+	// Calculate number of 10 min intervals that is the max driving duration for the session if we rest 35 mins per hour.
+	qi := i.drivingSessionsQueryInfo(iot.DailyDrivingDuration, tenMinutePeriods(35, iot.DailyDrivingDuration))
+	qi.label = "VictoriaMetrics trucks with longer daily sessions"
+	i.fillInQuery(qq, qi)
+}
+
+// drivingSessionsQueryInfo selects the trucks of a random fleet that were
+// driving for more than the given number of ten minute periods in a random
+// window of the given duration,
+// e.g. in pseudo-MetricsQL:
+//
+//	count_over_time((avg_over_time(readings_velocity{fleet='fleet', name!=''}[10m]) > 1)[4h:10m]) > 22
+func (i *IoT) drivingSessionsQueryInfo(duration time.Duration, periods int) *queryInfo {
+	return &queryInfo{
+		query: fmt.Sprintf("count_over_time((avg_over_time(readings_velocity{%s}[10m]) > 1)[%ds:10m]) > %d",
+			i.getFleetClause(), int(duration.Seconds()), periods),
+		interval: i.Interval.MustRandWindow(duration),
+		step:     strconv.Itoa(int(duration.Seconds())),
+	}
+}
+
+// AvgVsProjectedFuelConsumption calculates average and projected fuel
+// consumption per fleet. The average is the mean of the average consumption
+// of every truck while driving,
+// e.g. in pseudo-MetricsQL:
+//
+//	union(
+//		label_set(avg(avg_over_time((readings_fuel_consumption{name!=''} and readings_velocity{name!=''} > 1)[dataset])) by (fleet), 'consumption', 'avg'),
+//		label_set(avg(label_value(last_over_time(readings_fuel_consumption{name!=''}[dataset]), 'nominal_fuel_consumption')) by (fleet), 'consumption', 'projected')
+//	)
+func (i *IoT) AvgVsProjectedFuelConsumption(qq query.Query) {
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf(`union(`+
+			`label_set(avg(avg_over_time((readings_fuel_consumption{name!=''} and readings_velocity{name!=''} > 1)[%[1]s])) by (fleet), 'consumption', 'avg'), `+
+			`label_set(avg(label_value(last_over_time(readings_fuel_consumption{name!=''}[%[1]s]), 'nominal_fuel_consumption')) by (fleet), 'consumption', 'projected'))`,
+			window)
+	}, "VictoriaMetrics average vs projected fuel consumption per fleet")
+	i.fillInQuery(qq, qi)
+}
+
+// AvgDailyDrivingDuration finds the average driving duration per driver,
+// e.g. in pseudo-MetricsQL:
+//
+//	avg_over_time(
+//		(count_over_time((avg_over_time(readings_velocity{name!=''}[10m]) > 1)[1d:10m]) / 6)[dataset:1d]
+//	)
+func (i *IoT) AvgDailyDrivingDuration(qq query.Query) {
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("avg_over_time((count_over_time((avg_over_time(readings_velocity{name!=''}[10m]) > 1)[1d:10m]) / 6)[%s:1d])", window)
+	}, "VictoriaMetrics average driver driving duration per day")
+	i.fillInQuery(qq, qi)
+}
+
+// AvgDailyDrivingSession is not supported, as the driving sessions depend on
+// the previous ten minute periods of a truck.
+func (i *IoT) AvgDailyDrivingSession(qq query.Query) {
+	panic("AvgDailyDrivingSession not supported in MetricsQL")
+}
+
+// AvgLoad finds the average load per truck model per fleet,
+// e.g. in pseudo-MetricsQL:
+//
+//	with (load = avg_over_time(diagnostics_current_load{name!=''}[dataset]))
+//	avg(load / label_value(load, 'load_capacity')) by (fleet, model, load_capacity)
+func (i *IoT) AvgLoad(qq query.Query) {
+	qi := i.datasetQueryInfo(func(window string) string {
+		return fmt.Sprintf("with (load = avg_over_time(diagnostics_current_load{name!=''}[%s])) avg(load / label_value(load, 'load_capacity')) by (fleet, model, load_capacity)", window)
+	}, "VictoriaMetrics average load per truck model per fleet")
+	i.fillInQuery(qq, qi)
+}
+
+// DailyTruckActivity returns the number of hours trucks has been active (not out-of-commission) per day per fleet per model,
+// e.g. in pseudo-MetricsQL:
+//
+//	sum(count_over_time((avg_over_time(diagnostics_status{name!=''}[10m]) < 1)[1d:10m])) by (fleet, model) / 144
+func (i *IoT) DailyTruckActivity(qq query.Query) {
+	qi := &queryInfo{
+		query:    "sum(count_over_time((avg_over_time(diagnostics_status{name!=''}[10m]) < 1)[1d:10m])) by (fleet, model) / 144",
+		label:    "VictoriaMetrics daily truck activity per fleet per model",
+		interval: i.Interval.TimeInterval,
+		step:     "86400",
+	}
+	i.fillInQuery(qq, qi)
+}
+
+// TruckBreakdownFrequency is not supported, as a breakdown depends on the
+// previous ten minute period of a truck.
+func (i *IoT) TruckBreakdownFrequency(qq query.Query) {
+	panic("TruckBreakdownFrequency not supported in MetricsQL")
+}
+
+// tenMinutePeriods calculates the number of 10 minute periods that can fit in
+// the time duration if we subtract the minutes specified by minutesPerHour value.
+// E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
+func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
+	durationMinutes := duration.Minutes()
+	leftover := minutesPerHour * duration.Hours()
+	return int((durationMinutes - leftover) / 10)
+}
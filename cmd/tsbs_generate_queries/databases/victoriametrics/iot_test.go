@@ -0,0 +1,141 @@
+package victoriametrics
+
+import (
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIoTQueries(t *testing.T) {
+	testCases := map[string]struct {
+		fn        func(i *IoT, q *query.HTTP)
+		expQuery  string
+		expStep   string
+		expToFail bool
+	}{
+		"LastLocByTruck": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.LastLocByTruck(q, 2)
+			},
+			expQuery: "last_over_time({__name__=~'readings_(latitude|longitude)', name=~'truck_5|truck_9'}[172800s]) keep_metric_names",
+			expStep:  "172800",
+		},
+		"LastLocPerTruck": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.LastLocPerTruck(q)
+			},
+			expQuery: "last_over_time({__name__=~'readings_(latitude|longitude)', fleet='South', name!=''}[172800s]) keep_metric_names",
+			expStep:  "172800",
+		},
+		"TrucksWithLowFuel": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.TrucksWithLowFuel(q)
+			},
+			expQuery: "last_over_time(diagnostics_fuel_state{fleet='South', name!=''}[172800s]) < 0.1",
+			expStep:  "172800",
+		},
+		"TrucksWithHighLoad": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.TrucksWithHighLoad(q)
+			},
+			expQuery: "with (load = last_over_time(diagnostics_current_load{fleet='South', name!=''}[172800s])) load / label_value(load, 'load_capacity') > 0.9",
+			expStep:  "172800",
+		},
+		"StationaryTrucks": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.StationaryTrucks(q)
+			},
+			expQuery: "avg_over_time(readings_velocity{fleet='South', name!=''}[10m]) < 1",
+			expStep:  "600",
+		},
+		"TrucksWithLongDrivingSessions": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.TrucksWithLongDrivingSessions(q)
+			},
+			expQuery: "count_over_time((avg_over_time(readings_velocity{fleet='South', name!=''}[10m]) > 1)[14400s:10m]) > 22",
+			expStep:  "14400",
+		},
+		"TrucksWithLongDailySessions": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.TrucksWithLongDailySessions(q)
+			},
+			expQuery: "count_over_time((avg_over_time(readings_velocity{fleet='South', name!=''}[10m]) > 1)[86400s:10m]) > 60",
+			expStep:  "86400",
+		},
+		"AvgVsProjectedFuelConsumption": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.AvgVsProjectedFuelConsumption(q)
+			},
+			expQuery: "union(label_set(avg(avg_over_time((readings_fuel_consumption{name!=''} and readings_velocity{name!=''} > 1)[172800s])) by (fleet), 'consumption', 'avg'), label_set(avg(label_value(last_over_time(readings_fuel_consumption{name!=''}[172800s]), 'nominal_fuel_consumption')) by (fleet), 'consumption', 'projected'))",
+			expStep:  "172800",
+		},
+		"AvgDailyDrivingDuration": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.AvgDailyDrivingDuration(q)
+			},
+			expQuery: "avg_over_time((count_over_time((avg_over_time(readings_velocity{name!=''}[10m]) > 1)[1d:10m]) / 6)[172800s:1d])",
+			expStep:  "172800",
+		},
+		"AvgDailyDrivingSession": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.AvgDailyDrivingSession(q)
+			},
+			expToFail: true,
+		},
+		"AvgLoad": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.AvgLoad(q)
+			},
+			expQuery: "with (load = avg_over_time(diagnostics_current_load{name!=''}[172800s])) avg(load / label_value(load, 'load_capacity')) by (fleet, model, load_capacity)",
+			expStep:  "172800",
+		},
+		"DailyTruckActivity": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.DailyTruckActivity(q)
+			},
+			expQuery: "sum(count_over_time((avg_over_time(diagnostics_status{name!=''}[10m]) < 1)[1d:10m])) by (fleet, model) / 144",
+			expStep:  "86400",
+		},
+		"TruckBreakdownFrequency": {
+			fn: func(i *IoT, q *query.HTTP) {
+				i.TruckBreakdownFrequency(q)
+			},
+			expToFail: true,
+		},
+	}
+	b := &BaseGenerator{}
+	s := time.Unix(0, 0)
+	g, err := b.NewIoT(s, s.Add(48*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Error while creating iot generator")
+	}
+	i := g.(*IoT)
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			q := i.GenerateEmptyQuery().(*query.HTTP)
+			if tc.expToFail {
+				func() {
+					defer func() {
+						if recover() == nil {
+							t.Errorf("expected to panic")
+						}
+					}()
+					tc.fn(i, q)
+				}()
+				return
+			}
+
+			tc.fn(i, q)
+			vals, err := url.ParseQuery(string(q.Path))
+			if err != nil {
+				t.Fatalf("unexpected err while parsing query: %s", err)
+			}
+			checkEqual(t, "query", tc.expQuery, vals.Get("query"))
+			checkEqual(t, "step", tc.expStep, vals.Get("step"))
+		})
+	}
+}
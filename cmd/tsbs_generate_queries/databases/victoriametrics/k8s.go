@@ -0,0 +1,70 @@
+package victoriametrics
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// K8s produces MetricsQL queries for all the k8s query types.
+type K8s struct {
+	*BaseGenerator
+	*k8s.Core
+}
+
+// TopPodsByCPU finds the pods of a namespace which used the most CPU time in a time window,
+// e.g. in pseudo-MetricsQL:
+//
+// topk(10,
+// 	sum(
+// 		increase(container_cpu_usage_seconds_total{namespace='namespace'}[1h])
+// 	) by (pod)
+// )
+func (k *K8s) TopPodsByCPU(qq query.Query, limit int) {
+	qi := &queryInfo{
+		query: fmt.Sprintf(`topk(%d, sum(increase(container_cpu_usage_seconds_total{namespace='%s'}[1h])) by (pod))`,
+			limit, k.GetRandomNamespace()),
+		label:    fmt.Sprintf("VictoriaMetrics top %d pods by CPU usage per namespace", limit),
+		interval: k.Interval.MustRandWindow(k8s.TopPodsDuration),
+		step:     strconv.Itoa(int(k8s.TopPodsDuration.Seconds())),
+	}
+	k.fillInQuery(qq, qi)
+}
+
+// RestartsPerDeployment counts the container restarts per deployment of a namespace in a time window,
+// e.g. in pseudo-MetricsQL:
+//
+// sum(
+// 	increase(kube_pod_container_status_restarts_total{namespace='namespace'}[1d])
+// ) by (deployment)
+func (k *K8s) RestartsPerDeployment(qq query.Query) {
+	qi := &queryInfo{
+		query: fmt.Sprintf(`sum(increase(kube_pod_container_status_restarts_total{namespace='%s'}[1d])) by (deployment)`,
+			k.GetRandomNamespace()),
+		label:    "VictoriaMetrics container restarts per deployment",
+		interval: k.Interval.MustRandWindow(k8s.RestartsDuration),
+		step:     strconv.Itoa(int(k8s.RestartsDuration.Seconds())),
+	}
+	k.fillInQuery(qq, qi)
+}
+
+// NodeSaturation calculates the CPU cores and memory used on each node per 5 minutes in a time window,
+// e.g. in pseudo-MetricsQL:
+//
+// union(
+// 	label_set(sum(rate(container_cpu_usage_seconds_total[5m])) by (node), 'resource', 'cpu_cores'),
+// 	label_set(sum(avg_over_time(container_memory_working_set_bytes[5m])) by (node), 'resource', 'memory_bytes')
+// )
+func (k *K8s) NodeSaturation(qq query.Query) {
+	qi := &queryInfo{
+		query: `union(` +
+			`label_set(sum(rate(container_cpu_usage_seconds_total[5m])) by (node), 'resource', 'cpu_cores'), ` +
+			`label_set(sum(avg_over_time(container_memory_working_set_bytes[5m])) by (node), 'resource', 'memory_bytes'))`,
+		label:    "VictoriaMetrics node CPU and memory saturation",
+		interval: k.Interval.MustRandWindow(k8s.NodeSaturationDuration),
+		step:     strconv.Itoa(int(k8s.NodeSaturationBucket.Seconds())),
+	}
+	k.fillInQuery(qq, qi)
+}
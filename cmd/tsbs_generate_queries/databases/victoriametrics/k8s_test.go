@@ -0,0 +1,60 @@
+package victoriametrics
+
+import (
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestK8sQueries(t *testing.T) {
+	testCases := map[string]struct {
+		fn       func(k *K8s, q *query.HTTP)
+		expQuery string
+		expStep  string
+	}{
+		"TopPodsByCPU": {
+			fn: func(k *K8s, q *query.HTTP) {
+				k.TopPodsByCPU(q, 10)
+			},
+			expQuery: "topk(10, sum(increase(container_cpu_usage_seconds_total{namespace='payments'}[1h])) by (pod))",
+			expStep:  "3600",
+		},
+		"RestartsPerDeployment": {
+			fn: func(k *K8s, q *query.HTTP) {
+				k.RestartsPerDeployment(q)
+			},
+			expQuery: "sum(increase(kube_pod_container_status_restarts_total{namespace='payments'}[1d])) by (deployment)",
+			expStep:  "86400",
+		},
+		"NodeSaturation": {
+			fn: func(k *K8s, q *query.HTTP) {
+				k.NodeSaturation(q)
+			},
+			expQuery: "union(label_set(sum(rate(container_cpu_usage_seconds_total[5m])) by (node), 'resource', 'cpu_cores'), label_set(sum(avg_over_time(container_memory_working_set_bytes[5m])) by (node), 'resource', 'memory_bytes'))",
+			expStep:  "300",
+		},
+	}
+	b := &BaseGenerator{}
+	s := time.Unix(0, 0)
+	g, err := b.NewK8s(s, s.Add(48*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Error while creating k8s generator")
+	}
+	k := g.(*K8s)
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			q := k.GenerateEmptyQuery().(*query.HTTP)
+			tc.fn(k, q)
+			vals, err := url.ParseQuery(string(q.Path))
+			if err != nil {
+				t.Fatalf("unexpected err while parsing query: %s", err)
+			}
+			checkEqual(t, "query", tc.expQuery, vals.Get("query"))
+			checkEqual(t, "step", tc.expStep, vals.Get("step"))
+		})
+	}
+}
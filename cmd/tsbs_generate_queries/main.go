@@ -10,7 +10,10 @@ import (
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/finance"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/k8s"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/smartmeter"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/internal/inputs"
 	internalUtils "github.com/timescale/tsbs/internal/utils"
@@ -33,6 +36,15 @@ var useCaseMatrix = map[string]map[string]utils.QueryFillerMaker{
 		devops.LabelHighCPU + "-all":          devops.NewHighCPU(0),
 		devops.LabelHighCPU + "-1":            devops.NewHighCPU(1),
 		devops.LabelLastpoint:                 devops.NewLastPointPerHost,
+		devops.LabelPercentileCPU:             devops.NewPercentileCPU,
+		devops.LabelTopKHosts:                 devops.NewTopKHosts(devops.TopKHostsLimit),
+		devops.LabelRate + "-net":             devops.NewCounterRate(devops.NetTableName),
+		devops.LabelRate + "-diskio":          devops.NewCounterRate(devops.DiskIOTableName),
+		devops.LabelHighCPUMem:                devops.NewHighCPUMem,
+		devops.LabelCPUNginx:                  devops.NewCPUNginx,
+		devops.LabelRawScan + "-1":            devops.NewRawScan(1),
+		devops.LabelRawScan + "-8":            devops.NewRawScan(8),
+		devops.LabelDownsample:                devops.NewDownsample,
 	},
 	"iot": {
 		iot.LabelLastLoc:                       iot.NewLastLocPerTruck,
@@ -49,6 +61,20 @@ var useCaseMatrix = map[string]map[string]utils.QueryFillerMaker{
 		iot.LabelDailyActivity:                 iot.NewDailyTruckActivity,
 		iot.LabelBreakdownFrequency:            iot.NewTruckBreakdownFrequency,
 	},
+	"k8s": {
+		k8s.LabelTopPodsCPU:            k8s.NewTopPodsByCPU(k8s.TopPodsLimit),
+		k8s.LabelRestartsPerDeployment: k8s.NewRestartsPerDeployment,
+		k8s.LabelNodeSaturation:        k8s.NewNodeSaturation,
+	},
+	"finance": {
+		finance.LabelOHLC:      finance.NewOHLC,
+		finance.LabelVWAP:      finance.NewVWAP,
+		finance.LabelLastQuote: finance.NewLastQuote,
+	},
+	"smart-meter": {
+		smartmeter.LabelDailyConsumptionPerFeeder: smartmeter.NewDailyConsumptionPerFeeder,
+		smartmeter.LabelTopConsumers:              smartmeter.NewTopConsumers(smartmeter.TopConsumersLimit),
+	},
 }
 
 var conf = &config.QueryGeneratorConfig{}
@@ -17,10 +17,13 @@ const (
 // Core is the common component of all generators for all systems
 type Core struct {
 	// Interval is the entire time range of the dataset
-	Interval *internalutils.TimeInterval
+	Interval *Interval
 
 	// Scale is the cardinality of the dataset in terms of devices/hosts
 	Scale int
+
+	// Series holds the names of the loaded series if they are known (see SetDataset)
+	Series []string
 }
 
 // NewCore returns a new Core for the given time range and cardinality
@@ -30,7 +33,13 @@ func NewCore(start, end time.Time, scale int) (*Core, error) {
 		return nil, err
 	}
 
-	return &Core{Interval: ti, Scale: scale}, nil
+	return &Core{Interval: &Interval{TimeInterval: ti}, Scale: scale}, nil
+}
+
+// SetTimeRangeStrategy sets the strategy selecting the time ranges of the queries
+// within the dataset.
+func (c *Core) SetTimeRangeStrategy(strategy TimeRangeStrategy) {
+	c.Interval.strategy = strategy
 }
 
 // PanicUnimplementedQuery generates a panic for the provided query generator.
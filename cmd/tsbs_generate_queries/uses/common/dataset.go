@@ -0,0 +1,60 @@
+package common
+
+import (
+	"fmt"
+
+	internalutils "github.com/timescale/tsbs/internal/utils"
+)
+
+const (
+	errMoreSeriesThanDatasetFmt = "number of series (%d) larger than the series in the dataset (%d)"
+)
+
+// Dataset describes the data actually loaded into the database under test,
+// which can differ from the scale and time range the queries are generated for,
+// e.g. when the data was generated with a lower initial scale or a point limit.
+type Dataset struct {
+	// Span is the time range from the first loaded point up to one log interval
+	// after the last one.
+	Span *internalutils.TimeInterval
+
+	// Scale is the number of loaded series.
+	Scale int
+
+	// Series holds the names of the loaded series (e.g. host_7 in devops or
+	// truck_3 in iot), in the order they first reported. It is nil if the
+	// series are the ones the generators name for Scale, e.g. host_0 to
+	// host_{Scale-1}.
+	Series []string
+
+	// PartialSeries is the number of series which did not report over the
+	// entire Span, e.g. because they started late or died early.
+	PartialSeries int
+}
+
+// SetDataset constrains the queries to the series and time span of the loaded
+// dataset. The time range strategy, if any, is kept.
+func (c *Core) SetDataset(dataset *Dataset) {
+	c.Interval.TimeInterval = dataset.Span
+	c.Scale = dataset.Scale
+	c.Series = dataset.Series
+}
+
+// GetRandomSeries returns a random subset of numSeries names of the loaded
+// series. It should only be used after SetDataset.
+func (c *Core) GetRandomSeries(numSeries int) ([]string, error) {
+	if numSeries > len(c.Series) {
+		return nil, fmt.Errorf(errMoreSeriesThanDatasetFmt, numSeries, len(c.Series))
+	}
+
+	indices, err := GetRandomSubsetPerm(numSeries, len(c.Series))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(indices))
+	for i, n := range indices {
+		names[i] = c.Series[n]
+	}
+	return names, nil
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/internal/utils"
+)
+
+func TestCoreSetDataset(t *testing.T) {
+	s := time.Unix(0, 0).UTC()
+	c, err := NewCore(s, s.Add(24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.SetTimeRangeStrategy(LastTimeRange{})
+
+	span, err := utils.NewTimeInterval(s, s.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.SetDataset(&Dataset{Span: span, Scale: 3, Series: []string{"host_0", "host_1", "host_12"}})
+
+	if c.Scale != 3 {
+		t.Errorf("incorrect scale: got %d want 3", c.Scale)
+	}
+	// the time range strategy applies to the dataset span
+	window := c.Interval.MustRandWindow(time.Hour)
+	if got, want := window.End(), s.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("incorrect window end: got %v want %v", got, want)
+	}
+
+	series, err := c.GetRandomSeries(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sort.Strings(series)
+	if got, want := strings.Join(series, ","), "host_0,host_1,host_12"; got != want {
+		t.Errorf("incorrect series: got %s want %s", got, want)
+	}
+
+	_, err = c.GetRandomSeries(4)
+	if got, want := fmt.Sprint(err), fmt.Sprintf(errMoreSeriesThanDatasetFmt, 4, 3); got != want {
+		t.Errorf("incorrect error: got %s want %s", got, want)
+	}
+}
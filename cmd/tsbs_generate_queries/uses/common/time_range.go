@@ -0,0 +1,168 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	internalutils "github.com/timescale/tsbs/internal/utils"
+)
+
+// Time range strategy choices
+const (
+	TimeRangeUniform = "uniform"
+	TimeRangeLast    = "last"
+	TimeRangeRecency = "recency"
+	TimeRangeSliding = "sliding"
+
+	errBadTimeRangeStrategyFmt = "invalid time range strategy specified: '%v'"
+	errWindowTooLargeFmt       = "query window equal to or larger than the dataset: window %v, dataset %v"
+)
+
+// TimeRangeStrategyChoices contains all the supported time range strategy names.
+var TimeRangeStrategyChoices = []string{
+	TimeRangeUniform,
+	TimeRangeLast,
+	TimeRangeRecency,
+	TimeRangeSliding,
+}
+
+// TimeRangeStrategy decides which part of the dataset a query reads by
+// placing a window of a given duration within the dataset time range.
+type TimeRangeStrategy interface {
+	// Window returns a TimeInterval of duration window within dataset.
+	Window(dataset *internalutils.TimeInterval, window time.Duration) (*internalutils.TimeInterval, error)
+}
+
+// UniformTimeRange starts the windows at a uniformly random time of the dataset.
+type UniformTimeRange struct{}
+
+// Window returns a window at a uniformly random position within the dataset.
+func (UniformTimeRange) Window(dataset *internalutils.TimeInterval, window time.Duration) (*internalutils.TimeInterval, error) {
+	return dataset.RandWindow(window)
+}
+
+// LastTimeRange anchors the windows at the end of the dataset, the way dashboards
+// show the last hour or day. The windows end at a uniformly random time within
+// the last Span of the dataset, or exactly at its end if Span is zero.
+type LastTimeRange struct {
+	Span time.Duration
+}
+
+// Window returns a window ending within the last Span of the dataset.
+func (s LastTimeRange) Window(dataset *internalutils.TimeInterval, window time.Duration) (*internalutils.TimeInterval, error) {
+	maxOffset, err := maxEndOffset(dataset, window)
+	if err != nil {
+		return nil, err
+	}
+	offset := time.Duration(0)
+	if span := minDuration(s.Span, maxOffset); span > 0 {
+		offset = time.Duration(rand.Int63n(int64(span)))
+	}
+	return windowEndingAt(dataset.End().Add(-offset), window)
+}
+
+// RecencyTimeRange favours the most recent data: the distance between the end of
+// a window and the end of the dataset is exponentially distributed with mean Decay.
+type RecencyTimeRange struct {
+	Decay time.Duration
+}
+
+// Window returns a window whose distance to the end of the dataset decays exponentially.
+func (s RecencyTimeRange) Window(dataset *internalutils.TimeInterval, window time.Duration) (*internalutils.TimeInterval, error) {
+	maxOffset, err := maxEndOffset(dataset, window)
+	if err != nil {
+		return nil, err
+	}
+	offset := minDuration(time.Duration(rand.ExpFloat64()*float64(s.Decay)), maxOffset)
+	return windowEndingAt(dataset.End().Add(-offset), window)
+}
+
+// SlidingTimeRange simulates a dashboard refreshing during a long run: every
+// window ends at "now", which starts at the first possible window end and moves
+// forward by Step per query, wrapping around at the end of the dataset.
+type SlidingTimeRange struct {
+	Step time.Duration
+
+	// now is the distance of the current window end to the first possible one.
+	now time.Duration
+}
+
+// Window returns the window ending at the current "now" and advances it.
+func (s *SlidingTimeRange) Window(dataset *internalutils.TimeInterval, window time.Duration) (*internalutils.TimeInterval, error) {
+	maxOffset, err := maxEndOffset(dataset, window)
+	if err != nil {
+		return nil, err
+	}
+	if s.now > maxOffset {
+		s.now = 0
+	}
+	end := dataset.Start().Add(window + s.now)
+	s.now += s.Step
+	return windowEndingAt(end, window)
+}
+
+// maxEndOffset returns how far before the end of the dataset a window can end.
+func maxEndOffset(dataset *internalutils.TimeInterval, window time.Duration) (time.Duration, error) {
+	if window >= dataset.Duration() {
+		return 0, fmt.Errorf(errWindowTooLargeFmt, window, dataset.Duration())
+	}
+	return dataset.Duration() - window, nil
+}
+
+func windowEndingAt(end time.Time, window time.Duration) (*internalutils.TimeInterval, error) {
+	return internalutils.NewTimeInterval(end.Add(-window), end)
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// NewTimeRangeStrategy returns the TimeRangeStrategy with the given name. An empty
+// name selects the uniform strategy. The span is the part of the dataset the last
+// strategy reads, the mean distance to the end of the dataset of the recency
+// strategy and the step of the sliding strategy.
+func NewTimeRangeStrategy(name string, span time.Duration) (TimeRangeStrategy, error) {
+	switch name {
+	case "", TimeRangeUniform:
+		return UniformTimeRange{}, nil
+	case TimeRangeLast:
+		return LastTimeRange{Span: span}, nil
+	case TimeRangeRecency:
+		return RecencyTimeRange{Decay: span}, nil
+	case TimeRangeSliding:
+		return &SlidingTimeRange{Step: span}, nil
+	default:
+		return nil, fmt.Errorf(errBadTimeRangeStrategyFmt, name)
+	}
+}
+
+// Interval is the entire time range of the dataset, from which the time ranges
+// of the queries are selected by a TimeRangeStrategy.
+type Interval struct {
+	*internalutils.TimeInterval
+
+	strategy TimeRangeStrategy
+}
+
+// RandWindow returns a TimeInterval of duration window within the dataset,
+// placed by the time range strategy.
+func (i *Interval) RandWindow(window time.Duration) (*internalutils.TimeInterval, error) {
+	if i.strategy == nil {
+		return i.TimeInterval.RandWindow(window)
+	}
+	return i.strategy.Window(i.TimeInterval, window)
+}
+
+// MustRandWindow is the form of RandWindow that cannot error; if it does error,
+// it causes a panic.
+func (i *Interval) MustRandWindow(window time.Duration) *internalutils.TimeInterval {
+	res, err := i.RandWindow(window)
+	if err != nil {
+		panic(err.Error())
+	}
+	return res
+}
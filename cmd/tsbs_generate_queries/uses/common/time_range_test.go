@@ -0,0 +1,130 @@
+package common
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/internal/utils"
+)
+
+func newTestDataset(t *testing.T) *utils.TimeInterval {
+	start := time.Unix(0, 0).UTC()
+	ti, err := utils.NewTimeInterval(start, start.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return ti
+}
+
+func TestTimeRangeStrategies(t *testing.T) {
+	dataset := newTestDataset(t)
+	window := 12 * time.Hour
+	cases := []struct {
+		desc     string
+		strategy TimeRangeStrategy
+		// minEnd is the earliest allowed end of the windows.
+		minEnd time.Time
+	}{
+		{desc: "uniform", strategy: UniformTimeRange{}, minEnd: dataset.Start().Add(window)},
+		{desc: "last", strategy: LastTimeRange{Span: time.Hour}, minEnd: dataset.End().Add(-time.Hour)},
+		{desc: "last no span", strategy: LastTimeRange{}, minEnd: dataset.End()},
+		{desc: "recency", strategy: RecencyTimeRange{Decay: time.Hour}, minEnd: dataset.Start().Add(window)},
+		{desc: "sliding", strategy: &SlidingTimeRange{Step: time.Hour}, minEnd: dataset.Start().Add(window)},
+	}
+
+	rand.Seed(123)
+	for _, c := range cases {
+		for i := 0; i < 100; i++ {
+			got, err := c.strategy.Window(dataset, window)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", c.desc, err)
+			}
+			if got.Duration() != window {
+				t.Errorf("%s: incorrect window duration: got %v want %v", c.desc, got.Duration(), window)
+			}
+			if got.End().Before(c.minEnd) || got.End().After(dataset.End()) {
+				t.Errorf("%s: window end %v outside of [%v, %v]", c.desc, got.End(), c.minEnd, dataset.End())
+			}
+		}
+	}
+}
+
+func TestTimeRangeStrategyWindowTooLarge(t *testing.T) {
+	dataset := newTestDataset(t)
+	for _, name := range TimeRangeStrategyChoices {
+		s, err := NewTimeRangeStrategy(name, time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error for strategy '%s': %v", name, err)
+		}
+		if _, err := s.Window(dataset, dataset.Duration()+time.Hour); err == nil {
+			t.Errorf("unexpected lack of error for too large window with strategy '%s'", name)
+		}
+	}
+}
+
+func TestSlidingTimeRange(t *testing.T) {
+	dataset := newTestDataset(t)
+	window := 12 * time.Hour
+	s := &SlidingTimeRange{Step: 24 * time.Hour}
+	wantEnds := []time.Time{
+		dataset.Start().Add(12 * time.Hour),
+		dataset.Start().Add(36 * time.Hour),
+		dataset.Start().Add(12 * time.Hour), // wraps around
+	}
+	for i, want := range wantEnds {
+		got, err := s.Window(dataset, window)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.End().Equal(want) {
+			t.Errorf("incorrect end for window %d: got %v want %v", i, got.End(), want)
+		}
+	}
+}
+
+func TestNewTimeRangeStrategy(t *testing.T) {
+	cases := []struct {
+		name string
+		want TimeRangeStrategy
+	}{
+		{name: "", want: UniformTimeRange{}},
+		{name: TimeRangeUniform, want: UniformTimeRange{}},
+		{name: TimeRangeLast, want: LastTimeRange{Span: time.Hour}},
+		{name: TimeRangeRecency, want: RecencyTimeRange{Decay: time.Hour}},
+	}
+	for _, c := range cases {
+		got, err := NewTimeRangeStrategy(c.name, time.Hour)
+		if err != nil {
+			t.Errorf("unexpected error for strategy '%s': %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("incorrect strategy for '%s': got %v want %v", c.name, got, c.want)
+		}
+	}
+
+	got, err := NewTimeRangeStrategy(TimeRangeSliding, time.Hour)
+	if err != nil {
+		t.Errorf("unexpected error for sliding strategy: %v", err)
+	}
+	if s, ok := got.(*SlidingTimeRange); !ok || s.Step != time.Hour {
+		t.Errorf("incorrect strategy for sliding: got %v", got)
+	}
+
+	if _, err := NewTimeRangeStrategy("bogus", 0); err == nil {
+		t.Errorf("unexpected lack of error for bogus strategy")
+	}
+}
+
+func TestCoreSetTimeRangeStrategy(t *testing.T) {
+	dataset := newTestDataset(t)
+	c, err := NewCore(dataset.Start(), dataset.End(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.SetTimeRangeStrategy(LastTimeRange{})
+	got := c.Interval.MustRandWindow(time.Hour)
+	if !got.End().Equal(dataset.End()) {
+		t.Errorf("incorrect window end: got %v want %v", got.End(), dataset.End())
+	}
+}
@@ -2,6 +2,7 @@ package devops
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
@@ -13,6 +14,7 @@ const (
 	errNHostsCannotNegative = "nHosts cannot be negative"
 	errNoMetrics            = "cannot get 0 metrics"
 	errTooManyMetrics       = "too many metrics asked for"
+	errNoCounters           = "no counters for measurement %s"
 
 	// TableName is the name of the table where the time series data is stored for devops use case.
 	TableName = "cpu"
@@ -23,6 +25,37 @@ const (
 	HighCPUDuration = 12 * time.Hour
 	// MaxAllDuration is the how big the time range for MaxAll query is
 	MaxAllDuration = 8 * time.Hour
+	// PercentileCPUDuration is the how big the time range for PercentileCPU query is
+	PercentileCPUDuration = 12 * time.Hour
+	// TopKHostsDuration is the how big the time range for TopKHosts query is
+	TopKHostsDuration = time.Hour
+	// TopKHostsLimit is the number of hosts returned by the TopKHosts query
+	TopKHostsLimit = 10
+	// CounterRateDuration is the how big the time range for CounterRate query is
+	CounterRateDuration = time.Hour
+	// HighCPUMemDuration is the how big the time range for HighCPUMem query is
+	HighCPUMemDuration = 12 * time.Hour
+	// CPUNginxDuration is the how big the time range for CPUNginx query is
+	CPUNginxDuration = 12 * time.Hour
+	// JoinBucket is the width of the time buckets the multi-measurement queries join on
+	JoinBucket = 5 * time.Minute
+	// HighCPUThreshold is the mean CPU usage above which the HighCPUMem query selects a bucket
+	HighCPUThreshold = 90.0
+	// HighMemThreshold is the mean used memory percentage above which the HighCPUMem query selects a bucket
+	HighMemThreshold = 80.0
+	// RawScanDuration is the how big the time range for RawScan query is
+	RawScanDuration = 12 * time.Hour
+	// DownsampleBucket is the width of the time buckets of the Downsample query, which reads the entire dataset
+	DownsampleBucket = 24 * time.Hour
+
+	// NetTableName is the name of the table storing the net measurement
+	NetTableName = "net"
+	// DiskIOTableName is the name of the table storing the diskio measurement
+	DiskIOTableName = "diskio"
+	// MemTableName is the name of the table storing the mem measurement
+	MemTableName = "mem"
+	// NginxTableName is the name of the table storing the nginx measurement
+	NginxTableName = "nginx"
 
 	// LabelSingleGroupby is the label prefix for queries of the single groupby variety
 	LabelSingleGroupby = "single-groupby"
@@ -36,6 +69,20 @@ const (
 	LabelGroupbyOrderbyLimit = "groupby-orderby-limit"
 	// LabelHighCPU is the prefix for queries of the high-CPU variety
 	LabelHighCPU = "high-cpu"
+	// LabelPercentileCPU is the label for the percentile-cpu query
+	LabelPercentileCPU = "percentile-cpu"
+	// LabelTopKHosts is the label for the topk-hosts query
+	LabelTopKHosts = "topk-hosts"
+	// LabelRate is the label prefix for queries of the counter rate variety
+	LabelRate = "rate"
+	// LabelHighCPUMem is the label for the high-cpu-mem query
+	LabelHighCPUMem = "high-cpu-mem"
+	// LabelCPUNginx is the label for the cpu-nginx query
+	LabelCPUNginx = "cpu-nginx"
+	// LabelRawScan is the label prefix for queries of the raw scan variety
+	LabelRawScan = "raw-scan"
+	// LabelDownsample is the label for the downsample query
+	LabelDownsample = "downsample"
 )
 
 // Core is the common component of all generators for all systems
@@ -50,8 +97,12 @@ func NewCore(start, end time.Time, scale int) (*Core, error) {
 
 }
 
-// GetRandomHosts returns a random set of nHosts from a given Core
+// GetRandomHosts returns a random set of nHosts from a given Core, out of the
+// loaded hosts if the dataset is known
 func (d *Core) GetRandomHosts(nHosts int) ([]string, error) {
+	if d.Series != nil && nHosts >= 1 {
+		return d.GetRandomSeries(nHosts)
+	}
 	return getRandomHosts(nHosts, d.Scale)
 }
 
@@ -80,6 +131,37 @@ func GetCPUMetricsSlice(numMetrics int) ([]string, error) {
 	return cpuMetrics[:numMetrics], nil
 }
 
+// cpuPercentiles is the list of percentiles of the PercentileCPU query
+var cpuPercentiles = []float64{0.5, 0.95, 0.99}
+
+// GetCPUPercentiles returns the percentiles of the CPU usage computed by the
+// PercentileCPU query, as fractions
+func GetCPUPercentiles() []float64 {
+	return cpuPercentiles
+}
+
+// GetPercentileName returns the short name of a percentile given as a
+// fraction, e.g. p95 for 0.95
+func GetPercentileName(p float64) string {
+	return fmt.Sprintf("p%g", p*100)
+}
+
+// counterMetrics is the list of monotonic counter metric names per measurement
+// used by the CounterRate query
+var counterMetrics = map[string][]string{
+	NetTableName:    {"bytes_sent", "bytes_recv"},
+	DiskIOTableName: {"read_bytes", "write_bytes"},
+}
+
+// GetCounterMetrics returns the monotonic counter metrics of a measurement
+func GetCounterMetrics(measurement string) ([]string, error) {
+	metrics, ok := counterMetrics[measurement]
+	if !ok {
+		return nil, fmt.Errorf(errNoCounters, measurement)
+	}
+	return metrics, nil
+}
+
 // GetAllCPUMetrics returns all the metrics for CPU
 func GetAllCPUMetrics() []string {
 	return cpuMetrics
@@ -120,6 +202,41 @@ type HighCPUFiller interface {
 	HighCPUForHosts(query.Query, int)
 }
 
+// PercentileCPUFiller is a type that can fill in a percentile-cpu query
+type PercentileCPUFiller interface {
+	PercentileCPU(query.Query)
+}
+
+// TopKHostsFiller is a type that can fill in a topk-hosts query
+type TopKHostsFiller interface {
+	TopKHostsByCPU(query.Query, int)
+}
+
+// CounterRateFiller is a type that can fill in a counter rate query
+type CounterRateFiller interface {
+	CounterRate(query.Query, string)
+}
+
+// HighCPUMemFiller is a type that can fill in a high-cpu-mem query
+type HighCPUMemFiller interface {
+	HighCPUAndMem(query.Query)
+}
+
+// CPUNginxFiller is a type that can fill in a cpu-nginx query
+type CPUNginxFiller interface {
+	CPUNginxCorrelation(query.Query)
+}
+
+// RawScanFiller is a type that can fill in a raw-scan query
+type RawScanFiller interface {
+	RawScan(query.Query, int)
+}
+
+// DownsampleFiller is a type that can fill in a downsample query
+type DownsampleFiller interface {
+	DownsampleCPU(query.Query)
+}
+
 // GetDoubleGroupByLabel returns the Query human-readable label for DoubleGroupBy queries
 func GetDoubleGroupByLabel(dbName string, numMetrics int) string {
 	return fmt.Sprintf("%s mean of %d metrics, all hosts, random %s by 1h", dbName, numMetrics, DoubleGroupByDuration)
@@ -143,6 +260,47 @@ func GetMaxAllLabel(dbName string, nHosts int) string {
 	return fmt.Sprintf("%s max of all CPU metrics, random %4d hosts, random %s by 1h", dbName, nHosts, MaxAllDuration)
 }
 
+// GetPercentileCPULabel returns the Query human-readable label for PercentileCPU queries
+func GetPercentileCPULabel(dbName string) string {
+	percentiles := make([]string, len(cpuPercentiles))
+	for i, p := range cpuPercentiles {
+		percentiles[i] = GetPercentileName(p)
+	}
+	return fmt.Sprintf("%s %s of CPU usage per host, all hosts, random %s by 1h", dbName, strings.Join(percentiles, ", "), PercentileCPUDuration)
+}
+
+// GetTopKHostsLabel returns the Query human-readable label for TopKHosts queries
+func GetTopKHostsLabel(dbName string, limit int) string {
+	return fmt.Sprintf("%s top %d hosts by mean CPU usage, random %s", dbName, limit, TopKHostsDuration)
+}
+
+// GetCounterRateLabel returns the Query human-readable label for CounterRate queries
+func GetCounterRateLabel(dbName, measurement string) string {
+	return fmt.Sprintf("%s per-second rate of %s counters per host, all hosts, random %s by 1m", dbName, measurement, CounterRateDuration)
+}
+
+// GetHighCPUMemLabel returns the Query human-readable label for HighCPUMem queries
+func GetHighCPUMemLabel(dbName string) string {
+	return fmt.Sprintf("%s CPU over %g%% and mem over %g%% in the same bucket, all hosts, random %s by %s",
+		dbName, HighCPUThreshold, HighMemThreshold, HighCPUMemDuration, JoinBucket)
+}
+
+// GetCPUNginxLabel returns the Query human-readable label for CPUNginx queries
+func GetCPUNginxLabel(dbName string) string {
+	return fmt.Sprintf("%s mean CPU usage and nginx requests per host, all hosts, random %s by %s",
+		dbName, CPUNginxDuration, JoinBucket)
+}
+
+// GetRawScanLabel returns the Query human-readable label for RawScan queries
+func GetRawScanLabel(dbName string, nHosts int) string {
+	return fmt.Sprintf("%s all CPU metrics, raw points, random %4d hosts, random %s", dbName, nHosts, RawScanDuration)
+}
+
+// GetDownsampleLabel returns the Query human-readable label for Downsample queries
+func GetDownsampleLabel(dbName string) string {
+	return fmt.Sprintf("%s mean of all CPU metrics, all hosts, entire dataset by %s", dbName, DownsampleBucket)
+}
+
 // getRandomHosts returns a subset of numHosts hostnames of a permutation of hostnames,
 // numbered from 0 to totalHosts.
 // Ex.: host_12, host_7, host_25 for numHosts=3 and totalHosts=30 (3 out of 30)
@@ -139,6 +139,24 @@ func TestGetAllCPUMetrics(t *testing.T) {
 	}
 }
 
+func TestGetCounterMetrics(t *testing.T) {
+	got, err := GetCounterMetrics(NetTableName)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "bytes_sent,bytes_recv"; strings.Join(got, ",") != want {
+		t.Errorf("incorrect output: got %s want %s", strings.Join(got, ","), want)
+	}
+
+	_, err = GetCounterMetrics("cpu")
+	if err == nil {
+		t.Fatalf("expected error for measurement without counters")
+	}
+	if want := fmt.Sprintf(errNoCounters, "cpu"); err.Error() != want {
+		t.Errorf("incorrect error: got %s want %s", err.Error(), want)
+	}
+}
+
 func TestGetCPUMetricsLen(t *testing.T) {
 	result := GetCPUMetricsLen()
 	want := len(cpuMetrics)
@@ -268,3 +286,59 @@ func TestGetMaxAllLabel(t *testing.T) {
 		t.Errorf("incorrect output: got %s want %s", got, want)
 	}
 }
+
+func TestGetPercentileCPULabel(t *testing.T) {
+	want := fmt.Sprintf("Foo p50, p95, p99 of CPU usage per host, all hosts, random %s by 1h", PercentileCPUDuration)
+	got := GetPercentileCPULabel("Foo")
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetTopKHostsLabel(t *testing.T) {
+	want := fmt.Sprintf("Foo top 10 hosts by mean CPU usage, random %s", TopKHostsDuration)
+	got := GetTopKHostsLabel("Foo", 10)
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetCounterRateLabel(t *testing.T) {
+	want := fmt.Sprintf("Foo per-second rate of net counters per host, all hosts, random %s by 1m", CounterRateDuration)
+	got := GetCounterRateLabel("Foo", NetTableName)
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetHighCPUMemLabel(t *testing.T) {
+	want := "Foo CPU over 90% and mem over 80% in the same bucket, all hosts, random 12h0m0s by 5m0s"
+	got := GetHighCPUMemLabel("Foo")
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetCPUNginxLabel(t *testing.T) {
+	want := "Foo mean CPU usage and nginx requests per host, all hosts, random 12h0m0s by 5m0s"
+	got := GetCPUNginxLabel("Foo")
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetRawScanLabel(t *testing.T) {
+	want := fmt.Sprintf("Foo all CPU metrics, raw points, random    8 hosts, random %s", RawScanDuration)
+	got := GetRawScanLabel("Foo", 8)
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
+
+func TestGetDownsampleLabel(t *testing.T) {
+	want := "Foo mean of all CPU metrics, all hosts, entire dataset by 24h0m0s"
+	got := GetDownsampleLabel("Foo")
+	if got != want {
+		t.Errorf("incorrect output: got %s want %s", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// CounterRate contains info for filling in counter rate queries
+type CounterRate struct {
+	core        utils.QueryGenerator
+	measurement string
+}
+
+// NewCounterRate produces a new function that produces a new CounterRate
+// over the counters of the given measurement
+func NewCounterRate(measurement string) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &CounterRate{
+			core:        core,
+			measurement: measurement,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *CounterRate) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(CounterRateFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.CounterRate(q, d.measurement)
+	return q
+}
@@ -0,0 +1,27 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// CPUNginx contains info for filling in cpu-nginx queries
+type CPUNginx struct {
+	core utils.QueryGenerator
+}
+
+// NewCPUNginx returns a new CPUNginx for given parameters
+func NewCPUNginx(core utils.QueryGenerator) utils.QueryFiller {
+	return &CPUNginx{core}
+}
+
+// Fill fills in the query.Query with query details
+func (d *CPUNginx) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(CPUNginxFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.CPUNginxCorrelation(q)
+	return q
+}
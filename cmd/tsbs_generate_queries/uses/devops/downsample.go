@@ -0,0 +1,27 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Downsample contains info for filling in downsample queries
+type Downsample struct {
+	core utils.QueryGenerator
+}
+
+// NewDownsample returns a new Downsample for given parameters
+func NewDownsample(core utils.QueryGenerator) utils.QueryFiller {
+	return &Downsample{core}
+}
+
+// Fill fills in the query.Query with query details
+func (d *Downsample) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(DownsampleFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.DownsampleCPU(q)
+	return q
+}
@@ -0,0 +1,27 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// HighCPUMem contains info for filling in high-cpu-mem queries
+type HighCPUMem struct {
+	core utils.QueryGenerator
+}
+
+// NewHighCPUMem returns a new HighCPUMem for given parameters
+func NewHighCPUMem(core utils.QueryGenerator) utils.QueryFiller {
+	return &HighCPUMem{core}
+}
+
+// Fill fills in the query.Query with query details
+func (d *HighCPUMem) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(HighCPUMemFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.HighCPUAndMem(q)
+	return q
+}
@@ -0,0 +1,27 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// PercentileCPU contains info for filling in percentile-cpu queries
+type PercentileCPU struct {
+	core utils.QueryGenerator
+}
+
+// NewPercentileCPU returns a new PercentileCPU for given parameters
+func NewPercentileCPU(core utils.QueryGenerator) utils.QueryFiller {
+	return &PercentileCPU{core}
+}
+
+// Fill fills in the query.Query with query details
+func (d *PercentileCPU) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(PercentileCPUFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.PercentileCPU(q)
+	return q
+}
@@ -0,0 +1,33 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// RawScan contains info for filling in raw-scan queries
+type RawScan struct {
+	core  utils.QueryGenerator
+	hosts int
+}
+
+// NewRawScan produces a new function that produces a new RawScan
+func NewRawScan(hosts int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &RawScan{
+			core:  core,
+			hosts: hosts,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *RawScan) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(RawScanFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.RawScan(q, d.hosts)
+	return q
+}
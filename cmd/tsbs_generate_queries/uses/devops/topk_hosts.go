@@ -0,0 +1,34 @@
+package devops
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TopKHosts contains info for filling in topk-hosts queries
+type TopKHosts struct {
+	core  utils.QueryGenerator
+	limit int
+}
+
+// NewTopKHosts produces a new function that produces a new TopKHosts filler
+// returning the given number of hosts
+func NewTopKHosts(limit int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &TopKHosts{
+			core:  core,
+			limit: limit,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details
+func (d *TopKHosts) Fill(q query.Query) query.Query {
+	fc, ok := d.core.(TopKHostsFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(d.core)
+	}
+	fc.TopKHostsByCPU(q, d.limit)
+	return q
+}
@@ -0,0 +1,67 @@
+package finance
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// TradeTableName is the name of the table where the trades are stored.
+	TradeTableName = "trade"
+	// QuoteTableName is the name of the table where the quotes are stored.
+	QuoteTableName = "quote"
+
+	// OHLCDuration is the time range to calculate the OHLC bars for.
+	OHLCDuration = time.Hour
+	// OHLCBucket is the size of an OHLC bar.
+	OHLCBucket = time.Minute
+	// VWAPDuration is the time range to calculate the VWAP for.
+	VWAPDuration = time.Hour
+
+	// LabelOHLC is the label for the OHLC bars per minute query.
+	LabelOHLC = "ohlc-1m"
+	// LabelVWAP is the label for the VWAP per symbol query.
+	LabelVWAP = "vwap"
+	// LabelLastQuote is the label for the last quote per symbol query.
+	LabelLastQuote = "last-quote"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+}
+
+// NewCore returns a new Core for the given time range and cardinality.
+func NewCore(start, end time.Time, scale int) (*Core, error) {
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c}, err
+}
+
+// GetRandomSymbol returns the symbol of a random instrument.
+func (c *Core) GetRandomSymbol() string {
+	return finance.SymbolName(rand.Intn(c.Scale))
+}
+
+// GetRandomExchange returns one of the exchange choices by random.
+func (c *Core) GetRandomExchange() string {
+	return finance.ExchangeChoices[rand.Intn(len(finance.ExchangeChoices))]
+}
+
+// OHLCFiller is a type that can fill in an OHLC bars query.
+type OHLCFiller interface {
+	OHLC(query.Query)
+}
+
+// VWAPFiller is a type that can fill in a VWAP per symbol query.
+type VWAPFiller interface {
+	VWAP(query.Query)
+}
+
+// LastQuoteFiller is a type that can fill in a last quote per symbol query.
+type LastQuoteFiller interface {
+	LastQuote(query.Query)
+}
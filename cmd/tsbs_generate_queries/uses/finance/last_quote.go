@@ -0,0 +1,29 @@
+package finance
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// LastQuote contains info for filling in last quote per symbol queries.
+type LastQuote struct {
+	core utils.QueryGenerator
+}
+
+// NewLastQuote creates a new last quote per symbol query filler.
+func NewLastQuote(core utils.QueryGenerator) utils.QueryFiller {
+	return &LastQuote{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *LastQuote) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(LastQuoteFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.LastQuote(q)
+	return q
+}
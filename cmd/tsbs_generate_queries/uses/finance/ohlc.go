@@ -0,0 +1,29 @@
+package finance
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// OHLC contains info for filling in OHLC bars queries.
+type OHLC struct {
+	core utils.QueryGenerator
+}
+
+// NewOHLC creates a new OHLC bars query filler.
+func NewOHLC(core utils.QueryGenerator) utils.QueryFiller {
+	return &OHLC{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *OHLC) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(OHLCFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.OHLC(q)
+	return q
+}
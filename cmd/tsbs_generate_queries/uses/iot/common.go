@@ -71,8 +71,12 @@ func NewCore(start, end time.Time, scale int) (*Core, error) {
 
 }
 
-// GetRandomTrucks returns a random set of nTrucks from a given Core
+// GetRandomTrucks returns a random set of nTrucks from a given Core, out of the
+// loaded trucks if the dataset is known
 func (c *Core) GetRandomTrucks(nTrucks int) ([]string, error) {
+	if c.Series != nil && nTrucks >= 1 {
+		return c.GetRandomSeries(nTrucks)
+	}
 	return getRandomTrucks(nTrucks, c.Scale)
 }
 
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// ContainerTableName is the name of the table where the container
+	// resource usage time series data is stored.
+	ContainerTableName = "container"
+	// KubePodTableName is the name of the table where the pod state time
+	// series data is stored.
+	KubePodTableName = "kube_pod"
+
+	// TopPodsDuration is the time range to find the top pods by CPU usage in.
+	TopPodsDuration = time.Hour
+	// TopPodsLimit is the number of pods returned by the top pods query.
+	TopPodsLimit = 10
+	// RestartsDuration is the time range to count container restarts in.
+	RestartsDuration = 24 * time.Hour
+	// NodeSaturationDuration is the time range to evaluate node saturation in.
+	NodeSaturationDuration = time.Hour
+	// NodeSaturationBucket is the bucket size used by the node saturation query.
+	NodeSaturationBucket = 5 * time.Minute
+
+	// LabelTopPodsCPU is the label for the top pods by CPU per namespace query.
+	LabelTopPodsCPU = "top-pods-cpu"
+	// LabelRestartsPerDeployment is the label for the restarts per deployment query.
+	LabelRestartsPerDeployment = "restarts-per-deployment"
+	// LabelNodeSaturation is the label for the node saturation query.
+	LabelNodeSaturation = "node-saturation"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+}
+
+// NewCore returns a new Core for the given time range and cardinality.
+func NewCore(start, end time.Time, scale int) (*Core, error) {
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c}, err
+}
+
+// GetRandomNamespace returns one of the namespace choices by random.
+func (c *Core) GetRandomNamespace() string {
+	return k8s.NamespaceChoices[rand.Intn(len(k8s.NamespaceChoices))]
+}
+
+// TopPodsByCPUFiller is a type that can fill in a top pods by CPU usage per namespace query.
+type TopPodsByCPUFiller interface {
+	TopPodsByCPU(query.Query, int)
+}
+
+// RestartsPerDeploymentFiller is a type that can fill in a container restarts per deployment query.
+type RestartsPerDeploymentFiller interface {
+	RestartsPerDeployment(query.Query)
+}
+
+// NodeSaturationFiller is a type that can fill in a node CPU and memory saturation query.
+type NodeSaturationFiller interface {
+	NodeSaturation(query.Query)
+}
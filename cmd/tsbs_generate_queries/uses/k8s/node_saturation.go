@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// NodeSaturation contains info for filling in node saturation queries.
+type NodeSaturation struct {
+	core utils.QueryGenerator
+}
+
+// NewNodeSaturation creates a new node saturation query filler.
+func NewNodeSaturation(core utils.QueryGenerator) utils.QueryFiller {
+	return &NodeSaturation{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *NodeSaturation) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(NodeSaturationFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.NodeSaturation(q)
+	return q
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// RestartsPerDeployment contains info for filling in restarts per deployment queries.
+type RestartsPerDeployment struct {
+	core utils.QueryGenerator
+}
+
+// NewRestartsPerDeployment creates a new restarts per deployment query filler.
+func NewRestartsPerDeployment(core utils.QueryGenerator) utils.QueryFiller {
+	return &RestartsPerDeployment{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *RestartsPerDeployment) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(RestartsPerDeploymentFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.RestartsPerDeployment(q)
+	return q
+}
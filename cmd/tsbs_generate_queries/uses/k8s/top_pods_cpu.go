@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TopPodsByCPU contains info for filling in top pods by CPU usage queries.
+type TopPodsByCPU struct {
+	core  utils.QueryGenerator
+	limit int
+}
+
+// NewTopPodsByCPU produces a new function that produces a new TopPodsByCPU
+// filler returning the given number of pods.
+func NewTopPodsByCPU(limit int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &TopPodsByCPU{
+			core:  core,
+			limit: limit,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *TopPodsByCPU) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(TopPodsByCPUFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.TopPodsByCPU(q, i.limit)
+	return q
+}
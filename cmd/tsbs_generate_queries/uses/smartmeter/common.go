@@ -0,0 +1,51 @@
+package smartmeter
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/smartmeter"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// TableName is the name of the table where the meter readings are stored.
+	TableName = "meter"
+
+	// ConsumptionDuration is the time range to calculate the consumption in.
+	ConsumptionDuration = 24 * time.Hour
+	// TopConsumersLimit is the number of meters returned by the top consumers query.
+	TopConsumersLimit = 10
+
+	// LabelDailyConsumptionPerFeeder is the label for the daily consumption per feeder query.
+	LabelDailyConsumptionPerFeeder = "daily-consumption-per-feeder"
+	// LabelTopConsumers is the label for the top consumers query.
+	LabelTopConsumers = "top-consumers"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+}
+
+// NewCore returns a new Core for the given time range and cardinality.
+func NewCore(start, end time.Time, scale int) (*Core, error) {
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c}, err
+}
+
+// GetRandomRegion returns one of the region choices by random.
+func (c *Core) GetRandomRegion() string {
+	return smartmeter.RegionChoices[rand.Intn(len(smartmeter.RegionChoices))]
+}
+
+// DailyConsumptionPerFeederFiller is a type that can fill in a daily consumption per feeder query.
+type DailyConsumptionPerFeederFiller interface {
+	DailyConsumptionPerFeeder(query.Query)
+}
+
+// TopConsumersFiller is a type that can fill in a top consumers query.
+type TopConsumersFiller interface {
+	TopConsumers(query.Query, int)
+}
@@ -0,0 +1,29 @@
+package smartmeter
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// DailyConsumptionPerFeeder contains info for filling in daily consumption per feeder queries.
+type DailyConsumptionPerFeeder struct {
+	core utils.QueryGenerator
+}
+
+// NewDailyConsumptionPerFeeder creates a new daily consumption per feeder query filler.
+func NewDailyConsumptionPerFeeder(core utils.QueryGenerator) utils.QueryFiller {
+	return &DailyConsumptionPerFeeder{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *DailyConsumptionPerFeeder) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(DailyConsumptionPerFeederFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.DailyConsumptionPerFeeder(q)
+	return q
+}
@@ -0,0 +1,34 @@
+package smartmeter
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TopConsumers contains info for filling in top consumers queries.
+type TopConsumers struct {
+	core  utils.QueryGenerator
+	limit int
+}
+
+// NewTopConsumers produces a new function that produces a new TopConsumers
+// filler returning the given number of meters.
+func NewTopConsumers(limit int) utils.QueryFillerMaker {
+	return func(core utils.QueryGenerator) utils.QueryFiller {
+		return &TopConsumers{
+			core:  core,
+			limit: limit,
+		}
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *TopConsumers) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(TopConsumersFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.TopConsumers(q, i.limit)
+	return q
+}
@@ -51,4 +51,8 @@ type SimulatorDataSourceConfig struct {
 	Limit                 uint64        `yaml:"max-data-points" mapstructure:"max-data-points"`
 	LogInterval           time.Duration `yaml:"log-interval" mapstructure:"log-interval"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	ScaleUpCurve          string        `yaml:"scale-up-curve" mapstructure:"scale-up-curve"`
+	ScaleUpSteps          uint64        `yaml:"scale-up-steps" mapstructure:"scale-up-steps"`
+	ChurnRate             float64       `yaml:"churn-rate" mapstructure:"churn-rate"`
+	NonNumericFields      bool          `yaml:"non-numeric-fields" mapstructure:"non-numeric-fields"`
 }
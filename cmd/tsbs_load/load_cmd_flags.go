@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"strings"
 	"time"
 )
@@ -96,6 +97,22 @@ func addDataSourceFlags(fs *pflag.FlagSet) {
 		100,
 		"Max number of metric fields to generate per host. Used only in devops-generic use-case",
 	)
+	fs.String(
+		"data-source.simulator.scale-up-curve",
+		common.ScaleUpLinear,
+		"Curve used to scale from initial-scale to scale over time. Valid: "+strings.Join(common.ScaleUpCurveChoices, ", "),
+	)
+	fs.Uint64("data-source.simulator.scale-up-steps", 4, "Number of steps used by the 'step' scale-up curve")
+	fs.Float64(
+		"data-source.simulator.churn-rate",
+		common.DefaultChurnRate,
+		"Fraction of reporting entities replaced by new ones with fresh tag values every log-interval, 0 = no churn, -1 = default of the use case (no churn, except pods in 'k8s')",
+	)
+	fs.Bool(
+		"data-source.simulator.non-numeric-fields",
+		false,
+		"Add string and boolean fields to the devops and iot use cases",
+	)
 	fs.Uint64(
 		"data-source.simulator.scale",
 		defaultScale,
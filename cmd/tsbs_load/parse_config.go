@@ -137,6 +137,10 @@ func convertDataSourceConfigToInternalRepresentation(format string, d *DataSourc
 			Limit:                 d.Simulator.Limit,
 			LogInterval:           d.Simulator.LogInterval,
 			MaxMetricCountPerHost: d.Simulator.MaxMetricCountPerHost,
+			ScaleUpCurve:          d.Simulator.ScaleUpCurve,
+			ScaleUpSteps:          d.Simulator.ScaleUpSteps,
+			ChurnRate:             d.Simulator.ChurnRate,
+			NonNumericFields:      d.Simulator.NonNumericFields,
 			InterleavedNumGroups:  1,
 		}
 	}
@@ -17,6 +17,7 @@ type tableDef struct {
 	tags     []string
 	tagTypes []string
 	cols     []string
+	colTypes []string
 }
 
 // fqn returns the fully-qualified name of a table
@@ -64,6 +65,8 @@ func (d *dbCreator) Init() {
 // list of column names, comma-separated:
 //     disk,total,free,used,used_percent,inodes_total,inodes_free,inodes_used
 //
+// Non-numeric columns are followed by their type, e.g. "status string".
+//
 // The last line being blank to separate the header from the data.
 //
 // Header example:
@@ -80,6 +83,7 @@ func (d *dbCreator) readDataHeader(header *common.GeneratedDataHeaders) ([]*tabl
 				tags:     header.TagKeys,
 				tagTypes: header.TagTypes,
 				cols:     fieldCols,
+				colTypes: header.FieldTypes[tableName],
 			},
 		)
 	}
@@ -102,19 +106,16 @@ func (d *dbCreator) CreateDB(dbName string) error {
 func (d *dbCreator) createMetricsTable(table *tableDef) error {
 	var tagsObjectChildCols []string
 	for i, column := range table.tags {
-		if table.tagTypes[i] != "string" {
-			return fmt.Errorf("cratedb db creator does not support non-string tags")
-		}
 		tagsObjectChildCols = append(
 			tagsObjectChildCols,
-			fmt.Sprintf("%s %s", column, "string"))
+			fmt.Sprintf("%s %s", column, tagTypeToCrateType(table.tagTypes, i)))
 	}
 
 	var metricCols []string
-	for _, column := range table.cols {
+	for i, column := range table.cols {
 		metricCols = append(
 			metricCols,
-			fmt.Sprintf("%s %s", column, "double"))
+			fmt.Sprintf("%s %s", column, fieldTypeToCrateType(table.colTypes, i)))
 	}
 
 	// TODO partition table by configurable time interval
@@ -137,6 +138,33 @@ func (d *dbCreator) createMetricsTable(table *tableDef) error {
 	return nil
 }
 
+// tagTypeToCrateType returns the type of the i-th tag of the tags object,
+// which is string unless the tag is numeric, like the load capacity of the
+// trucks of the iot use case. The tag values are serialized as JSON strings
+// and cast to the type of their column on insert.
+func tagTypeToCrateType(tagTypes []string, i int) string {
+	if i >= len(tagTypes) || !common.IsNumericFieldType(tagTypes[i]) {
+		return "string"
+	}
+	return "double"
+}
+
+// fieldTypeToCrateType returns the type of the i-th metric column, which is
+// double unless the field is not numeric.
+func fieldTypeToCrateType(colTypes []string, i int) string {
+	if i >= len(colTypes) {
+		return "double"
+	}
+	switch colTypes[i] {
+	case common.FieldTypeString:
+		return "string"
+	case common.FieldTypeBool:
+		return "boolean"
+	default:
+		return "double"
+	}
+}
+
 // loader.DBCreator interface implementation
 //
 // returns true if there are any tables in a schema
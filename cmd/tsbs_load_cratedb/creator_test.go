@@ -93,6 +93,16 @@ func TestDBCreatorReadDataHeader(t *testing.T) {
 	}
 }
 
+func TestTagTypeToCrateType(t *testing.T) {
+	tagTypes := []string{"string", "float64", "int64"}
+	want := []string{"string", "double", "double", "string"}
+	for i, w := range want {
+		if got := tagTypeToCrateType(tagTypes, i); got != w {
+			t.Errorf("incorrect type of tag %d: got %s want %s", i, got, w)
+		}
+	}
+}
+
 func arrEq(a []string, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -62,8 +62,9 @@ type fileDataSource struct {
 // Decodes a data point of a following format:
 //       <measurement_type>\t<tags>\t<timestamp>\t<metric1>\t...\t<metricN>
 //
-// Converts metric values to double-precision floating-point number, timestamp
-// to time.Time and tags to bytes array.
+// Converts metric values to double-precision floating-point number unless the
+// header says they are strings or bools, timestamp to time.Time and tags to
+// bytes array.
 func (d *fileDataSource) NextItem() data.LoadedPoint {
 	ok := d.scanner.Scan()
 	if !ok && d.scanner.Err() == nil {
@@ -84,7 +85,11 @@ func (d *fileDataSource) NextItem() data.LoadedPoint {
 	table := parts[0]
 	tags := []byte(parts[1])
 
-	metrics, err := parseMetrics(strings.Split(parts[3], "\t"))
+	var fieldTypes []string
+	if d.headers != nil {
+		fieldTypes = d.headers.FieldTypes[table]
+	}
+	metrics, err := parseMetrics(strings.Split(parts[3], "\t"), fieldTypes)
 	if err != nil {
 		fatal("cannot parse metrics: %v", err)
 		return data.LoadedPoint{}
@@ -135,6 +140,7 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 		tagTypes[i] = tagAndTypeSplit[1]
 	}
 	fields := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	for {
 		ok := d.scanner.Scan()
 		if !ok && d.scanner.Err() == nil {
@@ -156,12 +162,13 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 			fatal("metric columns are missing")
 			return nil
 		}
-		fields[parts[0]] = strings.Split(parts[1], ",")
+		fields[parts[0]], fieldTypes[parts[0]] = common.ParseFieldColumns(strings.Split(parts[1], ","))
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tags,
-		FieldKeys: fields,
+		TagTypes:   tagTypes,
+		TagKeys:    tags,
+		FieldKeys:  fields,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
@@ -174,14 +181,55 @@ func parseTime(v string) (time.Time, error) {
 	return time.Unix(0, ts), nil
 }
 
-func parseMetrics(values []string) (row, error) {
+func parseMetrics(values, fieldTypes []string) (row, error) {
 	metrics := make(row, len(values))
 	for i := range values {
-		metric, err := strconv.ParseFloat(values[i], 64)
-		if err != nil {
-			return nil, err
+		fieldType := ""
+		if i < len(fieldTypes) {
+			fieldType = fieldTypes[i]
+		}
+
+		switch fieldType {
+		case common.FieldTypeString:
+			metrics[i] = unescape(values[i])
+		case common.FieldTypeBool:
+			metric, err := strconv.ParseBool(values[i])
+			if err != nil {
+				return nil, err
+			}
+			metrics[i] = metric
+		default:
+			metric, err := strconv.ParseFloat(values[i], 64)
+			if err != nil {
+				return nil, err
+			}
+			metrics[i] = metric
 		}
-		metrics[i] = metric
 	}
 	return metrics, nil
 }
+
+// unescape reverts the escaping of tabs, newlines and backslashes in string
+// values done by the serializer.
+func unescape(v string) string {
+	if !strings.Contains(v, "\\") {
+		return v
+	}
+	var sb strings.Builder
+	for i := 0; i < len(v); i++ {
+		if v[i] == '\\' && i+1 < len(v) {
+			i++
+			switch v[i] {
+			case 't':
+				sb.WriteByte('\t')
+			case 'n':
+				sb.WriteByte('\n')
+			default:
+				sb.WriteByte(v[i])
+			}
+			continue
+		}
+		sb.WriteByte(v[i])
+	}
+	return sb.String()
+}
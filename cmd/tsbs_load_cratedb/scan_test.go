@@ -155,6 +155,23 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeNonNumeric(t *testing.T) {
+	input := "health\tnull\t1454608400000000000\tdisk \\\\C:\\tfull\ttrue\t42\n"
+	br := bufio.NewReader(bytes.NewReader([]byte(input)))
+	decoder := &fileDataSource{
+		scanner: bufio.NewScanner(br),
+		headers: &common.GeneratedDataHeaders{
+			FieldKeys:  map[string][]string{"health": {"last_error", "alerting", "uptime"}},
+			FieldTypes: map[string][]string{"health": {"string", "bool", "float64"}},
+		},
+	}
+	p := decoder.NextItem().Data.(*point)
+	want := row{"disk \\C:\tfull", true, 42.0}
+	if got := p.row[2:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect metrics: got %#v want %#v", got, want)
+	}
+}
+
 func TestDecodeEOF(t *testing.T) {
 	input := []byte("cpu\t{\"hostname\":\"host_0\"}\t1454608400000000000\t38.24311829\n")
 	br := bufio.NewReader(bytes.NewReader([]byte(input)))
@@ -178,17 +195,19 @@ func TestDataSourceHeaders(t *testing.T) {
 			desc:  "min case: exactly three lines",
 			input: "tags,tag1 string,tag2 string2\ncpu,col1,col2\n\n",
 			expectedHeader: &common.GeneratedDataHeaders{
-				TagTypes:  []string{"string", "string2"},
-				TagKeys:   []string{"tag1", "tag2"},
-				FieldKeys: map[string][]string{"cpu": {"col1", "col2"}},
+				TagTypes:   []string{"string", "string2"},
+				TagKeys:    []string{"tag1", "tag2"},
+				FieldKeys:  map[string][]string{"cpu": {"col1", "col2"}},
+				FieldTypes: map[string][]string{"cpu": {"float64", "float64"}},
 			},
 		}, {
 			desc:  "min case: exactly three lines, tags don't have types",
 			input: "tags,tag1 string,tag2 string2\ncpu,col1,col2\n\n",
 			expectedHeader: &common.GeneratedDataHeaders{
-				TagTypes:  []string{"string", "string2"},
-				TagKeys:   []string{"tag1", "tag2"},
-				FieldKeys: map[string][]string{"cpu": {"col1", "col2"}},
+				TagTypes:   []string{"string", "string2"},
+				TagKeys:    []string{"tag1", "tag2"},
+				FieldKeys:  map[string][]string{"cpu": {"col1", "col2"}},
+				FieldTypes: map[string][]string{"cpu": {"float64", "float64"}},
 			},
 		},
 		{
@@ -206,6 +225,10 @@ func TestDataSourceHeaders(t *testing.T) {
 					"cpu":  {"col1", "col2"},
 					"disk": {"col21", "col22"},
 				},
+				FieldTypes: map[string][]string{
+					"cpu":  {"float64", "float64"},
+					"disk": {"float64", "float64"},
+				},
 			},
 		},
 		{
@@ -218,6 +241,20 @@ func TestDataSourceHeaders(t *testing.T) {
 					"cpu": {"col1", "col2"},
 					"mem": {"col21", "col22"},
 				},
+				FieldTypes: map[string][]string{
+					"cpu": {"float64", "float64"},
+					"mem": {"float64", "float64"},
+				},
+			},
+		},
+		{
+			desc:  "non-numeric fields",
+			input: "tags,tag1 string\nhealth,status string,alerting bool,uptime\n\n",
+			expectedHeader: &common.GeneratedDataHeaders{
+				TagTypes:   []string{"string"},
+				TagKeys:    []string{"tag1"},
+				FieldKeys:  map[string][]string{"health": {"status", "alerting", "uptime"}},
+				FieldTypes: map[string][]string{"health": {"string", "bool", "float64"}},
 			},
 		},
 		{
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// dbCreatorV2 manages the bucket the data is written to through the
+// InfluxDB v2 API. The bucket takes the place of the database, so it is
+// named after the database name.
+type dbCreatorV2 struct {
+	daemonURL string
+	org       string
+	token     string
+	orgID     string
+	client    *http.Client
+}
+
+func (d *dbCreatorV2) Init() {
+	d.daemonURL = daemonURLs[0] // pick first one since it always exists
+	d.org = org
+	d.token = token
+	d.client = &http.Client{}
+
+	id, err := d.findOrgID()
+	if err != nil {
+		log.Fatal(err)
+	}
+	d.orgID = id
+}
+
+// do sends a request with the token to the v2 API and decodes the JSON
+// response into v, unless v is nil. The status code of the response must
+// be the expected one.
+func (d *dbCreatorV2) do(method, path string, body interface{}, expected int, v interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, d.daemonURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(headerAuthorization, "Token "+d.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != expected {
+		return fmt.Errorf("%s %s returned code %d: %s", method, path, resp.StatusCode, respBody)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, v)
+}
+
+func (d *dbCreatorV2) findOrgID() (string, error) {
+	var listing struct {
+		Orgs []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"orgs"`
+	}
+	err := d.do(http.MethodGet, "/api/v2/orgs?org="+url.QueryEscape(d.org), nil, http.StatusOK, &listing)
+	if err != nil {
+		return "", fmt.Errorf("find org error: %s", err.Error())
+	}
+	for _, o := range listing.Orgs {
+		if o.Name == d.org {
+			return o.ID, nil
+		}
+	}
+	return "", fmt.Errorf("org %s not found", d.org)
+}
+
+// findBucketID returns the ID of the bucket with the given name, or an
+// empty string if the org has no such bucket.
+func (d *dbCreatorV2) findBucketID(name string) (string, error) {
+	var listing struct {
+		Buckets []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"buckets"`
+	}
+	v := url.Values{}
+	v.Set("orgID", d.orgID)
+	v.Set("name", name)
+	err := d.do(http.MethodGet, "/api/v2/buckets?"+v.Encode(), nil, http.StatusOK, &listing)
+	if err != nil {
+		return "", fmt.Errorf("list buckets error: %s", err.Error())
+	}
+	for _, b := range listing.Buckets {
+		if b.Name == name {
+			return b.ID, nil
+		}
+	}
+	return "", nil
+}
+
+func (d *dbCreatorV2) DBExists(dbName string) bool {
+	id, err := d.findBucketID(dbName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return id != ""
+}
+
+func (d *dbCreatorV2) RemoveOldDB(dbName string) error {
+	id, err := d.findBucketID(dbName)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+	if err := d.do(http.MethodDelete, "/api/v2/buckets/"+id, nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("drop bucket error: %s", err.Error())
+	}
+	return nil
+}
+
+func (d *dbCreatorV2) CreateDB(dbName string) error {
+	bucket := map[string]interface{}{
+		"orgID":          d.orgID,
+		"name":           dbName,
+		"retentionRules": []interface{}{},
+	}
+	if err := d.do(http.MethodPost, "/api/v2/buckets", bucket, http.StatusCreated, nil); err != nil {
+		return fmt.Errorf("create bucket error: %s", err.Error())
+	}
+	return nil
+}
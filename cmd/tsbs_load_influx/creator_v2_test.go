@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newFakeV2Server serves the parts of the InfluxDB v2 API used by
+// dbCreatorV2, keeping the buckets of a single org in memory.
+func newFakeV2Server(t *testing.T, buckets map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(headerAuthorization); got != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/orgs":
+			if r.URL.Query().Get("org") != "my-org" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"orgs": []interface{}{}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"orgs": []map[string]string{{"id": "org1", "name": "my-org"}},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/buckets":
+			if got := r.URL.Query().Get("orgID"); got != "org1" {
+				t.Errorf("incorrect orgID: got %s want org1", got)
+			}
+			name := r.URL.Query().Get("name")
+			list := []map[string]string{}
+			if id, ok := buckets[name]; ok {
+				list = append(list, map[string]string{"id": id, "name": name})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"buckets": list})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/buckets":
+			var b struct {
+				OrgID string `json:"orgID"`
+				Name  string `json:"name"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&b); err != nil || b.OrgID != "org1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			buckets[b.Name] = "id-" + b.Name
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/buckets/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v2/buckets/")
+			for name, bid := range buckets {
+				if bid == id {
+					delete(buckets, name)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDBCreatorV2(t *testing.T) {
+	buckets := map[string]string{"other": "id-other"}
+	s := newFakeV2Server(t, buckets)
+	defer s.Close()
+
+	d := &dbCreatorV2{daemonURL: s.URL, org: "my-org", token: "secret", client: s.Client()}
+	id, err := d.findOrgID()
+	if err != nil {
+		t.Fatalf("unexpected error finding org: %v", err)
+	}
+	if id != "org1" {
+		t.Fatalf("incorrect org id: got %s want org1", id)
+	}
+	d.orgID = id
+
+	if d.DBExists("benchmark") {
+		t.Errorf("bucket should not exist before it is created")
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error creating bucket: %v", err)
+	}
+	if !d.DBExists("benchmark") {
+		t.Errorf("bucket should exist after it is created")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error removing bucket: %v", err)
+	}
+	if d.DBExists("benchmark") {
+		t.Errorf("bucket should not exist after it is removed")
+	}
+	if _, ok := buckets["other"]; !ok {
+		t.Errorf("other bucket should not be removed")
+	}
+	// removing a missing bucket is not an error
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Errorf("unexpected error removing missing bucket: %v", err)
+	}
+}
+
+func TestDBCreatorV2Errors(t *testing.T) {
+	s := newFakeV2Server(t, map[string]string{})
+	defer s.Close()
+
+	d := &dbCreatorV2{daemonURL: s.URL, org: "missing", token: "secret", client: s.Client()}
+	if _, err := d.findOrgID(); err == nil {
+		t.Errorf("expected error for missing org")
+	}
+
+	d = &dbCreatorV2{daemonURL: s.URL, org: "my-org", token: "wrong", client: s.Client()}
+	if _, err := d.findOrgID(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected unauthorized error for wrong token, got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ const (
 	httpClientName        = "tsbs_load_influx"
 	headerContentEncoding = "Content-Encoding"
 	headerGzip            = "gzip"
+	headerAuthorization   = "Authorization"
 )
 
 var (
@@ -34,8 +35,18 @@ type HTTPWriterConfig struct {
 	Host string
 
 	// Name of the target database into which points will be written.
+	// With version 2 of the API this is the name of the bucket.
 	Database string
 
+	// Version of the InfluxDB API to write with, 1 or 2.
+	APIVersion int
+
+	// Organization owning the bucket (only for version 2 of the API).
+	Org string
+
+	// Token used to authenticate the writes (only for version 2 of the API).
+	Token string
+
 	// Debug label for more informative errors.
 	DebugInfo string
 }
@@ -49,14 +60,21 @@ type HTTPWriter struct {
 }
 
 // NewHTTPWriter returns a new HTTPWriter from the supplied HTTPWriterConfig.
+// The consistency only applies to version 1 of the API.
 func NewHTTPWriter(c HTTPWriterConfig, consistency string) *HTTPWriter {
+	var u string
+	if c.APIVersion == 2 {
+		u = c.Host + "/api/v2/write?org=" + url.QueryEscape(c.Org) + "&bucket=" + url.QueryEscape(c.Database) + "&precision=ns"
+	} else {
+		u = c.Host + "/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)
+	}
 	return &HTTPWriter{
 		client: fasthttp.Client{
 			Name: httpClientName,
 		},
 
 		c:   c,
-		url: []byte(c.Host + "/write?consistency=" + consistency + "&db=" + url.QueryEscape(c.Database)),
+		url: []byte(u),
 	}
 }
 
@@ -72,6 +90,9 @@ func (w *HTTPWriter) initializeReq(req *fasthttp.Request, body []byte, isGzip bo
 	if isGzip {
 		req.Header.Add(headerContentEncoding, headerGzip)
 	}
+	if w.c.Token != "" {
+		req.Header.Add(headerAuthorization, "Token "+w.c.Token)
+	}
 	req.SetBody(body)
 }
 
@@ -109,6 +109,28 @@ func TestNewHTTPWriter(t *testing.T) {
 	}
 }
 
+func TestNewHTTPWriterV2(t *testing.T) {
+	conf := HTTPWriterConfig{
+		Host:       "http://localhost:8086",
+		Database:   "bench mark",
+		APIVersion: 2,
+		Org:        "my-org",
+		Token:      "secret",
+	}
+	w := NewHTTPWriter(conf, testConsistency)
+	want := "http://localhost:8086/api/v2/write?org=my-org&bucket=bench+mark&precision=ns"
+	if got := string(w.url); got != want {
+		t.Errorf("incorrect v2 url: got %s want %s", got, want)
+	}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	w.initializeReq(req, []byte("body"), false)
+	if got := string(req.Header.Peek(headerAuthorization)); got != "Token secret" {
+		t.Errorf("incorrect Authorization header: got %s want Token secret", got)
+	}
+}
+
 func TestHTTPWriterInitializeReq(t *testing.T) {
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
@@ -128,6 +150,9 @@ func TestHTTPWriterInitializeReq(t *testing.T) {
 	if got := string(req.Header.Peek(headerContentEncoding)); got != "" {
 		t.Errorf("non-gzip: Content-Encoding is not empty: got %s", got)
 	}
+	if got := string(req.Header.Peek(headerAuthorization)); got != "" {
+		t.Errorf("non-gzip: Authorization is not empty without a token: got %s", got)
+	}
 
 	w.initializeReq(req, []byte(body), true)
 	if got := string(req.Header.Peek(headerContentEncoding)); got != headerGzip {
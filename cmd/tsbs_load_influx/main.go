@@ -30,6 +30,9 @@ var (
 	useGzip           bool
 	doAbortOnExist    bool
 	consistency       string
+	apiVersion        int
+	org               string
+	token             string
 )
 
 // Global vars
@@ -75,10 +78,22 @@ func init() {
 	consistency = viper.GetString("consistency")
 	backoff = viper.GetDuration("backoff")
 	useGzip = viper.GetBool("gzip")
+	apiVersion = viper.GetInt("api-version")
+	org = viper.GetString("org")
+	token = viper.GetString("token")
 
 	if _, ok := consistencyChoices[consistency]; !ok {
 		log.Fatalf("invalid consistency settings")
 	}
+	switch apiVersion {
+	case 1:
+	case 2:
+		if org == "" {
+			log.Fatal("missing 'org' flag, required for api-version 2")
+		}
+	default:
+		log.Fatalf("invalid api-version %d, must be 1 or 2", apiVersion)
+	}
 
 	daemonURLs = strings.Split(csvDaemonURLs, ",")
 	if len(daemonURLs) == 0 {
@@ -107,6 +122,9 @@ func (b *benchmark) GetProcessor() targets.Processor {
 }
 
 func (b *benchmark) GetDBCreator() targets.DBCreator {
+	if apiVersion == 2 {
+		return &dbCreatorV2{}
+	}
 	return &dbCreator{}
 }
 
@@ -23,9 +23,12 @@ type processor struct {
 func (p *processor) Init(numWorker int, _, _ bool) {
 	daemonURL := daemonURLs[numWorker%len(daemonURLs)]
 	cfg := HTTPWriterConfig{
-		DebugInfo: fmt.Sprintf("worker #%d, dest url: %s", numWorker, daemonURL),
-		Host:      daemonURL,
-		Database:  loader.DatabaseName(),
+		DebugInfo:  fmt.Sprintf("worker #%d, dest url: %s", numWorker, daemonURL),
+		Host:       daemonURL,
+		Database:   loader.DatabaseName(),
+		APIVersion: apiVersion,
+		Org:        org,
+		Token:      token,
 	}
 	w := NewHTTPWriter(cfg, consistency)
 	p.initWithHTTPWriter(numWorker, w)
@@ -23,12 +23,20 @@ const (
 	aggKeyID           = "key_id"
 	aggInsertBatchSize = 500 // found via trial-and-error
 	timestampField     = "timestamp_ns"
+	tsTimeField        = "time"
+	tsMetaField        = "meta"
 )
 
+// Granularities of a time-series collection, i.e. the expected interval
+// between the events of a series
+var timeseriesGranularities = map[string]bool{"seconds": true, "minutes": true, "hours": true}
+
 // Program option vars:
 var (
 	daemonURL    string
 	documentPer  bool
+	timeseries   bool
+	granularity  string
 	writeTimeout time.Duration
 )
 
@@ -61,7 +69,18 @@ func init() {
 	daemonURL = viper.GetString("url")
 	writeTimeout = viper.GetDuration("write-timeout")
 	documentPer = viper.GetBool("document-per-event")
-	if documentPer {
+	timeseries = viper.GetBool("timeseries")
+	granularity = viper.GetString("timeseries-granularity")
+	if timeseries {
+		if documentPer {
+			panic("timeseries and document-per-event are mutually exclusive")
+		}
+		if !timeseriesGranularities[granularity] {
+			panic(fmt.Sprintf("invalid timeseries-granularity %s, must be seconds, minutes or hours", granularity))
+		}
+		// hash-workers is left as configured: it keeps the events of a
+		// series, i.e. of a bucket, on one worker, but is not required
+	} else if documentPer {
 		config.HashWorkers = false
 	} else {
 		config.HashWorkers = true
@@ -72,7 +91,9 @@ func init() {
 
 func main() {
 	var benchmark targets.Benchmark
-	if documentPer {
+	if timeseries {
+		benchmark = newTimeseriesBenchmark(loader, &config)
+	} else if documentPer {
 		benchmark = newNaiveBenchmark(loader, &config)
 	} else {
 		benchmark = newAggBenchmark(loader, &config)
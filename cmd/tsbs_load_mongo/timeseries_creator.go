@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// codeNamespaceExists is returned when creating a collection that exists
+const codeNamespaceExists = 48
+
+// timeseriesDBCreator creates the time-series collection with the official
+// driver, since mgo does not speak the OP_MSG protocol required by MongoDB
+// 6.0 and later.
+type timeseriesDBCreator struct {
+	client *mongo.Client
+}
+
+// mongoURI returns the connection string of a daemon URL, which mgo allows
+// to be given without the mongodb:// scheme.
+func mongoURI(url string) string {
+	if strings.Contains(url, "://") {
+		return url
+	}
+	return "mongodb://" + url
+}
+
+func (d *timeseriesDBCreator) Init() {
+	var err error
+	d.client, err = mongo.Connect(options.Client().ApplyURI(mongoURI(daemonURL)).SetTimeout(writeTimeout))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := d.client.Ping(context.Background(), nil); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (d *timeseriesDBCreator) DBExists(dbName string) bool {
+	dbs, err := d.client.ListDatabaseNames(context.Background(), bson.D{{Key: "name", Value: dbName}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return len(dbs) > 0
+}
+
+func (d *timeseriesDBCreator) RemoveOldDB(dbName string) error {
+	return d.client.Database(dbName).Drop(context.Background())
+}
+
+func (d *timeseriesDBCreator) CreateDB(dbName string) error {
+	ctx := context.Background()
+	db := d.client.Database(dbName)
+	// time-series collections compress their buckets on their own
+	ts := options.TimeSeries().SetTimeField(tsTimeField).SetMetaField(tsMetaField).SetGranularity(granularity)
+	err := db.CreateCollection(ctx, collectionName, options.CreateCollection().SetTimeSeriesOptions(ts))
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.HasErrorCode(codeNamespaceExists) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("create collection err: %v", err)
+	}
+
+	_, err = db.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: tsMetaField + ".measurement", Value: 1},
+			{Key: tsMetaField + ".hostname", Value: 1},
+			{Key: tsTimeField, Value: 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create basic index err: %v", err)
+	}
+	return nil
+}
+
+func (d *timeseriesDBCreator) Close() {
+	if err := d.client.Disconnect(context.Background()); err != nil {
+		log.Printf("could not disconnect: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/mongo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	driver "go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// timeseriesBenchmark allows you to run a benchmark using a native time-series
+// collection, available since MongoDB 5.0. Unlike the other layouts, it is
+// loaded with the official driver, see timeseriesDBCreator.
+type timeseriesBenchmark struct {
+	mongoBenchmark
+	dbc *timeseriesDBCreator
+}
+
+func newTimeseriesBenchmark(l load.BenchmarkRunner, loaderConf *load.BenchmarkRunnerConfig) *timeseriesBenchmark {
+	return &timeseriesBenchmark{
+		mongoBenchmark: mongoBenchmark{loaderFileName: loaderConf.FileName, l: l},
+		dbc:            &timeseriesDBCreator{},
+	}
+}
+
+func (b *timeseriesBenchmark) GetProcessor() targets.Processor {
+	return &timeseriesProcessor{dbc: b.dbc}
+}
+
+func (b *timeseriesBenchmark) GetDBCreator() targets.DBCreator {
+	return b.dbc
+}
+
+func (b *timeseriesBenchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &hostnameIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+type timeseriesProcessor struct {
+	dbc        *timeseriesDBCreator
+	collection *driver.Collection
+
+	docs []interface{}
+}
+
+func (p *timeseriesProcessor) Init(_ int, doLoad, _ bool) {
+	if doLoad {
+		p.collection = p.dbc.client.Database(loader.DatabaseName()).Collection(collectionName)
+	}
+	p.docs = []interface{}{}
+}
+
+// ProcessBatch inserts a document per event into the time-series collection,
+// which MongoDB stores in buckets of events sharing the same meta field.
+//
+// A document is structured like so:
+//
+//	{
+//	  "time": ISODate("2016-01-01T00:00:00Z"),
+//	  "meta": {
+//	    "measurement": "cpu",
+//	    "hostname": "host_0",
+//	    ...
+//	  },
+//	  "usage_user": 58.0,
+//	  ...
+//	}
+//
+// The meta field is an ordered document, since events whose meta fields only
+// differ in the order of their keys would otherwise end up in separate buckets.
+func (p *timeseriesProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+	batch := b.(*batch).arr
+	if cap(p.docs) < len(batch) {
+		p.docs = make([]interface{}, len(batch))
+	}
+	p.docs = p.docs[:len(batch)]
+	var metricCnt uint64
+	for i, event := range batch {
+		meta := make(bson.D, 0, event.TagsLength()+1)
+		meta = append(meta, bson.E{Key: "measurement", Value: string(event.MeasurementName())})
+		t := &mongo.MongoTag{}
+		for j := 0; j < event.TagsLength(); j++ {
+			event.Tags(t, j)
+			meta = append(meta, bson.E{Key: string(t.Key()), Value: string(t.Value())})
+		}
+
+		doc := make(bson.D, 0, event.FieldsLength()+2)
+		doc = append(doc,
+			bson.E{Key: tsTimeField, Value: time.Unix(0, event.Timestamp()).UTC()},
+			bson.E{Key: tsMetaField, Value: meta},
+		)
+		f := &mongo.MongoReading{}
+		for j := 0; j < event.FieldsLength(); j++ {
+			event.Fields(f, j)
+			doc = append(doc, bson.E{Key: string(f.Key()), Value: f.Value()})
+		}
+		p.docs[i] = doc
+		metricCnt += uint64(event.FieldsLength())
+	}
+
+	if doLoad {
+		_, err := p.collection.InsertMany(context.Background(), p.docs, options.InsertMany().SetOrdered(false))
+		if err != nil {
+			log.Fatalf("Bulk insert docs err: %s\n", err.Error())
+		}
+	}
+
+	return metricCnt, uint64(len(batch))
+}
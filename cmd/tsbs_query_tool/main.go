@@ -0,0 +1,90 @@
+// tsbs_query_tool inspects and curates query files generated by tsbs_generate_queries.
+//
+// It reads gob or JSON encoded queries from stdin or file and writes them to stdout
+// or file, by default as one human readable JSON object per line. On the way, it
+// can keep only the queries whose label matches a regular expression, take a
+// random sample of them, shuffle them and convert them between gob and JSON.
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/globalsign/mgo/bson"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+var config toolConfig
+
+// Parse args:
+func init() {
+	// needed for deserializing the mongo query from gob
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]map[string]interface{}{})
+	gob.Register(bson.M{})
+	gob.Register([]bson.M{})
+	gob.Register(bson.D{})
+	gob.Register(time.Time{})
+
+	formats := make([]string, 0, len(queryPools))
+	for f := range queryPools {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+
+	pflag.String("format", "", fmt.Sprintf("Format the queries were generated for. (choices: %s)", strings.Join(formats, ", ")))
+	pflag.String("file", "", "File name to read queries from, stdin if empty")
+	pflag.String("output", "", "File name to write queries to, stdout if empty")
+	pflag.String("encoding", query.EncodingJSON, fmt.Sprintf("Encoding of the written queries. (choices: %s)", strings.Join(query.EncodingChoices, ", ")))
+	pflag.String("label", "", "Only keep the queries whose human label matches this regular expression")
+	pflag.Uint64("sample", 0, "Keep a uniformly random sample of this many queries, in their original order (0 = keep all)")
+	pflag.Bool("shuffle", false, "Shuffle the order of the queries")
+	pflag.Int64("seed", 0, "PRNG seed for sampling and shuffling (default: 0, which uses the current timestamp)")
+
+	pflag.Parse()
+
+	if err := utils.SetupConfigFile(); err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+}
+
+func main() {
+	var in io.Reader = os.Stdin
+	if config.File != "" {
+		f, err := os.Open(config.File)
+		if err != nil {
+			log.Fatalf("cannot open file for read %s: %v", config.File, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var out io.Writer = os.Stdout
+	if config.Output != "" {
+		f, err := os.Create(config.Output)
+		if err != nil {
+			log.Fatalf("cannot open file for write %s: %v", config.Output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	n, err := transform(&config, in, out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d queries\n", n)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+const (
+	errUnknownFormatFmt  = "unknown query format: '%s'"
+	errBadLabelFmt       = "invalid label filter '%s': %v"
+	errCannotDecodeFmt   = "cannot decode query %d: %v"
+	errCannotEncodeFmt   = "cannot encode query %d: %v"
+	errCannotFlushOutFmt = "cannot flush output: %v"
+)
+
+// queryPools maps the formats to the pools of the query type their
+// tsbs_run_queries_ binary reads.
+var queryPools = map[string]*sync.Pool{
+	constants.FormatAkumuli:         &query.HTTPPool,
+	constants.FormatCassandra:       &query.CassandraPool,
+	constants.FormatClickhouse:      &query.ClickHousePool,
+	constants.FormatCrateDB:         &query.CrateDBPool,
+	constants.FormatElasticsearch:   &query.HTTPPool,
+	constants.FormatFileSink:        &query.FileSinkPool,
+	constants.FormatInflux:          &query.HTTPPool,
+	constants.FormatMongo:           &query.MongoPool,
+	constants.FormatQuestDB:         &query.TimescaleDBPool,
+	constants.FormatSiriDB:          &query.SiriDBPool,
+	constants.FormatTDengine:        &query.TimescaleDBPool,
+	constants.FormatTimescaleDB:     &query.TimescaleDBPool,
+	constants.FormatTimestream:      &query.TimestreamPool,
+	constants.FormatVictoriaMetrics: &query.HTTPPool,
+}
+
+// toolConfig holds the operations to apply to a query file.
+type toolConfig struct {
+	Format   string `mapstructure:"format"`
+	File     string `mapstructure:"file"`
+	Output   string `mapstructure:"output"`
+	Encoding string `mapstructure:"encoding"`
+	Label    string `mapstructure:"label"`
+	Sample   uint64 `mapstructure:"sample"`
+	Shuffle  bool   `mapstructure:"shuffle"`
+	Seed     int64  `mapstructure:"seed"`
+}
+
+// indexedQuery is a query kept in memory, with its position in the input.
+type indexedQuery struct {
+	index uint64
+	q     query.Query
+}
+
+// transform reads the queries from r, keeps the ones whose label matches, samples
+// and shuffles them, and writes them to w in the output encoding. It returns the
+// number of queries written.
+func transform(c *toolConfig, r io.Reader, w io.Writer) (uint64, error) {
+	pool, ok := queryPools[c.Format]
+	if !ok {
+		return 0, fmt.Errorf(errUnknownFormatFmt, c.Format)
+	}
+
+	var label *regexp.Regexp
+	if c.Label != "" {
+		var err error
+		if label, err = regexp.Compile(c.Label); err != nil {
+			return 0, fmt.Errorf(errBadLabelFmt, c.Label, err)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	enc, err := query.NewEncoder(bw, c.Encoding)
+	if err != nil {
+		return 0, err
+	}
+
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// Sampling and shuffling need the queries in memory, everything else streams.
+	inMemory := c.Sample > 0 || c.Shuffle
+	var kept []indexedQuery
+	written := uint64(0)
+	matched := uint64(0)
+
+	dec := query.NewDecoder(r)
+	for i := uint64(0); ; i++ {
+		q := pool.Get().(query.Query)
+		if err := dec.Decode(q); err == io.EOF {
+			q.Release()
+			break
+		} else if err != nil {
+			return written, fmt.Errorf(errCannotDecodeFmt, i, err)
+		}
+
+		if label != nil && !label.Match(q.HumanLabelName()) {
+			q.Release()
+			continue
+		}
+		matched++
+
+		switch {
+		case !inMemory:
+			if err := enc.Encode(q); err != nil {
+				return written, fmt.Errorf(errCannotEncodeFmt, i, err)
+			}
+			written++
+			q.Release()
+		case c.Sample == 0 || uint64(len(kept)) < c.Sample:
+			kept = append(kept, indexedQuery{index: i, q: q})
+		default:
+			// Reservoir sampling keeps every query with the same probability.
+			if j := uint64(rng.Int63n(int64(matched))); j < c.Sample {
+				kept[j].q.Release()
+				kept[j] = indexedQuery{index: i, q: q}
+			} else {
+				q.Release()
+			}
+		}
+	}
+
+	if c.Shuffle {
+		rng.Shuffle(len(kept), func(i, j int) { kept[i], kept[j] = kept[j], kept[i] })
+	} else {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].index < kept[j].index })
+	}
+	for _, k := range kept {
+		if err := enc.Encode(k.q); err != nil {
+			return written, fmt.Errorf(errCannotEncodeFmt, k.index, err)
+		}
+		written++
+		k.q.Release()
+	}
+
+	if err := bw.Flush(); err != nil {
+		return written, fmt.Errorf(errCannotFlushOutFmt, err)
+	}
+	return written, nil
+}
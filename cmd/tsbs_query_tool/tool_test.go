@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func encodeTestQueries(t *testing.T, encoding string, n int) *bytes.Buffer {
+	var buf bytes.Buffer
+	enc, err := query.NewEncoder(&buf, encoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		label := "TimescaleDB max cpu"
+		if i%2 == 1 {
+			label = "TimescaleDB lastpoint"
+		}
+		q := &query.TimescaleDB{
+			HumanLabel:       []byte(label),
+			HumanDescription: []byte(fmt.Sprintf("query %d", i)),
+			Hypertable:       []byte("cpu"),
+			SqlQuery:         []byte(fmt.Sprintf("SELECT %d", i)),
+		}
+		if err := enc.Encode(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+func decodeTestQueries(t *testing.T, r io.Reader) []string {
+	var descs []string
+	dec := query.NewDecoder(r)
+	for {
+		var q query.TimescaleDB
+		err := dec.Decode(&q)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected decode error: %v", err)
+		}
+		descs = append(descs, string(q.HumanDescription))
+	}
+	return descs
+}
+
+func TestTransform(t *testing.T) {
+	cases := []struct {
+		desc   string
+		input  string
+		config toolConfig
+		want   []string
+	}{
+		{
+			desc:   "convert to json",
+			input:  query.EncodingGob,
+			config: toolConfig{Encoding: query.EncodingJSON},
+			want:   []string{"query 0", "query 1", "query 2", "query 3", "query 4", "query 5"},
+		},
+		{
+			desc:   "convert to gob",
+			input:  query.EncodingJSON,
+			config: toolConfig{Encoding: query.EncodingGob},
+			want:   []string{"query 0", "query 1", "query 2", "query 3", "query 4", "query 5"},
+		},
+		{
+			desc:   "filter by label",
+			input:  query.EncodingGob,
+			config: toolConfig{Label: "lastpoint$"},
+			want:   []string{"query 1", "query 3", "query 5"},
+		},
+		{
+			desc:   "sample keeps order",
+			input:  query.EncodingJSON,
+			config: toolConfig{Sample: 3, Seed: 123},
+			want:   []string{"query 0", "query 2", "query 4"},
+		},
+		{
+			desc:   "shuffle",
+			input:  query.EncodingGob,
+			config: toolConfig{Shuffle: true, Seed: 123},
+			want:   []string{"query 2", "query 5", "query 1", "query 4", "query 0", "query 3"},
+		},
+	}
+
+	for _, c := range cases {
+		c.config.Format = constants.FormatTimescaleDB
+		var out bytes.Buffer
+		n, err := transform(&c.config, encodeTestQueries(t, c.input, 6), &out)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if n != uint64(len(c.want)) {
+			t.Errorf("%s: incorrect number of queries written: got %d want %d", c.desc, n, len(c.want))
+		}
+		if c.config.Encoding == query.EncodingJSON && out.Bytes()[0] != '{' {
+			t.Errorf("%s: output is not JSON", c.desc)
+		}
+		got := decodeTestQueries(t, &out)
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: incorrect queries: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	cases := []struct {
+		desc   string
+		config toolConfig
+	}{
+		{desc: "unknown format", config: toolConfig{Format: "foo"}},
+		{desc: "bad label", config: toolConfig{Format: constants.FormatTimescaleDB, Label: "("}},
+		{desc: "bad encoding", config: toolConfig{Format: constants.FormatTimescaleDB, Encoding: "xml"}},
+	}
+	for _, c := range cases {
+		if _, err := transform(&c.config, encodeTestQueries(t, query.EncodingGob, 1), ioutil.Discard); err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		}
+	}
+}
@@ -25,12 +25,14 @@ var (
 	user      string
 	password  string
 
-	showExplain bool
+	showExplain  bool
+	capturePlans string
 )
 
 // Global vars:
 var (
-	runner *query.BenchmarkRunner
+	runner       *query.BenchmarkRunner
+	planRecorder *query.PlanRecorder
 )
 
 // Parse args:
@@ -45,6 +47,7 @@ func init() {
 		"Comma separated list of ClickHouse hosts (pass multiple values for sharding reads on a multi-node setup)")
 	pflag.String("user", "default", "User to connect to ClickHouse as")
 	pflag.String("password", "", "Password to connect to ClickHouse")
+	pflag.String("capture-plans", "", "Write the EXPLAIN plan (with the indexes used) of the first query of every label to this file once all queries have run, one JSON object per line")
 
 	pflag.Parse()
 
@@ -62,6 +65,7 @@ func init() {
 	hosts = viper.GetString("hosts")
 	user = viper.GetString("user")
 	password = viper.GetString("password")
+	capturePlans = viper.GetString("capture-plans")
 
 	// Parse comma separated string of hosts and put in a slice (for multi-node setups)
 	for _, host := range strings.Split(hosts, ",") {
@@ -69,10 +73,49 @@ func init() {
 	}
 
 	runner = query.NewBenchmarkRunner(config)
+
+	if capturePlans != "" {
+		planRecorder, err = query.NewPlanRecorder(capturePlans)
+		if err != nil {
+			panic(err)
+		}
+	}
 }
 
 func main() {
 	runner.Run(&query.ClickHousePool, newProcessor)
+	if planRecorder != nil {
+		if err := recordPlans(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// recordPlans captures the plans of the queries claimed during the benchmark,
+// along with the ClickHouse version. ClickHouse cannot EXPLAIN ANALYZE, so the
+// plans list the indexes and parts the queries read instead of the actual
+// execution.
+func recordPlans() error {
+	defer planRecorder.Close()
+	db, err := sqlx.Connect("clickhouse", getConnectString(0))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.Get(&version, "SELECT version()"); err != nil {
+		return err
+	}
+	return planRecorder.Capture(func(p *query.Plan) error {
+		p.ServerVersion = version
+		var lines []string
+		if err := db.Select(&lines, p.Explain); err != nil {
+			return err
+		}
+		p.Plan = lines
+		return nil
+	})
 }
 
 // Get the connection string for a connection to PostgreSQL.
@@ -176,5 +219,10 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
+	// The plan is only captured once the benchmark is done (see recordPlans)
+	if !isWarm {
+		planRecorder.Claim(q, sql, "EXPLAIN indexes = 1 "+sql)
+	}
+
 	return []*query.Stat{stat}, err
 }
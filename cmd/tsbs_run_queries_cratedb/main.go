@@ -18,14 +18,18 @@ import (
 )
 
 var (
-	hosts       string
-	user        string
-	pass        string
-	port        int
-	showExplain bool
+	hosts        string
+	user         string
+	pass         string
+	port         int
+	showExplain  bool
+	capturePlans string
 )
 
-var runner *query.BenchmarkRunner
+var (
+	runner       *query.BenchmarkRunner
+	planRecorder *query.PlanRecorder
+)
 
 func init() {
 	var config query.BenchmarkRunnerConfig
@@ -36,6 +40,7 @@ func init() {
 	pflag.String("pass", "", "Password for user connecting to CrateDB")
 	pflag.Int("port", 5432, "A port to connect to database instances")
 	pflag.Bool("show-explain", false, "Print out the EXPLAIN output for sample query")
+	pflag.String("capture-plans", "", "Write the EXPLAIN ANALYZE output of the first query of every label to this file once all queries have run, one JSON object per line")
 
 	pflag.Parse()
 
@@ -54,9 +59,17 @@ func init() {
 	pass = viper.GetString("pass")
 	port = viper.GetInt("port")
 	showExplain = viper.GetBool("show-explain")
+	capturePlans = viper.GetString("capture-plans")
 
 	runner = query.NewBenchmarkRunner(config)
 
+	if capturePlans != "" {
+		planRecorder, err = query.NewPlanRecorder(capturePlans)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if showExplain {
 		runner.SetLimit(1)
 	}
@@ -70,6 +83,41 @@ func main() {
 	runner.Run(&query.CrateDBPool, func() query.Processor {
 		return processor
 	})
+	if planRecorder != nil {
+		if err := recordPlans(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// recordPlans captures the plans of the queries claimed during the benchmark
+// with EXPLAIN ANALYZE, along with the CrateDB version.
+func recordPlans() error {
+	defer planRecorder.Close()
+	connCfg, err := parseConnConfig()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	conn, err := pgx.ConnectConfig(ctx, connCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	var version string
+	if err := conn.QueryRow(ctx, "SELECT version['number'] FROM sys.nodes LIMIT 1").Scan(&version); err != nil {
+		return err
+	}
+	return planRecorder.Capture(func(p *query.Plan) error {
+		p.ServerVersion = version
+		var analyzed interface{}
+		if err := conn.QueryRow(ctx, p.Explain).Scan(&analyzed); err != nil {
+			return err
+		}
+		p.Plan = analyzed
+		return nil
+	})
 }
 
 type processor struct {
@@ -84,12 +132,20 @@ type executorOptions struct {
 	printResponse bool
 }
 
-func newProcessor() (query.Processor, error) {
+func parseConnConfig() (*pgx.ConnConfig, error) {
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password='%s' dbname=%s", hosts, port, user, pass, runner.DatabaseName())
 	connConfig, err := pgx.ParseConfig(connStr)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not parse connection config")
 	}
+	return connConfig, nil
+}
+
+func newProcessor() (query.Processor, error) {
+	connConfig, err := parseConnConfig()
+	if err != nil {
+		return nil, err
+	}
 	return &processor{
 		connCfg: connConfig,
 		opts: &executorOptions{
@@ -141,6 +197,11 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
+	// The plan is only captured once the benchmark is done (see recordPlans)
+	if !isWarm {
+		planRecorder.Claim(q, string(tq.SqlQuery), "EXPLAIN ANALYZE "+string(tq.SqlQuery))
+	}
+
 	return []*query.Stat{stat}, err
 }
 
@@ -0,0 +1,95 @@
+// tsbs_run_queries_filesink speed tests the file sink using requests from stdin or file.
+//
+// It reads encoded Query objects from stdin, and concurrently runs their
+// plans over the CSV files written by the file sink loader. As nothing but
+// the files is involved, it gives a baseline of the query benchmark itself.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/filesink"
+)
+
+// Program option vars:
+var (
+	dbDir string
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("dir", "./filesink", "Directory holding a directory of CSV files for every database")
+
+	pflag.Parse()
+
+	if err := utils.SetupConfigFile(); err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	runner = query.NewBenchmarkRunner(config)
+	dbDir = filepath.Join(viper.GetString("dir"), runner.DatabaseName())
+}
+
+func main() {
+	runner.Run(&query.FileSinkPool, newProcessor)
+}
+
+func newProcessor() query.Processor {
+	return &processor{}
+}
+
+// query.Processor interface implementation
+type processor struct {
+	prettyPrintResponses bool
+}
+
+// query.Processor interface implementation
+func (p *processor) Init(workerNum int) {
+	p.prettyPrintResponses = runner.DoPrintResponses()
+}
+
+// query.Processor interface implementation
+func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	fq := q.(*query.FileSink)
+	var plan filesink.Plan
+	if err := json.Unmarshal(fq.Plan, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan: %v", err)
+	}
+
+	start := time.Now()
+	res, err := filesink.Execute(dbDir, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("query execution error: %v", err)
+	}
+	lag := float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
+
+	if p.prettyPrintResponses {
+		out, err := json.MarshalIndent(map[string]interface{}{"plan": plan, "results": res}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "ID %d: %s\n", q.GetID(), out)
+	}
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), lag)
+	return []*query.Stat{stat}, nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -30,6 +32,9 @@ type HTTPClientDoOptions struct {
 	PrettyPrintResponses bool
 	chunkSize            uint64
 	database             string
+	apiVersion           int
+	org                  string
+	token                string
 }
 
 var httpClientOnce = sync.Once{}
@@ -57,23 +62,44 @@ func NewHTTPClient(host string) *HTTPClient {
 
 // Do performs the action specified by the given Query. It uses fasthttp, and
 // tries to minimize heap allocations.
+//
+// Queries with a body are Flux queries for the InfluxDB v2 query API, which
+// are posted to the org. All other queries are InfluxQL queries, sent to the
+// database in the query string.
 func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, err error) {
+	isFlux := len(q.Body) > 0
+
 	// populate uri from the reusable byte slice:
 	w.uri = w.uri[:0]
 	w.uri = append(w.uri, w.Host...)
 	//w.uri = append(w.uri, bytesSlash...)
 	w.uri = append(w.uri, q.Path...)
-	w.uri = append(w.uri, []byte("&db="+url.QueryEscape(opts.database))...)
-	if opts.chunkSize > 0 {
-		s := fmt.Sprintf("&chunked=true&chunk_size=%d", opts.chunkSize)
-		w.uri = append(w.uri, []byte(s)...)
+	if isFlux {
+		w.uri = append(w.uri, []byte("?org="+url.QueryEscape(opts.org))...)
+	} else {
+		w.uri = append(w.uri, []byte("&db="+url.QueryEscape(opts.database))...)
+		if opts.chunkSize > 0 {
+			s := fmt.Sprintf("&chunked=true&chunk_size=%d", opts.chunkSize)
+			w.uri = append(w.uri, []byte(s)...)
+		}
 	}
 
 	// populate a request with data from the Query:
-	req, err := http.NewRequest(string(q.Method), string(w.uri), nil)
+	var body io.Reader
+	if isFlux {
+		body = bytes.NewReader(q.Body)
+	}
+	req, err := http.NewRequest(string(q.Method), string(w.uri), body)
 	if err != nil {
 		panic(err)
 	}
+	if isFlux {
+		req.Header.Set("Content-Type", "application/vnd.flux")
+		req.Header.Set("Accept", "application/csv")
+	}
+	if opts.apiVersion == 2 {
+		req.Header.Set("Authorization", "Token "+opts.token)
+	}
 
 	// Perform the request while tracking latency:
 	start := time.Now()
@@ -86,8 +112,8 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 		panic("http request did not return status 200 OK")
 	}
 
-	var body []byte
-	body, err = ioutil.ReadAll(resp.Body)
+	var respBody []byte
+	respBody, err = ioutil.ReadAll(resp.Body)
 
 	if err != nil {
 		panic(err)
@@ -108,21 +134,27 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 		case 4:
 			fmt.Fprintf(os.Stderr, "debug: %s in %7.2fms -- %s\n", q.HumanLabel, lag, q.HumanDescription)
 			fmt.Fprintf(os.Stderr, "debug:   request: %s\n", string(q.String()))
-			fmt.Fprintf(os.Stderr, "debug:   response: %s\n", string(body))
+			fmt.Fprintf(os.Stderr, "debug:   response: %s\n", string(respBody))
 		default:
 		}
 
 		// Pretty print JSON responses, if applicable:
 		if opts.PrettyPrintResponses {
 			// Assumes the response is JSON! This holds for Influx
-			// and Elastic.
+			// and Elastic. Flux responses are annotated CSV and
+			// printed as is.
 
 			prefix := fmt.Sprintf("ID %d: ", q.GetID())
 			var v interface{}
 			var line []byte
 			full := make(map[string]interface{})
-			full["influxql"] = string(q.RawQuery)
-			json.Unmarshal(body, &v)
+			if isFlux {
+				full["flux"] = string(q.RawQuery)
+				v = string(respBody)
+			} else {
+				full["influxql"] = string(q.RawQuery)
+				json.Unmarshal(respBody, &v)
+			}
 			full["response"] = v
 			line, err = json.MarshalIndent(full, prefix, "  ")
 			if err != nil {
@@ -20,6 +20,9 @@ import (
 var (
 	daemonUrls []string
 	chunkSize  uint64
+	apiVersion int
+	org        string
+	token      string
 )
 
 // Global vars:
@@ -35,6 +38,9 @@ func init() {
 
 	pflag.String("urls", "http://localhost:8086", "Daemon URLs, comma-separated. Will be used in a round-robin fashion.")
 	pflag.Uint64("chunk-response-size", 0, "Number of series to chunk results into. 0 means no chunking.")
+	pflag.Int("api-version", 1, "InfluxDB API version, 1 or 2. Version 2 sends Flux queries to /api/v2/query.")
+	pflag.String("org", "", "InfluxDB v2 organization to run the Flux queries as (only applies to api-version 2).")
+	pflag.String("token", "", "InfluxDB v2 API token (only applies to api-version 2).")
 
 	pflag.Parse()
 
@@ -50,11 +56,23 @@ func init() {
 
 	csvDaemonUrls = viper.GetString("urls")
 	chunkSize = viper.GetUint64("chunk-response-size")
+	apiVersion = viper.GetInt("api-version")
+	org = viper.GetString("org")
+	token = viper.GetString("token")
 
 	daemonUrls = strings.Split(csvDaemonUrls, ",")
 	if len(daemonUrls) == 0 {
 		log.Fatal("missing 'urls' flag")
 	}
+	switch apiVersion {
+	case 1:
+	case 2:
+		if org == "" {
+			log.Fatal("missing 'org' flag, required for api-version 2")
+		}
+	default:
+		log.Fatalf("invalid api-version %d, must be 1 or 2", apiVersion)
+	}
 
 	runner = query.NewBenchmarkRunner(config)
 }
@@ -76,6 +94,9 @@ func (p *processor) Init(workerNumber int) {
 		PrettyPrintResponses: runner.DoPrintResponses(),
 		chunkSize:            chunkSize,
 		database:             runner.DatabaseName(),
+		apiVersion:           apiVersion,
+		org:                  org,
+		token:                token,
 	}
 	url := daemonUrls[workerNumber%len(daemonUrls)]
 	p.w = NewHTTPClient(url)
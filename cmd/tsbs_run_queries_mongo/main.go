@@ -1,21 +1,25 @@
 // tsbs_run_queries_mongo speed tests Mongo using requests from stdin.
 //
 // It reads encoded Query objects from stdin, and makes concurrent requests
-// to the provided Mongo endpoint using mgo.
+// to the provided Mongo endpoint using the official driver.
 package main
 
 import (
+	"context"
 	"encoding/gob"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/blagojts/viper"
-	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/query"
+	driverbson "go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 // Program option vars:
@@ -26,18 +30,21 @@ var (
 
 // Global vars:
 var (
-	runner  *query.BenchmarkRunner
-	session *mgo.Session
+	runner *query.BenchmarkRunner
+	client *mongo.Client
 )
 
 // Parse args:
 func init() {
-	// needed for deserializing the mongo query from gob
+	// needed for deserializing the mongo query from gob, the queries are
+	// generated with the mgo bson types
 	gob.Register([]interface{}{})
 	gob.Register(map[string]interface{}{})
 	gob.Register([]map[string]interface{}{})
 	gob.Register(bson.M{})
 	gob.Register([]bson.M{})
+	gob.Register(bson.D{})
+	gob.Register(time.Time{})
 
 	var config query.BenchmarkRunnerConfig
 	config.AddToFlagSet(pflag.CommandLine)
@@ -63,38 +70,72 @@ func init() {
 	runner = query.NewBenchmarkRunner(config)
 }
 
+// mongoURI returns the connection string of a daemon URL, which mgo allowed
+// to be given without the mongodb:// scheme.
+func mongoURI(url string) string {
+	if strings.Contains(url, "://") {
+		return url
+	}
+	return "mongodb://" + url
+}
+
 func main() {
 	var err error
-	session, err = mgo.DialWithTimeout(daemonURL, timeout)
+	client, err = mongo.Connect(options.Client().ApplyURI(mongoURI(daemonURL)).SetConnectTimeout(timeout))
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer client.Disconnect(context.Background())
 	runner.Run(&query.MongoPool, newProcessor)
 }
 
 type processor struct {
-	collection *mgo.Collection
+	collection *mongo.Collection
 }
 
 func newProcessor() query.Processor { return &processor{} }
 
 func (p *processor) Init(workerNumber int) {
-	sess := session.Copy()
-	db := sess.DB(runner.DatabaseName())
-	p.collection = db.C("point_data")
+	p.collection = client.Database(runner.DatabaseName()).Collection("point_data")
+}
+
+// pipeline converts the stages of a query to BSON documents the driver
+// sends as they are.
+func pipeline(stages []bson.M) ([]driverbson.Raw, error) {
+	docs := make([]driverbson.Raw, len(stages))
+	for i, stage := range stages {
+		doc, err := bson.Marshal(stage)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+	return docs, nil
 }
 
 func (p *processor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
 	mq := q.(*query.Mongo)
+	docs, err := pipeline(mq.BsonDoc)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 	start := time.Now().UnixNano()
-	pipe := p.collection.Pipe(mq.BsonDoc).AllowDiskUse()
-	iter := pipe.Iter()
+	cursor, err := p.collection.Aggregate(ctx, docs, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
 	if runner.DebugLevel() > 0 {
 		fmt.Println(mq.BsonDoc)
 	}
 	var result map[string]interface{}
 	cnt := 0
-	for iter.Next(&result) {
+	for cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			cursor.Close(ctx)
+			return nil, err
+		}
 		if runner.DoPrintResponses() {
 			fmt.Printf("ID %d: %v\n", q.GetID(), result)
 		}
@@ -103,7 +144,8 @@ func (p *processor) ProcessQuery(q query.Query, _ bool) ([]*query.Stat, error) {
 	if runner.DebugLevel() > 0 {
 		fmt.Println(cnt)
 	}
-	err := iter.Close()
+	err = cursor.Err()
+	cursor.Close(ctx)
 
 	took := time.Now().UnixNano() - start
 	lag := float64(took) / 1e6 // milliseconds
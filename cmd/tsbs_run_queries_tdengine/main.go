@@ -0,0 +1,117 @@
+// tsbs_run_queries_tdengine speed tests TDengine using requests from stdin or file.
+//
+// It reads encoded Query objects from stdin, and makes concurrent requests
+// to the REST interface of the provided TDengine URLs, running the SQL of
+// every query in the benchmark database.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/query"
+	"github.com/timescale/tsbs/pkg/targets/tdengine"
+)
+
+// Program option vars:
+var (
+	urls []string
+	user string
+	pass string
+)
+
+// Global vars:
+var (
+	runner *query.BenchmarkRunner
+)
+
+// Parse args:
+func init() {
+	var config query.BenchmarkRunnerConfig
+	config.AddToFlagSet(pflag.CommandLine)
+
+	pflag.String("urls", "http://localhost:6041",
+		"Comma-separated list of URLs of the TDengine REST interface. Workers are assigned to them in round-robin")
+	pflag.String("user", "root", "User to connect to TDengine as")
+	pflag.String("pass", "taosdata", "Password of the user")
+
+	pflag.Parse()
+
+	if err := utils.SetupConfigFile(); err != nil {
+		panic(fmt.Errorf("fatal error config file: %s", err))
+	}
+	if err := viper.Unmarshal(&config); err != nil {
+		panic(fmt.Errorf("unable to decode config: %s", err))
+	}
+
+	list := viper.GetString("urls")
+	if len(list) == 0 {
+		log.Fatalf("missing `urls` flag")
+	}
+	urls = strings.Split(list, ",")
+	user = viper.GetString("user")
+	pass = viper.GetString("pass")
+	runner = query.NewBenchmarkRunner(config)
+}
+
+func main() {
+	runner.Run(&query.TimescaleDBPool, newProcessor)
+}
+
+func newProcessor() query.Processor {
+	return &processor{}
+}
+
+// query.Processor interface implementation
+type processor struct {
+	c *tdengine.Client
+
+	debug                bool
+	prettyPrintResponses bool
+}
+
+// query.Processor interface implementation
+func (p *processor) Init(workerNum int) {
+	p.c = tdengine.NewClient(urls[workerNum%len(urls)], user, pass)
+	p.debug = runner.DebugLevel() > 0
+	p.prettyPrintResponses = runner.DoPrintResponses()
+}
+
+// query.Processor interface implementation
+func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	tq := q.(*query.TimescaleDB)
+	if p.debug {
+		fmt.Println(string(tq.SqlQuery))
+	}
+
+	start := time.Now()
+	body, err := p.c.Do(runner.DatabaseName(), string(tq.SqlQuery))
+	if err != nil {
+		return nil, fmt.Errorf("query execution error: %v", err)
+	}
+	took := float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
+
+	// Pretty print JSON responses, if applicable:
+	if p.prettyPrintResponses {
+		var pretty bytes.Buffer
+		prefix := fmt.Sprintf("ID %d: ", q.GetID())
+		if err := json.Indent(&pretty, body, prefix, "  "); err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Fprintf(os.Stderr, "%s%s\n", prefix, pretty.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	stat := query.GetStat()
+	stat.Init(q.HumanLabelName(), took)
+	return []*query.Stat{stat}, nil
+}
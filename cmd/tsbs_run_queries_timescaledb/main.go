@@ -34,12 +34,14 @@ var (
 	port            string
 	showExplain     bool
 	forceTextFormat bool
+	capturePlans    string
 )
 
 // Global vars:
 var (
-	runner *query.BenchmarkRunner
-	driver string
+	runner       *query.BenchmarkRunner
+	driver       string
+	planRecorder *query.PlanRecorder
 )
 
 // Parse args:
@@ -56,6 +58,7 @@ func init() {
 
 	pflag.Bool("show-explain", false, "Print out the EXPLAIN output for sample query")
 	pflag.Bool("force-text-format", false, "Send/receive data in text format")
+	pflag.String("capture-plans", "", "Write the EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) plan of the first query of every label to this file once all queries have run, one JSON object per line")
 
 	pflag.Parse()
 
@@ -76,9 +79,17 @@ func init() {
 	port = viper.GetString("port")
 	showExplain = viper.GetBool("show-explain")
 	forceTextFormat = viper.GetBool("force-text-format")
+	capturePlans = viper.GetString("capture-plans")
 
 	runner = query.NewBenchmarkRunner(config)
 
+	if capturePlans != "" {
+		planRecorder, err = query.NewPlanRecorder(capturePlans)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if showExplain {
 		runner.SetLimit(1)
 	}
@@ -97,6 +108,38 @@ func init() {
 
 func main() {
 	runner.Run(&query.TimescaleDBPool, newProcessor)
+	if planRecorder != nil {
+		if err := recordPlans(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// recordPlans captures the plans of the queries claimed during the benchmark
+// with EXPLAIN ANALYZE, along with the PostgreSQL and TimescaleDB versions.
+func recordPlans() error {
+	defer planRecorder.Close()
+	db, err := sql.Open(driver, getConnectString(0))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var version string
+	err = db.QueryRow(`SELECT 'PostgreSQL ' || current_setting('server_version') ||
+		coalesce(', TimescaleDB ' || (SELECT extversion FROM pg_extension WHERE extname = 'timescaledb'), '')`).Scan(&version)
+	if err != nil {
+		return err
+	}
+	return planRecorder.Capture(func(p *query.Plan) error {
+		p.ServerVersion = version
+		var text []byte
+		if err := db.QueryRow(p.Explain).Scan(&text); err != nil {
+			return err
+		}
+		p.Plan = json.RawMessage(text)
+		return nil
+	})
 }
 
 // Get the connection string for a connection to PostgreSQL.
@@ -237,5 +280,10 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	stat := query.GetStat()
 	stat.Init(q.HumanLabelName(), took)
 
+	// The plan is only captured once the benchmark is done (see recordPlans)
+	if !isWarm {
+		planRecorder.Claim(q, string(tq.SqlQuery), "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+string(tq.SqlQuery))
+	}
+
 	return []*query.Stat{stat}, err
 }
@@ -0,0 +1,245 @@
+package inputs
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	queryCommon "github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	queryUtils "github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	internalUtils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// loadSimulatorConfigKey is the section of a tsbs_load config holding the
+	// data generation config.
+	loadSimulatorConfigKey = "data-source.simulator"
+
+	errCannotReadDataConfigFmt   = "cannot read data config '%s': %v"
+	errCannotDecodeDataConfigFmt = "cannot decode data config '%s': %v"
+	errDatasetNotSupportedFmt    = "data config not supported for format '%s'"
+	errEmptyDataset              = "data config does not generate any points"
+
+	warnUseCaseMismatchFmt = "warning: data was generated for use case '%s', queries are for '%s'\n"
+	warnSeriesMismatchFmt  = "warning: dataset has %d series, --scale is %d; queries use the loaded series\n"
+	warnSpanMismatchFmt    = "warning: dataset spans %s to %s, --timestamp-start/end are %s to %s; queries use the dataset span\n"
+	warnPartialSeriesFmt   = "warning: %d of %d series did not report over the entire dataset span; their queries may read partial data\n"
+)
+
+// DatasetSetter is a query generator whose queries can be constrained to the
+// series and time span of the loaded dataset
+type DatasetSetter interface {
+	SetDataset(dataset *queryCommon.Dataset)
+}
+
+// readDataConfig reads the DataGeneratorConfig from a config file of
+// tsbs_generate_data, or from the simulator data source of a tsbs_load config.
+// Options missing from the file get the defaults of tsbs_generate_data.
+func readDataConfig(path string) (*common.DataGeneratorConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf(errCannotReadDataConfigFmt, path, err)
+	}
+	if sub := v.Sub(loadSimulatorConfigKey); sub != nil {
+		v = sub
+	}
+
+	dgc := &common.DataGeneratorConfig{}
+	fs := pflag.NewFlagSet(path, pflag.ContinueOnError)
+	dgc.AddToFlagSet(fs)
+	if err := v.BindPFlags(fs); err != nil {
+		return nil, fmt.Errorf(errCannotDecodeDataConfigFmt, path, err)
+	}
+	if err := v.Unmarshal(&dgc.BaseConfig); err != nil {
+		return nil, fmt.Errorf(errCannotDecodeDataConfigFmt, path, err)
+	}
+	if err := v.Unmarshal(dgc); err != nil {
+		return nil, fmt.Errorf(errCannotDecodeDataConfigFmt, path, err)
+	}
+
+	if dgc.InitialScale == 0 {
+		dgc.InitialScale = dgc.Scale
+	}
+	if dgc.InterleavedNumGroups == 0 {
+		dgc.InterleavedNumGroups = 1
+	}
+	return dgc, nil
+}
+
+// profileDataset returns the series and time span of the data generated with
+// the given config. Unless every series reports over the entire time range, the
+// data generation is replayed without serializing the points, which takes a
+// while for large datasets.
+func profileDataset(dgc *common.DataGeneratorConfig) (*queryCommon.Dataset, error) {
+	tsStart, err := internalUtils.ParseUTCTime(dgc.TimeStart)
+	if err != nil {
+		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeStart, err)
+	}
+	tsEnd, err := internalUtils.ParseUTCTime(dgc.TimeEnd)
+	if err != nil {
+		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
+	}
+
+	// replaying is only needed if the data generation leaves out points
+	noChurn := dgc.ChurnRate == 0 || (dgc.ChurnRate == common.DefaultChurnRate && dgc.Use != common.UseCaseK8s)
+	if dgc.Limit == 0 && dgc.InitialScale == dgc.Scale && noChurn &&
+		dgc.InterleavedNumGroups == 1 && dgc.Use != common.UseCaseDevopsGeneric {
+		return completeDataset(dgc, tsStart, tsEnd)
+	}
+
+	// the simulators draw from the global source while being created
+	rand.Seed(dgc.Seed)
+	scfg, err := usecases.GetSimulatorConfig(dgc)
+	if err != nil {
+		return nil, err
+	}
+	return replayDataset(scfg.NewSimulator(dgc.LogInterval, dgc.Limit), dgc)
+}
+
+// completeDataset returns the dataset of a config where all series report in
+// every log interval of the time range.
+func completeDataset(dgc *common.DataGeneratorConfig, tsStart, tsEnd time.Time) (*queryCommon.Dataset, error) {
+	if _, err := usecases.GetSimulatorConfig(dgc); err != nil {
+		return nil, err
+	}
+
+	epochs := tsEnd.Sub(tsStart) / dgc.LogInterval
+	if epochs == 0 {
+		return nil, fmt.Errorf(errEmptyDataset)
+	}
+	span, err := internalUtils.NewTimeInterval(tsStart, tsStart.Add(epochs*dgc.LogInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	return &queryCommon.Dataset{Span: span, Scale: int(dgc.Scale)}, nil
+}
+
+// seriesSpan is the time range a single series reported in.
+type seriesSpan struct {
+	first, last time.Time
+}
+
+// replayDataset runs the simulator the way tsbs_generate_data does and records
+// the first tag value (e.g. the hostname) and timestamps of the written points.
+func replayDataset(sim common.Simulator, dgc *common.DataGeneratorConfig) (*queryCommon.Dataset, error) {
+	spans := make(map[string]*seriesSpan)
+	var names []string
+	var first, last time.Time
+
+	currGroupID := uint(0)
+	point := data.NewPoint()
+	for !sim.Finished() {
+		write := sim.Next(point)
+		if !write {
+			point.Reset()
+			continue
+		}
+
+		if currGroupID == dgc.InterleavedGroupID {
+			ts := *point.Timestamp()
+			if first.IsZero() || ts.Before(first) {
+				first = ts
+			}
+			if ts.After(last) {
+				last = ts
+			}
+
+			// some use cases write points with missing tag values
+			if values := point.TagValues(); len(values) > 0 && values[0] != nil {
+				name := fmt.Sprint(values[0])
+				if s, ok := spans[name]; !ok {
+					spans[name] = &seriesSpan{first: ts, last: ts}
+					names = append(names, name)
+				} else if ts.Before(s.first) {
+					s.first = ts
+				} else if ts.After(s.last) {
+					s.last = ts
+				}
+			}
+		}
+		point.Reset()
+
+		currGroupID = (currGroupID + 1) % dgc.InterleavedNumGroups
+	}
+
+	if first.IsZero() {
+		return nil, fmt.Errorf(errEmptyDataset)
+	}
+	span, err := internalUtils.NewTimeInterval(first, last.Add(dgc.LogInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	partial := 0
+	// series reporting in the first and last epoch are considered complete
+	for _, s := range spans {
+		if s.first.After(first) || s.last.Before(last) {
+			partial++
+		}
+	}
+	return &queryCommon.Dataset{Span: span, Scale: len(names), Series: names, PartialSeries: partial}, nil
+}
+
+// setDataset constrains the queries of the use case generator to the dataset
+// generated with the data config, if any, and warns about everything the query
+// generation options get wrong about that dataset.
+func (g *QueryGenerator) setDataset(useGen queryUtils.QueryGenerator) error {
+	if g.conf.DataConfig == "" {
+		return nil
+	}
+
+	setter, ok := useGen.(DatasetSetter)
+	if !ok {
+		return fmt.Errorf(errDatasetNotSupportedFmt, g.conf.Format)
+	}
+
+	dgc, err := readDataConfig(g.conf.DataConfig)
+	if err != nil {
+		return err
+	}
+	dataset, err := profileDataset(dgc)
+	if err != nil {
+		return err
+	}
+	if err := g.warnDatasetMismatch(dgc, dataset); err != nil {
+		return err
+	}
+	setter.SetDataset(dataset)
+	return nil
+}
+
+func (g *QueryGenerator) warnDatasetMismatch(dgc *common.DataGeneratorConfig, dataset *queryCommon.Dataset) error {
+	var warnings []string
+	if dgc.Use != g.conf.Use {
+		warnings = append(warnings, fmt.Sprintf(warnUseCaseMismatchFmt, dgc.Use, g.conf.Use))
+	}
+
+	if uint64(dataset.Scale) != g.conf.Scale {
+		warnings = append(warnings, fmt.Sprintf(warnSeriesMismatchFmt, dataset.Scale, g.conf.Scale))
+	}
+
+	if !dataset.Span.Start().Equal(g.tsStart) || !dataset.Span.End().Equal(g.tsEnd) {
+		warnings = append(warnings, fmt.Sprintf(warnSpanMismatchFmt,
+			dataset.Span.StartString(), dataset.Span.EndString(),
+			g.tsStart.Format(time.RFC3339), g.tsEnd.Format(time.RFC3339)))
+	}
+
+	if dataset.PartialSeries > 0 {
+		warnings = append(warnings, fmt.Sprintf(warnPartialSeriesFmt, dataset.PartialSeries, len(dataset.Series)))
+	}
+
+	for _, w := range warnings {
+		if _, err := io.WriteString(g.DebugOut, w); err != nil {
+			return fmt.Errorf(errCouldNotDebugFmt, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package inputs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/timescaledb"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func writeDataConfig(t *testing.T, contents string) string {
+	dir, err := ioutil.TempDir("", "tsbs-data-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadDataConfig(t *testing.T) {
+	cases := []struct {
+		desc     string
+		contents string
+	}{
+		{
+			desc: "tsbs_generate_data config",
+			contents: `
+use-case: cpu-only
+scale: 10
+initial-scale: 2
+max-data-points: 300
+timestamp-end: 2016-01-01T01:00:00Z
+`,
+		},
+		{
+			desc: "tsbs_load config",
+			contents: `
+data-source:
+  type: SIMULATOR
+  simulator:
+    use-case: cpu-only
+    scale: 10
+    initial-scale: 2
+    max-data-points: 300
+    timestamp-end: 2016-01-01T01:00:00Z
+loader:
+  target: timescaledb
+`,
+		},
+	}
+
+	for _, c := range cases {
+		dgc, err := readDataConfig(writeDataConfig(t, c.contents))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if dgc.Use != common.UseCaseCPUOnly || dgc.Scale != 10 || dgc.InitialScale != 2 || dgc.Limit != 300 {
+			t.Errorf("%s: incorrect config: %+v", c.desc, dgc)
+		}
+		if dgc.TimeStart != defaultTimeStart || dgc.TimeEnd != "2016-01-01T01:00:00Z" {
+			t.Errorf("%s: incorrect time range: %s to %s", c.desc, dgc.TimeStart, dgc.TimeEnd)
+		}
+		if dgc.LogInterval != 10*time.Second || dgc.InterleavedNumGroups != 1 {
+			t.Errorf("%s: defaults not applied: %+v", c.desc, dgc)
+		}
+	}
+
+	if _, err := readDataConfig(filepath.Join(os.TempDir(), "missing-tsbs-data-config.yaml")); err == nil {
+		t.Errorf("unexpected lack of error for missing data config")
+	}
+}
+
+func TestProfileDataset(t *testing.T) {
+	newConfig := func() *common.DataGeneratorConfig {
+		return &common.DataGeneratorConfig{
+			BaseConfig: common.BaseConfig{
+				Use:       common.UseCaseCPUOnly,
+				Scale:     10,
+				TimeStart: "2016-01-01T00:00:00Z",
+				TimeEnd:   "2016-01-01T01:00:05Z",
+				Seed:      123,
+			},
+			InitialScale:         10,
+			LogInterval:          10 * time.Second,
+			InterleavedNumGroups: 1,
+		}
+	}
+
+	// all hosts report over the entire time range, which ends after the last
+	// complete log interval
+	ds, err := profileDataset(newConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ds.Scale != 10 || ds.Series != nil || ds.PartialSeries != 0 {
+		t.Errorf("incorrect complete dataset: %+v", ds)
+	}
+	if got := ds.Span.EndString(); got != "2016-01-01T01:00:00Z" {
+		t.Errorf("incorrect complete dataset end: got %s", got)
+	}
+
+	// two hosts report from the start, scaling up to six in the second epoch,
+	// after which the limit of 20 points is reached
+	dgc := newConfig()
+	dgc.InitialScale = 2
+	dgc.Limit = 20
+	dgc.TimeEnd = "2016-01-01T00:00:30Z"
+	ds, err = profileDataset(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := strings.Join(ds.Series, ","), "host_0,host_1,host_2,host_3,host_4,host_5"; got != want {
+		t.Errorf("incorrect series: got %s want %s", got, want)
+	}
+	if ds.Scale != len(ds.Series) || ds.PartialSeries != 4 {
+		t.Errorf("incorrect scaled up dataset: %+v", ds)
+	}
+	if got := ds.Span.StartString() + " " + ds.Span.EndString(); got != "2016-01-01T00:00:00Z 2016-01-01T00:00:20Z" {
+		t.Errorf("incorrect scaled up dataset span: got %s", got)
+	}
+
+	dgc = newConfig()
+	dgc.TimeEnd = dgc.TimeStart
+	if _, err := profileDataset(dgc); err == nil || err.Error() != errEmptyDataset {
+		t.Errorf("unexpected error for empty dataset: got %v", err)
+	}
+}
+
+func TestQueryGeneratorSetDataset(t *testing.T) {
+	path := writeDataConfig(t, `
+use-case: cpu-only
+scale: 10
+initial-scale: 2
+max-data-points: 20
+timestamp-end: 2016-01-01T00:00:30Z
+`)
+	c, g := getTestConfigAndGenerator()
+	c.DataConfig = path
+	var debug bytes.Buffer
+	g.DebugOut = &debug
+
+	useGen, err := (&timescaledb.BaseGenerator{}).NewDevops(g.tsStart, g.tsEnd, int(c.Scale))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := g.setDataset(useGen); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := useGen.(*timescaledb.Devops)
+	if d.Scale != 6 || len(d.Series) != 6 {
+		t.Errorf("dataset not set: scale %d, series %v", d.Scale, d.Series)
+	}
+	if got := d.Interval.EndString(); got != "2016-01-01T00:00:20Z" {
+		t.Errorf("dataset span not set: got end %s", got)
+	}
+	hosts, err := d.GetRandomHosts(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := strings.Join(sortedCopy(hosts), ","), "host_0,host_1,host_2,host_3,host_4,host_5"; got != want {
+		t.Errorf("incorrect hosts: got %s want %s", got, want)
+	}
+	if _, err := d.GetRandomHosts(7); err == nil {
+		t.Errorf("unexpected lack of error for more hosts than in the dataset")
+	}
+
+	for _, want := range []string{
+		"dataset has 6 series, --scale is 10",
+		"dataset spans 2016-01-01T00:00:00Z to 2016-01-01T00:00:20Z",
+		"4 of 6 series did not report",
+	} {
+		if !strings.Contains(debug.String(), want) {
+			t.Errorf("missing warning %q in:\n%s", want, debug.String())
+		}
+	}
+	if strings.Contains(debug.String(), "use case") {
+		t.Errorf("unexpected use case warning:\n%s", debug.String())
+	}
+}
+
+func sortedCopy(s []string) []string {
+	ret := append([]string(nil), s...)
+	sort.Strings(ret)
+	return ret
+}
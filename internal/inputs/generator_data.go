@@ -133,6 +133,8 @@ func (g *DataGenerator) getSerializer(sim common.Simulator, target targets.Imple
 		fallthrough
 	case constants.FormatClickhouse:
 		fallthrough
+	case constants.FormatTDengine:
+		fallthrough
 	case constants.FormatTimescaleDB:
 		g.writeHeader(sim.Headers())
 	}
@@ -160,9 +162,10 @@ func (g *DataGenerator) writeHeader(headers *common.GeneratedDataHeaders) {
 	sort.Strings(keys)
 	for _, measurementName := range keys {
 		g.bufOut.WriteString(measurementName)
-		for _, field := range fields[measurementName] {
+		// non-numeric fields are followed by their type, like the tags
+		for _, column := range headers.FieldColumns(measurementName) {
 			g.bufOut.WriteString(",")
-			g.bufOut.Write([]byte(field))
+			g.bufOut.WriteString(column)
 		}
 		g.bufOut.WriteString("\n")
 	}
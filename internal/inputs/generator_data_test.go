@@ -341,6 +341,33 @@ func TestGetSerializer(t *testing.T) {
 	checkWriteHeader(constants.FormatVictoriaMetrics, false)
 }
 
+func TestWriteHeader(t *testing.T) {
+	headers := &common.GeneratedDataHeaders{
+		TagKeys:  []string{"hostname", "region"},
+		TagTypes: []string{"string", "string"},
+		FieldKeys: map[string][]string{
+			"health": {"status", "alerting", "uptime"},
+			"cpu":    {"usage_user", "usage_system"},
+		},
+		FieldTypes: map[string][]string{
+			"health": {"string", "bool", "float64"},
+			"cpu":    {"float64", "float64"},
+		},
+	}
+	var buf bytes.Buffer
+	g := &DataGenerator{bufOut: bufio.NewWriter(&buf)}
+	g.writeHeader(headers)
+	g.bufOut.Flush()
+
+	want := "tags,hostname string,region string\n" +
+		"cpu,usage_user,usage_system\n" +
+		"health,status string,alerting bool,uptime\n" +
+		"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("incorrect header: got\n%s\nwant\n%s", got, want)
+	}
+}
+
 type mockSerializer struct {
 	numCalledSerialize int
 	sentPoints         []*data.Point
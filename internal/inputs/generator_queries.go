@@ -2,7 +2,6 @@ package inputs
 
 import (
 	"bufio"
-	"encoding/gob"
 	"fmt"
 	"io"
 	"math/rand"
@@ -10,9 +9,11 @@ import (
 	"sort"
 	"time"
 
+	queryCommon "github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
 	queryUtils "github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	internalUtils "github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/query"
 	"github.com/timescale/tsbs/pkg/query/config"
 	"github.com/timescale/tsbs/pkg/query/factories"
 )
@@ -28,6 +29,7 @@ const (
 	errUnknownUseCaseFmt        = "use case '%s' is undefined"
 	errCannotParseTimeFmt       = "cannot parse time from string '%s': %v"
 	errBadUseFmt                = "invalid use case specified: '%v'"
+	errTimeRangeNotSupportedFmt = "time range strategy '%s' not supported for format '%s'"
 )
 
 // DevopsGeneratorMaker creates a query generator for devops use case
@@ -40,6 +42,27 @@ type IoTGeneratorMaker interface {
 	NewIoT(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
 }
 
+// K8sGeneratorMaker creates a query generator for k8s use case
+type K8sGeneratorMaker interface {
+	NewK8s(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
+// FinanceGeneratorMaker creates a query generator for finance use case
+type FinanceGeneratorMaker interface {
+	NewFinance(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
+// SmartMeterGeneratorMaker creates a query generator for smart-meter use case
+type SmartMeterGeneratorMaker interface {
+	NewSmartMeter(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
+}
+
+// TimeRangeStrategySetter is a query generator whose query time ranges can be
+// selected by a TimeRangeStrategy
+type TimeRangeStrategySetter interface {
+	SetTimeRangeStrategy(strategy queryCommon.TimeRangeStrategy)
+}
+
 // QueryGenerator is a type of Generator for creating queries to test against a
 // database. The output is specific to the type of database (due to each using
 // different querying techniques, e.g. SQL or REST), but is consumed by TSBS
@@ -87,6 +110,14 @@ func (g *QueryGenerator) Generate(config common.GeneratorConfig) error {
 		return err
 	}
 
+	if err := g.setDataset(useGen); err != nil {
+		return err
+	}
+
+	if err := g.setTimeRangeStrategy(useGen); err != nil {
+		return err
+	}
+
 	filler := g.useCaseMatrix[g.conf.Use][g.conf.QueryType](useGen)
 
 	return g.runQueryGeneration(useGen, filler, g.conf)
@@ -158,7 +189,7 @@ func (g *QueryGenerator) addFactory(database string, factory interface{}) error
 	validFactory := false
 
 	switch factory.(type) {
-	case DevopsGeneratorMaker, IoTGeneratorMaker:
+	case DevopsGeneratorMaker, IoTGeneratorMaker, K8sGeneratorMaker, FinanceGeneratorMaker, SmartMeterGeneratorMaker:
 		validFactory = true
 	}
 
@@ -189,6 +220,27 @@ func (g *QueryGenerator) getUseCaseGenerator(c *config.QueryGeneratorConfig) (qu
 		}
 
 		return iotFactory.NewIoT(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseK8s:
+		k8sFactory, ok := factory.(K8sGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		return k8sFactory.NewK8s(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseFinance:
+		financeFactory, ok := factory.(FinanceGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		return financeFactory.NewFinance(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseSmartMeter:
+		smartMeterFactory, ok := factory.(SmartMeterGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		return smartMeterFactory.NewSmartMeter(g.tsStart, g.tsEnd, scale)
 	case common.UseCaseDevops, common.UseCaseCPUOnly, common.UseCaseCPUSingle:
 		devopsFactory, ok := factory.(DevopsGeneratorMaker)
 		if !ok {
@@ -201,10 +253,31 @@ func (g *QueryGenerator) getUseCaseGenerator(c *config.QueryGeneratorConfig) (qu
 	}
 }
 
+func (g *QueryGenerator) setTimeRangeStrategy(useGen queryUtils.QueryGenerator) error {
+	strategy, err := queryCommon.NewTimeRangeStrategy(g.conf.TimeRangeStrategy, g.conf.TimeRangeSpan)
+	if err != nil {
+		return err
+	}
+
+	setter, ok := useGen.(TimeRangeStrategySetter)
+	if !ok {
+		if _, uniform := strategy.(queryCommon.UniformTimeRange); uniform {
+			return nil
+		}
+		return fmt.Errorf(errTimeRangeNotSupportedFmt, g.conf.TimeRangeStrategy, g.conf.Format)
+	}
+
+	setter.SetTimeRangeStrategy(strategy)
+	return nil
+}
+
 func (g *QueryGenerator) runQueryGeneration(useGen queryUtils.QueryGenerator, filler queryUtils.QueryFiller, c *config.QueryGeneratorConfig) error {
 	stats := make(map[string]int64)
 	currentGroup := uint(0)
-	enc := gob.NewEncoder(g.bufOut)
+	enc, err := query.NewEncoder(g.bufOut, c.QueryEncoding)
+	if err != nil {
+		return err
+	}
 	defer g.bufOut.Flush()
 
 	rand.Seed(g.conf.Seed)
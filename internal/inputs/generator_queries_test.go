@@ -3,7 +3,6 @@ package inputs
 import (
 	"bufio"
 	"bytes"
-	"encoding/gob"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,6 +19,7 @@ import (
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/mongo"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/siridb"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/timescaledb"
+	queryCommon "github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
 	queryUtils "github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	internalUtils "github.com/timescale/tsbs/internal/utils"
@@ -68,6 +68,30 @@ func TestQueryGeneratorConfigValidate(t *testing.T) {
 	}
 	c.QueryType = "foo"
 
+	// Test time range validation
+	c.TimeRangeStrategy = "bogus"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for bogus time range strategy")
+	}
+	c.TimeRangeStrategy = queryCommon.TimeRangeSliding
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for sliding time range without span")
+	}
+	c.TimeRangeSpan = -time.Hour
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for negative time range span")
+	}
+	c.TimeRangeSpan = time.Hour
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for sliding time range: %v", err)
+	}
+	c.TimeRangeStrategy = ""
+	c.TimeRangeSpan = 0
+
 	// Test groups validation
 	c.InterleavedNumGroups = 0
 	err = c.Validate()
@@ -307,6 +331,14 @@ func TestGetUseCaseGenerator(t *testing.T) {
 	g.conf.MongoUseNaive = true
 	checkType(constants.FormatMongo, nmongo)
 
+	bm.UseTimeseries = true
+	tsmongo, err := bm.NewDevops(tsStart, tsEnd, scale)
+	if err != nil {
+		t.Fatalf("Error creating time-series mongodb query generator")
+	}
+	g.conf.MongoUseTimeseries = true
+	checkType(constants.FormatMongo, tsmongo)
+
 	bcc := clickhouse.BaseGenerator{}
 	clickh, err := bcc.NewDevops(tsStart, tsEnd, scale)
 	if err != nil {
@@ -436,8 +468,7 @@ func getTestConfigAndGenerator() (*config.QueryGeneratorConfig, *QueryGenerator)
 }
 
 func checkGeneratedOutput(t *testing.T, buf *bytes.Buffer) {
-	r := bufio.NewReader(buf)
-	decoder := gob.NewDecoder(r)
+	decoder := query.NewDecoder(buf)
 	i := 0
 	for {
 		var q query.TimescaleDB
@@ -535,6 +566,38 @@ func TestQueryGeneratorRunQueryGeneration(t *testing.T) {
 	}
 }
 
+func TestQueryGeneratorRunQueryGenerationJSON(t *testing.T) {
+	config, g := getTestConfigAndGenerator()
+	config.QueryEncoding = query.EncodingJSON
+	err := g.init(config)
+	if err != nil {
+		t.Fatalf("Error initializing query generator: %s", err)
+	}
+
+	var buf bytes.Buffer
+	g.bufOut = bufio.NewWriter(&buf)
+	g.DebugOut = ioutil.Discard
+
+	useGen, err := g.getUseCaseGenerator(config)
+	if err != nil {
+		t.Fatalf("could not get use case gen: %v", err)
+	}
+	filler := g.useCaseMatrix[config.Use][config.QueryType](useGen)
+
+	err = g.runQueryGeneration(useGen, filler, config)
+	if err != nil {
+		t.Errorf("unexpected error: got %v", err)
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != len(wantQueries) {
+		t.Errorf("incorrect number of lines: got %d want %d", got, len(wantQueries))
+	}
+	if !strings.HasPrefix(buf.String(), `{"HumanLabel":"TimescaleDB 1 cpu metric(s)`) {
+		t.Errorf("output is not human readable JSON:\n%s", buf.String())
+	}
+	checkGeneratedOutput(t, &buf)
+}
+
 type badWriter struct {
 	when  int
 	count int
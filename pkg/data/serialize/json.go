@@ -0,0 +1,56 @@
+package serialize
+
+import (
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// AppendJSON appends the point as a JSON object that looks like:
+// {"measurement":"cpu","timestamp":1451606400000000000,"tags":{"hostname":"host_0"},"fields":{"usage_user":58}}
+// with the timestamp in nanoseconds.
+func AppendJSON(buf []byte, p *data.Point) []byte {
+	buf = append(buf, `{"measurement":`...)
+	buf = appendJSONString(buf, p.MeasurementName())
+	buf = append(buf, `,"timestamp":`...)
+	buf = strconv.AppendInt(buf, p.Timestamp().UnixNano(), 10)
+	buf = append(buf, `,"tags":`...)
+	buf = appendJSONObject(buf, p.TagKeys(), p.TagValues())
+	buf = append(buf, `,"fields":`...)
+	buf = appendJSONObject(buf, p.FieldKeys(), p.FieldValues())
+	return append(buf, '}')
+}
+
+func appendJSONObject(buf []byte, keys [][]byte, values []interface{}) []byte {
+	buf = append(buf, '{')
+	for i, key := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONString(buf, key)
+		buf = append(buf, ':')
+		if s, ok := values[i].(string); ok {
+			buf = appendJSONString(buf, []byte(s))
+		} else {
+			buf = FastFormatAppend(values[i], buf)
+		}
+	}
+	return append(buf, '}')
+}
+
+// appendJSONString appends s as a quoted JSON string.
+func appendJSONString(buf, s []byte) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
@@ -16,12 +16,16 @@ var (
 	TestColFloat    = []byte("usage_guest_nice")
 	TestColInt      = []byte("usage_guest")
 	TestColInt64    = []byte("big_usage_guest")
+	TestColString   = []byte("last_error")
+	TestColBool     = []byte("alerting")
 )
 
 const (
 	TestFloat             = float64(38.24311829)
 	TestInt               = 38
 	TestInt64             = int64(5000000000)
+	TestString            = `disk "C:\" is 95% full, cleanup failed`
+	TestBool              = true
 	ErrWriterAlwaysErr    = "bad write: I always error"
 	ErrWriterSometimesErr = "bad write: I sometimes error"
 )
@@ -71,6 +75,12 @@ func TestPointInt() *data.Point {
 		[][]byte{TestColInt}, []interface{}{TestInt})
 }
 
+func TestPointNonNumeric() *data.Point {
+	return generateTestPoint(TestMeasurement, TestTagKeys, TestTagVals,
+		&TestNow, [][]byte{TestColString, TestColBool, TestColFloat},
+		[]interface{}{TestString, TestBool, TestFloat})
+}
+
 func TestPointNoTags() *data.Point {
 	return generateTestPoint(TestMeasurement, [][]byte{}, []interface{}{}, &TestNow,
 		[][]byte{TestColFloat}, []interface{}{TestFloat})
@@ -2,9 +2,14 @@ package serialize
 
 import (
 	"fmt"
+	"log"
 	"strconv"
+	"sync"
 )
 
+// skippedFields holds the fields a format already warned about skipping.
+var skippedFields sync.Map
+
 // Utility function for appending various data types to a byte string
 func FastFormatAppend(v interface{}, buf []byte) []byte {
 	switch v.(type) {
@@ -34,3 +39,22 @@ func FastFormatAppend(v interface{}, buf []byte) []byte {
 		panic(fmt.Sprintf("unknown field type for %#v", v))
 	}
 }
+
+// IsNumeric tells whether a field value is a number.
+func IsNumeric(v interface{}) bool {
+	switch v.(type) {
+	case int, int64, float64, float32:
+		return true
+	default:
+		return false
+	}
+}
+
+// WarnSkippedField logs that values of a field are left out because the format
+// cannot store them. The warning is only logged once per format and field.
+func WarnSkippedField(format string, key []byte, v interface{}) {
+	if _, warned := skippedFields.LoadOrStore(format+"/"+string(key), true); warned {
+		return
+	}
+	log.Printf("%s: skipping field '%s', %T values are not supported", format, key, v)
+}
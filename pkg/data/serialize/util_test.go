@@ -1,6 +1,10 @@
 package serialize
 
 import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -90,3 +94,41 @@ func TestFastFormatAppend(t *testing.T) {
 		}
 	}
 }
+
+func TestIsNumeric(t *testing.T) {
+	cases := []struct {
+		input interface{}
+		want  bool
+	}{
+		{float64(29.37), true},
+		{float32(29.37), true},
+		{int(29), true},
+		{int64(29), true},
+		{"29", false},
+		{true, false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := IsNumeric(c.input); got != c.want {
+			t.Errorf("incorrect result for %#v: got %v want %v", c.input, got, c.want)
+		}
+	}
+}
+
+func TestWarnSkippedField(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	WarnSkippedField("test", []byte("status"), "ok")
+	WarnSkippedField("test", []byte("status"), "ok")
+	WarnSkippedField("test", []byte("alerting"), true)
+	WarnSkippedField("other", []byte("status"), "ok")
+
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("incorrect number of warnings: got %d want 3\n%s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "test: skipping field 'alerting', bool values are not supported") {
+		t.Errorf("incorrect warning: %s", buf.String())
+	}
+}
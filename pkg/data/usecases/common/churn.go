@@ -0,0 +1,156 @@
+package common
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scale-up curve choices
+const (
+	ScaleUpLinear      = "linear"
+	ScaleUpExponential = "exponential"
+	ScaleUpStep        = "step"
+
+	defaultScaleUpSteps = 4
+)
+
+// ScaleUpCurveChoices contains all the supported scale-up curve names.
+var ScaleUpCurveChoices = []string{
+	ScaleUpLinear,
+	ScaleUpExponential,
+	ScaleUpStep,
+}
+
+// ScaleUpCurve decides how many entities (hosts, trucks, ...) should be reporting
+// in a given epoch when scaling from an initial to a final entity count.
+type ScaleUpCurve interface {
+	// EpochScale returns the number of reporting entities for the epoch (0-indexed)
+	// out of the total number of epochs.
+	EpochScale(epoch, epochs, initScale, scale uint64) uint64
+}
+
+// LinearScaleUp adds the missing scale in proportion to the percentage of epochs
+// that have passed.
+type LinearScaleUp struct{}
+
+// EpochScale returns the linearly interpolated scale for the epoch.
+func (LinearScaleUp) EpochScale(epoch, epochs, initScale, scale uint64) uint64 {
+	if epochs <= 1 || epoch >= epochs-1 {
+		return scale
+	}
+	missingScale := float64(scale - initScale)
+	return clampScale(initScale+uint64(missingScale*float64(epoch)/float64(epochs-1)), scale)
+}
+
+// ExponentialScaleUp grows the scale by a constant factor every epoch, so that
+// the final scale is reached in the last epoch.
+type ExponentialScaleUp struct{}
+
+// EpochScale returns the exponentially interpolated scale for the epoch.
+func (ExponentialScaleUp) EpochScale(epoch, epochs, initScale, scale uint64) uint64 {
+	if epochs <= 1 || epoch >= epochs-1 {
+		return scale
+	}
+	base := math.Max(float64(initScale), 1)
+	growth := math.Pow(float64(scale)/base, float64(epoch)/float64(epochs-1))
+	return clampScale(uint64(base*growth), scale)
+}
+
+// StepScaleUp adds the missing scale in a fixed number of equally sized steps,
+// e.g., to simulate a deployment being rolled out in waves.
+type StepScaleUp struct {
+	Steps uint64
+}
+
+// EpochScale returns the scale of the step the epoch falls into.
+func (s StepScaleUp) EpochScale(epoch, epochs, initScale, scale uint64) uint64 {
+	if epochs <= 1 || epoch >= epochs-1 {
+		return scale
+	}
+	steps := s.Steps
+	if steps == 0 {
+		steps = defaultScaleUpSteps
+	}
+	step := epoch * steps / (epochs - 1)
+	return clampScale(initScale+(scale-initScale)*step/steps, scale)
+}
+
+func clampScale(epochScale, scale uint64) uint64 {
+	if epochScale > scale {
+		return scale
+	}
+	return epochScale
+}
+
+// NewScaleUpCurve returns the ScaleUpCurve with the given name. An empty name
+// selects the linear curve. The steps are only used by the step curve.
+func NewScaleUpCurve(name string, steps uint64) (ScaleUpCurve, error) {
+	switch name {
+	case "", ScaleUpLinear:
+		return LinearScaleUp{}, nil
+	case ScaleUpExponential:
+		return ExponentialScaleUp{}, nil
+	case ScaleUpStep:
+		return StepScaleUp{Steps: steps}, nil
+	default:
+		return nil, fmt.Errorf(errBadScaleUpCurveFmt, name)
+	}
+}
+
+// Churn models series churn: at every epoch a fraction of the reporting entities
+// dies and is replaced by a new entity with a fresh id and fresh tag values, the
+// way pods get replaced in a Kubernetes cluster.
+type Churn struct {
+	// Rate is the fraction of reporting entities replaced per epoch.
+	Rate float64
+
+	// pending accumulates the fractional replacements across epochs.
+	pending float64
+	// cursor is the index of the next entity to replace; replacing in a round
+	// robin manner means the oldest entity always dies first.
+	cursor uint64
+	// nextID is the id given to the next created entity.
+	nextID uint64
+}
+
+// NewChurn creates a Churn replacing the given fraction of entities per epoch.
+// New entities get ids starting at firstID, so they never clash with the ids of
+// the initially created entities.
+func NewChurn(rate float64, firstID uint64) *Churn {
+	return &Churn{
+		Rate:   rate,
+		nextID: firstID,
+	}
+}
+
+// Replacement identifies an entity to replace and the id the new entity should get.
+type Replacement struct {
+	Index uint64
+	ID    uint64
+}
+
+// Replacements returns the entities to replace in the current epoch, given the
+// number of entities currently reporting.
+func (c *Churn) Replacements(active uint64) []Replacement {
+	if c == nil || c.Rate <= 0 || active == 0 {
+		return nil
+	}
+
+	c.pending += c.Rate * float64(active)
+	count := uint64(c.pending)
+	c.pending -= float64(count)
+	if count > active {
+		count = active
+	}
+
+	ret := make([]Replacement, count)
+	for i := range ret {
+		if c.cursor >= active {
+			c.cursor = 0
+		}
+		ret[i] = Replacement{Index: c.cursor, ID: c.nextID}
+		c.cursor++
+		c.nextID++
+	}
+	return ret
+}
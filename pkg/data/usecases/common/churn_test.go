@@ -0,0 +1,170 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestScaleUpCurves(t *testing.T) {
+	cases := []struct {
+		desc  string
+		curve ScaleUpCurve
+		init  uint64
+		want  []uint64
+	}{
+		{
+			desc:  "linear",
+			curve: LinearScaleUp{},
+			init:  10,
+			want:  []uint64{10, 32, 55, 77, 100},
+		},
+		{
+			desc:  "exponential",
+			curve: ExponentialScaleUp{},
+			init:  1,
+			want:  []uint64{1, 3, 10, 31, 100},
+		},
+		{
+			desc:  "exponential from 0",
+			curve: ExponentialScaleUp{},
+			init:  0,
+			want:  []uint64{1, 3, 10, 31, 100},
+		},
+		{
+			desc:  "step with 2 steps",
+			curve: StepScaleUp{Steps: 2},
+			init:  0,
+			want:  []uint64{0, 0, 50, 50, 100},
+		},
+		{
+			desc:  "step with default steps",
+			curve: StepScaleUp{},
+			init:  20,
+			want:  []uint64{20, 40, 60, 80, 100},
+		},
+		{
+			desc:  "no missing scale",
+			curve: ExponentialScaleUp{},
+			init:  100,
+			want:  []uint64{100, 100, 100, 100, 100},
+		},
+	}
+
+	for _, c := range cases {
+		for epoch, want := range c.want {
+			if got := c.curve.EpochScale(uint64(epoch), uint64(len(c.want)), c.init, 100); got != want {
+				t.Errorf("%s: incorrect scale for epoch %d: got %d want %d", c.desc, epoch, got, want)
+			}
+		}
+	}
+}
+
+func TestNewScaleUpCurve(t *testing.T) {
+	cases := []struct {
+		name string
+		want ScaleUpCurve
+	}{
+		{name: "", want: LinearScaleUp{}},
+		{name: ScaleUpLinear, want: LinearScaleUp{}},
+		{name: ScaleUpExponential, want: ExponentialScaleUp{}},
+		{name: ScaleUpStep, want: StepScaleUp{Steps: 3}},
+	}
+	for _, c := range cases {
+		got, err := NewScaleUpCurve(c.name, 3)
+		if err != nil {
+			t.Errorf("unexpected error for curve '%s': %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("incorrect curve for '%s': got %v want %v", c.name, got, c.want)
+		}
+	}
+
+	if _, err := NewScaleUpCurve("bogus", 0); err == nil {
+		t.Errorf("unexpected lack of error for bogus curve")
+	}
+}
+
+func TestChurnReplacements(t *testing.T) {
+	c := NewChurn(0.25, 10)
+
+	// 0.25 * 6 = 1.5 replacements per epoch, so it alternates between 1 and 2
+	wantIndexes := [][]uint64{{0}, {1, 2}, {3}, {4, 5}, {0}}
+	wantID := uint64(10)
+	for epoch, want := range wantIndexes {
+		got := c.Replacements(6)
+		if len(got) != len(want) {
+			t.Fatalf("epoch %d: incorrect number of replacements: got %d want %d", epoch, len(got), len(want))
+		}
+		for i, r := range got {
+			if r.Index != want[i] {
+				t.Errorf("epoch %d: incorrect index replaced: got %d want %d", epoch, r.Index, want[i])
+			}
+			if r.ID != wantID {
+				t.Errorf("epoch %d: incorrect id: got %d want %d", epoch, r.ID, wantID)
+			}
+			wantID++
+		}
+	}
+}
+
+func TestChurnNoReplacements(t *testing.T) {
+	var nilChurn *Churn
+	if got := nilChurn.Replacements(10); len(got) != 0 {
+		t.Errorf("nil churn replaced entities: %v", got)
+	}
+	if got := NewChurn(0, 10).Replacements(10); len(got) != 0 {
+		t.Errorf("zero rate churn replaced entities: %v", got)
+	}
+	if got := NewChurn(1, 10).Replacements(0); len(got) != 0 {
+		t.Errorf("churn replaced entities when none are active: %v", got)
+	}
+}
+
+type idGenerator struct {
+	dummyGenerator
+	id    int
+	start time.Time
+}
+
+func TestBaseSimulatorChurn(t *testing.T) {
+	var created []*idGenerator
+	conf := &BaseSimulatorConfig{
+		Start:              testTime,
+		End:                testTime.Add(3 * time.Second),
+		InitGeneratorScale: 4,
+		GeneratorScale:     4,
+		GeneratorConstructor: func(i int, start time.Time) Generator {
+			g := &idGenerator{id: i, start: start}
+			created = append(created, g)
+			return g
+		},
+		ChurnRate: 0.5,
+	}
+	s := conf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	p := data.NewPoint()
+	// Run through the first two epochs and tick over to the third one; half of
+	// the generators are replaced at each epoch change.
+	for i := 0; i < 2*4*dummyGeneratorMeasurementCount+1; i++ {
+		s.Next(p)
+		p.Reset()
+	}
+
+	if got := len(created); got != 8 {
+		t.Fatalf("incorrect number of created generators: got %d want 8", got)
+	}
+	wantIDs := []int{4, 5, 6, 7}
+	for i, g := range s.generators {
+		ig := g.(*idGenerator)
+		if ig.id != wantIDs[i] {
+			t.Errorf("incorrect generator id at index %d: got %d want %d", i, ig.id, wantIDs[i])
+		}
+	}
+	if got := s.generators[0].(*idGenerator).start; !got.Equal(testTime.Add(time.Second)) {
+		t.Errorf("replacement generator has incorrect start: got %v", got)
+	}
+	if got := s.generators[2].(*idGenerator).start; !got.Equal(testTime.Add(2 * time.Second)) {
+		t.Errorf("replacement generator has incorrect start: got %v", got)
+	}
+}
@@ -24,6 +24,9 @@ const (
 	UseCaseDevops        = "devops"
 	UseCaseIoT           = "iot"
 	UseCaseDevopsGeneric = "devops-generic"
+	UseCaseK8s           = "k8s"
+	UseCaseFinance       = "finance"
+	UseCaseSmartMeter    = "smart-meter"
 )
 
 var UseCaseChoices = []string{
@@ -32,4 +35,7 @@ var UseCaseChoices = []string{
 	UseCaseDevops,
 	UseCaseIoT,
 	UseCaseDevopsGeneric,
+	UseCaseK8s,
+	UseCaseFinance,
+	UseCaseSmartMeter,
 }
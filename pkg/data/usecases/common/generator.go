@@ -25,9 +25,15 @@ type Tag struct {
 const (
 	errMaxMetricCountValue = "max metric count per host has to be greater than 0"
 	errLogIntervalZero     = "cannot have log interval of 0"
+	errBadScaleUpCurveFmt  = "invalid scale-up curve specified: '%v'"
+	errChurnRateRange      = "churn rate has to be in the range [0, 1], or -1 for the default of the use case"
 	defaultLogInterval     = 10 * time.Second
 )
 
+// DefaultChurnRate selects the churn rate of the use case, which is no churn
+// except for the pods of the k8s use case.
+const DefaultChurnRate = -1
+
 // DataGeneratorConfig is the GeneratorConfig that should be used with a
 // DataGenerator. It includes all the fields from a BaseConfig, as well as some
 // options that are specific to generating the data for database write operations,
@@ -40,6 +46,10 @@ type DataGeneratorConfig struct {
 	InterleavedGroupID    uint          `yaml:"interleaved-generation-group-id" mapstructure:"interleaved-generation-group-id"`
 	InterleavedNumGroups  uint          `yaml:"interleaved-generation-groups" mapstructure:"interleaved-generation-groups"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	ScaleUpCurve          string        `yaml:"scale-up-curve" mapstructure:"scale-up-curve"`
+	ScaleUpSteps          uint64        `yaml:"scale-up-steps" mapstructure:"scale-up-steps"`
+	ChurnRate             float64       `yaml:"churn-rate" mapstructure:"churn-rate"`
+	NonNumericFields      bool          `yaml:"non-numeric-fields" mapstructure:"non-numeric-fields"`
 }
 
 // Validate checks that the values of the DataGeneratorConfig are reasonable.
@@ -57,6 +67,14 @@ func (c *DataGeneratorConfig) Validate() error {
 		return fmt.Errorf(errLogIntervalZero)
 	}
 
+	if c.ScaleUpCurve != "" && !utils.IsIn(c.ScaleUpCurve, ScaleUpCurveChoices) {
+		return fmt.Errorf(errBadScaleUpCurveFmt, c.ScaleUpCurve)
+	}
+
+	if c.ChurnRate != DefaultChurnRate && (c.ChurnRate < 0 || c.ChurnRate > 1) {
+		return fmt.Errorf(errChurnRateRange)
+	}
+
 	err = utils.ValidateGroups(c.InterleavedGroupID, c.InterleavedNumGroups)
 
 	if c.Use == UseCaseDevopsGeneric && c.MaxMetricCountPerHost < 1 {
@@ -77,6 +95,13 @@ func (c *DataGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("interleaved-generation-groups", 1,
 		"The number of round-robin serialization groups. Use this to scale up data generation to multiple processes.")
 	fs.Uint64("max-metric-count", 100, "Max number of metric fields to generate per host. Used only in devops-generic use-case")
+	fs.String("scale-up-curve", ScaleUpLinear,
+		fmt.Sprintf("Curve used to scale from initial-scale to scale over time. (choices: %s)", strings.Join(ScaleUpCurveChoices, ", ")))
+	fs.Uint64("scale-up-steps", defaultScaleUpSteps, "Number of steps used by the 'step' scale-up curve")
+	fs.Float64("churn-rate", DefaultChurnRate,
+		"Fraction of reporting entities (e.g., hosts in 'devops') replaced by new ones with fresh tag values every log-interval, 0 = no churn, -1 = default of the use case (no churn, except pods in 'k8s')")
+	fs.Bool("non-numeric-fields", false,
+		"Add string and boolean fields (e.g., host status, truck engine state and error messages) to the devops and iot use cases")
 }
 
 const defaultTimeStart = "2016-01-01T00:00:00Z"
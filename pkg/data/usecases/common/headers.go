@@ -0,0 +1,93 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const (
+	// FieldTypeString is the type of fields holding text, e.g. error messages
+	// or states out of a fixed set of choices.
+	FieldTypeString = "string"
+	// FieldTypeBool is the type of fields holding booleans.
+	FieldTypeBool = "bool"
+
+	// defaultFieldType is reported for fields without a known type.
+	defaultFieldType = "float64"
+)
+
+// IsNumericFieldType tells whether the values of a field of the given type
+// are numbers.
+func IsNumericFieldType(fieldType string) bool {
+	return fieldType != FieldTypeString && fieldType != FieldTypeBool
+}
+
+// PointFieldTypes returns the type of each field value of the point. Missing
+// (nil) values are assumed to be numeric, so non-numeric fields should always
+// be set on the points used to describe a measurement.
+func PointFieldTypes(p *data.Point) []string {
+	values := p.FieldValues()
+	types := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			types[i] = defaultFieldType
+			continue
+		}
+		types[i] = reflect.TypeOf(v).String()
+	}
+	return types
+}
+
+// FieldType returns the type of the i-th field of the given measurement.
+func (h *GeneratedDataHeaders) FieldType(measurement string, i int) string {
+	types := h.FieldTypes[measurement]
+	if i >= len(types) {
+		return defaultFieldType
+	}
+	return types[i]
+}
+
+// HasNonNumericFields tells whether any field of the given measurement is not
+// a number.
+func (h *GeneratedDataHeaders) HasNonNumericFields(measurement string) bool {
+	for _, t := range h.FieldTypes[measurement] {
+		if !IsNumericFieldType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldColumns returns the column definitions of the given measurement as
+// written in the header of data files. Numeric fields are listed by name,
+// while other fields are followed by their type like tags are, e.g.
+// "status string", so files with only numeric fields keep their format.
+func (h *GeneratedDataHeaders) FieldColumns(measurement string) []string {
+	keys := h.FieldKeys[measurement]
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key
+		if t := h.FieldType(measurement, i); !IsNumericFieldType(t) {
+			columns[i] += " " + t
+		}
+	}
+	return columns
+}
+
+// ParseFieldColumns splits the column definitions of a measurement in the
+// header of a data file into field keys and field types (see FieldColumns).
+func ParseFieldColumns(columns []string) (keys []string, types []string) {
+	keys = make([]string, len(columns))
+	types = make([]string, len(columns))
+	for i, column := range columns {
+		keyAndType := strings.SplitN(column, " ", 2)
+		keys[i] = keyAndType[0]
+		types[i] = defaultFieldType
+		if len(keyAndType) == 2 {
+			types[i] = keyAndType[1]
+		}
+	}
+	return keys, types
+}
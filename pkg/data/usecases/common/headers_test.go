@@ -0,0 +1,62 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestPointFieldTypes(t *testing.T) {
+	p := data.NewPoint()
+	p.AppendField([]byte("usage"), 1.5)
+	p.AppendField([]byte("count"), int64(2))
+	p.AppendField([]byte("status"), "ok")
+	p.AppendField([]byte("alerting"), false)
+	p.AppendField([]byte("missing"), nil)
+
+	want := []string{"float64", "int64", FieldTypeString, FieldTypeBool, "float64"}
+	if got := PointFieldTypes(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect field types: got %v want %v", got, want)
+	}
+}
+
+func TestFieldColumns(t *testing.T) {
+	h := &GeneratedDataHeaders{
+		FieldKeys: map[string][]string{
+			"cpu":    {"usage_user", "usage_system"},
+			"health": {"status", "alerting", "uptime"},
+		},
+		FieldTypes: map[string][]string{
+			"health": {FieldTypeString, FieldTypeBool, "int64"},
+		},
+	}
+
+	cases := []struct {
+		measurement string
+		want        []string
+		nonNumeric  bool
+	}{
+		{"cpu", []string{"usage_user", "usage_system"}, false},
+		{"health", []string{"status string", "alerting bool", "uptime"}, true},
+	}
+	for _, c := range cases {
+		columns := h.FieldColumns(c.measurement)
+		if !reflect.DeepEqual(columns, c.want) {
+			t.Errorf("%s: incorrect columns: got %v want %v", c.measurement, columns, c.want)
+		}
+		if got := h.HasNonNumericFields(c.measurement); got != c.nonNumeric {
+			t.Errorf("%s: incorrect non-numeric fields: got %v want %v", c.measurement, got, c.nonNumeric)
+		}
+
+		keys, types := ParseFieldColumns(columns)
+		if !reflect.DeepEqual(keys, h.FieldKeys[c.measurement]) {
+			t.Errorf("%s: incorrect parsed keys: got %v want %v", c.measurement, keys, h.FieldKeys[c.measurement])
+		}
+		for i, typ := range types {
+			if IsNumericFieldType(typ) != IsNumericFieldType(h.FieldType(c.measurement, i)) {
+				t.Errorf("%s: incorrect parsed type of %s: got %s want %s", c.measurement, keys[i], typ, h.FieldType(c.measurement, i))
+			}
+		}
+	}
+}
@@ -23,6 +23,10 @@ type BaseSimulatorConfig struct {
 	GeneratorScale uint64
 	// GeneratorConstructor is the function used to create a new Generator given an id number and start time
 	GeneratorConstructor func(i int, start time.Time) Generator
+	// ScaleUpCurve decides how the Generators scale from InitGeneratorScale to GeneratorScale (linear if nil)
+	ScaleUpCurve ScaleUpCurve
+	// ChurnRate is the fraction of reporting Generators replaced by new ones every reporting period
+	ChurnRate float64
 }
 
 func calculateEpochs(duration time.Duration, interval time.Duration) uint64 {
@@ -57,6 +61,10 @@ func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64
 		timestampEnd:    sc.End,
 		interval:        interval,
 
+		scaleUp:              sc.ScaleUpCurve,
+		churn:                NewChurn(sc.ChurnRate, sc.GeneratorScale),
+		generatorConstructor: sc.GeneratorConstructor,
+
 		simulatedMeasurementIndex: 0,
 	}
 
@@ -67,6 +75,9 @@ type GeneratedDataHeaders struct {
 	TagTypes  []string
 	TagKeys   []string
 	FieldKeys map[string][]string
+	// FieldTypes holds the type of each field in FieldKeys (e.g. "float64",
+	// "string" or "bool"). Measurements without types only have numeric fields.
+	FieldTypes map[string][]string
 }
 
 // Simulator simulates a use case.
@@ -96,6 +107,10 @@ type BaseSimulator struct {
 	timestampEnd   time.Time
 	interval       time.Duration
 
+	scaleUp              ScaleUpCurve
+	churn                *Churn
+	generatorConstructor func(i int, start time.Time) Generator
+
 	simulatedMeasurementIndex int
 }
 
@@ -158,6 +173,23 @@ func (s *BaseSimulator) Fields() map[string][]string {
 	return toReturn
 }
 
+// FieldTypes returns the type of the fields of all the simulated measurements
+// for the device.
+func (s *BaseSimulator) FieldTypes() map[string][]string {
+	if len(s.generators) <= 0 {
+		panic("cannot get field types because no Generators added")
+	}
+
+	toReturn := make(map[string][]string, len(s.generators))
+	for _, sm := range s.generators[0].Measurements() {
+		point := data.NewPoint()
+		sm.ToPoint(point)
+		toReturn[string(point.MeasurementName())] = PointFieldTypes(point)
+	}
+
+	return toReturn
+}
+
 // TagKeys returns all the tag keys for the device.
 func (s *BaseSimulator) TagKeys() []string {
 	if len(s.generators) <= 0 {
@@ -190,25 +222,34 @@ func (s *BaseSimulator) TagTypes() []string {
 
 func (s *BaseSimulator) Headers() *GeneratedDataHeaders {
 	return &GeneratedDataHeaders{
-		TagTypes:  s.TagTypes(),
-		TagKeys:   s.TagKeys(),
-		FieldKeys: s.Fields(),
+		TagTypes:   s.TagTypes(),
+		TagKeys:    s.TagKeys(),
+		FieldKeys:  s.Fields(),
+		FieldTypes: s.FieldTypes(),
 	}
 }
 
-// TODO(rrk) - Can probably turn this logic into a separate interface and implement other
-// types of scale up, e.g., exponential
-//
 // To "scale up" the number of reporting items, we need to know when
-// which epoch we are currently in. Once we know that, we can take the "missing"
-// amount of scale -- i.e., the max amount of scale less the initial amount
-// -- and add it in proportion to the percentage of epochs that have passed. This
+// which epoch we are currently in. Once we know that, the ScaleUpCurve
+// tells how much of the "missing" amount of scale -- i.e., the max amount
+// of scale less the initial amount -- should be reporting. This
 // way we simulate all items at each epoch, but at the end of the function
 // we check whether the point should be recorded by the calling process.
+//
+// Afterwards, churned Generators are replaced with new ones which get a
+// fresh id, fresh tag values and start reporting at the current epoch.
 func (s *BaseSimulator) adjustNumHostsForEpoch() {
 	s.epoch++
-	missingScale := float64(uint64(len(s.generators)) - s.initGenerators)
-	s.epochGenerators = s.initGenerators + uint64(missingScale*float64(s.epoch)/float64(s.epochs-1))
+	scaleUp := s.scaleUp
+	if scaleUp == nil {
+		scaleUp = LinearScaleUp{}
+	}
+	s.epochGenerators = scaleUp.EpochScale(s.epoch, s.epochs, s.initGenerators, uint64(len(s.generators)))
+
+	start := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	for _, r := range s.churn.Replacements(s.epochGenerators) {
+		s.generators[r.Index] = s.generatorConstructor(int(r.ID), start)
+	}
 }
 
 // SimulatedMeasurement simulates one measurement (e.g. Redis for DevOps).
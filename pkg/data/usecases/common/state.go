@@ -0,0 +1,66 @@
+package common
+
+import "math/rand"
+
+// StateWalk models a value which moves between a fixed set of states, such as
+// the status of a host or the state of an engine. Every time it advances, it
+// stays in its current state with the Stay chance and otherwise jumps to a
+// state drawn according to the Weights of the states.
+type StateWalk struct {
+	States  []string
+	Weights []float64
+	Stay    float64
+
+	state   int
+	changed bool
+}
+
+// SW creates a new StateWalk over the given states, starting in a state drawn
+// according to their weights.
+func SW(states []string, weights []float64, stay float64) *StateWalk {
+	if len(states) != len(weights) {
+		panic("each state needs a weight")
+	}
+	w := &StateWalk{
+		States:  states,
+		Weights: weights,
+		Stay:    stay,
+	}
+	w.state = w.draw()
+	return w
+}
+
+// Advance moves the walk to its next state, which may be the same state.
+func (w *StateWalk) Advance() {
+	previous := w.state
+	if rand.Float64() >= w.Stay {
+		w.state = w.draw()
+	}
+	w.changed = w.state != previous
+}
+
+// Get returns the current state.
+func (w *StateWalk) Get() string {
+	return w.States[w.state]
+}
+
+// Changed tells whether the last Advance moved the walk to another state.
+func (w *StateWalk) Changed() bool {
+	return w.changed
+}
+
+func (w *StateWalk) draw() int {
+	total := 0.0
+	for _, weight := range w.Weights {
+		total += weight
+	}
+
+	x := rand.Float64() * total
+	for i, weight := range w.Weights {
+		if x < weight {
+			return i
+		}
+		x -= weight
+	}
+	return len(w.Weights) - 1
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStateWalk(t *testing.T) {
+	rand.Seed(123)
+	states := []string{"up", "down", "never"}
+	w := SW(states, []float64{3, 1, 0}, 0.9)
+
+	counts := make(map[string]int)
+	changes := 0
+	previous := w.Get()
+	for i := 0; i < 100000; i++ {
+		w.Advance()
+		counts[w.Get()]++
+		if w.Changed() != (w.Get() != previous) {
+			t.Fatalf("changed is %v going from %s to %s", w.Changed(), previous, w.Get())
+		}
+		if w.Changed() {
+			changes++
+		}
+		previous = w.Get()
+	}
+
+	if counts["never"] != 0 {
+		t.Errorf("state without weight was reached %d times", counts["never"])
+	}
+	if ratio := float64(counts["up"]) / float64(counts["down"]); ratio < 2.5 || ratio > 3.5 {
+		t.Errorf("states are not visited according to their weights: ratio %f", ratio)
+	}
+	// A change needs a jump (10%) to the other state (25% or 75%).
+	if changes < 3000 || changes > 4500 {
+		t.Errorf("unexpected number of state changes: %d", changes)
+	}
+}
+
+func TestStateWalkMismatchedWeights(t *testing.T) {
+	defer func() {
+		if re := recover(); re == nil {
+			t.Errorf("did not panic when states and weights have different lengths")
+		}
+	}()
+	SW([]string{"up", "down"}, []float64{1}, 0.5)
+}
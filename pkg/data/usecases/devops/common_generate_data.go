@@ -28,6 +28,10 @@ type commonDevopsSimulatorConfig struct {
 	HostConstructor func(ctx *HostContext) Host
 	// MaxMetricCount is the max number of metrics per host to create when using generic-devops use-case
 	MaxMetricCount uint64
+	// ScaleUpCurve decides how the hosts scale from InitHostCount to HostCount (linear if nil)
+	ScaleUpCurve common.ScaleUpCurve
+	// ChurnRate is the fraction of reporting hosts replaced by new ones every reporting period
+	ChurnRate float64
 }
 
 func NewHostCtx(id int, start time.Time) *HostContext {
@@ -57,6 +61,10 @@ type commonDevopsSimulator struct {
 	timestampStart time.Time
 	timestampEnd   time.Time
 	interval       time.Duration
+
+	scaleUp         common.ScaleUpCurve
+	churn           *common.Churn
+	hostConstructor func(ctx *HostContext) Host
 }
 
 // Finished tells whether we have simulated all the necessary points
@@ -71,6 +79,19 @@ func (s *commonDevopsSimulator) Fields() map[string][]string {
 	return s.fields(s.hosts[0].SimulatedMeasurements)
 }
 
+func (s *commonDevopsSimulator) FieldTypes() map[string][]string {
+	if len(s.hosts) <= 0 {
+		panic("cannot get field types because no hosts added")
+	}
+	types := make(map[string][]string)
+	for _, sm := range s.hosts[0].SimulatedMeasurements {
+		point := data.NewPoint()
+		sm.ToPoint(point)
+		types[string(point.MeasurementName())] = common.PointFieldTypes(point)
+	}
+	return types
+}
+
 func (s *commonDevopsSimulator) TagKeys() []string {
 	tagKeysAsStr := make([]string, len(MachineTagKeys))
 	for i, t := range MachineTagKeys {
@@ -89,9 +110,10 @@ func (s *commonDevopsSimulator) TagTypes() []string {
 
 func (d *commonDevopsSimulator) Headers() *common.GeneratedDataHeaders {
 	return &common.GeneratedDataHeaders{
-		TagTypes:  d.TagTypes(),
-		TagKeys:   d.TagKeys(),
-		FieldKeys: d.Fields(),
+		TagTypes:   d.TagTypes(),
+		TagKeys:    d.TagKeys(),
+		FieldKeys:  d.Fields(),
+		FieldTypes: d.FieldTypes(),
 	}
 }
 func (s *commonDevopsSimulator) fields(measurements []common.SimulatedMeasurement) map[string][]string {
@@ -134,17 +156,26 @@ func (s *commonDevopsSimulator) populatePoint(p *data.Point, measureIdx int) boo
 	return ret
 }
 
-// TODO(rrk) - Can probably turn this logic into a separate interface and implement other
-// types of scale up, e.g., exponential
-//
 // To "scale up" the number of reporting items, we need to know when
-// which epoch we are currently in. Once we know that, we can take the "missing"
-// amount of scale -- i.e., the max amount of scale less the initial amount
-// -- and add it in proportion to the percentage of epochs that have passed. This
+// which epoch we are currently in. Once we know that, the scale-up curve
+// tells how much of the "missing" amount of scale -- i.e., the max amount
+// of scale less the initial amount -- should be reporting. This
 // way we simulate all items at each epoch, but at the end of the function
 // we check whether the point should be recorded by the calling process.
+//
+// Afterwards, churned hosts are replaced with new ones which get a fresh
+// hostname, fresh tag values and start reporting at the current epoch.
 func (s *commonDevopsSimulator) adjustNumHostsForEpoch() {
 	s.epoch++
-	missingScale := float64(uint64(len(s.hosts)) - s.initHosts)
-	s.epochHosts = s.initHosts + uint64(missingScale*float64(s.epoch)/float64(s.epochs-1))
+	scaleUp := s.scaleUp
+	if scaleUp == nil {
+		scaleUp = common.LinearScaleUp{}
+	}
+	s.epochHosts = scaleUp.EpochScale(s.epoch, s.epochs, s.initHosts, uint64(len(s.hosts)))
+
+	start := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	for _, r := range s.churn.Replacements(s.epochHosts) {
+		old := &s.hosts[r.Index]
+		s.hosts[r.Index] = s.hostConstructor(&HostContext{int(r.ID), start, old.GenericMetricCount, old.EpochsToLive})
+	}
 }
@@ -304,3 +304,35 @@ func TestCommonDevopsSimulatorTagTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestAdjustNumHostsForEpochChurn(t *testing.T) {
+	start := time.Now()
+	s := &commonDevopsSimulator{
+		epochs:          3,
+		timestampStart:  start,
+		interval:        time.Second,
+		churn:           common.NewChurn(0.5, 4),
+		hostConstructor: NewHost,
+	}
+	for i := 0; i < 4; i++ {
+		s.hosts = append(s.hosts, NewHost(NewHostCtx(i, start)))
+	}
+	s.initHosts = 2
+	s.epochHosts = 2
+
+	// 3 hosts report in epoch 1, so 1.5 hosts are replaced (rounded down, carried over)
+	s.adjustNumHostsForEpoch()
+	// 4 hosts report in epoch 2, so 2 + 0.5 carried over hosts are replaced
+	s.adjustNumHostsForEpoch()
+
+	wantNames := []string{"host_4", "host_5", "host_6", "host_3"}
+	for i, h := range s.hosts {
+		if h.Name != wantNames[i] {
+			t.Errorf("incorrect host name at index %d: got %s want %s", i, h.Name, wantNames[i])
+		}
+	}
+	ts := s.hosts[0].SimulatedMeasurements[0].(*CPUMeasurement).Timestamp
+	if want := start.Add(time.Second); !ts.Equal(want) {
+		t.Errorf("replacement host has incorrect start: got %v want %v", ts, want)
+	}
+}
@@ -71,6 +71,10 @@ func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint
 		timestampStart: c.Start,
 		timestampEnd:   c.End,
 		interval:       interval,
+
+		scaleUp:         c.ScaleUpCurve,
+		churn:           common.NewChurn(c.ChurnRate, c.HostCount),
+		hostConstructor: c.HostConstructor,
 	}}
 
 	return sim
@@ -52,9 +52,10 @@ func (s *DevopsSimulator) TagTypes() []string {
 
 func (d *DevopsSimulator) Headers() *common.GeneratedDataHeaders {
 	return &common.GeneratedDataHeaders{
-		TagTypes:  d.TagTypes(),
-		TagKeys:   d.TagKeys(),
-		FieldKeys: d.Fields(),
+		TagTypes:   d.TagTypes(),
+		TagKeys:    d.TagKeys(),
+		FieldKeys:  d.Fields(),
+		FieldTypes: d.FieldTypes(),
 	}
 }
 
@@ -89,6 +90,10 @@ func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint6
 			timestampStart: d.Start,
 			timestampEnd:   d.End,
 			interval:       interval,
+
+			scaleUp:         d.ScaleUpCurve,
+			churn:           common.NewChurn(d.ChurnRate, d.HostCount),
+			hostConstructor: d.HostConstructor,
 		},
 		simulatedMeasurementIndex: 0,
 	}
@@ -51,6 +51,10 @@ func (c *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, lim
 			timestampStart: c.Start,
 			timestampEnd:   c.End,
 			interval:       interval,
+
+			scaleUp:         c.ScaleUpCurve,
+			churn:           common.NewChurn(c.ChurnRate, c.HostCount),
+			hostConstructor: c.HostConstructor,
 		},
 	}
 
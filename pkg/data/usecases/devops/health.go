@@ -0,0 +1,100 @@
+package devops
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	healthStatusHealthy     = "healthy"
+	healthStatusDegraded    = "degraded"
+	healthStatusMaintenance = "maintenance"
+	healthStatusUnreachable = "unreachable"
+
+	// healthStayChance is the chance a host keeps its status for another interval.
+	healthStayChance = 0.95
+)
+
+var (
+	labelHealth          = []byte("health") // heap optimization
+	labelHealthStatus    = []byte("status")
+	labelHealthAlerting  = []byte("alerting")
+	labelHealthLastError = []byte("last_error")
+
+	// HealthStatusChoices are the values of the status field of the health measurement.
+	HealthStatusChoices = []string{
+		healthStatusHealthy,
+		healthStatusDegraded,
+		healthStatusMaintenance,
+		healthStatusUnreachable,
+	}
+	healthStatusWeights = []float64{0.85, 0.08, 0.04, 0.03}
+
+	healthErrorFormats = []func() string{
+		func() string { return fmt.Sprintf("disk /dev/sda%d is %d%% full", 1+rand.Intn(4), 90+rand.Intn(11)) },
+		func() string {
+			return fmt.Sprintf("connection to 10.0.%d.%d:5432 timed out after %dms", rand.Intn(256), rand.Intn(256), 1000+rand.Intn(9000))
+		},
+		func() string {
+			return fmt.Sprintf("process %d exited with code %d", 1000+rand.Intn(64000), 1+rand.Intn(255))
+		},
+		func() string { return fmt.Sprintf("health check returned HTTP %d", 500+rand.Intn(5)) },
+		func() string { return fmt.Sprintf("out of memory: killed process %d", 1000+rand.Intn(64000)) },
+	}
+)
+
+// HealthMeasurement reports the status of a host as seen by a monitoring
+// agent. Unlike the other measurements its fields are not numeric: the
+// status is an enum, alerting a boolean and last_error a free text message.
+type HealthMeasurement struct {
+	timestamp time.Time
+	status    *common.StateWalk
+	lastError string
+}
+
+// NewHealthMeasurement creates a new HealthMeasurement with start time.
+func NewHealthMeasurement(start time.Time) *HealthMeasurement {
+	m := &HealthMeasurement{
+		timestamp: start,
+		status:    common.SW(HealthStatusChoices, healthStatusWeights, healthStayChance),
+	}
+	m.updateLastError()
+	return m
+}
+
+// Tick advances the status of the host by the given duration.
+func (m *HealthMeasurement) Tick(d time.Duration) {
+	m.timestamp = m.timestamp.Add(d)
+	m.status.Advance()
+	if m.status.Changed() {
+		m.updateLastError()
+	}
+}
+
+// ToPoint fills in the status of the host. The last error is empty while the
+// host is not alerting.
+func (m *HealthMeasurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(labelHealth)
+	p.SetTimestamp(&m.timestamp)
+
+	p.AppendField(labelHealthStatus, m.status.Get())
+	p.AppendField(labelHealthAlerting, m.alerting())
+	p.AppendField(labelHealthLastError, m.lastError)
+}
+
+func (m *HealthMeasurement) alerting() bool {
+	status := m.status.Get()
+	return status == healthStatusDegraded || status == healthStatusUnreachable
+}
+
+// updateLastError draws a new error message when the host starts alerting.
+func (m *HealthMeasurement) updateLastError() {
+	m.lastError = ""
+	if m.alerting() {
+		m.lastError = healthErrorFormats[rand.Intn(len(healthErrorFormats))]()
+	}
+}
@@ -0,0 +1,64 @@
+package devops
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func TestHealthMeasurementTick(t *testing.T) {
+	rand.Seed(123)
+	now := time.Now()
+	m := NewHealthMeasurement(now)
+	duration := time.Second
+
+	statuses := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		lastError := m.lastError
+		m.Tick(duration)
+		statuses[m.status.Get()] = true
+		if m.alerting() == (m.lastError == "") {
+			t.Fatalf("alerting %v does not match last error '%s'", m.alerting(), m.lastError)
+		}
+		if !m.status.Changed() && m.lastError != lastError {
+			t.Fatalf("last error changed without a status change: got '%s' want '%s'", m.lastError, lastError)
+		}
+	}
+	if got := m.timestamp; !got.Equal(now.Add(1000 * duration)) {
+		t.Errorf("incorrect timestamp: got %v", got)
+	}
+	if len(statuses) != len(HealthStatusChoices) {
+		t.Errorf("not all statuses were reached: got %v", statuses)
+	}
+}
+
+func TestHealthMeasurementToPoint(t *testing.T) {
+	now := time.Now()
+	m := NewHealthMeasurement(now)
+	m.Tick(time.Second)
+
+	p := data.NewPoint()
+	m.ToPoint(p)
+	if got := string(p.MeasurementName()); got != string(labelHealth) {
+		t.Errorf("incorrect measurement name: got %s want %s", got, labelHealth)
+	}
+	if got := p.GetFieldValue(labelHealthStatus).(string); got != m.status.Get() {
+		t.Errorf("incorrect status: got %s want %s", got, m.status.Get())
+	}
+	if got := p.GetFieldValue(labelHealthAlerting).(bool); got != m.alerting() {
+		t.Errorf("incorrect alerting: got %v want %v", got, m.alerting())
+	}
+	if got := p.GetFieldValue(labelHealthLastError).(string); got != m.lastError {
+		t.Errorf("incorrect last error: got %s want %s", got, m.lastError)
+	}
+
+	want := []string{common.FieldTypeString, common.FieldTypeBool, common.FieldTypeString}
+	for i, got := range common.PointFieldTypes(p) {
+		if got != want[i] {
+			t.Errorf("incorrect type of field %s: got %s want %s", p.FieldKeys()[i], got, want[i])
+		}
+	}
+}
@@ -173,6 +173,10 @@ func newHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	}
 }
 
+func newNonNumericHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
+	return append(newHostMeasurements(ctx), NewHealthMeasurement(ctx.start))
+}
+
 func newCPUOnlyHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
 		NewCPUMeasurement(ctx.start),
@@ -194,6 +198,12 @@ func NewHost(ctx *HostContext) Host {
 	return newHostWithMeasurementGenerator(newHostMeasurements, ctx)
 }
 
+// NewHostNonNumeric creates a new host in a simulated devops use case which
+// also reports its health with string and boolean fields
+func NewHostNonNumeric(ctx *HostContext) Host {
+	return newHostWithMeasurementGenerator(newNonNumericHostMeasurements, ctx)
+}
+
 // NewHostCPUOnly creates a new host in a simulated cpu-only use case, which is a subset of a devops case
 // with only CPU metrics simulated
 func NewHostCPUOnly(ctx *HostContext) Host {
@@ -0,0 +1,231 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// TradeMeasurementName is the name of the measurement holding the trades.
+	TradeMeasurementName = "trade"
+	// QuoteMeasurementName is the name of the measurement holding the top of book quotes.
+	QuoteMeasurementName = "quote"
+
+	// TickSize is the smallest price increment of all instruments.
+	TickSize = 0.01
+	// LotSize is the smallest quantity that can be quoted or traded.
+	LotSize = 100
+
+	symbolLetters    = 4
+	symbolMultiplier = 7919 // coprime with 26^4, so symbols are unique
+
+	tradeChance      = 0.2
+	burstStartChance = 0.005
+	burstEndChance   = 0.05
+	burstFactor      = 20.0
+	volatility       = 0.0005
+	maxSpreadTicks   = 4
+	maxQuoteLots     = 50
+	maxTradeLots     = 20
+)
+
+var (
+	// InstrumentTagKeys are the tag keys common to all instruments.
+	InstrumentTagKeys = [][]byte{
+		[]byte("symbol"),
+		[]byte("exchange"),
+		[]byte("currency"),
+	}
+
+	// TradeFields are the fields of the trade measurement.
+	TradeFields = [][]byte{
+		[]byte("price"),
+		[]byte("size"),
+	}
+
+	// QuoteFields are the fields of the quote measurement.
+	QuoteFields = [][]byte{
+		[]byte("bid_price"),
+		[]byte("bid_size"),
+		[]byte("ask_price"),
+		[]byte("ask_size"),
+	}
+
+	// ExchangeChoices are the exchanges instruments are listed on.
+	ExchangeChoices = []string{"NYSE", "NASDAQ", "LSE", "XETRA", "TSE", "HKEX"}
+
+	exchangeCurrencies = map[string]string{
+		"NYSE":   "USD",
+		"NASDAQ": "USD",
+		"LSE":    "GBP",
+		"XETRA":  "EUR",
+		"TSE":    "JPY",
+		"HKEX":   "HKD",
+	}
+
+	// activityChoices scale the event rate of an instrument, liquid
+	// instruments see a lot more trades and quotes than illiquid ones.
+	activityChoices = []float64{1, 2, 5, 10}
+
+	tradeMeasurementName = []byte(TradeMeasurementName)
+	quoteMeasurementName = []byte(QuoteMeasurementName)
+)
+
+// SymbolName returns the ticker symbol of the i-th instrument.
+func SymbolName(i int) string {
+	space := int(math.Pow(26, symbolLetters))
+	code := (i%space + 1) * symbolMultiplier % space
+	symbol := make([]byte, symbolLetters)
+	for j := symbolLetters - 1; j >= 0; j-- {
+		symbol[j] = byte('A' + code%26)
+		code /= 26
+	}
+	if i >= space {
+		return fmt.Sprintf("%s%d", symbol, i/space)
+	}
+	return string(symbol)
+}
+
+// Instrument models a listed instrument whose trades and quotes arrive at
+// irregular, bursty intervals. Events follow a Poisson process whose rate
+// jumps by burstFactor while the instrument is in a burst, e.g., after news.
+type Instrument struct {
+	tags     []common.Tag
+	activity float64
+	bursting bool
+
+	// mid is the random walk of the mid price between the bid and the ask.
+	mid    common.Distribution
+	spread float64
+
+	// next is the time of the pending event; the rest of the state is the
+	// content of the event.
+	next     time.Time
+	isTrade  bool
+	bidPrice float64
+	bidSize  int64
+	askPrice float64
+	askSize  int64
+	price    float64
+	size     int64
+
+	queueIndex int
+}
+
+// NewInstrument creates a new instrument with the given id. The first event
+// is scheduled at start; the following events are scheduled by Advance.
+func NewInstrument(i int, start time.Time) *Instrument {
+	exchange := ExchangeChoices[rand.Intn(len(ExchangeChoices))]
+	values := []string{
+		SymbolName(i),
+		exchange,
+		exchangeCurrencies[exchange],
+	}
+	tags := make([]common.Tag, len(InstrumentTagKeys))
+	for j := range tags {
+		tags[j] = common.Tag{Key: InstrumentTagKeys[j], Value: values[j]}
+	}
+
+	// Prices are log-uniformly distributed between 1 and 1000.
+	initPrice := roundToTick(math.Pow(10, rand.Float64()*3))
+	inst := &Instrument{
+		tags:     tags,
+		activity: activityChoices[rand.Intn(len(activityChoices))],
+		mid: common.FP(
+			common.CWD(common.ND(0, initPrice*volatility), math.Max(initPrice/10, TickSize), initPrice*10, initPrice),
+			2,
+		),
+		spread: float64(1+rand.Intn(maxSpreadTicks)) * TickSize,
+		next:   start,
+	}
+	inst.quote()
+	return inst
+}
+
+// Tags returns the instrument tags.
+func (inst *Instrument) Tags() []common.Tag {
+	return inst.tags
+}
+
+// Next returns the time of the pending event.
+func (inst *Instrument) Next() time.Time {
+	return inst.next
+}
+
+// Advance schedules the next event of the instrument. The mean time between
+// the events of the least active instruments outside of bursts is meanGap.
+func (inst *Instrument) Advance(meanGap time.Duration) {
+	if inst.bursting {
+		inst.bursting = rand.Float64() >= burstEndChance
+	} else {
+		inst.bursting = rand.Float64() < burstStartChance
+	}
+
+	rate := inst.activity
+	if inst.bursting {
+		rate *= burstFactor
+	}
+	gap := time.Duration(rand.ExpFloat64() * float64(meanGap) / rate)
+	if gap <= 0 {
+		gap = time.Nanosecond
+	}
+	inst.next = inst.next.Add(gap)
+
+	inst.isTrade = rand.Float64() < tradeChance
+	if inst.isTrade {
+		inst.trade()
+	} else {
+		inst.mid.Advance()
+		inst.quote()
+	}
+}
+
+// quote moves the top of the book around the current mid price.
+func (inst *Instrument) quote() {
+	mid := inst.mid.Get()
+	inst.bidPrice = roundToTick(math.Max(mid-inst.spread/2, TickSize))
+	inst.askPrice = roundToTick(inst.bidPrice + inst.spread)
+	inst.bidSize = int64(1+rand.Intn(maxQuoteLots)) * LotSize
+	inst.askSize = int64(1+rand.Intn(maxQuoteLots)) * LotSize
+}
+
+// trade executes a trade against either side of the current quote.
+func (inst *Instrument) trade() {
+	if rand.Intn(2) == 0 {
+		inst.price = inst.bidPrice
+	} else {
+		inst.price = inst.askPrice
+	}
+	inst.size = int64(1+rand.Intn(maxTradeLots)) * LotSize
+}
+
+// ToPoint fills in the tags and fields of the pending event. The timestamp
+// is left to the caller, since it changes when the instrument advances.
+func (inst *Instrument) ToPoint(p *data.Point) {
+	for _, tag := range inst.tags {
+		p.AppendTag(tag.Key, tag.Value)
+	}
+
+	if inst.isTrade {
+		p.SetMeasurementName(tradeMeasurementName)
+		p.AppendField(TradeFields[0], inst.price)
+		p.AppendField(TradeFields[1], inst.size)
+		return
+	}
+
+	p.SetMeasurementName(quoteMeasurementName)
+	p.AppendField(QuoteFields[0], inst.bidPrice)
+	p.AppendField(QuoteFields[1], inst.bidSize)
+	p.AppendField(QuoteFields[2], inst.askPrice)
+	p.AppendField(QuoteFields[3], inst.askSize)
+}
+
+func roundToTick(price float64) float64 {
+	// Dividing by the inverse keeps the result the closest float to the decimal price.
+	return math.Round(price/TickSize) / (1 / TickSize)
+}
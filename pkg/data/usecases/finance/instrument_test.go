@@ -0,0 +1,71 @@
+package finance
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestSymbolName(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 30000; i++ {
+		s := SymbolName(i)
+		if seen[s] {
+			t.Fatalf("duplicate symbol %s for instrument %d", s, i)
+		}
+		seen[s] = true
+	}
+	if got := SymbolName(26 * 26 * 26 * 26); got != SymbolName(0)+"1" {
+		t.Errorf("incorrect symbol for instrument beyond letter space: got %s", got)
+	}
+}
+
+func TestInstrumentEvents(t *testing.T) {
+	rand.Seed(123)
+	start := time.Unix(0, 0)
+	inst := NewInstrument(1, start)
+	if got := inst.Tags()[0].Value; got != SymbolName(1) {
+		t.Errorf("incorrect symbol: got %v want %s", got, SymbolName(1))
+	}
+	if got := inst.Tags()[2].Value; got != exchangeCurrencies[inst.Tags()[1].Value.(string)] {
+		t.Errorf("incorrect currency for exchange %v: got %v", inst.Tags()[1].Value, got)
+	}
+
+	prev := inst.Next()
+	trades := 0
+	p := data.NewPoint()
+	for i := 0; i < 1000; i++ {
+		inst.Advance(time.Second)
+		if !inst.Next().After(prev) {
+			t.Fatalf("event %d not after the previous one: %v <= %v", i, inst.Next(), prev)
+		}
+		prev = inst.Next()
+
+		inst.ToPoint(p)
+		switch string(p.MeasurementName()) {
+		case TradeMeasurementName:
+			trades++
+			price := p.GetFieldValue(TradeFields[0]).(float64)
+			if price != inst.bidPrice && price != inst.askPrice {
+				t.Errorf("trade price %f outside of quote %f-%f", price, inst.bidPrice, inst.askPrice)
+			}
+			if size := p.GetFieldValue(TradeFields[1]).(int64); size <= 0 || size%LotSize != 0 {
+				t.Errorf("incorrect trade size: %d", size)
+			}
+		case QuoteMeasurementName:
+			bid := p.GetFieldValue(QuoteFields[0]).(float64)
+			ask := p.GetFieldValue(QuoteFields[2]).(float64)
+			if bid <= 0 || ask <= bid {
+				t.Errorf("crossed or non-positive quote: %f-%f", bid, ask)
+			}
+		default:
+			t.Fatalf("unknown measurement %s", p.MeasurementName())
+		}
+		p.Reset()
+	}
+	if trades == 0 || trades == 1000 {
+		t.Errorf("unexpected number of trades: %d", trades)
+	}
+}
@@ -0,0 +1,219 @@
+package finance
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a finance Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	// Start is the beginning time for the Simulator
+	Start time.Time
+	// End is the ending time for the Simulator
+	End time.Time
+	// InitInstrumentCount is the number of instruments trading in the first reporting period
+	InitInstrumentCount uint64
+	// InstrumentCount is the total number of instruments trading in the last reporting period
+	InstrumentCount uint64
+	// InstrumentConstructor is the function used to create a new instrument given an id number and start time
+	InstrumentConstructor func(i int, start time.Time) *Instrument
+	// ScaleUpCurve decides how the instruments scale from InitInstrumentCount to InstrumentCount (linear if nil)
+	ScaleUpCurve common.ScaleUpCurve
+	// ChurnRate is the fraction of trading instruments delisted and replaced by new listings every reporting period
+	ChurnRate float64
+}
+
+// NewSimulator produces a finance Simulator with the given config. Unlike
+// other use cases, the interval is not the time between the readings of an
+// instrument but the mean time between the events of the least active
+// instruments, and the reporting period used for scaling up and churn.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	instruments := make([]*Instrument, sc.InstrumentCount)
+	for i := range instruments {
+		instruments[i] = sc.InstrumentConstructor(i, sc.Start)
+	}
+
+	scaleUp := sc.ScaleUpCurve
+	if scaleUp == nil {
+		scaleUp = common.LinearScaleUp{}
+	}
+
+	s := &Simulator{
+		maxPoints: limit,
+
+		instruments: instruments,
+		queue:       make(eventQueue, 0, len(instruments)),
+
+		epochs:              uint64(sc.End.Sub(sc.Start) / interval),
+		initInstrumentCount: sc.InitInstrumentCount,
+		timestampStart:      sc.Start,
+		timestampEnd:        sc.End,
+		interval:            interval,
+
+		scaleUp:               scaleUp,
+		churn:                 common.NewChurn(sc.ChurnRate, sc.InstrumentCount),
+		instrumentConstructor: sc.InstrumentConstructor,
+	}
+	s.activateInstruments(scaleUp.EpochScale(0, s.epochs, s.initInstrumentCount, uint64(len(instruments))), sc.Start)
+	s.catchUp()
+
+	return s
+}
+
+// Simulator generates trades and quotes of a set of instruments in time
+// order. Every instrument schedules its own events, so the Simulator keeps
+// the trading instruments in a queue ordered by the time of their next event.
+type Simulator struct {
+	madePoints uint64
+	// maxPoints is the number of points to generate, no limit if zero.
+	maxPoints uint64
+
+	instruments []*Instrument
+	queue       eventQueue
+	// timestamp holds the timestamp of the last generated point, since the
+	// instrument it came from has already moved on to its next event.
+	timestamp time.Time
+
+	epoch               uint64
+	epochs              uint64
+	activeInstruments   uint64
+	initInstrumentCount uint64
+	timestampStart      time.Time
+	timestampEnd        time.Time
+	interval            time.Duration
+
+	scaleUp               common.ScaleUpCurve
+	churn                 *common.Churn
+	instrumentConstructor func(i int, start time.Time) *Instrument
+}
+
+// Finished tells whether we have simulated all the necessary points.
+func (s *Simulator) Finished() bool {
+	if s.maxPoints > 0 && s.madePoints >= s.maxPoints {
+		return true
+	}
+	return len(s.queue) == 0 || !s.queue[0].Next().Before(s.timestampEnd)
+}
+
+// Next populates the data.Point with the earliest pending event and
+// schedules the next event of that instrument.
+func (s *Simulator) Next(p *data.Point) bool {
+	inst := s.queue[0]
+	s.timestamp = inst.Next()
+	p.SetTimestamp(&s.timestamp)
+	inst.ToPoint(p)
+
+	inst.Advance(s.interval)
+	heap.Fix(&s.queue, 0)
+	s.catchUp()
+
+	s.madePoints++
+	return true
+}
+
+// Fields returns the fields of the trade and quote measurements.
+func (s *Simulator) Fields() map[string][]string {
+	return map[string][]string{
+		TradeMeasurementName: bytesToStrings(TradeFields),
+		QuoteMeasurementName: bytesToStrings(QuoteFields),
+	}
+}
+
+// TagKeys returns the tag keys of the instruments.
+func (s *Simulator) TagKeys() []string {
+	return bytesToStrings(InstrumentTagKeys)
+}
+
+// TagTypes returns the type of each instrument tag.
+func (s *Simulator) TagTypes() []string {
+	types := make([]string, len(InstrumentTagKeys))
+	for i := range types {
+		types[i] = "string"
+	}
+	return types
+}
+
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{
+		TagTypes:  s.TagTypes(),
+		TagKeys:   s.TagKeys(),
+		FieldKeys: s.Fields(),
+	}
+}
+
+// catchUp moves on to the reporting period of the next pending event, so
+// instruments joining or being listed in between are queued in time.
+func (s *Simulator) catchUp() {
+	for s.epoch+1 < s.epochs {
+		nextEpochStart := s.timestampStart.Add(time.Duration(s.epoch+1) * s.interval)
+		if len(s.queue) > 0 && s.queue[0].Next().Before(nextEpochStart) {
+			return
+		}
+		s.adjustInstrumentsForEpoch(nextEpochStart)
+	}
+}
+
+// adjustInstrumentsForEpoch starts the epoch at the given time: instruments
+// are added according to the scale-up curve and churned instruments are
+// replaced by new listings.
+func (s *Simulator) adjustInstrumentsForEpoch(start time.Time) {
+	s.epoch++
+	s.activateInstruments(s.scaleUp.EpochScale(s.epoch, s.epochs, s.initInstrumentCount, uint64(len(s.instruments))), start)
+
+	for _, r := range s.churn.Replacements(s.activeInstruments) {
+		old := s.instruments[r.Index]
+		inst := s.instrumentConstructor(int(r.ID), start)
+		inst.queueIndex = old.queueIndex
+		s.instruments[r.Index] = inst
+		s.queue[inst.queueIndex] = inst
+		heap.Fix(&s.queue, inst.queueIndex)
+	}
+}
+
+// activateInstruments queues the instruments up to the given count with their
+// first event at start.
+func (s *Simulator) activateInstruments(count uint64, start time.Time) {
+	for ; s.activeInstruments < count; s.activeInstruments++ {
+		inst := s.instruments[s.activeInstruments]
+		inst.next = start
+		heap.Push(&s.queue, inst)
+	}
+}
+
+func bytesToStrings(in [][]byte) []string {
+	out := make([]string, len(in))
+	for i, b := range in {
+		out[i] = string(b)
+	}
+	return out
+}
+
+// eventQueue is a container/heap of instruments ordered by their next event.
+type eventQueue []*Instrument
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool { return q[i].next.Before(q[j].next) }
+
+func (q eventQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].queueIndex = i
+	q[j].queueIndex = j
+}
+
+func (q *eventQueue) Push(x interface{}) {
+	inst := x.(*Instrument)
+	inst.queueIndex = len(*q)
+	*q = append(*q, inst)
+}
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	inst := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return inst
+}
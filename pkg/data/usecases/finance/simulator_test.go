@@ -0,0 +1,92 @@
+package finance
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestSimulatorTimeOrder(t *testing.T) {
+	rand.Seed(123)
+	start := time.Unix(0, 0)
+	interval := time.Second
+	sc := &SimulatorConfig{
+		Start:                 start,
+		End:                   start.Add(60 * interval),
+		InitInstrumentCount:   5,
+		InstrumentCount:       10,
+		InstrumentConstructor: NewInstrument,
+		ChurnRate:             0.1,
+	}
+
+	sim := sc.NewSimulator(interval, 0)
+	symbols := make(map[string]bool)
+	var prev time.Time
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if !sim.Next(p) {
+			t.Fatalf("simulator returned a point which should not be written")
+		}
+		ts := *p.Timestamp()
+		if ts.Before(prev) {
+			t.Fatalf("points out of order: %v before %v", ts, prev)
+		}
+		if ts.Before(sc.Start) || !ts.Before(sc.End) {
+			t.Fatalf("point outside of the time range: %v", ts)
+		}
+		prev = ts
+		symbols[p.GetTagValue(InstrumentTagKeys[0]).(string)] = true
+		p.Reset()
+	}
+
+	// At most 1 of the 10 instruments is replaced in each of the 59 epoch
+	// changes, and replaced instruments do not necessarily trade beforehand.
+	if got := len(symbols); got <= 10 || got > 69 {
+		t.Errorf("incorrect number of distinct symbols: got %d", got)
+	}
+}
+
+func TestSimulatorLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	sc := &SimulatorConfig{
+		Start:                 start,
+		End:                   start.Add(time.Hour),
+		InitInstrumentCount:   3,
+		InstrumentCount:       3,
+		InstrumentConstructor: NewInstrument,
+	}
+
+	sim := sc.NewSimulator(time.Second, 10)
+	p := data.NewPoint()
+	count := 0
+	for !sim.Finished() {
+		sim.Next(p)
+		p.Reset()
+		count++
+	}
+	if count != 10 {
+		t.Errorf("incorrect number of points: got %d want 10", count)
+	}
+}
+
+func TestSimulatorHeaders(t *testing.T) {
+	sc := &SimulatorConfig{
+		Start:                 time.Unix(0, 0),
+		End:                   time.Unix(60, 0),
+		InitInstrumentCount:   1,
+		InstrumentCount:       1,
+		InstrumentConstructor: NewInstrument,
+	}
+	headers := sc.NewSimulator(time.Second, 0).Headers()
+	if got := len(headers.TagKeys); got != len(InstrumentTagKeys) || len(headers.TagTypes) != got {
+		t.Errorf("incorrect number of tag keys or types: %v %v", headers.TagKeys, headers.TagTypes)
+	}
+	if got := headers.FieldKeys[TradeMeasurementName]; len(got) != 2 || got[0] != "price" {
+		t.Errorf("incorrect trade fields: %v", got)
+	}
+	if got := headers.FieldKeys[QuoteMeasurementName]; len(got) != 4 || got[0] != "bid_price" {
+		t.Errorf("incorrect quote fields: %v", got)
+	}
+}
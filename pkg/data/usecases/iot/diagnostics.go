@@ -1,8 +1,10 @@
 package iot
 
 import (
+	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
@@ -10,6 +12,9 @@ const (
 	maxFuel          = 1.0
 	maxLoad          = 5000.0
 	loadChangeChance = 0.05
+
+	engineStateFault = "fault"
+	engineStayChance = 0.9
 )
 
 var (
@@ -22,6 +27,24 @@ var (
 	loadSaddleUD     = common.UD(0, 1)
 	statusND         = common.ND(0, 1)
 
+	labelEngineState  = []byte("engine_state")
+	labelErrorMessage = []byte("error_message")
+
+	// EngineStateChoices are the values of the engine_state field of the
+	// diagnostics measurement.
+	EngineStateChoices = []string{"off", "idle", "running", engineStateFault}
+	engineStateWeights = []float64{0.3, 0.15, 0.5, 0.05}
+
+	troubleCodeFormats = []func() string{
+		func() string { return fmt.Sprintf("P030%d: cylinder %[1]d misfire detected", 1+rand.Intn(6)) },
+		func() string { return fmt.Sprintf("P0217: engine coolant over temperature (%d C)", 110+rand.Intn(20)) },
+		func() string { return fmt.Sprintf("P0524: engine oil pressure too low (%d kPa)", 50+rand.Intn(50)) },
+		func() string {
+			return fmt.Sprintf("P020%d: injector circuit malfunction - cylinder %[1]d", 1+rand.Intn(6))
+		},
+		func() string { return "P0087: fuel rail pressure too low" },
+	}
+
 	diagnosticsFields = []common.LabeledDistributionMaker{
 		{
 			Label: labelFuelState,
@@ -69,6 +92,24 @@ func (d *customFuelDistribution) Advance() {
 // DiagnosticsMeasurement represents a diagnostics subset of measurements.
 type DiagnosticsMeasurement struct {
 	*common.SubsystemMeasurement
+
+	// engineState and errorMessage are only reported by trucks with
+	// non-numeric fields, engineState is nil otherwise.
+	engineState  *common.StateWalk
+	errorMessage string
+}
+
+// Tick advances the distributions and the engine state by the given duration.
+func (m *DiagnosticsMeasurement) Tick(d time.Duration) {
+	m.SubsystemMeasurement.Tick(d)
+	if m.engineState == nil {
+		return
+	}
+
+	m.engineState.Advance()
+	if m.engineState.Changed() {
+		m.updateErrorMessage()
+	}
 }
 
 // ToPoint serializes DiagnosticsMeasurement to generate.Point.
@@ -80,6 +121,20 @@ func (m *DiagnosticsMeasurement) ToPoint(p *data.Point) {
 	p.AppendField(diagnosticsFields[0].Label, float64(m.Distributions[0].Get()))
 	p.AppendField(diagnosticsFields[1].Label, float64(m.Distributions[1].Get()))
 	p.AppendField(diagnosticsFields[2].Label, int64(m.Distributions[2].Get()))
+
+	if m.engineState != nil {
+		p.AppendField(labelEngineState, m.engineState.Get())
+		p.AppendField(labelErrorMessage, m.errorMessage)
+	}
+}
+
+// updateErrorMessage reads a new diagnostic trouble code when the engine
+// faults. The message is empty otherwise.
+func (m *DiagnosticsMeasurement) updateErrorMessage() {
+	m.errorMessage = ""
+	if m.engineState.Get() == engineStateFault {
+		m.errorMessage = troubleCodeFormats[rand.Intn(len(troubleCodeFormats))]()
+	}
 }
 
 // NewDiagnosticsMeasurement creates a DiagnosticsMeasurement with start time.
@@ -90,3 +145,12 @@ func NewDiagnosticsMeasurement(start time.Time) *DiagnosticsMeasurement {
 		SubsystemMeasurement: sub,
 	}
 }
+
+// NewDiagnosticsMeasurementNonNumeric creates a DiagnosticsMeasurement with
+// start time which also reports the engine state and error messages.
+func NewDiagnosticsMeasurementNonNumeric(start time.Time) *DiagnosticsMeasurement {
+	m := NewDiagnosticsMeasurement(start)
+	m.engineState = common.SW(EngineStateChoices, engineStateWeights, engineStayChance)
+	m.updateErrorMessage()
+	return m
+}
@@ -3,6 +3,8 @@ package iot
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,6 +28,45 @@ func TestDiagnosticsMeasurementToPoint(t *testing.T) {
 	}
 }
 
+func TestDiagnosticsMeasurementNonNumeric(t *testing.T) {
+	rand.Seed(123)
+	now := time.Now()
+	m := NewDiagnosticsMeasurementNonNumeric(now)
+	duration := time.Second
+
+	faults := 0
+	for i := 0; i < 1000; i++ {
+		m.Tick(duration)
+
+		p := data.NewPoint()
+		m.ToPoint(p)
+		if got := len(p.FieldKeys()); got != len(diagnosticsFields)+2 {
+			t.Fatalf("incorrect number of fields: got %d", got)
+		}
+		state := p.GetFieldValue(labelEngineState).(string)
+		message := p.GetFieldValue(labelErrorMessage).(string)
+		if (state == engineStateFault) != (message != "") {
+			t.Fatalf("error message '%s' does not match engine state %s", message, state)
+		}
+		if state == engineStateFault {
+			faults++
+			if !strings.HasPrefix(message, "P0") {
+				t.Fatalf("error message is not a trouble code: %s", message)
+			}
+		}
+	}
+	if faults == 0 {
+		t.Errorf("engine never faulted")
+	}
+
+	// Trucks without non-numeric fields do not report the engine state.
+	p := data.NewPoint()
+	NewDiagnosticsMeasurement(now).ToPoint(p)
+	if got := p.GetFieldValue(labelEngineState); got != nil {
+		t.Errorf("engine state reported unexpectedly: %v", got)
+	}
+}
+
 func TestCustomFuelDistribution(t *testing.T) {
 	testCount := 5
 	fuelMin, fuelMax := 10.0, 100.0
@@ -94,11 +94,7 @@ func (s *Simulator) Next(p *data.Point) bool {
 }
 
 func (s *Simulator) Headers() *common.GeneratedDataHeaders {
-	return &common.GeneratedDataHeaders{
-		TagTypes:  s.TagTypes(),
-		TagKeys:   s.TagKeys(),
-		FieldKeys: s.Fields(),
-	}
+	return s.base.Headers()
 }
 
 // pendingOutOfOrderItems returns whether the simulator has pending
@@ -95,12 +95,26 @@ func newTruckMeasurements(start time.Time) []common.SimulatedMeasurement {
 	}
 }
 
+func newNonNumericTruckMeasurements(start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewReadingsMeasurement(start),
+		NewDiagnosticsMeasurementNonNumeric(start),
+	}
+}
+
 // NewTruck creates a new truck in a simulated iot use case
 func NewTruck(i int, start time.Time) common.Generator {
 	truck := newTruckWithMeasurementGenerator(i, start, newTruckMeasurements)
 	return &truck
 }
 
+// NewTruckNonNumeric creates a new truck in a simulated iot use case which
+// also reports its engine state and error messages as string fields
+func NewTruckNonNumeric(i int, start time.Time) common.Generator {
+	truck := newTruckWithMeasurementGenerator(i, start, newNonNumericTruckMeasurements)
+	return &truck
+}
+
 func newTruckWithMeasurementGenerator(i int, start time.Time, generator func(time.Time) []common.SimulatedMeasurement) Truck {
 	sm := generator(start)
 
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// PodsPerDeployment is the average number of replicas of a deployment.
+	PodsPerDeployment = 8
+	// PodsPerNode is the average number of pods scheduled on a node.
+	PodsPerNode = 30
+	// DefaultPodLifetime is the average time a pod lives before it is replaced
+	// by a new pod, unless a churn rate is set explicitly.
+	DefaultPodLifetime = 6 * time.Hour
+
+	deploymentNameFmt = "%s-%d"
+	nodeNameFmt       = "node-%d"
+	podNameFmt        = "%s-%08x"
+
+	// podNameHashMultiplier is an odd number, so multiplying the pod id with it
+	// is a bijection on uint32 and scrambles the id like a replica set hash.
+	podNameHashMultiplier = 2654435761
+)
+
+var (
+	// NamespaceChoices contains all the namespace values for the k8s use case.
+	NamespaceChoices = []string{
+		"default",
+		"kube-system",
+		"monitoring",
+		"ingress",
+		"payments",
+		"checkout",
+		"search",
+	}
+
+	appChoices = []string{
+		"api",
+		"web",
+		"worker",
+		"cache",
+		"auth",
+		"indexer",
+		"scheduler",
+		"gateway",
+	}
+
+	qosClassChoices = []string{
+		"Guaranteed",
+		"Burstable",
+		"BestEffort",
+	}
+)
+
+// Deployment is a set of pods running the same container.
+type Deployment struct {
+	Name      string
+	Namespace string
+	Container string
+}
+
+// Cluster describes the deployments and nodes that pods are assigned to.
+// Their number is derived from the number of pods, which keeps the label
+// cardinality of each tag realistic at any scale.
+type Cluster struct {
+	Deployments []Deployment
+	Nodes       []string
+}
+
+// DeploymentCount returns the number of deployments in a cluster of podCount pods.
+func DeploymentCount(podCount int) int {
+	return maxInt(1, podCount/PodsPerDeployment)
+}
+
+// NodeCount returns the number of nodes in a cluster of podCount pods.
+func NodeCount(podCount int) int {
+	return maxInt(1, (podCount+PodsPerNode-1)/PodsPerNode)
+}
+
+// DeploymentName returns the name of the i-th deployment.
+func DeploymentName(i int) string {
+	return fmt.Sprintf(deploymentNameFmt, appChoices[i%len(appChoices)], i)
+}
+
+// DeploymentNamespace returns the namespace of the i-th deployment.
+func DeploymentNamespace(i int) string {
+	return NamespaceChoices[i%len(NamespaceChoices)]
+}
+
+// NodeName returns the name of the i-th node.
+func NodeName(i int) string {
+	return fmt.Sprintf(nodeNameFmt, i)
+}
+
+// NewCluster creates a Cluster sized for podCount pods.
+func NewCluster(podCount uint64) *Cluster {
+	c := &Cluster{
+		Deployments: make([]Deployment, DeploymentCount(int(podCount))),
+		Nodes:       make([]string, NodeCount(int(podCount))),
+	}
+	for i := range c.Deployments {
+		c.Deployments[i] = Deployment{
+			Name:      DeploymentName(i),
+			Namespace: DeploymentNamespace(i),
+			Container: appChoices[i%len(appChoices)],
+		}
+	}
+	for i := range c.Nodes {
+		c.Nodes[i] = NodeName(i)
+	}
+	return c
+}
+
+// NewPod creates a new pod with the given id, scheduled on a random node and
+// belonging to a random deployment of the cluster. It fulfills the
+// common.BaseSimulatorConfig GeneratorConstructor signature.
+func (c *Cluster) NewPod(i int, start time.Time) common.Generator {
+	d := c.Deployments[rand.Intn(len(c.Deployments))]
+	pod := newPodWithMeasurementGenerator(i, start, d, common.RandomStringSliceChoice(c.Nodes), newPodMeasurements)
+	return &pod
+}
+
+func podName(d Deployment, i int) string {
+	return fmt.Sprintf(podNameFmt, d.Name, uint32(i+1)*podNameHashMultiplier)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/internal/utils"
+)
+
+func TestNewCluster(t *testing.T) {
+	cases := []struct {
+		pods            uint64
+		wantDeployments int
+		wantNodes       int
+	}{
+		{pods: 1, wantDeployments: 1, wantNodes: 1},
+		{pods: 30, wantDeployments: 3, wantNodes: 1},
+		{pods: 31, wantDeployments: 3, wantNodes: 2},
+		{pods: 1000, wantDeployments: 125, wantNodes: 34},
+	}
+
+	for _, c := range cases {
+		cluster := NewCluster(c.pods)
+		if got := len(cluster.Deployments); got != c.wantDeployments {
+			t.Errorf("incorrect deployment count for %d pods: got %d want %d", c.pods, got, c.wantDeployments)
+		}
+		if got := len(cluster.Nodes); got != c.wantNodes {
+			t.Errorf("incorrect node count for %d pods: got %d want %d", c.pods, got, c.wantNodes)
+		}
+		for i, d := range cluster.Deployments {
+			if d.Name != DeploymentName(i) {
+				t.Errorf("incorrect deployment name: got %s want %s", d.Name, DeploymentName(i))
+			}
+			if d.Namespace != DeploymentNamespace(i) {
+				t.Errorf("incorrect deployment namespace: got %s want %s", d.Namespace, DeploymentNamespace(i))
+			}
+			if !strings.HasPrefix(d.Name, d.Container+"-") {
+				t.Errorf("deployment %s does not run container %s", d.Name, d.Container)
+			}
+		}
+	}
+}
+
+func TestClusterNewPod(t *testing.T) {
+	cluster := NewCluster(100)
+	names := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		pod := cluster.NewPod(i, time.Now()).(*Pod)
+
+		if got := len(pod.Tags()); got != len(PodTagKeys) {
+			t.Fatalf("incorrect pod tag count: got %d want %d", got, len(PodTagKeys))
+		}
+		if got := len(pod.Measurements()); got != 2 {
+			t.Fatalf("incorrect pod measurement count: got %d want 2", got)
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range pod.Tags() {
+			tags[string(tag.Key)] = tag.Value.(string)
+		}
+		if names[tags["pod"]] {
+			t.Errorf("duplicate pod name %s", tags["pod"])
+		}
+		names[tags["pod"]] = true
+		if !strings.HasPrefix(tags["pod"], tags["deployment"]+"-") {
+			t.Errorf("pod %s does not belong to deployment %s", tags["pod"], tags["deployment"])
+		}
+		if !utils.IsIn(tags["node"], cluster.Nodes) {
+			t.Errorf("pod %s scheduled on unknown node %s", tags["pod"], tags["node"])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	kib = 1 << 10
+	mib = 1 << 20
+	gib = 1 << 30
+)
+
+var (
+	labelContainer = []byte("container") // heading for all fields
+
+	// ContainerFields are the cAdvisor-style fields of a container. Combined with
+	// the measurement name they give the usual metric names, e.g.
+	// container_cpu_usage_seconds_total.
+	ContainerFields = []common.LabeledDistributionMaker{
+		{Label: []byte("cpu_usage_seconds_total"), DistributionMaker: func() common.Distribution { return common.MWD(common.ND(0, 1), 0) }},
+		{Label: []byte("cpu_cfs_throttled_seconds_total"), DistributionMaker: func() common.Distribution {
+			return common.MWD(common.LD(common.UD(0, 1), common.UD(0, 0.5), 0.9), 0)
+		}},
+		{Label: []byte("memory_working_set_bytes"), DistributionMaker: func() common.Distribution {
+			return common.CWD(common.ND(0, 4*mib), 16*mib, 2*gib, 64*mib)
+		}},
+		{Label: []byte("memory_rss_bytes"), DistributionMaker: func() common.Distribution {
+			return common.CWD(common.ND(0, 2*mib), 8*mib, 2*gib, 32*mib)
+		}},
+		{Label: []byte("network_receive_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(common.ND(0, 256*kib), 0) }},
+		{Label: []byte("network_transmit_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(common.ND(0, 256*kib), 0) }},
+		{Label: []byte("fs_reads_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(common.ND(0, 64*kib), 0) }},
+		{Label: []byte("fs_writes_bytes_total"), DistributionMaker: func() common.Distribution { return common.MWD(common.ND(0, 128*kib), 0) }},
+	}
+)
+
+// ContainerMeasurement represents the resource usage of a container.
+type ContainerMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewContainerMeasurement creates a ContainerMeasurement with start time.
+func NewContainerMeasurement(start time.Time) *ContainerMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, ContainerFields)
+	return &ContainerMeasurement{sub}
+}
+
+// ToPoint serializes ContainerMeasurement to data.Point.
+func (m *ContainerMeasurement) ToPoint(p *data.Point) {
+	m.SubsystemMeasurement.ToPoint(p, labelContainer, ContainerFields)
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	restartChance  = 0.001
+	notReadyChance = 0.01
+)
+
+var (
+	labelKubePod = []byte("kube_pod") // heading for all fields
+
+	// KubePodFields are the kube-state-metrics-style fields of a pod. Combined
+	// with the measurement name they give the usual metric names, e.g.
+	// kube_pod_container_status_restarts_total.
+	KubePodFields = []common.LabeledDistributionMaker{
+		{Label: []byte("container_status_restarts_total"), DistributionMaker: func() common.Distribution {
+			return &eventCounterDistribution{Chance: restartChance}
+		}},
+		{Label: []byte("container_status_ready"), DistributionMaker: func() common.Distribution {
+			return &readyDistribution{value: 1}
+		}},
+		{Label: []byte("container_resource_requests_cpu_cores"), DistributionMaker: func() common.Distribution {
+			return &common.ConstantDistribution{State: cpuRequestChoices[rand.Intn(len(cpuRequestChoices))]}
+		}},
+		{Label: []byte("container_resource_limits_memory_bytes"), DistributionMaker: func() common.Distribution {
+			return &common.ConstantDistribution{State: memoryLimitChoices[rand.Intn(len(memoryLimitChoices))]}
+		}},
+	}
+
+	cpuRequestChoices  = []float64{0.1, 0.25, 0.5, 1, 2}
+	memoryLimitChoices = []float64{256 * mib, 512 * mib, 1 * gib, 2 * gib, 4 * gib}
+)
+
+// eventCounterDistribution is a counter which is incremented by one with the
+// given chance on every advance, e.g., a container restart.
+type eventCounterDistribution struct {
+	Chance float64
+	State  float64
+}
+
+// Advance increments the counter with the given chance.
+func (d *eventCounterDistribution) Advance() {
+	if rand.Float64() < d.Chance {
+		d.State++
+	}
+}
+
+// Get returns the current value of the counter.
+func (d *eventCounterDistribution) Get() float64 {
+	return d.State
+}
+
+// readyDistribution is 1 when the container is ready and 0 when it's not.
+type readyDistribution struct {
+	value float64
+}
+
+// Advance decides whether the container is ready.
+func (d *readyDistribution) Advance() {
+	d.value = 1
+	if rand.Float64() < notReadyChance {
+		d.value = 0
+	}
+}
+
+// Get returns the last readiness value.
+func (d *readyDistribution) Get() float64 {
+	return d.value
+}
+
+// KubePodMeasurement represents the state of a pod as reported by the Kubernetes API.
+type KubePodMeasurement struct {
+	*common.SubsystemMeasurement
+}
+
+// NewKubePodMeasurement creates a KubePodMeasurement with start time.
+func NewKubePodMeasurement(start time.Time) *KubePodMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, KubePodFields)
+	return &KubePodMeasurement{sub}
+}
+
+// ToPoint serializes KubePodMeasurement to data.Point.
+func (m *KubePodMeasurement) ToPoint(p *data.Point) {
+	m.SubsystemMeasurement.ToPoint(p, labelKubePod, KubePodFields)
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestContainerMeasurementToPoint(t *testing.T) {
+	now := time.Now()
+	m := NewContainerMeasurement(now)
+	m.Tick(time.Second)
+
+	p := data.NewPoint()
+	m.ToPoint(p)
+	if got := string(p.MeasurementName()); got != string(labelContainer) {
+		t.Errorf("incorrect measurement name: got %s want %s", got, labelContainer)
+	}
+	for _, ldm := range ContainerFields {
+		if got := p.GetFieldValue(ldm.Label); got == nil {
+			t.Errorf("field %s returned a nil value unexpectedly", ldm.Label)
+		}
+	}
+}
+
+func TestContainerCountersAreMonotonic(t *testing.T) {
+	m := NewContainerMeasurement(time.Now())
+	prev := m.Distributions[0].Get()
+	for i := 0; i < 1000; i++ {
+		m.Tick(time.Second)
+		if got := m.Distributions[0].Get(); got < prev {
+			t.Fatalf("cpu counter decreased: got %f after %f", got, prev)
+		}
+		prev = m.Distributions[0].Get()
+	}
+}
+
+func TestKubePodMeasurementToPoint(t *testing.T) {
+	now := time.Now()
+	m := NewKubePodMeasurement(now)
+	m.Tick(time.Second)
+
+	p := data.NewPoint()
+	m.ToPoint(p)
+	if got := string(p.MeasurementName()); got != string(labelKubePod) {
+		t.Errorf("incorrect measurement name: got %s want %s", got, labelKubePod)
+	}
+	for _, ldm := range KubePodFields {
+		if got := p.GetFieldValue(ldm.Label); got == nil {
+			t.Errorf("field %s returned a nil value unexpectedly", ldm.Label)
+		}
+	}
+}
+
+func TestEventCounterDistribution(t *testing.T) {
+	d := &eventCounterDistribution{Chance: 1}
+	for i := 1; i <= 5; i++ {
+		d.Advance()
+		if got := d.Get(); got != float64(i) {
+			t.Errorf("incorrect counter value: got %f want %d", got, i)
+		}
+	}
+
+	d = &eventCounterDistribution{Chance: 0}
+	d.Advance()
+	if got := d.Get(); got != 0 {
+		t.Errorf("counter incremented with 0 chance: got %f", got)
+	}
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+var (
+	// PodTagKeys are the tag keys common to all pods.
+	PodTagKeys = [][]byte{
+		[]byte("pod"),
+		[]byte("namespace"),
+		[]byte("deployment"),
+		[]byte("container"),
+		[]byte("node"),
+		[]byte("qos_class"),
+	}
+)
+
+// Pod models a Kubernetes pod with a single container which is being monitored.
+type Pod struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// TickAll advances all Distributions of a Pod.
+func (p *Pod) TickAll(d time.Duration) {
+	for i := range p.simulatedMeasurements {
+		p.simulatedMeasurements[i].Tick(d)
+	}
+}
+
+// Measurements returns the pod measurements.
+func (p Pod) Measurements() []common.SimulatedMeasurement {
+	return p.simulatedMeasurements
+}
+
+// Tags returns the pod tags.
+func (p Pod) Tags() []common.Tag {
+	return p.tags
+}
+
+func newPodMeasurements(start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewContainerMeasurement(start),
+		NewKubePodMeasurement(start),
+	}
+}
+
+func newPodWithMeasurementGenerator(i int, start time.Time, d Deployment, node string, generator func(time.Time) []common.SimulatedMeasurement) Pod {
+	sm := generator(start)
+
+	values := []string{
+		podName(d, i),
+		d.Namespace,
+		d.Name,
+		d.Container,
+		node,
+		qosClassChoices[rand.Intn(len(qosClassChoices))],
+	}
+	tags := make([]common.Tag, len(PodTagKeys))
+	for j := range tags {
+		tags[j] = common.Tag{Key: PodTagKeys[j], Value: values[j]}
+	}
+
+	return Pod{
+		tags:                  tags,
+		simulatedMeasurements: sm,
+	}
+}
@@ -0,0 +1,23 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a k8s Simulator. Each Generator is a pod,
+// so the scale is the number of pods running at the same time.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig common.BaseSimulatorConfig
+
+// NewSimulator produces a k8s Simulator with the given config over the
+// specified interval and points limit. With the common.DefaultChurnRate, pods
+// are replaced after DefaultPodLifetime on average.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	bsc := common.BaseSimulatorConfig(*sc)
+	if bsc.ChurnRate == common.DefaultChurnRate {
+		bsc.ChurnRate = float64(interval) / float64(DefaultPodLifetime)
+	}
+	return bsc.NewSimulator(interval, limit)
+}
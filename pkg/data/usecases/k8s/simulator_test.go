@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// distinctPods runs a simulator over 12 epochs of an hour with 10 pods and
+// returns the names of all pods that reported.
+func distinctPods(churnRate float64) map[string]bool {
+	start := time.Now()
+	interval := time.Hour
+	scale := uint64(10)
+	sc := &SimulatorConfig{
+		Start:                start,
+		End:                  start.Add(12 * interval),
+		InitGeneratorScale:   scale,
+		GeneratorScale:       scale,
+		GeneratorConstructor: NewCluster(scale).NewPod,
+		ChurnRate:            churnRate,
+	}
+
+	sim := sc.NewSimulator(interval, 0)
+	pods := make(map[string]bool)
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			pods[p.GetTagValue([]byte("pod")).(string)] = true
+		}
+		p.Reset()
+	}
+	return pods
+}
+
+func TestSimulatorDefaultChurn(t *testing.T) {
+	pods := distinctPods(common.DefaultChurnRate)
+
+	// With a 6h pod lifetime and 1h interval 1/6 of the pods is replaced
+	// every epoch, so after 11 epoch changes there should be 10 + 18 pods.
+	if got := len(pods); got != 28 {
+		t.Errorf("incorrect number of distinct pods: got %d want %d", got, 28)
+	}
+}
+
+func TestSimulatorNoChurn(t *testing.T) {
+	pods := distinctPods(0)
+	if got := len(pods); got != 10 {
+		t.Errorf("incorrect number of distinct pods: got %d want %d", got, 10)
+	}
+}
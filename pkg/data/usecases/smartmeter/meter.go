@@ -0,0 +1,216 @@
+package smartmeter
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const (
+	// MeasurementName is the name of the measurement holding the meter readings.
+	MeasurementName = "meter"
+
+	// MetersPerFeeder is the number of meters connected to a feeder.
+	MetersPerFeeder = 400
+
+	day             = 24 * time.Hour
+	nominalVoltage  = 230.0
+	voltageSpread   = 6.0
+	dailyAmplitude  = 0.6
+	solarChance     = 0.15
+	solarPeakKW     = 4.0
+	solarRiseOffset = 6 * time.Hour
+	solarDaylight   = 12 * time.Hour
+)
+
+var (
+	// TagKeys are the tag keys of every meter.
+	TagKeys = [][]byte{
+		[]byte("meter_id"),
+		[]byte("region"),
+		[]byte("feeder"),
+		[]byte("tariff"),
+		[]byte("customer_class"),
+	}
+
+	// Fields are the fields of the meter measurement.
+	Fields = [][]byte{
+		[]byte("energy_import_kwh"),
+		[]byte("energy_export_kwh"),
+		[]byte("power_kw"),
+		[]byte("voltage"),
+	}
+
+	// RegionChoices are the regions the feeders are located in.
+	RegionChoices = []string{"north", "south", "east", "west", "central", "coastal", "highlands", "metro"}
+
+	tariffChoices = []string{"flat", "time-of-use", "economy-7", "ev"}
+
+	// customerClasses are the kinds of customers by their share of the meters
+	// and their average load.
+	customerClasses = []struct {
+		name   string
+		share  float64
+		baseKW float64
+	}{
+		{"residential", 0.9, 0.5},
+		{"commercial", 0.09, 5},
+		{"industrial", 0.01, 50},
+	}
+
+	measurementName = []byte(MeasurementName)
+)
+
+// MeterName returns the meter_id tag value of the meter with the given id.
+func MeterName(id uint64) string {
+	return "meter_" + strconv.FormatUint(id, 10)
+}
+
+// FeederName returns the feeder tag value of the i-th feeder.
+func FeederName(i uint64) string {
+	return "feeder_" + strconv.FormatUint(i, 10)
+}
+
+// FeederRegion returns the region the i-th feeder is located in.
+func FeederRegion(i uint64) string {
+	return RegionChoices[i%uint64(len(RegionChoices))]
+}
+
+// meter holds the properties of a meter, which are derived from its slot, i.e.
+// the premises it is installed at, and its id instead of being kept in memory.
+type meter struct {
+	slot      uint64
+	id        uint64
+	installed time.Time
+
+	tariff    string
+	class     string
+	baseKW    float64
+	phase     time.Duration
+	solarPeak float64
+
+	// noiseSalt seeds the per reading noise of the meter.
+	noiseSalt uint64
+}
+
+func newMeter(salt, slot, id uint64, installed time.Time) meter {
+	h := mix(salt ^ mix(id))
+	m := meter{
+		slot:      slot,
+		id:        id,
+		installed: installed,
+		tariff:    tariffChoices[h%uint64(len(tariffChoices))],
+		noiseSalt: mix(h),
+	}
+
+	h = mix(h)
+	classChoice := unitFloat(h)
+	for _, c := range customerClasses {
+		m.class, m.baseKW = c.name, c.baseKW
+		if classChoice < c.share {
+			break
+		}
+		classChoice -= c.share
+	}
+
+	h = mix(h)
+	m.baseKW *= 0.5 + unitFloat(h)
+	h = mix(h)
+	// Loads peak in the evening, give or take a couple of hours.
+	m.phase = 12*time.Hour + time.Duration(unitFloat(h)*float64(4*time.Hour))
+	h = mix(h)
+	if m.class == "residential" && unitFloat(h) < solarChance {
+		m.solarPeak = solarPeakKW * (0.5 + unitFloat(mix(h)))
+	}
+	return m
+}
+
+// importKWh returns the imported energy counter at the time t. Since the
+// counter has a closed form, no state has to be kept between readings. The
+// noise of at most the minimum consumption of an interval keeps the counter
+// monotonic.
+func (m *meter) importKWh(t time.Time, interval time.Duration, epoch uint64) float64 {
+	minKWh := m.baseKW * (1 - dailyAmplitude) * interval.Hours()
+	noise := unitFloat(mix(m.noiseSalt ^ epoch))
+	return m.cumulativeLoad(t) - m.cumulativeLoad(m.installed) + noise*minKWh
+}
+
+// cumulativeLoad integrates the daily load profile
+// baseKW * (1 + dailyAmplitude * sin(2π(t - phase)/day)) up to the time t.
+func (m *meter) cumulativeLoad(t time.Time) float64 {
+	hours := float64(t.UnixNano()) / float64(time.Hour)
+	angle := 2 * math.Pi * float64(t.UnixNano()-int64(m.phase)) / float64(day)
+	return m.baseKW * (hours - dailyAmplitude*day.Hours()/(2*math.Pi)*math.Cos(angle))
+}
+
+// exportKWh returns the energy counter of the solar panels at the time t.
+func (m *meter) exportKWh(t time.Time) float64 {
+	if m.solarPeak == 0 {
+		return 0
+	}
+	return m.cumulativeSolar(t) - m.cumulativeSolar(m.installed)
+}
+
+// cumulativeSolar integrates a half sine between sunrise and sunset of every
+// day up to the time t.
+func (m *meter) cumulativeSolar(t time.Time) float64 {
+	days := t.UnixNano() / int64(day)
+	sinceRise := time.Duration(t.UnixNano()%int64(day)) - solarRiseOffset
+	daily := m.solarPeak * solarDaylight.Hours() * 2 / math.Pi
+
+	var today float64
+	switch {
+	case sinceRise <= 0:
+	case sinceRise >= solarDaylight:
+		today = daily
+	default:
+		today = m.solarPeak * solarDaylight.Hours() / math.Pi * (1 - math.Cos(math.Pi*float64(sinceRise)/float64(solarDaylight)))
+	}
+	return float64(days)*daily + today
+}
+
+// toPoint fills in the reading of the meter at the given epoch.
+func (m *meter) toPoint(p *data.Point, ts *time.Time, interval time.Duration, epoch uint64) {
+	feeder := m.slot / MetersPerFeeder
+	p.SetMeasurementName(measurementName)
+	p.SetTimestamp(ts)
+	p.AppendTag(TagKeys[0], MeterName(m.id))
+	p.AppendTag(TagKeys[1], FeederRegion(feeder))
+	p.AppendTag(TagKeys[2], FeederName(feeder))
+	p.AppendTag(TagKeys[3], m.tariff)
+	p.AppendTag(TagKeys[4], m.class)
+
+	energy := m.importKWh(*ts, interval, epoch)
+	var power float64
+	if ts.After(m.installed) {
+		previous := m.importKWh(ts.Add(-interval), interval, epoch-1)
+		power = (energy - previous) / interval.Hours()
+	}
+	voltage := nominalVoltage + voltageSpread*(2*unitFloat(mix(mix(m.noiseSalt^epoch)))-1)
+
+	p.AppendField(Fields[0], round(energy, 3))
+	p.AppendField(Fields[1], round(m.exportKWh(*ts), 3))
+	p.AppendField(Fields[2], round(power, 3))
+	p.AppendField(Fields[3], round(voltage, 1))
+}
+
+func round(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// mix is the SplitMix64 finalizer, which turns consecutive inputs into
+// uncorrelated pseudo-random outputs.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// unitFloat maps a hash to [0, 1).
+func unitFloat(h uint64) float64 {
+	return float64(h>>11) / (1 << 53)
+}
@@ -0,0 +1,69 @@
+package smartmeter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestNewMeterDeterministic(t *testing.T) {
+	start := time.Unix(0, 0)
+	a := newMeter(123, 5, 5, start)
+	b := newMeter(123, 5, 5, start)
+	if a != b {
+		t.Errorf("meters with the same salt and id differ: %v %v", a, b)
+	}
+	if c := newMeter(456, 5, 5, start); c.noiseSalt == a.noiseSalt {
+		t.Errorf("meters with different salts share their noise")
+	}
+	if a.baseKW <= 0 {
+		t.Errorf("non-positive base load: %f", a.baseKW)
+	}
+}
+
+func TestMeterCountersMonotonic(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := 15 * time.Minute
+	p := data.NewPoint()
+	for id := uint64(0); id < 200; id++ {
+		m := newMeter(42, id, id, start)
+		prevImport, prevExport := -1.0, -1.0
+		for epoch := uint64(0); epoch < 4*24*2; epoch++ {
+			ts := start.Add(time.Duration(epoch) * interval)
+			m.toPoint(p, &ts, interval, epoch)
+
+			imp := p.GetFieldValue(Fields[0]).(float64)
+			exp := p.GetFieldValue(Fields[1]).(float64)
+			if imp <= prevImport {
+				t.Fatalf("meter %d: import counter did not increase at epoch %d: %f <= %f", id, epoch, imp, prevImport)
+			}
+			if exp < prevExport {
+				t.Fatalf("meter %d: export counter decreased at epoch %d: %f < %f", id, epoch, exp, prevExport)
+			}
+			if power := p.GetFieldValue(Fields[2]).(float64); epoch > 0 && power <= 0 {
+				t.Fatalf("meter %d: non-positive power at epoch %d: %f", id, epoch, power)
+			}
+			prevImport, prevExport = imp, exp
+			p.Reset()
+		}
+	}
+}
+
+func TestMeterTags(t *testing.T) {
+	ts := time.Unix(0, 0)
+	m := newMeter(1, MetersPerFeeder+1, 7, ts)
+	p := data.NewPoint()
+	m.toPoint(p, &ts, time.Minute, 0)
+
+	want := map[string]string{
+		"meter_id": MeterName(7),
+		"region":   FeederRegion(1),
+		"feeder":   FeederName(1),
+	}
+	for k, v := range want {
+		if got := p.GetTagValue([]byte(k)); got != v {
+			t.Errorf("incorrect %s tag: got %v want %s", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package smartmeter
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a smart meter Simulator.
+// It fulfills the common.SimulatorConfig interface.
+type SimulatorConfig struct {
+	// Start is the beginning time for the Simulator
+	Start time.Time
+	// End is the ending time for the Simulator
+	End time.Time
+	// InitMeterCount is the number of meters to start with in the first reporting period
+	InitMeterCount uint64
+	// MeterCount is the total number of meters to have in the last reporting period
+	MeterCount uint64
+	// ScaleUpCurve decides how the meters scale from InitMeterCount to MeterCount (linear if nil)
+	ScaleUpCurve common.ScaleUpCurve
+	// ChurnRate is the fraction of meters swapped for new ones every reporting period
+	ChurnRate float64
+}
+
+// NewSimulator produces a smart meter Simulator with the given config over
+// the specified interval and points limit.
+func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+	scaleUp := sc.ScaleUpCurve
+	if scaleUp == nil {
+		scaleUp = common.LinearScaleUp{}
+	}
+
+	epochs := uint64(sc.End.Sub(sc.Start) / interval)
+	maxPoints := epochs * sc.MeterCount
+	if limit > 0 && limit < maxPoints {
+		// Set specified points number limit
+		maxPoints = limit
+	}
+
+	s := &Simulator{
+		maxPoints: maxPoints,
+
+		// The salt is the only randomness drawn from the seeded generator, all
+		// meter properties and readings are derived from it.
+		salt:       rand.Uint64(),
+		meterCount: sc.MeterCount,
+
+		epochs:         epochs,
+		initMeterCount: sc.InitMeterCount,
+		timestampStart: sc.Start,
+		interval:       interval,
+		timestamp:      sc.Start,
+
+		scaleUp: scaleUp,
+	}
+	if sc.ChurnRate > 0 {
+		s.churn = common.NewChurn(sc.ChurnRate, sc.MeterCount)
+		s.generations = make([]uint32, sc.MeterCount)
+		s.installEpochs = make([]uint32, sc.MeterCount)
+	}
+	s.activeMeters = scaleUp.EpochScale(0, epochs, sc.InitMeterCount, sc.MeterCount)
+
+	return s
+}
+
+// Simulator generates the readings of a large population of smart meters
+// reporting at the same slow interval, e.g. every 15 minutes. To handle
+// millions of meters, there is no Generator per meter: the properties of a
+// meter are derived from a hash of its id and its readings are closed form
+// functions of time, so a meter only exists while its reading is generated.
+// Swapping meters (churn) costs 8 bytes of state per meter.
+type Simulator struct {
+	madePoints uint64
+	maxPoints  uint64
+
+	salt       uint64
+	meterCount uint64
+	// slot is the index of the premises whose meter is read next.
+	slot         uint64
+	activeMeters uint64
+
+	// generations counts how many times the meter of a slot was swapped, and
+	// installEpochs holds when the current one was installed. Both are only
+	// allocated when meters churn.
+	generations   []uint32
+	installEpochs []uint32
+
+	epoch          uint64
+	epochs         uint64
+	initMeterCount uint64
+	timestampStart time.Time
+	timestamp      time.Time
+	interval       time.Duration
+
+	scaleUp common.ScaleUpCurve
+	churn   *common.Churn
+}
+
+// Finished tells whether we have simulated all the necessary points.
+func (s *Simulator) Finished() bool {
+	if s.madePoints >= s.maxPoints || s.epoch >= s.epochs {
+		return true
+	}
+	return s.epoch == s.epochs-1 && s.slot >= s.activeMeters
+}
+
+// Next populates the data.Point with the reading of the next meter. The
+// reporting period only changes on the following call, since the point
+// refers to the timestamp of the Simulator.
+func (s *Simulator) Next(p *data.Point) bool {
+	for s.slot >= s.activeMeters {
+		s.slot = 0
+		s.adjustMetersForEpoch()
+		if s.epoch >= s.epochs {
+			return false
+		}
+	}
+
+	m := s.meter(s.slot)
+	m.toPoint(p, &s.timestamp, s.interval, s.epoch)
+
+	s.slot++
+	s.madePoints++
+	return true
+}
+
+// meter derives the meter currently installed at the given slot.
+func (s *Simulator) meter(slot uint64) meter {
+	if s.generations == nil {
+		return newMeter(s.salt, slot, slot, s.timestampStart)
+	}
+
+	id := slot + uint64(s.generations[slot])*s.meterCount
+	installed := s.timestampStart.Add(time.Duration(s.installEpochs[slot]) * s.interval)
+	return newMeter(s.salt, slot, id, installed)
+}
+
+// adjustMetersForEpoch moves on to the next reporting period, in which
+// meters are added according to the scale-up curve and churned meters are
+// swapped for new ones.
+func (s *Simulator) adjustMetersForEpoch() {
+	s.epoch++
+	s.timestamp = s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	s.activeMeters = s.scaleUp.EpochScale(s.epoch, s.epochs, s.initMeterCount, s.meterCount)
+
+	for _, r := range s.churn.Replacements(s.activeMeters) {
+		s.generations[r.Index]++
+		s.installEpochs[r.Index] = uint32(s.epoch)
+	}
+}
+
+// Fields returns the fields of the meter measurement.
+func (s *Simulator) Fields() map[string][]string {
+	fields := make([]string, len(Fields))
+	for i, f := range Fields {
+		fields[i] = string(f)
+	}
+	return map[string][]string{MeasurementName: fields}
+}
+
+// TagKeys returns the tag keys of the meters.
+func (s *Simulator) TagKeys() []string {
+	keys := make([]string, len(TagKeys))
+	for i, k := range TagKeys {
+		keys[i] = string(k)
+	}
+	return keys
+}
+
+// TagTypes returns the type of each meter tag.
+func (s *Simulator) TagTypes() []string {
+	types := make([]string, len(TagKeys))
+	for i := range types {
+		types[i] = "string"
+	}
+	return types
+}
+
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{
+		TagTypes:  s.TagTypes(),
+		TagKeys:   s.TagKeys(),
+		FieldKeys: s.Fields(),
+	}
+}
@@ -0,0 +1,84 @@
+package smartmeter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func runSimulator(sim common.Simulator) (points int, meters map[string]int, last time.Time) {
+	meters = make(map[string]int)
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			points++
+			meters[p.GetTagValue(TagKeys[0]).(string)]++
+			last = *p.Timestamp()
+		}
+		p.Reset()
+	}
+	return points, meters, last
+}
+
+func TestSimulatorScaleUp(t *testing.T) {
+	start := time.Unix(0, 0)
+	interval := 15 * time.Minute
+	sc := &SimulatorConfig{
+		Start:          start,
+		End:            start.Add(5 * interval),
+		InitMeterCount: 2,
+		MeterCount:     10,
+	}
+
+	points, meters, last := runSimulator(sc.NewSimulator(interval, 0))
+	// Linear scale up over 5 epochs: 2, 4, 6, 8, 10 meters
+	if points != 30 {
+		t.Errorf("incorrect number of points: got %d want 30", points)
+	}
+	if len(meters) != 10 {
+		t.Errorf("incorrect number of meters: got %d want 10", len(meters))
+	}
+	if want := start.Add(4 * interval); !last.Equal(want) {
+		t.Errorf("incorrect last timestamp: got %v want %v", last, want)
+	}
+}
+
+func TestSimulatorChurn(t *testing.T) {
+	start := time.Unix(0, 0)
+	interval := 15 * time.Minute
+	sc := &SimulatorConfig{
+		Start:          start,
+		End:            start.Add(4 * interval),
+		InitMeterCount: 10,
+		MeterCount:     10,
+		ChurnRate:      0.2,
+	}
+
+	points, meters, _ := runSimulator(sc.NewSimulator(interval, 0))
+	if points != 40 {
+		t.Errorf("incorrect number of points: got %d want 40", points)
+	}
+	// 2 meters are swapped at each of the 3 epoch changes
+	if len(meters) != 16 {
+		t.Errorf("incorrect number of meters: got %d want 16", len(meters))
+	}
+	if _, ok := meters[MeterName(10)]; !ok {
+		t.Errorf("swapped meter of slot 0 missing")
+	}
+}
+
+func TestSimulatorLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	sc := &SimulatorConfig{
+		Start:          start,
+		End:            start.Add(time.Hour),
+		InitMeterCount: 10,
+		MeterCount:     10,
+	}
+
+	if points, _, _ := runSimulator(sc.NewSimulator(time.Minute, 15)); points != 15 {
+		t.Errorf("incorrect number of points: got %d want 15", points)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
+	"github.com/timescale/tsbs/pkg/data/usecases/smartmeter"
 	"math"
 )
 
@@ -22,25 +25,79 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 	if err != nil {
 		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
 	}
+	scaleUp, err := common.NewScaleUpCurve(dgc.ScaleUpCurve, dgc.ScaleUpSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the k8s use case churns by default
+	churnRate := dgc.ChurnRate
+	if churnRate == common.DefaultChurnRate && dgc.Use != common.UseCaseK8s {
+		churnRate = 0
+	}
 
 	switch dgc.Use {
 	case common.UseCaseDevops:
+		hostConstructor := devops.NewHost
+		if dgc.NonNumericFields {
+			hostConstructor = devops.NewHostNonNumeric
+		}
 		ret = &devops.DevopsSimulatorConfig{
 			Start: tsStart,
 			End:   tsEnd,
 
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
-			HostConstructor: devops.NewHost,
+			HostConstructor: hostConstructor,
+			ScaleUpCurve:    scaleUp,
+			ChurnRate:       churnRate,
 		}
 	case common.UseCaseIoT:
+		truckConstructor := iot.NewTruck
+		if dgc.NonNumericFields {
+			truckConstructor = iot.NewTruckNonNumeric
+		}
 		ret = &iot.SimulatorConfig{
 			Start: tsStart,
 			End:   tsEnd,
 
 			InitGeneratorScale:   dgc.InitialScale,
 			GeneratorScale:       dgc.Scale,
-			GeneratorConstructor: iot.NewTruck,
+			GeneratorConstructor: truckConstructor,
+			ScaleUpCurve:         scaleUp,
+			ChurnRate:            churnRate,
+		}
+	case common.UseCaseK8s:
+		ret = &k8s.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitGeneratorScale:   dgc.InitialScale,
+			GeneratorScale:       dgc.Scale,
+			GeneratorConstructor: k8s.NewCluster(dgc.Scale).NewPod,
+			ScaleUpCurve:         scaleUp,
+			ChurnRate:            churnRate,
+		}
+	case common.UseCaseFinance:
+		ret = &finance.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitInstrumentCount:   dgc.InitialScale,
+			InstrumentCount:       dgc.Scale,
+			InstrumentConstructor: finance.NewInstrument,
+			ScaleUpCurve:          scaleUp,
+			ChurnRate:             churnRate,
+		}
+	case common.UseCaseSmartMeter:
+		ret = &smartmeter.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitMeterCount: dgc.InitialScale,
+			MeterCount:     dgc.Scale,
+			ScaleUpCurve:   scaleUp,
+			ChurnRate:      churnRate,
 		}
 	case common.UseCaseCPUOnly:
 		ret = &devops.CPUOnlySimulatorConfig{
@@ -50,6 +107,8 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUOnly,
+			ScaleUpCurve:    scaleUp,
+			ChurnRate:       churnRate,
 		}
 	case common.UseCaseCPUSingle:
 		ret = &devops.CPUOnlySimulatorConfig{
@@ -59,6 +118,8 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUSingle,
+			ScaleUpCurve:    scaleUp,
+			ChurnRate:       churnRate,
 		}
 	case common.UseCaseDevopsGeneric:
 		if dgc.InitialScale == dgc.Scale {
@@ -74,6 +135,8 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 				HostCount:       dgc.Scale,
 				HostConstructor: devops.NewHostGenericMetrics,
 				MaxMetricCount:  dgc.MaxMetricCountPerHost,
+				ScaleUpCurve:    scaleUp,
+				ChurnRate:       churnRate,
 			},
 		}
 	default:
@@ -3,7 +3,10 @@ package usecases
 import (
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/finance"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/data/usecases/k8s"
+	"github.com/timescale/tsbs/pkg/data/usecases/smartmeter"
 	"reflect"
 	"testing"
 	"time"
@@ -36,6 +39,9 @@ func TestGetSimulatorConfig(t *testing.T) {
 
 	checkType(common.UseCaseDevops, &devops.DevopsSimulatorConfig{})
 	checkType(common.UseCaseIoT, &iot.SimulatorConfig{})
+	checkType(common.UseCaseK8s, &k8s.SimulatorConfig{})
+	checkType(common.UseCaseFinance, &finance.SimulatorConfig{})
+	checkType(common.UseCaseSmartMeter, &smartmeter.SimulatorConfig{})
 	checkType(common.UseCaseCPUOnly, &devops.CPUOnlySimulatorConfig{})
 	checkType(common.UseCaseCPUSingle, &devops.CPUOnlySimulatorConfig{})
 
@@ -45,3 +51,35 @@ func TestGetSimulatorConfig(t *testing.T) {
 		t.Errorf("unexpected lack of error for bogus use case")
 	}
 }
+
+func TestGetSimulatorConfigNonNumericFields(t *testing.T) {
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Scale:     1,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T00:01:00Z",
+		},
+		InitialScale: 1,
+		LogInterval:  defaultLogInterval,
+	}
+
+	for _, use := range []string{common.UseCaseDevops, common.UseCaseIoT} {
+		for _, nonNumeric := range []bool{false, true} {
+			dgc.Use = use
+			dgc.NonNumericFields = nonNumeric
+			scfg, err := GetSimulatorConfig(dgc)
+			if err != nil {
+				t.Fatalf("unexpected error with use case %s: %v", use, err)
+			}
+
+			headers := scfg.NewSimulator(dgc.LogInterval, 0).Headers()
+			got := false
+			for measurement := range headers.FieldKeys {
+				got = got || headers.HasNonNumericFields(measurement)
+			}
+			if got != nonNumeric {
+				t.Errorf("%s: non-numeric fields %v: got non-numeric fields %v", use, nonNumeric, got)
+			}
+		}
+	}
+}
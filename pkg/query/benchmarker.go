@@ -35,6 +35,11 @@ type BenchmarkRunnerConfig struct {
 	BurnIn           uint64 `mapstructure:"burn-in"`
 	PrintInterval    uint64 `mapstructure:"print-interval"`
 	PrewarmQueries   bool   `mapstructure:"prewarm-queries"`
+
+	DashboardPanels    string        `mapstructure:"dashboard-panels"`
+	DashboardRefresh   time.Duration `mapstructure:"dashboard-refresh"`
+	DashboardUsers     uint          `mapstructure:"dashboard-users"`
+	DashboardRefreshes uint64        `mapstructure:"dashboard-refreshes"`
 }
 
 // AddToFlagSet adds command line flags needed by the BenchmarkRunnerConfig to the flag set.
@@ -51,6 +56,11 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Bool("print-responses", false, "Pretty print response bodies for correctness checking (default false).")
 	fs.Int("debug", 0, "Whether to print debug messages.")
 	fs.String("file", "", "File name to read queries from")
+
+	fs.String("dashboard-panels", "", "Comma separated list of query files, one per dashboard panel. Simulates users refreshing a dashboard instead of reading queries from file (workers and max-queries are ignored)")
+	fs.Duration("dashboard-refresh", 30*time.Second, "Dashboard only: Interval at which every user refreshes the dashboard")
+	fs.Uint("dashboard-users", 1, "Dashboard only: Number of users looking at the dashboard concurrently")
+	fs.Uint64("dashboard-refreshes", 0, "Dashboard only: Number of refreshes per user, 0 = as many refreshes over all users as the smallest panel has queries")
 }
 
 // BenchmarkRunner contains the common components for running a query benchmarking
@@ -132,8 +142,39 @@ func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 
 // Run does the bulk of the benchmark execution.
 // It launches a gorountine to track stats, creates workers to process queries,
-// read in the input, execute the queries, and then does cleanup.
+// read in the input, execute the queries, and then does cleanup. If dashboard
+// panels are configured, it simulates users refreshing the dashboard instead.
 func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorCreate) {
+	// Wall clock start time
+	wallStart := time.Now()
+	if len(b.DashboardPanels) > 0 {
+		b.runDashboard(queryPool, processorCreateFn)
+	} else {
+		wallStart = b.runQueries(queryPool, processorCreateFn)
+	}
+
+	// Wall clock end time
+	wallEnd := time.Now()
+	wallTook := wallEnd.Sub(wallStart)
+	_, err := fmt.Printf("wall clock time: %fsec\n", float64(wallTook.Nanoseconds())/1e9)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// (Optional) create a memory profile:
+	if len(b.MemProfile) > 0 {
+		f, err := os.Create(b.MemProfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pprof.WriteHeapProfile(f)
+		f.Close()
+	}
+}
+
+// runQueries reads the queries from the input and processes them with the workers.
+// It returns the wall clock start time of the queries.
+func (b *BenchmarkRunner) runQueries(queryPool *sync.Pool, processorCreateFn ProcessorCreate) time.Time {
 	if b.Workers == 0 {
 		panic("must have at least one worker")
 	}
@@ -165,24 +206,7 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	// Block for workers to finish sending requests, closing the stats channel when done:
 	wg.Wait()
 	b.sp.CloseAndWait()
-
-	// Wall clock end time
-	wallEnd := time.Now()
-	wallTook := wallEnd.Sub(wallStart)
-	_, err := fmt.Printf("wall clock time: %fsec\n", float64(wallTook.Nanoseconds())/1e9)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// (Optional) create a memory profile:
-	if len(b.MemProfile) > 0 {
-		f, err := os.Create(b.MemProfile)
-		if err != nil {
-			log.Fatal(err)
-		}
-		pprof.WriteHeapProfile(f)
-		f.Close()
-	}
+	return wallStart
 }
 
 func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate.Limiter, queryPool *sync.Pool, processor Processor, workerNum int) {
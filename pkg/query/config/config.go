@@ -2,12 +2,26 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/spf13/pflag"
+	queryCommon "github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/query"
 )
 
-const ErrEmptyQueryType = "query type cannot be empty"
+const (
+	ErrEmptyQueryType = "query type cannot be empty"
+
+	errBadQueryEncodingFmt     = "invalid query encoding specified: '%v'"
+	errBadTimeRangeStrategyFmt = "invalid time range strategy specified: '%v'"
+	errTimeRangeSpanNegative   = "time range span cannot be negative"
+	errTimeRangeStepZero       = "time range span must be positive for the sliding time range strategy"
+
+	defaultTimeRangeSpan = time.Hour
+)
 
 // QueryGeneratorConfig is the GeneratorConfig that should be used with a
 // QueryGenerator. It includes all the fields from a BaseConfig, as well as
@@ -19,6 +33,12 @@ type QueryGeneratorConfig struct {
 	QueryType            string `mapstructure:"query-type"`
 	InterleavedGroupID   uint   `mapstructure:"interleaved-generation-group-id"`
 	InterleavedNumGroups uint   `mapstructure:"interleaved-generation-groups"`
+	QueryEncoding        string `mapstructure:"query-encoding"`
+
+	TimeRangeStrategy string        `mapstructure:"time-range-strategy"`
+	TimeRangeSpan     time.Duration `mapstructure:"time-range-span"`
+
+	DataConfig string `mapstructure:"data-config"`
 
 	// TODO - I think this needs some rethinking, but a simple, elegant solution escapes me right now
 	TimescaleUseJSON       bool `mapstructure:"timescale-use-json"`
@@ -27,8 +47,11 @@ type QueryGeneratorConfig struct {
 
 	ClickhouseUseTags bool `mapstructure:"clickhouse-use-tags"`
 
-	MongoUseNaive bool   `mapstructure:"mongo-use-native"`
-	DbName        string `mapstructure:"db-name"`
+	InfluxUseFlux bool `mapstructure:"influx-use-flux"`
+
+	MongoUseNaive      bool   `mapstructure:"mongo-use-native"`
+	MongoUseTimeseries bool   `mapstructure:"mongo-use-timeseries"`
+	DbName             string `mapstructure:"db-name"`
 }
 
 // Validate checks that the values of the QueryGeneratorConfig are reasonable.
@@ -42,6 +65,22 @@ func (c *QueryGeneratorConfig) Validate() error {
 		return fmt.Errorf(ErrEmptyQueryType)
 	}
 
+	if c.QueryEncoding != "" && !utils.IsIn(c.QueryEncoding, query.EncodingChoices) {
+		return fmt.Errorf(errBadQueryEncodingFmt, c.QueryEncoding)
+	}
+
+	if c.TimeRangeStrategy != "" && !utils.IsIn(c.TimeRangeStrategy, queryCommon.TimeRangeStrategyChoices) {
+		return fmt.Errorf(errBadTimeRangeStrategyFmt, c.TimeRangeStrategy)
+	}
+
+	if c.TimeRangeSpan < 0 {
+		return fmt.Errorf(errTimeRangeSpanNegative)
+	}
+
+	if c.TimeRangeStrategy == queryCommon.TimeRangeSliding && c.TimeRangeSpan == 0 {
+		return fmt.Errorf(errTimeRangeStepZero)
+	}
+
 	err = utils.ValidateGroups(c.InterleavedGroupID, c.InterleavedNumGroups)
 	return err
 }
@@ -55,9 +94,20 @@ func (c *QueryGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 		"Group (0-indexed) to perform round-robin serialization within. Use this to scale up data generation to multiple processes.")
 	fs.Uint("interleaved-generation-groups", 1,
 		"The number of round-robin serialization groups. Use this to scale up data generation to multiple processes.")
+	fs.String("query-encoding", query.EncodingGob,
+		fmt.Sprintf("Encoding of the generated queries, 'json' writes one human readable query per line. (choices: %s)", strings.Join(query.EncodingChoices, ", ")))
+
+	fs.String("time-range-strategy", queryCommon.TimeRangeUniform,
+		fmt.Sprintf("Strategy selecting the time ranges of the queries within the dataset. (choices: %s)", strings.Join(queryCommon.TimeRangeStrategyChoices, ", ")))
+	fs.Duration("time-range-span", defaultTimeRangeSpan,
+		"Part of the dataset read by the 'last' time range strategy, mean distance to the end of the dataset for 'recency' and step between queries for 'sliding'")
+	fs.String("data-config", "",
+		"Config file the loaded data was generated with (tsbs_generate_data or tsbs_load YAML). Queries are constrained to the series and time span of that data")
 
 	fs.Bool("clickhouse-use-tags", true, "ClickHouse only: Use separate tags table when querying")
+	fs.Bool("influx-use-flux", false, "InfluxDB only: Generate Flux queries for the InfluxDB v2 API, using db-name as the bucket")
 	fs.Bool("mongo-use-naive", true, "MongoDB only: Generate queries for the 'naive' data storage format for Mongo")
+	fs.Bool("mongo-use-timeseries", false, "MongoDB only: Generate queries for a native time-series collection (MongoDB 5.0+), takes precedence over mongo-use-naive")
 	fs.Bool("timescale-use-json", false, "TimescaleDB only: Use separate JSON tags table when querying")
 	fs.Bool("timescale-use-tags", true, "TimescaleDB only: Use separate tags table when querying")
 	fs.Bool("timescale-use-time-bucket", true, "TimescaleDB only: Use time bucket. Set to false to test on native PostgreSQL")
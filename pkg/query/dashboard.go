@@ -0,0 +1,177 @@
+package query
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	labelFullDashboard = "time to full dashboard"
+
+	errNoDashboardUsers       = "dashboard must have at least one user"
+	errDashboardNoRefresh     = "dashboard refresh interval must be positive"
+	errDashboardPrewarm       = "prewarm-queries is not supported for dashboards"
+	errCannotOpenPanelFileFmt = "cannot open panel query file %s: %v"
+	errEmptyPanelFmt          = "panel query file %s contains no queries"
+)
+
+// panel is a single panel of a dashboard, with the queries it can issue.
+type panel struct {
+	label   []byte
+	queries []Query
+}
+
+// dashboard simulates users looking at a dashboard: every user fires the queries
+// of all the panels at once and does so again every refresh interval, the way
+// Grafana refreshes a dashboard.
+type dashboard struct {
+	panels  []*panel
+	refresh time.Duration
+	users   uint64
+	// refreshes is the total number of dashboard refreshes over all users.
+	refreshes uint64
+}
+
+// newDashboard reads the queries of the panels from the comma separated list of
+// files, with one file per panel. Every refresh issues the next query of each
+// panel; if refreshesPerUser is 0, there are as many refreshes as the smallest
+// panel has queries.
+func newDashboard(files string, refresh time.Duration, users, refreshesPerUser uint64, queryPool *sync.Pool) (*dashboard, error) {
+	if users == 0 {
+		return nil, fmt.Errorf(errNoDashboardUsers)
+	}
+	if refresh <= 0 {
+		return nil, fmt.Errorf(errDashboardNoRefresh)
+	}
+
+	d := &dashboard{refresh: refresh, users: users, refreshes: refreshesPerUser * users}
+	for i, file := range strings.Split(files, ",") {
+		file = strings.TrimSpace(file)
+		p, err := readPanel(file, queryPool)
+		if err != nil {
+			return nil, err
+		}
+		p.label = []byte(fmt.Sprintf("dashboard panel %d (%s)", i+1, filepath.Base(file)))
+		d.panels = append(d.panels, p)
+
+		if refreshesPerUser == 0 && (i == 0 || uint64(len(p.queries)) < d.refreshes) {
+			d.refreshes = uint64(len(p.queries))
+		}
+	}
+	return d, nil
+}
+
+// readPanel decodes all the queries of a panel query file.
+func readPanel(file string, queryPool *sync.Pool) (*panel, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf(errCannotOpenPanelFileFmt, file, err)
+	}
+	defer f.Close()
+
+	noLimit := uint64(0)
+	c := make(chan Query)
+	go func() {
+		newScanner(&noLimit).setReader(bufio.NewReaderSize(f, defaultReadSize)).scan(queryPool, c)
+		close(c)
+	}()
+
+	p := &panel{}
+	for q := range c {
+		p.queries = append(p.queries, q)
+	}
+	if len(p.queries) == 0 {
+		return nil, fmt.Errorf(errEmptyPanelFmt, file)
+	}
+	return p, nil
+}
+
+// runDashboard runs the dashboard refreshes of all the users, each user with its
+// own processor per panel.
+func (b *BenchmarkRunner) runDashboard(queryPool *sync.Pool, processorCreateFn ProcessorCreate) {
+	if b.sp.getArgs().prewarmQueries {
+		panic(errDashboardPrewarm)
+	}
+	d, err := newDashboard(b.DashboardPanels, b.DashboardRefresh, uint64(b.DashboardUsers), b.DashboardRefreshes, queryPool)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	workers := uint(d.users) * uint(len(d.panels))
+	go b.sp.process(workers)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for u := uint64(0); u < d.users; u++ {
+		processors := make([]Processor, len(d.panels))
+		for i := range processors {
+			processors[i] = processorCreateFn()
+			processors[i].Init(int(u)*len(d.panels) + i)
+		}
+		wg.Add(1)
+		go b.dashboardUserHandler(&wg, d, processors, u, start)
+	}
+
+	wg.Wait()
+	b.sp.CloseAndWait()
+
+	for _, p := range d.panels {
+		for _, q := range p.queries {
+			queryPool.Put(q)
+		}
+	}
+}
+
+// dashboardUserHandler refreshes the dashboard for a single user. The users are
+// spread over the refresh interval, so they do not all refresh at the same time.
+// A refresh that takes longer than the interval delays the next one instead of
+// causing a burst of refreshes to catch up.
+func (b *BenchmarkRunner) dashboardUserHandler(wg *sync.WaitGroup, d *dashboard, processors []Processor, user uint64, start time.Time) {
+	next := start.Add(d.refresh * time.Duration(user) / time.Duration(d.users))
+	for n := user; n < d.refreshes; n += d.users {
+		time.Sleep(time.Until(next))
+		b.refreshDashboard(d, processors, n)
+
+		next = next.Add(d.refresh)
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
+	}
+	wg.Done()
+}
+
+// refreshDashboard issues the n-th query of every panel at once and reports the
+// latency of each panel as well as the time until all the panels are done.
+func (b *BenchmarkRunner) refreshDashboard(d *dashboard, processors []Processor, n uint64) {
+	latencies := make([]float64, len(d.panels))
+	var wg sync.WaitGroup
+	for i, p := range d.panels {
+		wg.Add(1)
+		go func(i int, p *panel) {
+			defer wg.Done()
+			q := p.queries[n%uint64(len(p.queries))]
+			start := time.Now()
+			stats, err := processors[i].ProcessQuery(q, false)
+			if err != nil {
+				panic(err)
+			}
+			latencies[i] = float64(time.Since(start).Nanoseconds()) / 1e6
+			b.sp.send(stats)
+			b.sp.send([]*Stat{GetPartialStat().Init(p.label, latencies[i])})
+		}(i, p)
+	}
+	wg.Wait()
+
+	full := 0.0
+	for _, l := range latencies {
+		if l > full {
+			full = l
+		}
+	}
+	b.sp.send([]*Stat{GetPartialStat().Init([]byte(labelFullDashboard), full)})
+}
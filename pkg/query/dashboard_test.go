@@ -0,0 +1,159 @@
+package query
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writePanelFile(t *testing.T, dir, name string, numQueries uint64) string {
+	var b bytes.Buffer
+	err := encodeQueries(&b, numQueries, func(_ uint64) Query {
+		return &testQuery{HumanLabel: []byte(name)}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestNewDashboard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cpu := writePanelFile(t, dir, "cpu", 5)
+	mem := writePanelFile(t, dir, "mem", 3)
+	empty := writePanelFile(t, dir, "empty", 0)
+
+	cases := []struct {
+		desc          string
+		files         string
+		users         uint64
+		refreshes     uint64
+		wantRefreshes uint64
+		wantErr       string
+	}{
+		{desc: "smallest panel", files: cpu + ", " + mem, users: 2, wantRefreshes: 3},
+		{desc: "refreshes per user", files: cpu + "," + mem, users: 2, refreshes: 4, wantRefreshes: 8},
+		{desc: "no users", files: cpu, wantErr: errNoDashboardUsers},
+		{desc: "missing file", files: filepath.Join(dir, "missing"), users: 1, wantErr: "cannot open panel query file"},
+		{desc: "empty file", files: empty, users: 1, wantErr: "contains no queries"},
+	}
+	for _, c := range cases {
+		d, err := newDashboard(c.files, time.Second, c.users, c.refreshes, &testQueryPool)
+		if c.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("%s: incorrect error: got %v want %s", c.desc, err, c.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if got := len(d.panels); got != 2 {
+			t.Errorf("%s: incorrect number of panels: got %d want 2", c.desc, got)
+		}
+		if got := string(d.panels[1].label); got != "dashboard panel 2 (mem)" {
+			t.Errorf("%s: incorrect panel label: got %s", c.desc, got)
+		}
+		if d.refreshes != c.wantRefreshes {
+			t.Errorf("%s: incorrect refreshes: got %d want %d", c.desc, d.refreshes, c.wantRefreshes)
+		}
+	}
+
+	if _, err := newDashboard(cpu, 0, 1, 0, &testQueryPool); err == nil || err.Error() != errDashboardNoRefresh {
+		t.Errorf("incorrect error for zero refresh interval: got %v", err)
+	}
+}
+
+func TestBenchmarkRunnerRunDashboard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dashboard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := []string{
+		writePanelFile(t, dir, "cpu", 4),
+		writePanelFile(t, dir, "mem", 4),
+		writePanelFile(t, dir, "disk", 4),
+	}
+
+	lock := &sync.Mutex{}
+	labels := map[string]int{}
+	wg := &sync.WaitGroup{}
+	sp := &mockStatProcessor{
+		args: &statProcessorArgs{},
+		onSend: func(stats []*Stat) {
+			lock.Lock()
+			for _, s := range stats {
+				labels[string(s.label)]++
+			}
+			lock.Unlock()
+		},
+		wg: wg,
+	}
+	b := &BenchmarkRunner{
+		BenchmarkRunnerConfig: BenchmarkRunnerConfig{
+			DashboardPanels:  strings.Join(files, ","),
+			DashboardRefresh: time.Millisecond,
+			DashboardUsers:   2,
+		},
+		sp: sp,
+	}
+
+	var processors []*testProcessor
+	wg.Add(1)
+	b.Run(&testQueryPool, func() Processor {
+		p := &testProcessor{}
+		processors = append(processors, p)
+		return p
+	})
+	wg.Wait()
+
+	if !sp.closed {
+		t.Error("stat processor wasn't closed")
+	}
+	if got := len(processors); got != 6 {
+		t.Fatalf("incorrect number of processors: got %d want 6", got)
+	}
+	for i, p := range processors {
+		if p.wNum != i {
+			t.Errorf("processor %d initialized with wrong worker number %d", i, p.wNum)
+		}
+		// 4 refreshes spread over 2 users
+		if p.count != 2 {
+			t.Errorf("processor %d processed wrong number of queries: got %d want 2", i, p.count)
+		}
+	}
+	if got := labels[labelFullDashboard]; got != 4 {
+		t.Errorf("incorrect number of full dashboard stats: got %d want 4", got)
+	}
+	if got := labels["dashboard panel 3 (disk)"]; got != 4 {
+		t.Errorf("incorrect number of panel stats: got %d want 4", got)
+	}
+}
+
+func TestBenchmarkRunnerRunDashboardPanicOnPrewarm(t *testing.T) {
+	b := &BenchmarkRunner{
+		BenchmarkRunnerConfig: BenchmarkRunnerConfig{DashboardPanels: "foo"},
+		sp:                    &mockStatProcessor{args: &statProcessorArgs{prewarmQueries: true}},
+	}
+	defer func() {
+		if r := recover(); r != errDashboardPrewarm {
+			t.Errorf("wrong panic: %v", r)
+		}
+	}()
+	b.Run(nil, nil)
+	t.Errorf("the code did not panic")
+}
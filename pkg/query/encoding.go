@@ -0,0 +1,242 @@
+package query
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Query file encoding choices
+const (
+	EncodingGob  = "gob"
+	EncodingJSON = "json"
+
+	errBadEncodingFmt        = "invalid query encoding specified: '%v'"
+	errJSONUnsupportedFmt    = "query type %T cannot be encoded as JSON: field %s holds values of arbitrary types"
+	errJSONNotStructFmt      = "query type %T is not a pointer to a struct"
+	errJSONUnknownFieldsFmt  = "unknown fields for query type %T: %s"
+	errJSONCannotDecodeFmt   = "cannot decode field %s of query type %T: %v"
+	errJSONCannotEncodeFmt   = "cannot encode field %s of query type %T: %v"
+	errJSONCannotDecodeLnFmt = "cannot decode JSON query: %v"
+)
+
+// EncodingChoices contains all the supported query file encodings.
+var EncodingChoices = []string{
+	EncodingGob,
+	EncodingJSON,
+}
+
+// Encoder writes Queries to a query file.
+type Encoder interface {
+	Encode(q Query) error
+}
+
+// Decoder reads Queries from a query file into the given Query, returning
+// io.EOF when there are no more queries.
+type Decoder interface {
+	Decode(q Query) error
+}
+
+// NewEncoder returns an Encoder writing queries to w in the given encoding. An
+// empty encoding selects gob.
+func NewEncoder(w io.Writer, encoding string) (Encoder, error) {
+	switch encoding {
+	case "", EncodingGob:
+		return &gobEncoder{gob.NewEncoder(w)}, nil
+	case EncodingJSON:
+		return &jsonEncoder{w: w}, nil
+	default:
+		return nil, fmt.Errorf(errBadEncodingFmt, encoding)
+	}
+}
+
+// NewDecoder returns a Decoder reading queries from r, detecting whether they are
+// gob or JSON encoded.
+func NewDecoder(r io.Reader) Decoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	if detectEncoding(br) == EncodingJSON {
+		return &jsonDecoder{json.NewDecoder(br)}
+	}
+	return &gobDecoder{gob.NewDecoder(br)}
+}
+
+// detectEncoding peeks at the start of a query file to find out its encoding. JSON
+// encoded files start with an object, while gob streams start with the length of
+// the first message followed by a negative type id, which is never a '"' or
+// whitespace.
+func detectEncoding(br *bufio.Reader) string {
+	start, err := br.Peek(2)
+	if err != nil || start[0] != '{' {
+		return EncodingGob
+	}
+	switch start[1] {
+	case '"', '}', ' ', '\t', '\r', '\n':
+		return EncodingJSON
+	default:
+		return EncodingGob
+	}
+}
+
+type gobEncoder struct {
+	enc *gob.Encoder
+}
+
+func (e *gobEncoder) Encode(q Query) error {
+	return e.enc.Encode(q)
+}
+
+type gobDecoder struct {
+	dec *gob.Decoder
+}
+
+func (d *gobDecoder) Decode(q Query) error {
+	return d.dec.Decode(q)
+}
+
+// jsonEncoder writes one JSON object per query and line. Like gob, only the
+// exported fields of the query are written, in the order of the struct. Byte
+// slices are written as strings, so the SQL or request of the query stays
+// readable.
+type jsonEncoder struct {
+	w io.Writer
+}
+
+func (e *jsonEncoder) Encode(q Query) error {
+	v, err := queryStruct(q)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range exportedFields(v.Type()) {
+		fv := v.FieldByIndex(f.Index)
+		var val []byte
+		if isBytes(f.Type) {
+			val, err = json.Marshal(string(fv.Bytes()))
+		} else {
+			val, err = json.Marshal(fv.Interface())
+		}
+		if err != nil {
+			return fmt.Errorf(errJSONCannotEncodeFmt, f.Name, q, err)
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.Name)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(val)
+	}
+	b.WriteString("}\n")
+
+	_, err = io.WriteString(e.w, b.String())
+	return err
+}
+
+type jsonDecoder struct {
+	dec *json.Decoder
+}
+
+// Decode sets all the exported fields of the query, resetting the ones missing
+// from the JSON object, since queries are reused from a pool.
+func (d *jsonDecoder) Decode(q Query) error {
+	v, err := queryStruct(q)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf(errJSONCannotDecodeLnFmt, err)
+	}
+
+	for _, f := range exportedFields(v.Type()) {
+		fv := v.FieldByIndex(f.Index)
+		val, ok := raw[f.Name]
+		delete(raw, f.Name)
+		if !ok {
+			fv.Set(reflect.Zero(f.Type))
+			continue
+		}
+
+		if isBytes(f.Type) {
+			var s string
+			err = json.Unmarshal(val, &s)
+			fv.SetBytes(append(fv.Bytes()[:0], s...))
+		} else {
+			fv.Set(reflect.Zero(f.Type))
+			err = json.Unmarshal(val, fv.Addr().Interface())
+		}
+		if err != nil {
+			return fmt.Errorf(errJSONCannotDecodeFmt, f.Name, q, err)
+		}
+	}
+
+	if len(raw) > 0 {
+		unknown := make([]string, 0, len(raw))
+		for k := range raw {
+			unknown = append(unknown, k)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf(errJSONUnknownFieldsFmt, q, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// queryStruct returns the struct the query points to, checking that all of its
+// fields survive a round trip through JSON.
+func queryStruct(q Query) (reflect.Value, error) {
+	v := reflect.ValueOf(q)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf(errJSONNotStructFmt, q)
+	}
+	v = v.Elem()
+	for _, f := range exportedFields(v.Type()) {
+		if hasInterface(f.Type) {
+			return reflect.Value{}, fmt.Errorf(errJSONUnsupportedFmt, q, f.Name)
+		}
+	}
+	return v, nil
+}
+
+func exportedFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.PkgPath == "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// hasInterface reports whether values of the type can hold interface values,
+// whose concrete types get lost when decoding JSON, e.g., the bson.M documents of
+// Mongo queries.
+func hasInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasInterface(t.Elem())
+	case reflect.Map:
+		return hasInterface(t.Key()) || hasInterface(t.Elem())
+	default:
+		return false
+	}
+}
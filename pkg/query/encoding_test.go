@@ -0,0 +1,175 @@
+package query
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query Query
+		empty func() Query
+	}{
+		{
+			desc: "timescaledb",
+			query: &TimescaleDB{
+				HumanLabel:       []byte("TimescaleDB max cpu"),
+				HumanDescription: []byte("TimescaleDB max cpu: 2016-01-01"),
+				Hypertable:       []byte("cpu"),
+				SqlQuery:         []byte("SELECT max(usage_user)\nFROM cpu\nWHERE hostname = 'host_1'"),
+			},
+			empty: func() Query { return &TimescaleDB{} },
+		},
+		{
+			desc: "cassandra",
+			query: &Cassandra{
+				HumanLabel:      []byte("Cassandra max cpu"),
+				MeasurementName: []byte("cpu"),
+				TimeStart:       time.Unix(1451606400, 0).UTC(),
+				TimeEnd:         time.Unix(1451610000, 0).UTC(),
+				TagSets:         [][]string{{"hostname=host_1", "hostname=host_2"}},
+				GroupByDuration: time.Minute,
+				Limit:           5,
+			},
+			empty: func() Query { return &Cassandra{} },
+		},
+	}
+
+	for _, c := range cases {
+		for _, encoding := range EncodingChoices {
+			var buf bytes.Buffer
+			enc, err := NewEncoder(&buf, encoding)
+			if err != nil {
+				t.Fatalf("%s/%s: unexpected error: %v", c.desc, encoding, err)
+			}
+			for i := 0; i < 2; i++ {
+				if err := enc.Encode(c.query); err != nil {
+					t.Fatalf("%s/%s: unexpected encode error: %v", c.desc, encoding, err)
+				}
+			}
+
+			dec := NewDecoder(&buf)
+			for i := 0; i < 2; i++ {
+				got := c.empty()
+				if err := dec.Decode(got); err != nil {
+					t.Fatalf("%s/%s: unexpected decode error: %v", c.desc, encoding, err)
+				}
+				if !reflect.DeepEqual(got, c.query) {
+					t.Errorf("%s/%s: incorrect query:\ngot\n%s\nwant\n%s", c.desc, encoding, got, c.query)
+				}
+			}
+			if err := dec.Decode(c.empty()); err != io.EOF {
+				t.Errorf("%s/%s: expected EOF, got %v", c.desc, encoding, err)
+			}
+		}
+	}
+}
+
+func TestJSONEncoderReadable(t *testing.T) {
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, EncodingJSON)
+	q := &SiriDB{
+		HumanLabel:       []byte("SiriDB max cpu"),
+		HumanDescription: []byte("desc"),
+		SqlQuery:         []byte("select max() from 'usage_user'"),
+	}
+	if err := enc.Encode(q); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"HumanLabel":"SiriDB max cpu","HumanDescription":"desc","SqlQuery":"select max() from 'usage_user'"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("incorrect JSON:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	cases := []struct {
+		desc    string
+		input   string
+		want    *SiriDB
+		wantErr string
+	}{
+		{
+			desc:  "missing fields are reset",
+			input: `{"HumanLabel":"foo", "SqlQuery":"select * from 'bar'"}`,
+			want:  &SiriDB{HumanLabel: []byte("foo"), HumanDescription: nil, SqlQuery: []byte("select * from 'bar'")},
+		},
+		{
+			desc:    "unknown field",
+			input:   `{"HumanLabel":"foo","Sql":"select"}`,
+			wantErr: "unknown fields for query type *query.SiriDB: Sql",
+		},
+		{
+			desc:    "wrong type",
+			input:   `{"HumanLabel":1}`,
+			wantErr: "cannot decode field HumanLabel",
+		},
+		{
+			desc:    "bad JSON",
+			input:   `{"HumanLabel":"foo"`,
+			wantErr: "cannot decode JSON query",
+		},
+	}
+
+	for _, c := range cases {
+		q := &SiriDB{HumanDescription: []byte("left over from the pool")}
+		err := NewDecoder(strings.NewReader(c.input)).Decode(q)
+		if c.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("%s: incorrect error: got %v want %s", c.desc, err, c.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if !reflect.DeepEqual(q, c.want) {
+			t.Errorf("%s: incorrect query: got %v want %v", c.desc, q, c.want)
+		}
+	}
+}
+
+func TestJSONEncoderUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, EncodingJSON)
+	if err := enc.Encode(NewMongo()); err == nil {
+		t.Errorf("unexpected lack of error for Mongo query")
+	}
+	if _, err := NewEncoder(&buf, "xml"); err == nil {
+		t.Errorf("unexpected lack of error for bad encoding")
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	var gobBuf bytes.Buffer
+	enc, _ := NewEncoder(&gobBuf, EncodingGob)
+	if err := enc.Encode(&TimescaleDB{HumanLabel: []byte("foo")}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		desc  string
+		input []byte
+		want  string
+	}{
+		{desc: "gob", input: gobBuf.Bytes(), want: EncodingGob},
+		{desc: "json", input: []byte(`{"HumanLabel":"foo"}`), want: EncodingJSON},
+		{desc: "json with whitespace", input: []byte("{ \"HumanLabel\":\"foo\"}"), want: EncodingJSON},
+		{desc: "empty", input: nil, want: EncodingGob},
+	}
+	for _, c := range cases {
+		dec := NewDecoder(bytes.NewReader(c.input))
+		got := EncodingGob
+		if _, ok := dec.(*jsonDecoder); ok {
+			got = EncodingJSON
+		}
+		if got != c.want {
+			t.Errorf("%s: incorrect encoding: got %s want %s", c.desc, got, c.want)
+		}
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/cassandra"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/clickhouse"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/cratedb"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/elasticsearch"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/filesink"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/influx"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/mongo"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/questdb"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/siridb"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/tdengine"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/timescaledb"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/timestream"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases/victoriametrics"
@@ -22,7 +26,10 @@ func InitQueryFactories(config *config.QueryGeneratorConfig) map[string]interfac
 		UseTags: config.ClickhouseUseTags,
 	}
 	factories[constants.FormatCrateDB] = &cratedb.BaseGenerator{}
-	factories[constants.FormatInflux] = &influx.BaseGenerator{}
+	factories[constants.FormatInflux] = &influx.BaseGenerator{
+		UseFlux: config.InfluxUseFlux,
+		Bucket:  config.DbName,
+	}
 	factories[constants.FormatTimescaleDB] = &timescaledb.BaseGenerator{
 		UseJSON:       config.TimescaleUseJSON,
 		UseTags:       config.TimescaleUseTags,
@@ -30,12 +37,19 @@ func InitQueryFactories(config *config.QueryGeneratorConfig) map[string]interfac
 	}
 	factories[constants.FormatSiriDB] = &siridb.BaseGenerator{}
 	factories[constants.FormatMongo] = &mongo.BaseGenerator{
-		UseNaive: config.MongoUseNaive,
+		UseNaive:      config.MongoUseNaive,
+		UseTimeseries: config.MongoUseTimeseries,
 	}
 	factories[constants.FormatAkumuli] = &akumuli.BaseGenerator{}
 	factories[constants.FormatVictoriaMetrics] = &victoriametrics.BaseGenerator{}
 	factories[constants.FormatTimestream] = &timestream.BaseGenerator{
 		DBName: config.DbName,
 	}
+	factories[constants.FormatQuestDB] = &questdb.BaseGenerator{}
+	factories[constants.FormatElasticsearch] = &elasticsearch.BaseGenerator{
+		Index: config.DbName,
+	}
+	factories[constants.FormatFileSink] = &filesink.BaseGenerator{}
+	factories[constants.FormatTDengine] = &tdengine.BaseGenerator{}
 	return factories
 }
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FileSink encodes a query of the file sink, a plan scanning the files of a
+// table. This will be serialized for use by the tsbs_run_queries_filesink
+// program.
+type FileSink struct {
+	HumanLabel       []byte
+	HumanDescription []byte
+
+	Plan []byte // JSON encoded filesink.Plan
+	id   uint64
+}
+
+// FileSinkPool is a sync.Pool of FileSink Query types
+var FileSinkPool = sync.Pool{
+	New: func() interface{} {
+		return &FileSink{
+			HumanLabel:       make([]byte, 0, 1024),
+			HumanDescription: make([]byte, 0, 1024),
+			Plan:             make([]byte, 0, 1024),
+		}
+	},
+}
+
+// NewFileSink returns a new FileSink Query instance
+func NewFileSink() *FileSink {
+	return FileSinkPool.Get().(*FileSink)
+}
+
+// GetID returns the ID of this Query
+func (q *FileSink) GetID() uint64 {
+	return q.id
+}
+
+// SetID sets the ID for this Query
+func (q *FileSink) SetID(n uint64) {
+	q.id = n
+}
+
+// String produces a debug-ready description of a Query.
+func (q *FileSink) String() string {
+	return fmt.Sprintf("HumanLabel: %s, HumanDescription: %s, Plan: %s", q.HumanLabel, q.HumanDescription, q.Plan)
+}
+
+// HumanLabelName returns the human readable name of this Query
+func (q *FileSink) HumanLabelName() []byte {
+	return q.HumanLabel
+}
+
+// HumanDescriptionName returns the human readable description of this Query
+func (q *FileSink) HumanDescriptionName() []byte {
+	return q.HumanDescription
+}
+
+// Release resets and returns this Query to its pool
+func (q *FileSink) Release() {
+	q.HumanLabel = q.HumanLabel[:0]
+	q.HumanDescription = q.HumanDescription[:0]
+	q.id = 0
+	q.Plan = q.Plan[:0]
+
+	FileSinkPool.Put(q)
+}
@@ -0,0 +1,38 @@
+package query
+
+import "testing"
+
+func TestNewFileSink(t *testing.T) {
+	check := func(q *FileSink) {
+		testValidNewQuery(t, q)
+		if got := len(q.Plan); got != 0 {
+			t.Errorf("new query has non-0 plan: got %d", got)
+		}
+	}
+	q := NewFileSink()
+	check(q)
+	q.HumanLabel = []byte("foo")
+	q.HumanDescription = []byte("bar")
+	q.Plan = []byte(`{"table":"cpu"}`)
+	q.SetID(1)
+	if got := string(q.HumanLabelName()); got != "foo" {
+		t.Errorf("incorrect label name: got %s", got)
+	}
+	if got := string(q.HumanDescriptionName()); got != "bar" {
+		t.Errorf("incorrect desc: got %s", got)
+	}
+	q.Release()
+
+	// Since we use a pool, check that the next one is reset
+	q = NewFileSink()
+	check(q)
+	q.Release()
+}
+
+func TestFileSinkSetAndGetID(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		q := NewFileSink()
+		testSetAndGetID(t, q)
+		q.Release()
+	}
+}
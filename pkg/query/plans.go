@@ -0,0 +1,138 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	errCannotCreatePlanFileFmt = "cannot create query plan file %s: %v"
+	errCannotWritePlanFmt      = "cannot write query plan: %v"
+)
+
+// Plan is the query plan of one query, as written by a PlanRecorder. Plans of
+// the same label captured against different database versions can be diffed
+// to tie latency regressions to plan changes.
+type Plan struct {
+	// Label is the human label of the query, e.g. "TimescaleDB max of all CPU metrics ..."
+	Label string `json:"label"`
+	// Description is the human description of the query, including its time range
+	Description string `json:"description"`
+	// Query is the query as sent by the benchmark
+	Query string `json:"query"`
+	// Explain is the statement that produced the plan, e.g. "EXPLAIN ANALYZE ..."
+	Explain string `json:"explain"`
+	// ServerVersion is the version of the database that planned the query
+	ServerVersion string `json:"server_version,omitempty"`
+	// Plan is the plan in the structure returned by the database: JSON if the
+	// database returns JSON, the lines of the plan as text otherwise
+	Plan interface{} `json:"plan,omitempty"`
+	// Error is the error returned by the database instead of a plan
+	Error string `json:"error,omitempty"`
+}
+
+// NewPlan returns the Plan of query q, which was sent to the database as sql,
+// to be captured with the explain statement.
+func NewPlan(q Query, sql, explain string) *Plan {
+	return &Plan{
+		Label:       string(q.HumanLabelName()),
+		Description: string(q.HumanDescriptionName()),
+		Query:       sql,
+		Explain:     explain,
+	}
+}
+
+// SetError records the error that prevented capturing the plan, if any.
+func (p *Plan) SetError(err error) {
+	if err != nil {
+		p.Error = err.Error()
+	}
+}
+
+// PlanRecorder captures the plan of the first query of every distinct label
+// into a file, with one JSON encoded Plan per line. The workers only claim the
+// queries whose plan to capture; the plans are captured once the benchmark is
+// done, so they do not take time from the measured queries. It is safe to use
+// from all the workers at once.
+type PlanRecorder struct {
+	mu      sync.Mutex
+	seen    map[string]bool
+	pending []*Plan
+	w       io.Writer
+	enc     *json.Encoder
+}
+
+// NewPlanRecorder returns a PlanRecorder writing to the file at path, which is
+// truncated if it already exists.
+func NewPlanRecorder(path string) (*PlanRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf(errCannotCreatePlanFileFmt, path, err)
+	}
+	return newPlanRecorder(f), nil
+}
+
+func newPlanRecorder(w io.Writer) *PlanRecorder {
+	return &PlanRecorder{
+		seen: make(map[string]bool),
+		w:    w,
+		enc:  json.NewEncoder(w),
+	}
+}
+
+// Claim marks the plan of query q, which was sent to the database as sql, to be
+// captured with the explain statement, if it is the first query of its label.
+// A nil PlanRecorder captures no plans.
+func (r *PlanRecorder) Claim(q Query, sql, explain string) {
+	if r == nil {
+		return
+	}
+	label := string(q.HumanLabelName())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen[label] {
+		return
+	}
+	r.seen[label] = true
+	r.pending = append(r.pending, NewPlan(q, sql, explain))
+}
+
+// Capture fills in the claimed plans, in the order they were claimed, with the
+// explain function and records them. It is meant to be called after the
+// benchmark is done.
+func (r *PlanRecorder) Capture(explain func(p *Plan) error) error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	for _, p := range pending {
+		p.SetError(explain(p))
+		if err := r.Record(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record writes the plan of a query. Plans are written even if capturing them
+// failed, with the Error instead of the plan, so every label has an entry.
+func (r *PlanRecorder) Record(p *Plan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(p); err != nil {
+		return fmt.Errorf(errCannotWritePlanFmt, err)
+	}
+	return nil
+}
+
+// Close closes the file the plans are written to.
+func (r *PlanRecorder) Close() error {
+	if c, ok := r.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestPlanRecorderClaim(t *testing.T) {
+	var r *PlanRecorder
+	r.Claim(&testQuery{HumanLabel: []byte("cpu")}, "SELECT 1", "EXPLAIN SELECT 1")
+
+	r = newPlanRecorder(&bytes.Buffer{})
+	labels := []string{"cpu", "mem", "cpu", "cpu", "mem", "disk"}
+	var wg sync.WaitGroup
+	for _, label := range labels {
+		wg.Add(1)
+		go func(label string) {
+			defer wg.Done()
+			r.Claim(&testQuery{HumanLabel: []byte(label)}, "SELECT 1", "EXPLAIN SELECT 1")
+		}(label)
+	}
+	wg.Wait()
+
+	claimed := map[string]int{}
+	for _, p := range r.pending {
+		claimed[p.Label]++
+	}
+	if len(claimed) != 3 {
+		t.Errorf("incorrect number of claimed labels: got %d want 3", len(claimed))
+	}
+	for label, n := range claimed {
+		if n != 1 {
+			t.Errorf("label %s claimed %d times", label, n)
+		}
+	}
+}
+
+func TestPlanRecorderCapture(t *testing.T) {
+	var buf bytes.Buffer
+	r := newPlanRecorder(&buf)
+
+	r.Claim(&testQuery{HumanLabel: []byte("cpu")}, "SELECT 1", "EXPLAIN SELECT 1")
+	r.Claim(&testQuery{HumanLabel: []byte("mem")}, "SELECT x", "EXPLAIN SELECT x")
+	r.Claim(&testQuery{HumanLabel: []byte("cpu")}, "SELECT 2", "EXPLAIN SELECT 2")
+	if buf.Len() != 0 {
+		t.Fatalf("plans written before capture: %s", buf.String())
+	}
+
+	err := r.Capture(func(p *Plan) error {
+		if p.Query == "SELECT x" {
+			return errors.New(`column "x" does not exist`)
+		}
+		p.Plan = []string{"Expression"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"label":"cpu","description":"","query":"SELECT 1","explain":"EXPLAIN SELECT 1","plan":["Expression"]}
+{"label":"mem","description":"","query":"SELECT x","explain":"EXPLAIN SELECT x","error":"column \"x\" does not exist"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("incorrect plans:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	// captured plans are not captured again
+	buf.Reset()
+	if err := r.Capture(func(p *Plan) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("plans captured twice: %s", buf.String())
+	}
+}
+
+func TestPlanRecorderRecord(t *testing.T) {
+	var buf bytes.Buffer
+	r := newPlanRecorder(&buf)
+
+	q := &testQuery{HumanLabel: []byte("cpu"), HumanDescription: []byte("cpu: 2016-01-01T00:00:00Z")}
+	p := NewPlan(q, "SELECT 1", "EXPLAIN ANALYZE SELECT 1")
+	p.ServerVersion = "13.1"
+	p.Plan = json.RawMessage(`[{"Plan": {"Node Type": "Result"}}]`)
+	p.SetError(nil)
+	if err := r.Record(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p = NewPlan(q, "SELECT x", "EXPLAIN ANALYZE SELECT x")
+	p.SetError(errors.New(`column "x" does not exist`))
+	if err := r.Record(p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `{"label":"cpu","description":"cpu: 2016-01-01T00:00:00Z","query":"SELECT 1","explain":"EXPLAIN ANALYZE SELECT 1","server_version":"13.1","plan":[{"Plan":{"Node Type":"Result"}}]}
+{"label":"cpu","description":"cpu: 2016-01-01T00:00:00Z","query":"SELECT x","explain":"EXPLAIN ANALYZE SELECT x","error":"column \"x\" does not exist"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("incorrect plans:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
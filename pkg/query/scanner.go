@@ -1,14 +1,13 @@
 package query
 
 import (
-	"encoding/gob"
 	"io"
 	"log"
 	"sync"
 )
 
 // scanner is used to read in Queries from a Reader where they are
-// Go-encoded and then distribute them to workers
+// gob or JSON encoded and then distribute them to workers
 type scanner struct {
 	r     io.Reader
 	limit *uint64
@@ -27,7 +26,7 @@ func (s *scanner) setReader(r io.Reader) *scanner {
 
 // scan reads encoded Queries and places them into a channel
 func (s *scanner) scan(pool *sync.Pool, c chan Query) {
-	decoder := gob.NewDecoder(s.r)
+	decoder := NewDecoder(s.r)
 
 	n := uint64(0)
 	for {
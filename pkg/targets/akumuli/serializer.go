@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
 	"io"
 )
 
@@ -34,8 +35,10 @@ func NewAkumuliSerializer() *Serializer {
 // Serialize writes Point data to the given writer, conforming to the
 // AKUMULI RESP protocol.  Serializer adds extra data to guide data loader.
 // This function writes output that contains binary and text data in RESP format.
+// Akumuli only stores numbers, so non-numeric fields are left out.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	deferPoint := false
+	fieldKeys, fieldValues := numericFields(p)
 
 	buf := make([]byte, 0, 1024)
 	// Add cue
@@ -44,7 +47,6 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	buf = append(buf, "+"...)
 
 	// Series name
-	fieldKeys := p.FieldKeys()
 	measurementName := p.MeasurementName()
 	for i := 0; i < len(fieldKeys); i++ {
 		buf = append(buf, measurementName...)
@@ -122,7 +124,6 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	buf = append(buf, '\n')
 
 	// Values
-	fieldValues := p.FieldValues()
 	buf = append(buf, fmt.Sprintf("*%d\n", len(fieldValues))...)
 	for i := 0; i < len(fieldValues); i++ {
 		v := fieldValues[i]
@@ -146,3 +147,30 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	_, err = w.Write(buf)
 	return err
 }
+
+// numericFields returns the field keys and values of the point without the
+// non-numeric fields.
+func numericFields(p *data.Point) ([][]byte, []interface{}) {
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	for i := range fieldValues {
+		if fieldValues[i] != nil && !serialize.IsNumeric(fieldValues[i]) {
+			return filterNumericFields(fieldKeys, fieldValues)
+		}
+	}
+	return fieldKeys, fieldValues
+}
+
+func filterNumericFields(fieldKeys [][]byte, fieldValues []interface{}) ([][]byte, []interface{}) {
+	keys := make([][]byte, 0, len(fieldKeys))
+	values := make([]interface{}, 0, len(fieldValues))
+	for i, v := range fieldValues {
+		if v != nil && !serialize.IsNumeric(v) {
+			serialize.WarnSkippedField(constants.FormatAkumuli, fieldKeys[i], v)
+			continue
+		}
+		keys = append(keys, fieldKeys[i])
+		values = append(values, v)
+	}
+	return keys, values
+}
@@ -78,3 +78,21 @@ func TestAkumuliSerializerSerialize(t *testing.T) {
 		}
 	}
 }
+
+func TestAkumuliSerializerSkipsNonNumericFields(t *testing.T) {
+	serializer := NewAkumuliSerializer()
+	buf := new(bytes.Buffer)
+	serializer.Serialize(serialize.TestPointNonNumeric(), buf)
+	serializer.Serialize(serialize.TestPointNonNumeric(), buf)
+
+	got := buf.String()
+	if want := "+cpu.usage_guest_nice  hostname=host_0"; !strings.Contains(got, want) {
+		t.Errorf("series name incorrect: %q does not contain %q", got, want)
+	}
+	if want := "*1\n+38.24311829"; strings.Count(got, want) != 2 {
+		t.Errorf("values incorrect: %q does not contain %q twice", got, want)
+	}
+	if strings.Contains(got, "last_error") || strings.Contains(got, "alerting") {
+		t.Errorf("non-numeric fields not skipped: %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package cassandra
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
@@ -18,6 +19,9 @@ type Serializer struct{}
 //
 // Which the loader will decode into a statement that looks like this:
 // INSERT INTO series_double(series_id,timestamp_ns,value) VALUES('cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,rack=67,os=Ubuntu16.10,arch=x86,team=NYC,service=7,service_version=0,service_environment=production#usage_guest_nice#2016-01-01', 1451606400000000000, 38.2431182911542820)
+//
+// String values go to the series_blob table as hex blob literals (e.g. 0x6f6b),
+// so they never clash with the commas of the format.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	seriesIDPrefix := make([]byte, 0, 256)
 	seriesIDPrefix = append(seriesIDPrefix, p.MeasurementName()...)
@@ -87,8 +91,22 @@ func generateFieldBuf(tsNanos int64, tsBucket string, seriesIDPrefix, key []byte
 	buf = append(buf, []byte(tsBucket)...)
 	buf = append(buf, comma...)
 	buf = append(buf, []byte(fmt.Sprintf("%d,", tsNanos))...)
-	buf = serialize.FastFormatAppend(value, buf)
+	switch v := value.(type) {
+	case string:
+		buf = appendBlobLiteral(buf, []byte(v))
+	case []byte:
+		buf = appendBlobLiteral(buf, v)
+	default:
+		buf = serialize.FastFormatAppend(value, buf)
+	}
 
 	buf = append(buf, []byte("\n")...)
 	return buf
 }
+
+func appendBlobLiteral(buf, v []byte) []byte {
+	buf = append(buf, "0x"...)
+	encoded := make([]byte, hex.EncodedLen(len(v)))
+	hex.Encode(encoded, v)
+	return append(buf, encoded...)
+}
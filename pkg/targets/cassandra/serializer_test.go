@@ -17,6 +17,13 @@ func TestCassandraSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointInt(),
 			Output:     "series_bigint,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,usage_guest,2016-01-01,1451606400000000000,38\n",
 		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output: "series_blob,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,last_error,2016-01-01,1451606400000000000,0x6469736b2022433a5c22206973203935252066756c6c2c20636c65616e7570206661696c6564\n" +
+				"series_boolean,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,alerting,2016-01-01,1451606400000000000,true\n" +
+				"series_double,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,usage_guest_nice,2016-01-01,1451606400000000000,38.24311829\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
@@ -31,6 +31,10 @@ type insertData struct {
 
 var tableCols map[string][]string
 
+// tableColTypes holds the type of each field column in tableCols, e.g.
+// float64, string or bool.
+var tableColTypes = make(map[string][]string)
+
 var tagColumnTypes []string
 
 // allows for testing
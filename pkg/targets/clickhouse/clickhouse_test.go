@@ -141,6 +141,7 @@ func TestHeaders(t *testing.T) {
 		input        string
 		wantTags     []string
 		wantCols     map[string][]string
+		wantColTypes map[string][]string
 		wantTypes    []string
 		shouldFatal  bool
 		wantBuffered int
@@ -177,6 +178,15 @@ func TestHeaders(t *testing.T) {
 			wantCols:     map[string][]string{"cols": {"col1", "col2"}, "cols2": {"col21", "col22"}},
 			wantBuffered: len([]byte("row1\nrow2\n")),
 		},
+		{
+			desc:         "non-numeric fields",
+			input:        "tags,tag1 string\nhealth,status string,alerting bool,uptime\n\n",
+			wantTags:     []string{"tag1"},
+			wantTypes:    []string{"string"},
+			wantCols:     map[string][]string{"health": {"status", "alerting", "uptime"}},
+			wantColTypes: map[string][]string{"health": {"string", "bool", "float64"}},
+			wantBuffered: 0,
+		},
 		{
 			desc:        "too few lines",
 			input:       "tags\ncols\n",
@@ -220,6 +230,32 @@ func TestHeaders(t *testing.T) {
 					t.Errorf("%s: cols row incorrect: got\n%v\nwant\n%v\n", c.desc, got, want)
 				}
 			}
+			for key, want := range c.wantColTypes {
+				if got := headers.FieldTypes[key]; !strArrEq(got, want) {
+					t.Errorf("%s: col types incorrect: got\n%v\nwant\n%v\n", c.desc, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestParseFieldValue(t *testing.T) {
+	fieldTypes := []string{"string", "bool", "float64"}
+	cases := []struct {
+		i     int
+		value string
+		want  interface{}
+	}{
+		{i: 0, value: "disk full", want: "disk full"},
+		{i: 1, value: "true", want: uint8(1)},
+		{i: 1, value: "false", want: uint8(0)},
+		{i: 2, value: "42.5", want: 42.5},
+		{i: 3, value: "1", want: 1.0},
+	}
+
+	for _, c := range cases {
+		if got := parseFieldValue(fieldTypes, c.i, c.value); got != c.want {
+			t.Errorf("incorrect value for '%s': got %v (%T) want %v (%T)", c.value, got, got, c.want, c.want)
 		}
 	}
 }
@@ -91,7 +91,7 @@ func (d *dbCreator) CreateDB(dbName string) error {
 		//tableName: cpu
 		// fieldColumns content:
 		// usage_user,usage_system,usage_idle,usage_nice,usage_iowait,usage_irq,usage_softirq,usage_steal,usage_guest,usage_guest_nice
-		createMetricsTable(d.config, db, tableName, fieldColumns)
+		createMetricsTable(d.config, db, tableName, fieldColumns, d.headers.FieldTypes[tableName])
 	}
 
 	return nil
@@ -110,29 +110,25 @@ func createTagsTable(conf *ClickhouseConfig, db *sqlx.DB, tagNames, tagTypes []s
 }
 
 // createMetricsTable builds CREATE TABLE SQL statement and runs it
-func createMetricsTable(conf *ClickhouseConfig, db *sqlx.DB, tableName string, fieldColumns []string) {
+func createMetricsTable(conf *ClickhouseConfig, db *sqlx.DB, tableName string, fieldColumns, fieldTypes []string) {
 	tableCols[tableName] = fieldColumns
+	tableColTypes[tableName] = fieldTypes
 
-	// We'll have some service columns in table to be created and columnNames contains all column names to be created
-	var columnNames []string
+	// columnsWithType - column specifications with type. Ex.: "cpu_usage Float64"
+	var columnsWithType []string
 
 	if conf.InTableTag {
 		// First column in the table - service column - partitioning field
 		partitioningColumn := tableCols["tags"][0] // would be 'hostname'
-		columnNames = append(columnNames, partitioningColumn)
+		columnsWithType = append(columnsWithType, fmt.Sprintf("%s Nullable(Float64)", partitioningColumn))
 	}
 
-	// Add all column names from fieldColumns into columnNames
-	columnNames = append(columnNames, fieldColumns...)
-
-	// columnsWithType - column specifications with type. Ex.: "cpu_usage Float64"
-	var columnsWithType []string
-	for _, column := range columnNames {
+	for i, column := range fieldColumns {
 		if len(column) == 0 {
 			// Skip nameless columns
 			continue
 		}
-		columnsWithType = append(columnsWithType, fmt.Sprintf("%s Nullable(Float64)", column))
+		columnsWithType = append(columnsWithType, fmt.Sprintf("%s %s", column, fieldTypeToClickHouseType(fieldTypes, i)))
 	}
 
 	sql := fmt.Sprintf(`
@@ -185,6 +181,23 @@ func generateTagsTableQuery(tagNames, tagTypes []string) string {
 		index)
 }
 
+// fieldTypeToClickHouseType returns the type of the i-th field column, which
+// is Nullable(Float64) unless the field is not numeric. Bools are stored as
+// UInt8, like ClickHouse does itself.
+func fieldTypeToClickHouseType(fieldTypes []string, i int) string {
+	if i >= len(fieldTypes) {
+		return "Nullable(Float64)"
+	}
+	switch fieldTypes[i] {
+	case common.FieldTypeString:
+		return "Nullable(String)"
+	case common.FieldTypeBool:
+		return "Nullable(UInt8)"
+	default:
+		return "Nullable(Float64)"
+	}
+}
+
 func serializedTypeToClickHouseType(serializedType string) string {
 	switch serializedType {
 	case "string":
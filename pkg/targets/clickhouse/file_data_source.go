@@ -121,12 +121,14 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 	}
 	tagNames, tagTypes := extractTagNamesAndTypes(parts[1:])
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	// cols content are lines (metrics descriptions) as:
 	// cpu,usage_user,usage_system,usage_idle,usage_nice,usage_iowait,usage_irq,usage_softirq,usage_steal,usage_guest,usage_guest_nice
 	// disk,total,free,used,used_percent,inodes_total,inodes_free,inodes_used
 	// nginx,accepts,active,handled,reading,requests,waiting,writing
 	// generalised description:
 	// tableName,fieldName1,...,fieldNameX
+	// where non-numeric fields are followed by their type, e.g. "status string"
 	for _, colsForMeasure := range cols {
 		tableSpec := strings.Split(colsForMeasure, ",")
 		// tableSpec contain
@@ -136,12 +138,13 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 
 		// Ex.: cpu OR disk OR nginx
 		tableName := tableSpec[0]
-		fieldKeys[tableName] = tableSpec[1:]
+		fieldKeys[tableName], fieldTypes[tableName] = common.ParseFieldColumns(tableSpec[1:])
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagKeys:   tagNames,
-		TagTypes:  tagTypes,
-		FieldKeys: fieldKeys,
+		TagKeys:    tagNames,
+		TagTypes:   tagTypes,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
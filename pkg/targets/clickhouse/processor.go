@@ -3,7 +3,9 @@ package clickhouse
 import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/timescaledb"
 	"strconv"
 	"strings"
 	"sync"
@@ -86,6 +88,7 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 	commonTagsLen := len(tableCols["tags"])
 
 	colLen := len(tableCols[tableName]) + 2
+	fieldTypes := tableColTypes[tableName]
 	if p.conf.InTableTag {
 		colLen++
 	}
@@ -126,7 +129,7 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 
 		// fields line ex.:
 		// 1451606400000000000,58,2,24,61,22,63,6,44,80,38
-		metrics := strings.Split(row.fields, ",")
+		metrics := timescaledb.SplitFields(row.fields)
 
 		// Count number of metrics processed
 		ret += uint64(len(metrics) - 1) // 1-st field is timestamp, do not count it
@@ -163,16 +166,12 @@ func (p *processor) processCSI(tableName string, rows []*insertData) uint64 {
 		if p.conf.InTableTag {
 			r = append(r, tags[0]) // tags[0] = hostname
 		}
-		for _, v := range metrics[1:] {
+		for i, v := range metrics[1:] {
 			if v == "" {
 				r = append(r, nil)
 				continue
 			}
-			f64, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				panic(err)
-			}
-			r = append(r, f64)
+			r = append(r, parseFieldValue(fieldTypes, i, v))
 		}
 
 		dataRows = append(dataRows, r)
@@ -357,6 +356,35 @@ func insertTags(conf *ClickhouseConfig, db *sqlx.DB, startID int, rows [][]strin
 	return nil
 }
 
+// parseFieldValue converts the value of the i-th field column according to its
+// type. Fields are float64 unless the header says otherwise.
+func parseFieldValue(fieldTypes []string, i int, value string) interface{} {
+	fieldType := ""
+	if i < len(fieldTypes) {
+		fieldType = fieldTypes[i]
+	}
+
+	switch fieldType {
+	case common.FieldTypeString:
+		return value
+	case common.FieldTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			panic(err)
+		}
+		if b {
+			return uint8(1)
+		}
+		return uint8(0)
+	default:
+		f64, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			panic(err)
+		}
+		return f64
+	}
+}
+
 func convertBasedOnType(serializedType, value string) interface{} {
 	if value == "" {
 		return nil
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+const tcpDialTimeout = 10 * time.Second
+
+// TCPWriter writes batches of lines to a plaintext TCP endpoint, such as
+// the Graphite or OpenTSDB line receivers or the ILP endpoint of QuestDB,
+// over a single connection.
+// These endpoints do not acknowledge writes and drop the connection on
+// errors, so a failed write is retried on a new connection after a
+// backoff that doubles with every retry. Lines of a batch may have been
+// received before the connection broke, so a retried batch can produce
+// duplicate points.
+type TCPWriter struct {
+	Address string
+	Retries int
+	Backoff time.Duration
+
+	conn net.Conn
+}
+
+// Connect opens the connection unless it is already open.
+func (w *TCPWriter) Connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	conn, err := net.DialTimeout("tcp", w.Address, tcpDialTimeout)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// Write sends the lines, reconnecting up to Retries times when the
+// connection fails.
+func (w *TCPWriter) Write(lines []byte) error {
+	backoff := w.Backoff
+	for attempt := 0; ; attempt++ {
+		err := w.Connect()
+		if err == nil {
+			_, err = w.conn.Write(lines)
+			if err == nil {
+				return nil
+			}
+			w.conn.Close()
+			w.conn = nil
+		}
+		if attempt >= w.Retries {
+			return fmt.Errorf("could not write to %s after %d retries: %v", w.Address, attempt, err)
+		}
+		log.Printf("write to %s failed: %v. Reconnecting in %v", w.Address, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Close closes the connection, if open.
+func (w *TCPWriter) Close() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+}
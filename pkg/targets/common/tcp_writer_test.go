@@ -0,0 +1,99 @@
+package common
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineServer is a TCP test server collecting the received lines.
+type lineServer struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	lines []string
+	conns int
+	wg    sync.WaitGroup
+}
+
+func newLineServer(t *testing.T) *lineServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	s := &lineServer{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					s.mu.Lock()
+					s.lines = append(s.lines, scanner.Text())
+					s.mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *lineServer) received() ([]string, int) {
+	s.ln.Close()
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lines, s.conns
+}
+
+func TestTCPWriterWrite(t *testing.T) {
+	s := newLineServer(t)
+	w := &TCPWriter{Address: s.ln.Addr().String(), Retries: 1, Backoff: time.Millisecond}
+	if err := w.Write([]byte("a 1 1\nb 2 2\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a broken connection is replaced by a new one
+	w.conn.Close()
+	if err := w.Write([]byte("c 3 3\n")); err != nil {
+		t.Fatalf("unexpected error after reconnect: %v", err)
+	}
+	w.Close()
+
+	lines, conns := s.received()
+	want := []string{"a 1 1", "b 2 2", "c 3 3"}
+	if len(lines) != len(want) {
+		t.Fatalf("incorrect lines received: got %v want %v", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("incorrect line %d: got %s want %s", i, lines[i], want[i])
+		}
+	}
+	if conns != 2 {
+		t.Errorf("incorrect number of connections: got %d want 2", conns)
+	}
+}
+
+func TestTCPWriterGivesUp(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	w := &TCPWriter{Address: addr, Retries: 2, Backoff: time.Millisecond}
+	if err := w.Write([]byte("a 1 1\n")); err == nil {
+		t.Errorf("expected an error writing to a closed port")
+	}
+}
@@ -13,6 +13,15 @@ const (
 	FormatPrometheus      = "prometheus"
 	FormatVictoriaMetrics = "victoriametrics"
 	FormatTimestream      = "timestream"
+	FormatQuestDB         = "questdb"
+	FormatGraphite        = "graphite"
+	FormatOpenTSDB        = "opentsdb"
+	FormatElasticsearch   = "elasticsearch"
+	FormatOTLP            = "otlp"
+	FormatKafka           = "kafka"
+	FormatFileSink        = "filesink"
+	FormatTDengine        = "tdengine"
+	FormatMQTT            = "mqtt"
 )
 
 func SupportedFormats() []string {
@@ -28,5 +37,14 @@ func SupportedFormats() []string {
 		FormatPrometheus,
 		FormatVictoriaMetrics,
 		FormatTimestream,
+		FormatQuestDB,
+		FormatGraphite,
+		FormatOpenTSDB,
+		FormatElasticsearch,
+		FormatOTLP,
+		FormatKafka,
+		FormatFileSink,
+		FormatTDengine,
+		FormatMQTT,
 	}
 }
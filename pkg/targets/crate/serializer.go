@@ -15,7 +15,8 @@ type Serializer struct{}
 // Serialize Point p to the given Writer w, so it can be  loaded by the CrateDB
 // loader. The format is TSV with one line per point, that contains the
 // measurement type, tags with keys and values as a JSON object, timestamp,
-// and metric values.
+// and metric values. Tabs, newlines and backslashes in string values are
+// escaped with a backslash.
 //
 // An example of a serialized point:
 //     cpu\t{"hostname":"host_0","rack":"1"}\t1451606400000000000\t38\t0\t50\t41234
@@ -53,9 +54,29 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	for _, v := range fieldValues {
 		buf = append(buf, TAB)
+		if str, ok := v.(string); ok {
+			buf = appendEscaped(buf, str)
+			continue
+		}
 		buf = serialize.FastFormatAppend(v, buf)
 	}
 	buf = append(buf, '\n')
 	_, err := w.Write(buf)
 	return err
 }
+
+func appendEscaped(buf []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			buf = append(buf, '\\', '\\')
+		case TAB:
+			buf = append(buf, '\\', 't')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		default:
+			buf = append(buf, s[i])
+		}
+	}
+	return buf
+}
@@ -22,6 +22,11 @@ func TestCrateDBSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointMultiField(),
 			Output:     "cpu\t{\"hostname\":\"host_0\",\"region\":\"eu-west-1\",\"datacenter\":\"eu-west-1b\"}\t1451606400000000000\t5000000000\t38\t38.24311829\n",
 		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     "cpu\t{\"hostname\":\"host_0\",\"region\":\"eu-west-1\",\"datacenter\":\"eu-west-1b\"}\t1451606400000000000\tdisk \"C:\\\\\" is 95% full, cleanup failed\ttrue\t38.24311829\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
@@ -0,0 +1,65 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const errNoFieldsFmt = "parse error: document has no fields object: %s"
+
+var (
+	fieldsKey = []byte(`,"fields":{`)
+	newLine   = []byte("\n")
+)
+
+// batch holds the action and document lines sent in a single _bulk request.
+type batch struct {
+	buf     *bytes.Buffer
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	that := item.Data.([]byte)
+	b.rows++
+
+	// The fields object is the last one of the document, which ends in "}}"
+	pos := bytes.LastIndex(that, fieldsKey)
+	if pos < 0 || !bytes.HasSuffix(that, []byte("}}")) {
+		log.Fatalf(errNoFieldsFmt, that)
+		return
+	}
+	b.metrics += uint64(countFields(that[pos+len(fieldsKey) : len(that)-2]))
+
+	b.buf.Write(that)
+	b.buf.Write(newLine)
+}
+
+// countFields counts the members of a JSON object without the braces,
+// skipping the commas in strings.
+func countFields(fields []byte) int {
+	if len(fields) == 0 {
+		return 0
+	}
+	count := 1
+	quoted := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,102 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the Elasticsearch specific loading options.
+type SpecificConfig struct {
+	URLs            []string      `yaml:"urls" mapstructure:"urls"`
+	User            string        `yaml:"user" mapstructure:"user"`
+	Pass            string        `yaml:"pass" mapstructure:"pass"`
+	Shards          int           `yaml:"shards" mapstructure:"shards"`
+	Replicas        int           `yaml:"replicas" mapstructure:"replicas"`
+	RefreshInterval string        `yaml:"refresh-interval" mapstructure:"refresh-interval"`
+	Retries         int           `yaml:"retries" mapstructure:"retries"`
+	Backoff         time.Duration `yaml:"backoff" mapstructure:"backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if len(conf.URLs) == 0 {
+		return nil, errors.New("at least one Elasticsearch url is required")
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	index      string
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+}
+
+func NewBenchmark(index string, esSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for Elasticsearch")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	return &benchmark{
+		index:      index,
+		conf:       esSpecificConfig,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br)},
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &factory{bufPool: &bufPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		urls:    b.conf.URLs,
+		user:    b.conf.User,
+		pass:    b.conf.Pass,
+		index:   b.index,
+		retries: b.conf.Retries,
+		backoff: b.conf.Backoff,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		c:               newClient(b.conf.URLs[0], b.conf.User, b.conf.Pass),
+		shards:          b.conf.Shards,
+		replicas:        b.conf.Replicas,
+		refreshInterval: b.conf.RefreshInterval,
+	}
+}
+
+type factory struct {
+	bufPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
+}
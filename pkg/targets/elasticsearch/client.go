@@ -0,0 +1,43 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+)
+
+// client sends requests to a single Elasticsearch node, with basic auth if
+// a user is set.
+type client struct {
+	url  string
+	user string
+	pass string
+	http *http.Client
+}
+
+func newClient(url, user, pass string) *client {
+	return &client{url: url, user: user, pass: pass, http: &http.Client{}}
+}
+
+// do sends the request and returns the status code and the body of the
+// response.
+func (c *client) do(method, path, contentType string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequest(method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, respBody, err
+}
@@ -0,0 +1,117 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+const contentTypeJSON = "application/json"
+
+// dbCreator manages the data stream the documents are written to, and the
+// index template the data stream is created from. Both are named after the
+// database.
+type dbCreator struct {
+	c               *client
+	shards          int
+	replicas        int
+	refreshInterval string
+}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	status, body, err := d.c.do(http.MethodGet, "/_data_stream/"+dbName, "", nil)
+	if err != nil {
+		log.Fatalf("could not get data stream %s: %v", dbName, err)
+	}
+	switch status {
+	case http.StatusOK:
+		return true
+	case http.StatusNotFound:
+		return false
+	default:
+		log.Fatalf("get data stream %s returned code %d: %s", dbName, status, body)
+		return false
+	}
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	for _, path := range []string{"/_data_stream/", "/_index_template/"} {
+		status, body, err := d.c.do(http.MethodDelete, path+dbName, "", nil)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK && status != http.StatusNotFound {
+			return fmt.Errorf("delete %s%s returned code %d: %s", path, dbName, status, body)
+		}
+	}
+	return nil
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	template, err := json.Marshal(d.indexTemplate(dbName))
+	if err != nil {
+		return err
+	}
+	status, body, err := d.c.do(http.MethodPut, "/_index_template/"+dbName, contentTypeJSON, template)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("create index template %s returned code %d: %s", dbName, status, body)
+	}
+
+	status, body, err = d.c.do(http.MethodPut, "/_data_stream/"+dbName, "", nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("create data stream %s returned code %d: %s", dbName, status, body)
+	}
+	return nil
+}
+
+// indexTemplate returns the index template of the data stream. The tags
+// and string fields are mapped as keywords and integer values as doubles,
+// so a float field whose first value has no fractional part is not
+// truncated. All other fields are mapped dynamically from their values.
+func (d *dbCreator) indexTemplate(dbName string) map[string]interface{} {
+	settings := map[string]interface{}{
+		"number_of_shards":   d.shards,
+		"number_of_replicas": d.replicas,
+	}
+	if d.refreshInterval != "" {
+		settings["refresh_interval"] = d.refreshInterval
+	}
+	return map[string]interface{}{
+		"index_patterns": []string{dbName},
+		"data_stream":    map[string]interface{}{},
+		// above the priority of the built-in templates, such as logs-*-*
+		"priority": 500,
+		"template": map[string]interface{}{
+			"settings": settings,
+			"mappings": map[string]interface{}{
+				"dynamic_templates": []interface{}{
+					map[string]interface{}{
+						"strings_as_keywords": map[string]interface{}{
+							"match_mapping_type": "string",
+							"mapping":            map[string]string{"type": "keyword"},
+						},
+					},
+					map[string]interface{}{
+						"longs_as_doubles": map[string]interface{}{
+							"match_mapping_type": "long",
+							"mapping":            map[string]string{"type": "double"},
+						},
+					},
+				},
+				"properties": map[string]interface{}{
+					"@timestamp":  map[string]string{"type": "date", "format": "epoch_millis||strict_date_optional_time"},
+					"measurement": map[string]string{"type": "keyword"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// fileDataSource reads the _bulk NDJSON written by the Serializer, every
+// item being an action line followed by a document line.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+}
+
+func (f *fileDataSource) NextItem() data.LoadedPoint {
+	if !f.scan() {
+		return data.LoadedPoint{}
+	}
+	item := make([]byte, 0, 2*len(f.scanner.Bytes()))
+	item = append(item, f.scanner.Bytes()...)
+	item = append(item, '\n')
+	if !f.scan() {
+		log.Fatalf("scan error: action without a document: %s", item)
+	}
+	item = append(item, f.scanner.Bytes()...)
+	return data.NewLoadedPoint(item)
+}
+
+// scan reads the next line, returning false on EOF.
+func (f *fileDataSource) scan() bool {
+	ok := f.scanner.Scan()
+	if !ok && f.scanner.Err() != nil {
+		log.Fatalf("scan error: %v", f.scanner.Err())
+	}
+	return ok
+}
+
+func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
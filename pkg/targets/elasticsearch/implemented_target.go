@@ -0,0 +1,47 @@
+package elasticsearch
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &esTarget{}
+}
+
+type esTarget struct {
+}
+
+func (t *esTarget) Benchmark(targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	esSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(targetDB, esSpecificConfig, dataSourceConfig)
+}
+
+func (t *esTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *esTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"urls", "http://localhost:9200", "Elasticsearch URLs, comma-separated. Workers are assigned to them in round-robin")
+	flagSet.String(flagPrefix+"user", "", "User for basic authentication. Empty disables authentication")
+	flagSet.String(flagPrefix+"pass", "", "Password for basic authentication")
+	flagSet.Int(flagPrefix+"shards", 1, "Number of primary shards of the data stream backing indices")
+	flagSet.Int(flagPrefix+"replicas", 0, "Number of replicas of the data stream backing indices")
+	flagSet.String(flagPrefix+"refresh-interval", "", "Refresh interval of the backing indices, e.g. 30s or -1. Empty keeps the Elasticsearch default")
+	flagSet.Int(flagPrefix+"retries", 10, "Times to resend documents rejected with 429 Too Many Requests before giving up")
+	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to wait before resending documents rejected with 429 Too Many Requests, doubled with every retry")
+}
+
+func (t *esTarget) TargetName() string {
+	return constants.FormatElasticsearch
+}
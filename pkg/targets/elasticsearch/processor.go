@@ -0,0 +1,129 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+// processor sends the batches of a worker as _bulk requests to one of the
+// nodes. Elasticsearch rejects requests and documents with 429 Too Many
+// Requests when its write queue is full, these are sent again after a
+// backoff that doubles with every retry, until the retries run out.
+type processor struct {
+	urls    []string
+	user    string
+	pass    string
+	index   string
+	retries int
+	backoff time.Duration
+
+	c    *client
+	path string
+}
+
+// bulkResponse holds the parts of a _bulk response needed to find the
+// rejected documents.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	p.c = newClient(p.urls[workerNum%len(p.urls)], p.user, p.pass)
+	p.path = "/" + p.index + "/_bulk"
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		if err := p.bulk(batch.buf.Bytes()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, batch.rows
+	batch.buf.Reset()
+	return metricCount, rowCount
+}
+
+// bulk sends the items, retrying the whole request or the rejected
+// documents until all of them are stored or the retries run out.
+func (p *processor) bulk(items []byte) error {
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		status, body, err := p.c.do(http.MethodPost, p.path, contentTypeNDJSON, items)
+		if err != nil {
+			return fmt.Errorf("error while executing bulk request: %v", err)
+		}
+		switch status {
+		case http.StatusOK:
+			var resp bulkResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return fmt.Errorf("could not decode bulk response: %v", err)
+			}
+			if !resp.Errors {
+				return nil
+			}
+			if items, err = rejectedItems(items, &resp); err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				return nil
+			}
+			if attempt >= p.retries {
+				return fmt.Errorf("%d documents still rejected with status 429 after %d retries", bytes.Count(items, newLine)/2, attempt)
+			}
+			log.Printf("%d documents rejected with status 429. Retrying in %v", bytes.Count(items, newLine)/2, backoff)
+		case http.StatusTooManyRequests:
+			if attempt >= p.retries {
+				return fmt.Errorf("bulk request still rejected with status 429 after %d retries", attempt)
+			}
+			log.Printf("bulk request rejected with status 429. Retrying in %v", backoff)
+		default:
+			return fmt.Errorf("bulk request returned code %d: %s", status, body)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// rejectedItems returns the action and document lines of the documents
+// rejected with 429, to be sent again. Any other error fails the batch.
+func rejectedItems(items []byte, resp *bulkResponse) ([]byte, error) {
+	var rejected []byte
+	for i, item := range resp.Items {
+		// every item is an action line and a document line
+		var action, doc []byte
+		action, items = nextLine(items)
+		doc, items = nextLine(items)
+		for _, result := range item {
+			switch {
+			case result.Status == http.StatusTooManyRequests:
+				rejected = append(rejected, action...)
+				rejected = append(rejected, '\n')
+				rejected = append(rejected, doc...)
+				rejected = append(rejected, '\n')
+			case result.Status >= 300:
+				return nil, fmt.Errorf("document %d of bulk request failed with status %d: %s", i, result.Status, result.Error)
+			}
+		}
+	}
+	return rejected, nil
+}
+
+func nextLine(b []byte) (line, rest []byte) {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
@@ -0,0 +1,245 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const (
+	testDoc1 = `{"@timestamp":1451606400000,"measurement":"cpu","tags":{"hostname":"host_0"},"fields":{"usage_user":58,"usage_system":2}}`
+	testDoc2 = `{"@timestamp":1451606410000,"measurement":"cpu","tags":{"hostname":"host_1"},"fields":{"usage_user":12,"note":"a,b"}}`
+)
+
+func newTestBatch(docs ...string) *batch {
+	f := &factory{bufPool: &sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}}
+	b := f.New().(*batch)
+	for _, doc := range docs {
+		b.Append(data.NewLoadedPoint([]byte(`{"create":{}}` + "\n" + doc)))
+	}
+	return b
+}
+
+func TestCountFields(t *testing.T) {
+	cases := []struct {
+		fields string
+		want   int
+	}{
+		{fields: ``, want: 0},
+		{fields: `"a":1`, want: 1},
+		{fields: `"a":1,"b":true,"c":2.5`, want: 3},
+		{fields: `"a":"x,y","b":"quote \" and, comma"`, want: 2},
+	}
+	for _, c := range cases {
+		if got := countFields([]byte(c.fields)); got != c.want {
+			t.Errorf("incorrect count for %s: got %d want %d", c.fields, got, c.want)
+		}
+	}
+}
+
+func TestFileDataSourceNextItem(t *testing.T) {
+	input := `{"create":{}}` + "\n" + testDoc1 + "\n" + `{"create":{}}` + "\n" + testDoc2 + "\n"
+	ds := &fileDataSource{scanner: bufio.NewScanner(strings.NewReader(input))}
+	for _, doc := range []string{testDoc1, testDoc2} {
+		item := ds.NextItem()
+		want := `{"create":{}}` + "\n" + doc
+		if got := string(item.Data.([]byte)); got != want {
+			t.Errorf("incorrect item:\ngot\n%s\nwant\n%s", got, want)
+		}
+	}
+	if item := ds.NextItem(); item.Data != nil {
+		t.Errorf("expected no item at EOF, got %s", item.Data)
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/benchmark/_bulk" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != contentTypeNDJSON {
+			t.Errorf("incorrect content type: got %s", got)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "elastic" || pass != "secret" {
+			t.Errorf("incorrect basic auth: got %s %s %v", user, pass, ok)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, string(body))
+
+		switch len(requests) {
+		case 1:
+			// the whole request is rejected
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			// the second document is rejected
+			resp := `{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`
+			w.Write([]byte(resp))
+		default:
+			w.Write([]byte(`{"errors":false,"items":[{"create":{"status":201}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	p := &processor{urls: []string{server.URL}, user: "elastic", pass: "secret", index: "benchmark", retries: 2}
+	p.Init(0, true, false)
+
+	b := newTestBatch(testDoc1, testDoc2)
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 4 || rows != 2 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 4 metrics 2 rows", metrics, rows)
+	}
+	if b.buf.Len() != 0 {
+		t.Errorf("batch buffer not reset")
+	}
+
+	all := `{"create":{}}` + "\n" + testDoc1 + "\n" + `{"create":{}}` + "\n" + testDoc2 + "\n"
+	want := []string{all, all, `{"create":{}}` + "\n" + testDoc2 + "\n"}
+	if len(requests) != len(want) {
+		t.Fatalf("incorrect number of requests: got %d want %d", len(requests), len(want))
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("incorrect request %d:\ngot\n%s\nwant\n%s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestProcessorBulkRetries(t *testing.T) {
+	cases := []struct {
+		desc     string
+		status   int
+		resp     string
+		requests int
+	}{
+		{
+			desc:     "rejected request",
+			status:   http.StatusTooManyRequests,
+			requests: 3,
+		},
+		{
+			desc:     "rejected document",
+			status:   http.StatusOK,
+			resp:     `{"errors":true,"items":[{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`,
+			requests: 3,
+		},
+		{
+			desc:     "failed document",
+			status:   http.StatusOK,
+			resp:     `{"errors":true,"items":[{"create":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`,
+			requests: 1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.resp))
+			}))
+			defer server.Close()
+
+			p := &processor{urls: []string{server.URL}, index: "benchmark", retries: 2, backoff: time.Millisecond}
+			p.Init(0, true, false)
+			b := newTestBatch(testDoc1)
+			if err := p.bulk(b.buf.Bytes()); err == nil {
+				t.Errorf("expected an error")
+			}
+			if requests != c.requests {
+				t.Errorf("incorrect number of requests: got %d want %d", requests, c.requests)
+			}
+		})
+	}
+}
+
+func TestProcessorProcessBatchNoLoad(t *testing.T) {
+	p := &processor{urls: []string{"http://localhost:1"}, index: "benchmark"}
+	p.Init(0, false, false)
+	metrics, rows := p.ProcessBatch(newTestBatch(testDoc1), false)
+	if metrics != 2 || rows != 1 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 2 metrics 1 row", metrics, rows)
+	}
+}
+
+func TestDBCreator(t *testing.T) {
+	streams := map[string]bool{}
+	var template map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/_index_template/") && r.Method == http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+				t.Errorf("could not decode index template: %v", err)
+			}
+		case strings.HasPrefix(r.URL.Path, "/_index_template/") && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/_data_stream/"):
+			name := strings.TrimPrefix(r.URL.Path, "/_data_stream/")
+			switch r.Method {
+			case http.MethodGet, http.MethodDelete:
+				if !streams[name] {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				if r.Method == http.MethodDelete {
+					delete(streams, name)
+				}
+			case http.MethodPut:
+				if template == nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				streams[name] = true
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	d := &dbCreator{c: newClient(server.URL, "", ""), shards: 2, replicas: 1, refreshInterval: "30s"}
+	if d.DBExists("benchmark") {
+		t.Fatalf("data stream should not exist before creation")
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("could not create data stream: %v", err)
+	}
+	if !d.DBExists("benchmark") {
+		t.Fatalf("data stream should exist after creation")
+	}
+
+	if got := template["index_patterns"].([]interface{})[0]; got != "benchmark" {
+		t.Errorf("incorrect index pattern: got %v", got)
+	}
+	if _, ok := template["data_stream"]; !ok {
+		t.Errorf("index template should enable data streams")
+	}
+	dynamic := template["template"].(map[string]interface{})["mappings"].(map[string]interface{})["dynamic_templates"].([]interface{})
+	longs := dynamic[1].(map[string]interface{})["longs_as_doubles"].(map[string]interface{})
+	if longs["match_mapping_type"] != "long" || longs["mapping"].(map[string]interface{})["type"] != "double" {
+		t.Errorf("integer values should be mapped as doubles: got %v", longs)
+	}
+	settings := template["template"].(map[string]interface{})["settings"].(map[string]interface{})
+	if settings["number_of_shards"] != 2.0 || settings["number_of_replicas"] != 1.0 || settings["refresh_interval"] != "30s" {
+		t.Errorf("incorrect settings: got %v", settings)
+	}
+
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("could not remove data stream: %v", err)
+	}
+	if d.DBExists("benchmark") {
+		t.Fatalf("data stream should not exist after removal")
+	}
+}
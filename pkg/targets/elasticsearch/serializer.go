@@ -0,0 +1,106 @@
+package elasticsearch
+
+import (
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+// bulkAction is the action line preceding every document. Documents of data
+// streams can only be created, the target data stream is set by the
+// loader in the URL of the _bulk request.
+var bulkAction = []byte(`{"create":{}}`)
+
+// Serializer writes a Point as a document of the Elasticsearch _bulk API.
+type Serializer struct{}
+
+// Serialize writes Point data to the given writer as two lines of NDJSON:
+// the action, followed by the document with the timestamp in milliseconds,
+// the measurement name, the tags and the fields.
+//
+// This function writes output that looks like:
+// {"create":{}}
+// {"@timestamp":<timestamp>,"measurement":"<measurement>","tags":{"<tag key>":"<tag value>"},"fields":{"<field name>":<field value>}}
+//
+// The fields object is always last, which lets the loader count them
+// without decoding the document.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	buf := make([]byte, 0, 1024)
+	buf = append(buf, bulkAction...)
+	buf = append(buf, '\n')
+	buf = append(buf, `{"@timestamp":`...)
+	buf = serialize.FastFormatAppend(p.Timestamp().UTC().UnixNano()/1e6, buf)
+	buf = append(buf, `,"measurement":`...)
+	buf = appendString(buf, p.MeasurementName())
+
+	buf = append(buf, `,"tags":{`...)
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	first := true
+	for i, key := range tagKeys {
+		if tagValues[i] == nil {
+			continue
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = appendString(buf, key)
+		buf = append(buf, ':')
+		buf = appendValue(buf, tagValues[i])
+	}
+
+	buf = append(buf, `},"fields":{`...)
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	first = true
+	for i, key := range fieldKeys {
+		if fieldValues[i] == nil {
+			continue
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = appendString(buf, key)
+		buf = append(buf, ':')
+		buf = appendValue(buf, fieldValues[i])
+	}
+	buf = append(buf, "}}\n"...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendValue appends a JSON value, strings are quoted while numbers and
+// bools are written as they are.
+func appendValue(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendString(buf, []byte(v))
+	case []byte:
+		return appendString(buf, v)
+	default:
+		return serialize.FastFormatAppend(v, buf)
+	}
+}
+
+// appendString appends s as a quoted JSON string.
+func appendString(buf, s []byte) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c == '\n':
+			buf = append(buf, '\\', 'n')
+		case c < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,54 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestElasticsearchSerializerSerialize(t *testing.T) {
+	const (
+		action = `{"create":{}}` + "\n"
+		doc    = `{"@timestamp":1451606400000,"measurement":"cpu",`
+		tags   = `"tags":{"hostname":"host_0","region":"eu-west-1","datacenter":"eu-west-1b"},`
+	)
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     action + doc + tags + `"fields":{"usage_guest_nice":38.24311829}}` + "\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     action + doc + tags + `"fields":{"usage_guest":38}}` + "\n",
+		},
+		{
+			Desc:       "a Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output:     action + doc + tags + `"fields":{"big_usage_guest":5000000000,"usage_guest":38,"usage_guest_nice":38.24311829}}` + "\n",
+		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     action + doc + tags + `"fields":{"last_error":"disk \"C:\\\" is 95% full, cleanup failed","alerting":true,"usage_guest_nice":38.24311829}}` + "\n",
+		},
+		{
+			Desc:       "a Point with no tags",
+			InputPoint: serialize.TestPointNoTags(),
+			Output:     action + doc + `"tags":{},"fields":{"usage_guest_nice":38.24311829}}` + "\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     action + doc + `"tags":{},"fields":{"usage_guest_nice":38.24311829}}` + "\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     action + doc + `"tags":{},"fields":{"usage_guest_nice":38.24311829}}` + "\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
@@ -0,0 +1,66 @@
+package filesink
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// batch holds the rows of a batch by table, one after the other as they
+// are written to the files.
+type batch struct {
+	tables  map[*table][]byte
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	r := item.Data.(*row)
+	buf := append(b.tables[r.table], r.line...)
+	b.tables[r.table] = append(buf, '\n')
+	b.rows++
+	b.metrics += uint64(len(r.table.columns) - r.table.tags)
+}
+
+func (b *batch) reset() {
+	for t, buf := range b.tables {
+		b.tables[t] = buf[:0]
+	}
+	b.rows = 0
+	b.metrics = 0
+}
+
+type factory struct {
+	batchPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return f.batchPool.Get().(*batch)
+}
+
+// seriesIndexer sends all the rows of a series, i.e. of a table and tag
+// values, to the same worker.
+type seriesIndexer struct {
+	partitions uint
+}
+
+func (i *seriesIndexer) GetIndex(item data.LoadedPoint) uint {
+	r := item.Data.(*row)
+	// skip the timestamp
+	start := indexCSV(r.line, 1) + 1
+	if start > len(r.line) {
+		start = len(r.line)
+	}
+	end := start + indexCSV(r.line[start:], r.table.tags)
+
+	h := fnv.New32a()
+	h.Write([]byte(r.table.name))
+	h.Write(r.line[start:end])
+	return uint(h.Sum32()) % i.partitions
+}
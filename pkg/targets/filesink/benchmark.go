@@ -0,0 +1,92 @@
+package filesink
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the file sink specific loading options.
+type SpecificConfig struct {
+	Dir string `yaml:"dir" mapstructure:"dir"`
+	// RotateSize is the size in MB after which a new file is started, 0
+	// never rotates the files.
+	RotateSize int64  `yaml:"rotate-size" mapstructure:"rotate-size"`
+	Fsync      string `yaml:"fsync" mapstructure:"fsync"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	switch conf.Fsync {
+	case fsyncNone, fsyncBatch, fsyncRotate:
+	default:
+		return nil, fmt.Errorf("unknown fsync mode %s, must be %s, %s or %s", conf.Fsync, fsyncNone, fsyncBatch, fsyncRotate)
+	}
+	if conf.RotateSize < 0 {
+		return nil, fmt.Errorf("invalid rotate size %d", conf.RotateSize)
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dbName     string
+	dataSource targets.DataSource
+	batchPool  *sync.Pool
+}
+
+func NewBenchmark(dbName string, fileSinkSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for the file sink")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	batchPool := &sync.Pool{New: func() interface{} {
+		return &batch{tables: map[*table][]byte{}}
+	}}
+	return &benchmark{
+		conf:       fileSinkSpecificConfig,
+		dbName:     dbName,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br), tables: map[string]*table{}},
+		batchPool:  batchPool,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &seriesIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		dir:        filepath.Join(b.conf.Dir, b.dbName),
+		rotateSize: b.conf.RotateSize * 1024 * 1024,
+		fsync:      b.conf.Fsync,
+		batchPool:  b.batchPool,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{dir: b.conf.Dir}
+}
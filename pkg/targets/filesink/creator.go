@@ -0,0 +1,31 @@
+package filesink
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// dbCreator manages the directory of the database, holding a directory of
+// files for every table.
+type dbCreator struct {
+	dir string
+}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	_, err := os.Stat(filepath.Join(d.dir, dbName))
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatalf("could not stat database directory: %v", err)
+	}
+	return err == nil
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	return os.RemoveAll(filepath.Join(d.dir, dbName))
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	return os.MkdirAll(filepath.Join(d.dir, dbName), 0755)
+}
@@ -0,0 +1,119 @@
+package filesink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// table holds the columns of a measurement, as declared by the header
+// lines of the data file.
+type table struct {
+	name    string
+	tags    int
+	columns []string // tag and field keys
+	header  []byte   // first line of the files of the table
+}
+
+// parseHeader parses a header line, without the leading #.
+func parseHeader(line []byte) (*table, error) {
+	values, err := csv.NewReader(bytes.NewReader(line)).Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) < 2 {
+		return nil, fmt.Errorf("header without tag count")
+	}
+	tags, err := strconv.Atoi(values[1])
+	if err != nil || tags < 0 || tags > len(values)-2 {
+		return nil, fmt.Errorf("invalid tag count %s", values[1])
+	}
+
+	t := &table{name: values[0], tags: tags, columns: values[2:]}
+	var header bytes.Buffer
+	w := csv.NewWriter(&header)
+	w.Write(append([]string{"time"}, t.columns...))
+	w.Flush()
+	t.header = bytes.Replace(header.Bytes(), []byte("\r\n"), []byte("\n"), -1)
+	return t, nil
+}
+
+// row is a row of a table, without the measurement, i.e. the timestamp and
+// the tag and field values.
+type row struct {
+	table *table
+	line  []byte
+}
+
+type fileDataSource struct {
+	scanner *bufio.Scanner
+	tables  map[string]*table
+}
+
+func (f *fileDataSource) NextItem() data.LoadedPoint {
+	for f.scanner.Scan() {
+		line := f.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] == '#' {
+			t, err := parseHeader(line[1:])
+			if err != nil {
+				log.Fatalf("invalid header %s: %v", line, err)
+			}
+			f.tables[t.name] = t
+			continue
+		}
+
+		i := indexCSV(line, 1)
+		if i >= len(line) {
+			log.Fatalf("invalid row: %s", line)
+		}
+		name := string(line[:i])
+		if strings.HasPrefix(name, `"`) {
+			values, err := csv.NewReader(bytes.NewReader(line[:i])).Read()
+			if err != nil {
+				log.Fatalf("invalid row %s: %v", line, err)
+			}
+			name = values[0]
+		}
+		t, ok := f.tables[name]
+		if !ok {
+			log.Fatalf("row of table %s before its header: %s", name, line)
+		}
+		return data.NewLoadedPoint(&row{table: t, line: line[i+1:]})
+	}
+	if err := f.scanner.Err(); err != nil {
+		log.Fatalf("scan error: %v", err)
+	}
+	return data.LoadedPoint{}
+}
+
+func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+// indexCSV returns the index of the separator after the first n values of
+// a CSV line, or its length if it has no more than n values.
+func indexCSV(line []byte, n int) int {
+	inQuotes := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			n--
+			if n == 0 {
+				return i
+			}
+		}
+	}
+	return len(line)
+}
@@ -0,0 +1,40 @@
+package filesink
+
+import (
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &fileSinkTarget{}
+}
+
+type fileSinkTarget struct {
+}
+
+func (t *fileSinkTarget) Benchmark(targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	fileSinkSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(targetDB, fileSinkSpecificConfig, dataSourceConfig)
+}
+
+func (t *fileSinkTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *fileSinkTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"dir", "./filesink", "Directory holding a directory of CSV files for every database")
+	flagSet.Int64(flagPrefix+"rotate-size", 256, "Size in MB after which a new file is started, 0 to never rotate")
+	flagSet.String(flagPrefix+"fsync", fsyncNone, "When to sync the files to disk: none, batch (after every batch) or rotate (before closing a file)")
+}
+
+func (t *fileSinkTarget) TargetName() string {
+	return constants.FormatFileSink
+}
@@ -0,0 +1,148 @@
+package filesink
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// Modes of syncing the files to disk.
+const (
+	fsyncNone   = "none"
+	fsyncBatch  = "batch"
+	fsyncRotate = "rotate"
+)
+
+// processor appends the rows of every table to a file of its own, in the
+// directory of the table:
+// <dir>/<db name>/<table>/<worker>-<sequence>.csv
+// A new file is started once a file reaches the rotation size, so files
+// are larger by up to a batch.
+type processor struct {
+	dir        string
+	rotateSize int64
+	fsync      string
+	batchPool  *sync.Pool
+
+	worker int
+	files  map[string]*sinkFile
+}
+
+// sinkFile is the file the rows of a table are currently appended to.
+type sinkFile struct {
+	f      *os.File
+	table  *table
+	size   int64
+	seq    int
+	synced bool
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	p.worker = workerNum
+	p.files = map[string]*sinkFile{}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		for t, rows := range batch.tables {
+			if len(rows) == 0 {
+				continue
+			}
+			if err := p.write(t, rows); err != nil {
+				log.Fatalf("could not write rows of table %s: %v", t.name, err)
+			}
+		}
+		if p.fsync == fsyncBatch {
+			if err := p.sync(); err != nil {
+				log.Fatalf("could not sync files: %v", err)
+			}
+		}
+	}
+	metricCount, rowCount = batch.metrics, batch.rows
+	batch.reset()
+	p.batchPool.Put(batch)
+	return metricCount, rowCount
+}
+
+func (p *processor) Close(doLoad bool) {
+	for _, f := range p.files {
+		if err := p.closeFile(f); err != nil {
+			log.Fatalf("could not close %s: %v", f.f.Name(), err)
+		}
+	}
+}
+
+// write appends the rows to the file of the table, starting a new file if
+// the columns of the table changed or the file is due for rotation.
+func (p *processor) write(t *table, rows []byte) error {
+	f := p.files[t.name]
+	if f == nil || f.table != t || (p.rotateSize > 0 && f.size >= p.rotateSize) {
+		next, err := p.nextFile(t, f)
+		if err != nil {
+			return err
+		}
+		f = next
+		p.files[t.name] = f
+	}
+	n, err := f.f.Write(rows)
+	f.size += int64(n)
+	f.synced = false
+	return err
+}
+
+// nextFile closes the current file of the table, if any, and creates the
+// next one with the header of the table. Files left by earlier runs are
+// skipped rather than overwritten.
+func (p *processor) nextFile(t *table, current *sinkFile) (*sinkFile, error) {
+	seq := 0
+	if current != nil {
+		seq = current.seq + 1
+		if err := p.closeFile(current); err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Join(p.dir, t.name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for ; ; seq++ {
+		path := filepath.Join(dir, fmt.Sprintf("%d-%06d.csv", p.worker, seq))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		n, err := f.Write(t.header)
+		return &sinkFile{f: f, table: t, size: int64(n), seq: seq}, err
+	}
+}
+
+func (p *processor) closeFile(f *sinkFile) error {
+	if p.fsync != fsyncNone && !f.synced {
+		if err := f.f.Sync(); err != nil {
+			return err
+		}
+	}
+	return f.f.Close()
+}
+
+// sync syncs the files written to since they were last synced.
+func (p *processor) sync() error {
+	for _, f := range p.files {
+		if f.synced {
+			continue
+		}
+		if err := f.f.Sync(); err != nil {
+			return err
+		}
+		f.synced = true
+	}
+	return nil
+}
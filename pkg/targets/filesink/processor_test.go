@@ -0,0 +1,219 @@
+package filesink
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func newTestPoint(measurement string, ts int64, hostname string, fieldKeys []string, fieldValues ...interface{}) *data.Point {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte(measurement))
+	t := time.Unix(0, ts)
+	p.SetTimestamp(&t)
+	p.AppendTag([]byte("hostname"), hostname)
+	for i, key := range fieldKeys {
+		p.AppendField([]byte(key), fieldValues[i])
+	}
+	return p
+}
+
+// newTestDataSource returns a data source reading the serialized points.
+func newTestDataSource(t *testing.T, points ...*data.Point) *fileDataSource {
+	b := new(bytes.Buffer)
+	s := &Serializer{}
+	for _, p := range points {
+		if err := s.Serialize(p, b); err != nil {
+			t.Fatalf("could not serialize: %v", err)
+		}
+	}
+	return &fileDataSource{scanner: bufio.NewScanner(b), tables: map[string]*table{}}
+}
+
+// loadBatches loads the points in batches of batchSize with a single
+// worker, returning the number of metrics and rows loaded.
+func loadBatches(t *testing.T, p *processor, batchSize int, points ...*data.Point) (metrics, rows uint64) {
+	ds := newTestDataSource(t, points...)
+	f := &factory{batchPool: p.batchPool}
+	p.Init(0, true, false)
+	b := f.New().(*batch)
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		b.Append(item)
+		if int(b.Len()) == batchSize {
+			m, r := p.ProcessBatch(b, true)
+			metrics, rows = metrics+m, rows+r
+			b = f.New().(*batch)
+		}
+	}
+	if b.Len() > 0 {
+		m, r := p.ProcessBatch(b, true)
+		metrics, rows = metrics+m, rows+r
+	}
+	p.Close(true)
+	return metrics, rows
+}
+
+func newTestProcessor(t *testing.T, rotateSize int64, fsync string) (*processor, string) {
+	dir, err := ioutil.TempDir("", "filesink")
+	if err != nil {
+		t.Fatalf("could not create directory: %v", err)
+	}
+	pool := &sync.Pool{New: func() interface{} { return &batch{tables: map[*table][]byte{}} }}
+	return &processor{dir: dir, rotateSize: rotateSize, fsync: fsync, batchPool: pool}, dir
+}
+
+func readFiles(t *testing.T, pattern string) map[string]string {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Base(path)] = string(b)
+	}
+	return files
+}
+
+var cpuFields = []string{"usage_user", "usage_system"}
+
+func TestFileDataSource(t *testing.T) {
+	ds := newTestDataSource(t,
+		newTestPoint("cpu", 10, "host_0", cpuFields, 1, 2.5),
+		newTestPoint("mem", 10, "host,0", []string{"used"}, 3),
+	)
+	item := ds.NextItem()
+	r := item.Data.(*row)
+	if r.table.name != "cpu" || r.table.tags != 1 || string(r.line) != "10,host_0,1,2.5" {
+		t.Errorf("incorrect row: table %s with %d tags, line %s", r.table.name, r.table.tags, r.line)
+	}
+	if got := string(r.table.header); got != "time,hostname,usage_user,usage_system\n" {
+		t.Errorf("incorrect table header: %s", got)
+	}
+	r = ds.NextItem().Data.(*row)
+	if r.table.name != "mem" || string(r.line) != `10,"host,0",3` {
+		t.Errorf("incorrect row: table %s, line %s", r.table.name, r.line)
+	}
+	if item := ds.NextItem(); item.Data != nil {
+		t.Errorf("expected the end of the data, got %v", item.Data)
+	}
+}
+
+func TestSeriesIndexer(t *testing.T) {
+	ds := newTestDataSource(t,
+		newTestPoint("cpu", 10, "host_0", cpuFields, 1, 2),
+		newTestPoint("cpu", 20, "host_0", cpuFields, 3, 4),
+		newTestPoint("cpu", 20, "host_1", cpuFields, 3, 4),
+	)
+	i := &seriesIndexer{partitions: 1000}
+	first, second, third := i.GetIndex(ds.NextItem()), i.GetIndex(ds.NextItem()), i.GetIndex(ds.NextItem())
+	if first != second {
+		t.Errorf("rows of a series should go to the same worker, got %d and %d", first, second)
+	}
+	if first == third {
+		t.Errorf("rows of different series should be spread, got %d for both", first)
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	p, dir := newTestProcessor(t, 0, fsyncBatch)
+	defer os.RemoveAll(dir)
+
+	metrics, rows := loadBatches(t, p, 2,
+		newTestPoint("cpu", 10, "host_0", cpuFields, 1, 2),
+		newTestPoint("mem", 10, "host_0", []string{"used"}, 3),
+		newTestPoint("cpu", 20, "host_1", cpuFields, 4, 5),
+	)
+	if metrics != 5 || rows != 3 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 5 metrics 3 rows", metrics, rows)
+	}
+
+	cpu := readFiles(t, filepath.Join(dir, "cpu", "*.csv"))
+	want := "time,hostname,usage_user,usage_system\n10,host_0,1,2\n20,host_1,4,5\n"
+	if len(cpu) != 1 || cpu["0-000000.csv"] != want {
+		t.Errorf("incorrect cpu files:\ngot\n%v\nwant\n%s", cpu, want)
+	}
+	mem := readFiles(t, filepath.Join(dir, "mem", "*.csv"))
+	if want := "time,hostname,used\n10,host_0,3\n"; mem["0-000000.csv"] != want {
+		t.Errorf("incorrect mem files:\ngot\n%v\nwant\n%s", mem, want)
+	}
+}
+
+func TestProcessorRotatesFiles(t *testing.T) {
+	p, dir := newTestProcessor(t, 1, fsyncRotate)
+	defer os.RemoveAll(dir)
+	// files of an earlier run are not overwritten
+	os.MkdirAll(filepath.Join(dir, "cpu"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "cpu", "0-000000.csv"), []byte("earlier run"), 0644)
+
+	loadBatches(t, p, 1,
+		newTestPoint("cpu", 10, "host_0", cpuFields, 1, 2),
+		newTestPoint("cpu", 20, "host_0", cpuFields, 3, 4),
+	)
+
+	files := readFiles(t, filepath.Join(dir, "cpu", "*.csv"))
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) != 3 || names[1] != "0-000001.csv" || names[2] != "0-000002.csv" {
+		t.Fatalf("incorrect files: %v", names)
+	}
+	if files["0-000000.csv"] != "earlier run" {
+		t.Errorf("file of an earlier run overwritten: %s", files["0-000000.csv"])
+	}
+	if want := "time,hostname,usage_user,usage_system\n20,host_0,3,4\n"; files["0-000002.csv"] != want {
+		t.Errorf("incorrect rotated file:\ngot\n%s\nwant\n%s", files["0-000002.csv"], want)
+	}
+}
+
+func TestProcessorColumnsChange(t *testing.T) {
+	p, dir := newTestProcessor(t, 0, fsyncNone)
+	defer os.RemoveAll(dir)
+
+	loadBatches(t, p, 1,
+		newTestPoint("cpu", 10, "host_0", cpuFields, 1, 2),
+		newTestPoint("cpu", 20, "host_0", []string{"usage_user"}, 3),
+	)
+	files := readFiles(t, filepath.Join(dir, "cpu", "*.csv"))
+	if want := "time,hostname,usage_user\n20,host_0,3\n"; files["0-000001.csv"] != want {
+		t.Errorf("new columns should start a new file, got %v", files)
+	}
+}
+
+func TestDBCreator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := &dbCreator{dir: dir}
+	if d.DBExists("benchmark") {
+		t.Errorf("database should not exist yet")
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("could not create database: %v", err)
+	}
+	if !d.DBExists("benchmark") {
+		t.Errorf("database should exist")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Fatalf("could not remove database: %v", err)
+	}
+	if d.DBExists("benchmark") {
+		t.Errorf("database should be removed")
+	}
+}
@@ -0,0 +1,322 @@
+package filesink
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregate functions of a Plan.
+const (
+	AggregateMax  = "max"
+	AggregateMean = "mean"
+	AggregateLast = "last"
+)
+
+// Plan is a query of a table, run by scanning all of its files. The
+// queries generated for the file sink are JSON encoded plans.
+type Plan struct {
+	Table string `json:"table"`
+	// Start and End bound the time of the rows in nanoseconds, End
+	// excluded. Zero leaves a bound open.
+	Start int64 `json:"start,omitempty"`
+	End   int64 `json:"end,omitempty"`
+	// In keeps the rows whose column holds one of the values, e.g. the
+	// hostnames of a set of hosts.
+	In *In `json:"in,omitempty"`
+	// Above keeps the rows whose column is greater than the value.
+	Above   *Above   `json:"above,omitempty"`
+	Columns []string `json:"columns"`
+	// Aggregate is the function applied to the columns, max, mean or last.
+	// Without one, the rows are returned as they are.
+	Aggregate string `json:"aggregate,omitempty"`
+	// Interval groups the rows into time buckets, in nanoseconds.
+	Interval int64    `json:"interval,omitempty"`
+	GroupBy  []string `json:"group_by,omitempty"`
+	// Descending orders the results by time descending, rather than
+	// ascending.
+	Descending bool `json:"descending,omitempty"`
+	Limit      int  `json:"limit,omitempty"`
+}
+
+// In is a filter of a Plan on the values of a column.
+type In struct {
+	Column string   `json:"column"`
+	Values []string `json:"values"`
+}
+
+// Above is a filter of a Plan on a numeric column.
+type Above struct {
+	Column string  `json:"column"`
+	Value  float64 `json:"value"`
+}
+
+// Result holds the rows returned by a Plan, the columns being the time (if
+// the plan has buckets, returns the last rows or no aggregate at all), the
+// group by columns and the columns of the plan.
+type Result struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// group holds the state of the aggregate of a time bucket and group by
+// values.
+type group struct {
+	time   int64
+	keys   []string
+	values []float64
+	counts []int64
+}
+
+// Execute runs the plan over the files of its table, in the directory of
+// the database.
+func Execute(dir string, p *Plan) (*Result, error) {
+	switch p.Aggregate {
+	case "", AggregateMax, AggregateMean, AggregateLast:
+	default:
+		return nil, fmt.Errorf("unknown aggregate %s", p.Aggregate)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, p.Table, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("table %s has no files in %s", p.Table, dir)
+	}
+	sort.Strings(files)
+
+	e := &execution{plan: p, groups: map[string]*group{}}
+	if p.In != nil {
+		e.in = make(map[string]bool, len(p.In.Values))
+		for _, v := range p.In.Values {
+			e.in[v] = true
+		}
+	}
+	for _, file := range files {
+		if err := e.scanFile(file); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	}
+	return e.result(), nil
+}
+
+type execution struct {
+	plan *Plan
+	in   map[string]bool
+
+	groups map[string]*group
+	rows   []group // rows of plans without aggregate
+}
+
+// scanFile adds the matching rows of a file to the groups.
+func (e *execution) scanFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.ReuseRecord = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[column] = i
+	}
+	lookup := func(columns ...string) ([]int, error) {
+		indexes := make([]int, len(columns))
+		for i, column := range columns {
+			var ok bool
+			if indexes[i], ok = index[column]; !ok {
+				return nil, fmt.Errorf("unknown column %s", column)
+			}
+		}
+		return indexes, nil
+	}
+	columns, err := lookup(e.plan.Columns...)
+	if err != nil {
+		return err
+	}
+	groupBy, err := lookup(e.plan.GroupBy...)
+	if err != nil {
+		return err
+	}
+	in, above := []int{-1}, []int{-1}
+	if e.plan.In != nil {
+		if in, err = lookup(e.plan.In.Column); err != nil {
+			return err
+		}
+	}
+	if e.plan.Above != nil {
+		if above, err = lookup(e.plan.Above.Column); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, len(groupBy))
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		ts, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid time %s", record[0])
+		}
+		if (e.plan.Start != 0 && ts < e.plan.Start) || (e.plan.End != 0 && ts >= e.plan.End) {
+			continue
+		}
+		if in[0] >= 0 && !e.in[record[in[0]]] {
+			continue
+		}
+		if above[0] >= 0 {
+			v, err := strconv.ParseFloat(record[above[0]], 64)
+			if err != nil || v <= e.plan.Above.Value {
+				continue
+			}
+		}
+		for i, c := range groupBy {
+			keys[i] = record[c]
+		}
+		if err := e.add(ts, keys, record, columns); err != nil {
+			return err
+		}
+	}
+}
+
+// add adds the values of a matching row to its group.
+func (e *execution) add(ts int64, keys, record []string, columns []int) error {
+	values := make([]float64, len(columns))
+	present := make([]bool, len(columns))
+	for i, c := range columns {
+		if record[c] == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(record[c], 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %s", record[c])
+		}
+		values[i], present[i] = v, true
+	}
+
+	if e.plan.Aggregate == "" {
+		g := group{time: ts, keys: append([]string(nil), keys...), values: values, counts: make([]int64, len(columns))}
+		for i := range present {
+			if present[i] {
+				g.counts[i] = 1
+			}
+		}
+		e.rows = append(e.rows, g)
+		return nil
+	}
+
+	bucket := int64(0)
+	if e.plan.Interval > 0 {
+		bucket = ts / e.plan.Interval * e.plan.Interval
+		if ts < 0 && ts%e.plan.Interval != 0 {
+			bucket -= e.plan.Interval
+		}
+	}
+	id := strconv.FormatInt(bucket, 10) + "\x00" + strings.Join(keys, "\x00")
+	g, ok := e.groups[id]
+	if !ok {
+		g = &group{time: bucket, keys: append([]string(nil), keys...), values: make([]float64, len(columns)), counts: make([]int64, len(columns))}
+		if e.plan.Aggregate == AggregateLast {
+			g.time = math.MinInt64
+		}
+		e.groups[id] = g
+	}
+
+	if e.plan.Aggregate == AggregateLast {
+		if ts < g.time {
+			return nil
+		}
+		g.time = ts
+		for i := range values {
+			g.values[i] = values[i]
+			g.counts[i] = 0
+			if present[i] {
+				g.counts[i] = 1
+			}
+		}
+		return nil
+	}
+	for i, v := range values {
+		if !present[i] {
+			continue
+		}
+		switch {
+		case e.plan.Aggregate == AggregateMax && (g.counts[i] == 0 || v > g.values[i]):
+			g.values[i] = v
+		case e.plan.Aggregate == AggregateMean:
+			g.values[i] += v
+		}
+		g.counts[i]++
+	}
+	return nil
+}
+
+func (e *execution) result() *Result {
+	groups := e.rows
+	for _, g := range e.groups {
+		groups = append(groups, *g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.time != b.time {
+			return (a.time < b.time) != e.plan.Descending
+		}
+		for k := range a.keys {
+			if a.keys[k] != b.keys[k] {
+				return a.keys[k] < b.keys[k]
+			}
+		}
+		return false
+	})
+	if e.plan.Limit > 0 && len(groups) > e.plan.Limit {
+		groups = groups[:e.plan.Limit]
+	}
+
+	withTime := e.plan.Interval > 0 || e.plan.Aggregate == "" || e.plan.Aggregate == AggregateLast
+	res := &Result{Rows: make([][]interface{}, 0, len(groups))}
+	if withTime {
+		res.Columns = append(res.Columns, "time")
+	}
+	res.Columns = append(res.Columns, e.plan.GroupBy...)
+	res.Columns = append(res.Columns, e.plan.Columns...)
+	for _, g := range groups {
+		row := make([]interface{}, 0, len(res.Columns))
+		if withTime {
+			row = append(row, time.Unix(0, g.time).UTC().Format(time.RFC3339Nano))
+		}
+		for _, k := range g.keys {
+			row = append(row, k)
+		}
+		for i, v := range g.values {
+			switch {
+			case g.counts[i] == 0:
+				row = append(row, nil)
+			case e.plan.Aggregate == AggregateMean:
+				row = append(row, v/float64(g.counts[i]))
+			default:
+				row = append(row, v)
+			}
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res
+}
@@ -0,0 +1,116 @@
+package filesink
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestTable writes the cpu table as two files, as loaded by two
+// workers.
+func writeTestTable(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "filesink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(dir, "cpu"), 0755)
+	const header = "time,hostname,usage_user,usage_system\n"
+	files := map[string]string{
+		"0-000000.csv": header +
+			"0,host_0,10,1\n" +
+			"30000000000,host_0,95,2\n" +
+			"60000000000,host_0,20,\n",
+		"1-000000.csv": header +
+			"0,host_1,50,5\n" +
+			"30000000000,host_1,30,6\n" +
+			"90000000000,host_1,91,7\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, "cpu", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExecute(t *testing.T) {
+	dir := writeTestTable(t)
+	defer os.RemoveAll(dir)
+
+	minute := int64(time.Minute)
+	cases := []struct {
+		desc        string
+		plan        Plan
+		wantColumns string
+		wantRows    string
+	}{
+		{
+			desc:        "max by minute of a host",
+			plan:        Plan{Table: "cpu", In: &In{Column: "hostname", Values: []string{"host_0"}}, Columns: []string{"usage_user", "usage_system"}, Aggregate: AggregateMax, Interval: minute},
+			wantColumns: "[time usage_user usage_system]",
+			wantRows:    "[[1970-01-01T00:00:00Z 95 2] [1970-01-01T00:01:00Z 20 <nil>]]",
+		},
+		{
+			desc:        "mean by minute and host within a time range",
+			plan:        Plan{Table: "cpu", Start: 1, End: 90000000000, Columns: []string{"usage_user"}, Aggregate: AggregateMean, Interval: minute, GroupBy: []string{"hostname"}},
+			wantColumns: "[time hostname usage_user]",
+			wantRows:    "[[1970-01-01T00:00:00Z host_0 95] [1970-01-01T00:00:00Z host_1 30] [1970-01-01T00:01:00Z host_0 20]]",
+		},
+		{
+			desc:        "mean of all rows",
+			plan:        Plan{Table: "cpu", Columns: []string{"usage_system"}, Aggregate: AggregateMean},
+			wantColumns: "[usage_system]",
+			wantRows:    "[[4.2]]",
+		},
+		{
+			desc:        "last row per host",
+			plan:        Plan{Table: "cpu", Columns: []string{"usage_user", "usage_system"}, Aggregate: AggregateLast, GroupBy: []string{"hostname"}},
+			wantColumns: "[time hostname usage_user usage_system]",
+			wantRows:    "[[1970-01-01T00:01:00Z host_0 20 <nil>] [1970-01-01T00:01:30Z host_1 91 7]]",
+		},
+		{
+			desc:        "rows above a threshold",
+			plan:        Plan{Table: "cpu", Above: &Above{Column: "usage_user", Value: 90}, Columns: []string{"usage_user"}, GroupBy: []string{"hostname"}},
+			wantColumns: "[time hostname usage_user]",
+			wantRows:    "[[1970-01-01T00:00:30Z host_0 95] [1970-01-01T00:01:30Z host_1 91]]",
+		},
+		{
+			desc:        "last minutes in descending order",
+			plan:        Plan{Table: "cpu", End: 90000000000, Columns: []string{"usage_user"}, Aggregate: AggregateMax, Interval: minute, Descending: true, Limit: 1},
+			wantColumns: "[time usage_user]",
+			wantRows:    "[[1970-01-01T00:01:00Z 20]]",
+		},
+	}
+	for _, c := range cases {
+		res, err := Execute(dir, &c.plan)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+			continue
+		}
+		if got := fmt.Sprint(res.Columns); got != c.wantColumns {
+			t.Errorf("%s: incorrect columns: got %s want %s", c.desc, got, c.wantColumns)
+		}
+		if got := fmt.Sprint(res.Rows); got != c.wantRows {
+			t.Errorf("%s: incorrect rows:\ngot\n%s\nwant\n%s", c.desc, got, c.wantRows)
+		}
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	dir := writeTestTable(t)
+	defer os.RemoveAll(dir)
+
+	plans := map[string]Plan{
+		"unknown table":     {Table: "mem", Columns: []string{"used"}},
+		"unknown column":    {Table: "cpu", Columns: []string{"usage_idle"}},
+		"unknown aggregate": {Table: "cpu", Columns: []string{"usage_user"}, Aggregate: "median"},
+	}
+	for desc, plan := range plans {
+		if _, err := Execute(dir, &plan); err == nil {
+			t.Errorf("%s: expected an error", desc)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package filesink
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+// Serializer writes a Point as a CSV row of its table, named after the
+// measurement. The columns of a table are declared by a header line before
+// its first row, and again whenever they change, e.g.:
+// #cpu,2,hostname,region,usage_user,usage_system
+// cpu,1451606400000000000,host_0,eu-west-1,58,2
+// The header holds the measurement, the number of tags and the tag and
+// field keys; the rows hold the measurement, the timestamp in nanoseconds
+// and the tag and field values. Nil values are left empty.
+type Serializer struct {
+	columns map[string][][]byte
+}
+
+// Serialize writes Point p to the given Writer w, so it can be
+// loaded by the file sink loader.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	if s.columns == nil {
+		s.columns = map[string][][]byte{}
+	}
+	measurement := p.MeasurementName()
+	tagKeys, fieldKeys := p.TagKeys(), p.FieldKeys()
+
+	buf := make([]byte, 0, 1024)
+	if !sameColumns(s.columns[string(measurement)], tagKeys, fieldKeys) {
+		buf = append(buf, '#')
+		buf = appendCSV(buf, measurement)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(len(tagKeys)), 10)
+		columns := make([][]byte, 0, len(tagKeys)+len(fieldKeys))
+		for _, keys := range [][][]byte{tagKeys, fieldKeys} {
+			for _, key := range keys {
+				buf = append(buf, ',')
+				buf = appendCSV(buf, key)
+				columns = append(columns, append([]byte(nil), key...))
+			}
+		}
+		buf = append(buf, '\n')
+		s.columns[string(measurement)] = columns
+	}
+
+	buf = appendCSV(buf, measurement)
+	buf = append(buf, ',')
+	buf = strconv.AppendInt(buf, p.Timestamp().UTC().UnixNano(), 10)
+	for _, values := range [][]interface{}{p.TagValues(), p.FieldValues()} {
+		for _, v := range values {
+			buf = append(buf, ',')
+			buf = appendValue(buf, v)
+		}
+	}
+	buf = append(buf, '\n')
+	_, err := w.Write(buf)
+	return err
+}
+
+func sameColumns(columns [][]byte, tagKeys, fieldKeys [][]byte) bool {
+	if columns == nil || len(columns) != len(tagKeys)+len(fieldKeys) {
+		return false
+	}
+	for i, key := range tagKeys {
+		if !bytes.Equal(columns[i], key) {
+			return false
+		}
+	}
+	for i, key := range fieldKeys {
+		if !bytes.Equal(columns[len(tagKeys)+i], key) {
+			return false
+		}
+	}
+	return true
+}
+
+func appendValue(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return buf
+	case string:
+		return appendCSV(buf, []byte(v))
+	case []byte:
+		return appendCSV(buf, v)
+	default:
+		return serialize.FastFormatAppend(v, buf)
+	}
+}
+
+// appendCSV appends s as a CSV value, quoted if it contains a separator,
+// a quote or a line break.
+func appendCSV(buf, s []byte) []byte {
+	if bytes.IndexAny(s, ",\"\r\n") < 0 {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	for _, c := range s {
+		if c == '"' {
+			buf = append(buf, '"')
+		}
+		buf = append(buf, c)
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,77 @@
+package filesink
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestFileSinkSerializerSerialize(t *testing.T) {
+	const (
+		tagKeys = "#cpu,3,hostname,region,datacenter"
+		row     = "cpu,1451606400000000000"
+		tags    = ",host_0,eu-west-1,eu-west-1b"
+	)
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     tagKeys + ",usage_guest_nice\n" + row + tags + ",38.24311829\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     tagKeys + ",usage_guest\n" + row + tags + ",38\n",
+		},
+		{
+			Desc:       "a Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output:     tagKeys + ",big_usage_guest,usage_guest,usage_guest_nice\n" + row + tags + ",5000000000,38,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     tagKeys + ",last_error,alerting,usage_guest_nice\n" + row + tags + `,"disk ""C:\"" is 95% full, cleanup failed",true,38.24311829` + "\n",
+		},
+		{
+			Desc:       "a Point with no tags",
+			InputPoint: serialize.TestPointNoTags(),
+			Output:     "#cpu,0,usage_guest_nice\n" + row + ",38.24311829\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "#cpu,1,hostname,usage_guest_nice\n" + row + ",,38.24311829\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "#cpu,0,big_usage_guest,usage_guest_nice\n" + row + ",,38.24311829\n",
+		},
+	}
+
+	for _, c := range cases {
+		serialize.SerializerTest(t, []serialize.SerializeCase{c}, &Serializer{})
+	}
+}
+
+func TestFileSinkSerializerHeaders(t *testing.T) {
+	s := &Serializer{}
+	b := new(bytes.Buffer)
+	s.Serialize(serialize.TestPointDefault(), b)
+	s.Serialize(serialize.TestPointDefault(), b)
+	s.Serialize(serialize.TestPointInt(), b)
+	s.Serialize(serialize.TestPointDefault(), b)
+
+	want := "#cpu,3,hostname,region,datacenter,usage_guest_nice\n" +
+		"cpu,1451606400000000000,host_0,eu-west-1,eu-west-1b,38.24311829\n" +
+		"cpu,1451606400000000000,host_0,eu-west-1,eu-west-1b,38.24311829\n" +
+		"#cpu,3,hostname,region,datacenter,usage_guest\n" +
+		"cpu,1451606400000000000,host_0,eu-west-1,eu-west-1b,38\n" +
+		"#cpu,3,hostname,region,datacenter,usage_guest_nice\n" +
+		"cpu,1451606400000000000,host_0,eu-west-1,eu-west-1b,38.24311829\n"
+	if got := b.String(); got != want {
+		t.Errorf("headers should be written when the columns change:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
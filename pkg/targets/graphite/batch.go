@@ -0,0 +1,118 @@
+package graphite
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
+
+var (
+	spaceSep = []byte(" ")
+	newLine  = []byte("\n")
+)
+
+// batch holds the plaintext lines sent to Graphite in a single write. Every
+// line holds a single value, so the metric count equals the row count.
+type batch struct {
+	buf       *bytes.Buffer
+	flattener *pathFlattener
+	rows      uint64
+	metrics   uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	that := item.Data.([]byte)
+	b.rows++
+	b.metrics++
+
+	// Each line is format "path value timestamp"
+	if args := bytes.Count(that, spaceSep); args != 2 {
+		log.Fatalf(errNotThreeTuplesFmt, args+1)
+		return
+	}
+	if b.flattener != nil {
+		that = b.flattener.flatten(that)
+	}
+
+	b.buf.Write(that)
+	b.buf.Write(newLine)
+}
+
+// pathFlattener rewrites tagged series into plain metric paths, for Graphite
+// servers without tag support, e.g.
+// cpu.usage_user;hostname=host_0;region=eu-west-1 becomes
+// cpu.host_0.eu-west-1.usage_user. The tag values are placed between the
+// measurement and the field, in the order of the tags of the series or in
+// the order of pathTags, if set. Tags of the series missing from pathTags
+// are left out of the path.
+type pathFlattener struct {
+	pathTags [][]byte
+	buf      []byte
+}
+
+func newPathFlattener(pathTags []string) *pathFlattener {
+	f := &pathFlattener{}
+	for _, t := range pathTags {
+		f.pathTags = append(f.pathTags, []byte(t))
+	}
+	return f
+}
+
+// flatten returns the flattened line, which is only valid until the next
+// call.
+func (f *pathFlattener) flatten(line []byte) []byte {
+	nameEnd := bytes.IndexByte(line, ' ')
+	parts := bytes.Split(line[:nameEnd], []byte(";"))
+	series := parts[0]
+	tags := parts[1:]
+
+	measurement, field := series, []byte(nil)
+	if dot := bytes.IndexByte(series, '.'); dot >= 0 {
+		measurement, field = series[:dot], series[dot+1:]
+	}
+
+	f.buf = append(f.buf[:0], measurement...)
+	if len(f.pathTags) == 0 {
+		for _, tag := range tags {
+			f.buf = appendPathNode(f.buf, tagValue(tag))
+		}
+	} else {
+		for _, key := range f.pathTags {
+			for _, tag := range tags {
+				if eq := bytes.IndexByte(tag, '='); eq >= 0 && bytes.Equal(tag[:eq], key) {
+					f.buf = appendPathNode(f.buf, tag[eq+1:])
+					break
+				}
+			}
+		}
+	}
+	if field != nil {
+		f.buf = append(f.buf, '.')
+		f.buf = append(f.buf, field...)
+	}
+	return append(f.buf, line[nameEnd:]...)
+}
+
+func tagValue(tag []byte) []byte {
+	return tag[bytes.IndexByte(tag, '=')+1:]
+}
+
+// appendPathNode appends a node to the path, replacing the dots of the value
+// so it stays a single node.
+func appendPathNode(buf, value []byte) []byte {
+	buf = append(buf, '.')
+	for _, c := range value {
+		if c == '.' {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
@@ -0,0 +1,106 @@
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// Tag modes of the series sent to Graphite.
+const (
+	tagModeTagged = "tagged"
+	tagModePath   = "path"
+)
+
+// SpecificConfig holds the Graphite specific loading options.
+type SpecificConfig struct {
+	Address          string        `yaml:"address" mapstructure:"address"`
+	TagMode          string        `yaml:"tag-mode" mapstructure:"tag-mode"`
+	PathTags         []string      `yaml:"path-tags" mapstructure:"path-tags"`
+	ReconnectRetries int           `yaml:"reconnect-retries" mapstructure:"reconnect-retries"`
+	ReconnectBackoff time.Duration `yaml:"reconnect-backoff" mapstructure:"reconnect-backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if conf.TagMode != tagModeTagged && conf.TagMode != tagModePath {
+		return nil, fmt.Errorf("invalid tag-mode %s, must be %s or %s", conf.TagMode, tagModeTagged, tagModePath)
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+}
+
+func NewBenchmark(graphiteSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for Graphite")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	return &benchmark{
+		conf:       graphiteSpecificConfig,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br)},
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &factory{
+		bufPool:  &bufPool,
+		flatten:  b.conf.TagMode == tagModePath,
+		pathTags: b.conf.PathTags,
+	}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		address: b.conf.Address,
+		retries: b.conf.ReconnectRetries,
+		backoff: b.conf.ReconnectBackoff,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
+
+type factory struct {
+	bufPool  *sync.Pool
+	flatten  bool
+	pathTags []string
+}
+
+func (f *factory) New() targets.Batch {
+	b := &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
+	if f.flatten {
+		b.flattener = newPathFlattener(f.pathTags)
+	}
+	return b
+}
@@ -0,0 +1,13 @@
+package graphite
+
+// Graphite doesn't have a database abstraction, series are created on the
+// first write.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool { return true }
+
+func (d *dbCreator) CreateDB(dbName string) error { return nil }
+
+func (d *dbCreator) RemoveOldDB(dbName string) error { return nil }
@@ -0,0 +1,44 @@
+package graphite
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &graphiteTarget{}
+}
+
+type graphiteTarget struct {
+}
+
+func (t *graphiteTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	graphiteSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(graphiteSpecificConfig, dataSourceConfig)
+}
+
+func (t *graphiteTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *graphiteTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"address", "localhost:2003", "Graphite plaintext protocol TCP address")
+	flagSet.String(flagPrefix+"tag-mode", tagModeTagged, "How tags are sent: 'tagged' keeps Graphite 1.1 tags, 'path' flattens the tag values into the metric path")
+	flagSet.String(flagPrefix+"path-tags", "", "Comma-separated tags to flatten into the path, in order, with tag-mode 'path'. Empty flattens all the tags")
+	flagSet.Int(flagPrefix+"reconnect-retries", 5, "Times to reconnect and resend a batch after a failed write")
+	flagSet.Duration(flagPrefix+"reconnect-backoff", time.Second, "Time to wait before reconnecting, doubled with every retry")
+}
+
+func (t *graphiteTarget) TargetName() string {
+	return constants.FormatGraphite
+}
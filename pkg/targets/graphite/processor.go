@@ -0,0 +1,45 @@
+package graphite
+
+import (
+	"log"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+)
+
+// processor streams the batches to the plaintext receiver of Graphite over
+// a TCP connection per worker.
+type processor struct {
+	address string
+	retries int
+	backoff time.Duration
+
+	w *common.TCPWriter
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	p.w = &common.TCPWriter{Address: p.address, Retries: p.retries, Backoff: p.backoff}
+	if !doLoad {
+		return
+	}
+	if err := p.w.Connect(); err != nil {
+		log.Fatalf("worker %d could not connect to %s: %v", workerNum, p.address, err)
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		if err := p.w.Write(batch.buf.Bytes()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, batch.rows
+	batch.buf.Reset()
+	return metricCount, rowCount
+}
+
+func (p *processor) Close(doLoad bool) {
+	p.w.Close()
+}
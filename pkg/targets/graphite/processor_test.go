@@ -0,0 +1,137 @@
+package graphite
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func newTestFactory(flatten bool, pathTags []string) *factory {
+	return &factory{
+		bufPool: &sync.Pool{
+			New: func() interface{} {
+				return bytes.NewBuffer(make([]byte, 0, 1024))
+			},
+		},
+		flatten:  flatten,
+		pathTags: pathTags,
+	}
+}
+
+func TestPathFlattener(t *testing.T) {
+	line := []byte("cpu.usage_user;hostname=host_0;region=eu-west-1;os=Ubuntu15.10 58.13 1451606400")
+	cases := []struct {
+		desc     string
+		pathTags []string
+		want     string
+	}{
+		{
+			desc: "all tags",
+			want: "cpu.host_0.eu-west-1.Ubuntu15_10.usage_user 58.13 1451606400",
+		},
+		{
+			desc:     "selected tags in order",
+			pathTags: []string{"region", "hostname"},
+			want:     "cpu.eu-west-1.host_0.usage_user 58.13 1451606400",
+		},
+		{
+			desc:     "missing tags are left out",
+			pathTags: []string{"hostname", "rack"},
+			want:     "cpu.host_0.usage_user 58.13 1451606400",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			f := newPathFlattener(c.pathTags)
+			if got := string(f.flatten(line)); got != c.want {
+				t.Errorf("incorrect path:\ngot\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+
+	f := newPathFlattener(nil)
+	if got := string(f.flatten([]byte("cpu.usage_user 1 2"))); got != "cpu.usage_user 1 2" {
+		t.Errorf("series without tags should not change, got %s", got)
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		received <- lines
+	}()
+
+	f := newTestFactory(true, []string{"hostname"})
+	b := f.New().(*batch)
+	b.Append(data.NewLoadedPoint([]byte("cpu.usage_user;hostname=host_0;region=eu-west-1 58.13 1451606400")))
+	b.Append(data.NewLoadedPoint([]byte("cpu.usage_system;hostname=host_0;region=eu-west-1 2.62 1451606400")))
+
+	p := &processor{address: ln.Addr().String(), retries: 1, backoff: time.Millisecond}
+	p.Init(0, true, false)
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 2 || rows != 2 {
+		t.Errorf("incorrect counts: got %d metrics and %d rows, want 2 and 2", metrics, rows)
+	}
+	if b.buf.Len() != 0 {
+		t.Errorf("batch buffer not reset after processing")
+	}
+	p.Close(true)
+
+	want := []string{
+		"cpu.host_0.usage_user 58.13 1451606400",
+		"cpu.host_0.usage_system 2.62 1451606400",
+	}
+	select {
+	case lines := <-received:
+		if len(lines) != len(want) {
+			t.Fatalf("incorrect lines received: got %v want %v", lines, want)
+		}
+		for i := range want {
+			if lines[i] != want[i] {
+				t.Errorf("incorrect line %d: got %s want %s", i, lines[i], want[i])
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the lines")
+	}
+}
+
+func TestParseSpecificConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("tag-mode", "path")
+	v.Set("path-tags", "hostname,region")
+	conf, err := parseSpecificConfig(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conf.PathTags) != 2 || conf.PathTags[0] != "hostname" || conf.PathTags[1] != "region" {
+		t.Errorf("incorrect path tags: got %v", conf.PathTags)
+	}
+
+	v.Set("tag-mode", "flat")
+	if _, err := parseSpecificConfig(v); err == nil {
+		t.Errorf("expected an error for an invalid tag-mode")
+	}
+}
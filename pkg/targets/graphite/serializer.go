@@ -0,0 +1,89 @@
+package graphite
+
+import (
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+// Serializer writes a Point in the Graphite plaintext protocol, with the
+// tags in the format of Graphite 1.1 tagged series. Every field becomes a
+// series of its own, named <measurement>.<field>. The loader can flatten
+// the tags into the metric path for servers without tag support.
+type Serializer struct{}
+
+// Serialize writes Point data to the given writer.
+//
+// This function writes one line per field that looks like:
+// <measurement>.<field>;<tag key>=<tag value> <field value> <timestamp>\n
+//
+// For example:
+// cpu.usage_user;hostname=host_0 38.24 1451606400\n
+//
+// Timestamps are in seconds. Bools are written as 1 and 0, string fields
+// are left out since Graphite only stores numbers.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	tags := make([]byte, 0, 256)
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	for i, key := range tagKeys {
+		if tagValues[i] == nil {
+			continue
+		}
+		tags = append(tags, ';')
+		tags = appendSanitized(tags, key)
+		tags = append(tags, '=')
+		tags = appendSanitized(tags, serialize.FastFormatAppend(tagValues[i], nil))
+	}
+
+	buf := make([]byte, 0, 1024)
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	ts := p.Timestamp().UTC().Unix()
+	for i, key := range fieldKeys {
+		v := fieldValues[i]
+		switch v.(type) {
+		case nil:
+			continue
+		case string:
+			serialize.WarnSkippedField(constants.FormatGraphite, key, v)
+			continue
+		}
+
+		buf = appendSanitized(buf, p.MeasurementName())
+		buf = append(buf, '.')
+		buf = appendSanitized(buf, key)
+		buf = append(buf, tags...)
+		buf = append(buf, ' ')
+		if b, ok := v.(bool); ok {
+			if b {
+				buf = append(buf, '1')
+			} else {
+				buf = append(buf, '0')
+			}
+		} else {
+			buf = serialize.FastFormatAppend(v, buf)
+		}
+		buf = append(buf, ' ')
+		buf = serialize.FastFormatAppend(ts, buf)
+		buf = append(buf, '\n')
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendSanitized appends a series name or tag, replacing the characters
+// that separate the parts of a Graphite line with underscores.
+func appendSanitized(buf, s []byte) []byte {
+	for _, c := range s {
+		switch c {
+		case ' ', '\t', '\n', ';', '=', '~':
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
@@ -0,0 +1,52 @@
+package graphite
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestGraphiteSerializerSerialize(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu.usage_guest_nice;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     "cpu.usage_guest;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 38 1451606400\n",
+		},
+		{
+			Desc:       "a Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output: "cpu.big_usage_guest;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 5000000000 1451606400\n" +
+				"cpu.usage_guest;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 38 1451606400\n" +
+				"cpu.usage_guest_nice;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output: "cpu.alerting;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 1 1451606400\n" +
+				"cpu.usage_guest_nice;hostname=host_0;region=eu-west-1;datacenter=eu-west-1b 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with no tags",
+			InputPoint: serialize.TestPointNoTags(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "cpu.usage_guest_nice 38.24311829 1451606400\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
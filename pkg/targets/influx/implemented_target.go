@@ -23,6 +23,9 @@ func (t *influxTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.Fla
 	flagSet.String(flagPrefix+"consistency", "all", "Write consistency. Must be one of: any, one, quorum, all.")
 	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to sleep between requests when server indicates backpressure is needed.")
 	flagSet.Bool(flagPrefix+"gzip", true, "Whether to gzip encode requests (default true).")
+	flagSet.Int(flagPrefix+"api-version", 1, "InfluxDB API version, 1 or 2. Version 2 writes to the bucket named db-name.")
+	flagSet.String(flagPrefix+"org", "", "InfluxDB v2 organization owning the bucket (only applies to api-version 2).")
+	flagSet.String(flagPrefix+"token", "", "InfluxDB v2 API token (only applies to api-version 2).")
 }
 
 func (t *influxTarget) TargetName() string {
@@ -0,0 +1,137 @@
+package influx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// SeriesKey returns the measurement and tags of a line, i.e. everything up
+// to the first unescaped space.
+func SeriesKey(line []byte) []byte {
+	if i := indexUnquoted(line, ' ', false); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// CountFields counts the fields of a line.
+func CountFields(line []byte) uint64 {
+	keyEnd := indexUnquoted(line, ' ', false)
+	tsStart := bytes.LastIndexByte(line, ' ')
+	if keyEnd < 0 || tsStart <= keyEnd {
+		return 0
+	}
+	return uint64(len(splitUnquoted(line[keyEnd+1:tsStart], ',', true)))
+}
+
+// ParseLine parses a line of the InfluxDB line protocol into p, as written
+// by the Serializer:
+// <measurement>,<tag key>=<tag value> <field name>=<field value> <timestamp>
+func ParseLine(line []byte, p *data.Point) error {
+	p.Reset()
+	keyEnd := indexUnquoted(line, ' ', false)
+	tsStart := bytes.LastIndexByte(line, ' ')
+	if keyEnd < 0 || tsStart <= keyEnd {
+		return fmt.Errorf("invalid line: %s", line)
+	}
+	ns, err := strconv.ParseInt(string(line[tsStart+1:]), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp in line: %s", line)
+	}
+	ts := time.Unix(0, ns).UTC()
+	p.SetTimestamp(&ts)
+
+	key := splitUnquoted(line[:keyEnd], ',', false)
+	p.SetMeasurementName(unescape(key[0]))
+	for _, tag := range key[1:] {
+		eq := indexUnquoted(tag, '=', false)
+		if eq < 0 {
+			return fmt.Errorf("invalid tag %s in line: %s", tag, line)
+		}
+		p.AppendTag(unescape(tag[:eq]), string(unescape(tag[eq+1:])))
+	}
+
+	for _, field := range splitUnquoted(line[keyEnd+1:tsStart], ',', true) {
+		eq := indexUnquoted(field, '=', false)
+		if eq < 0 {
+			return fmt.Errorf("invalid field %s in line: %s", field, line)
+		}
+		v, err := parseFieldValue(field[eq+1:])
+		if err != nil {
+			return fmt.Errorf("invalid field %s in line: %s", field, line)
+		}
+		p.AppendField(unescape(field[:eq]), v)
+	}
+	return nil
+}
+
+func parseFieldValue(v []byte) (interface{}, error) {
+	if len(v) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	switch {
+	case v[0] == '"':
+		if len(v) < 2 || v[len(v)-1] != '"' {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		return string(unescape(v[1 : len(v)-1])), nil
+	case v[len(v)-1] == 'i':
+		return strconv.ParseInt(string(v[:len(v)-1]), 10, 64)
+	}
+	switch string(v) {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	return strconv.ParseFloat(string(v), 64)
+}
+
+// indexUnquoted returns the index of the first sep that is neither escaped
+// with a backslash nor, if quoted is set, in a double-quoted string.
+func indexUnquoted(b []byte, sep byte, quoted bool) int {
+	inQuotes := false
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; {
+		case c == '\\':
+			i++
+		case c == '"' && quoted:
+			inQuotes = !inQuotes
+		case c == sep && !inQuotes:
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnquoted splits b around every sep found by indexUnquoted.
+func splitUnquoted(b []byte, sep byte, quoted bool) [][]byte {
+	var parts [][]byte
+	for {
+		i := indexUnquoted(b, sep, quoted)
+		if i < 0 {
+			return append(parts, b)
+		}
+		parts = append(parts, b[:i])
+		b = b[i+1:]
+	}
+}
+
+// unescape removes the backslashes escaping characters.
+func unescape(b []byte) []byte {
+	if bytes.IndexByte(b, '\\') < 0 {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] == '\\' && i+1 < len(b) {
+			i++
+		}
+		out = append(out, b[i])
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package influx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+// serializeLine returns the line of the point as written to the data file,
+// without the trailing newline.
+func serializeLine(t *testing.T, p *data.Point) []byte {
+	b := new(bytes.Buffer)
+	if err := (&Serializer{}).Serialize(p, b); err != nil {
+		t.Fatalf("could not serialize: %v", err)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+func TestSeriesKeyAndCountFields(t *testing.T) {
+	cases := []struct {
+		desc       string
+		point      *data.Point
+		wantKey    string
+		wantFields uint64
+	}{
+		{
+			desc:       "multi field",
+			point:      serialize.TestPointMultiField(),
+			wantKey:    "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b",
+			wantFields: 3,
+		},
+		{
+			desc:       "string with spaces and commas",
+			point:      serialize.TestPointNonNumeric(),
+			wantKey:    "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b",
+			wantFields: 3,
+		},
+		{
+			desc:       "no tags",
+			point:      serialize.TestPointNoTags(),
+			wantKey:    "cpu",
+			wantFields: 1,
+		},
+	}
+	for _, c := range cases {
+		line := serializeLine(t, c.point)
+		if got := string(SeriesKey(line)); got != c.wantKey {
+			t.Errorf("%s: incorrect series key: got %s want %s", c.desc, got, c.wantKey)
+		}
+		if got := CountFields(line); got != c.wantFields {
+			t.Errorf("%s: incorrect field count: got %d want %d", c.desc, got, c.wantFields)
+		}
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	want := serialize.TestPointNonNumeric()
+	p := data.NewPoint()
+	if err := ParseLine(serializeLine(t, want), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(p.MeasurementName()); got != "cpu" {
+		t.Errorf("incorrect measurement: got %s", got)
+	}
+	if !p.Timestamp().Equal(serialize.TestNow) {
+		t.Errorf("incorrect timestamp: got %v want %v", p.Timestamp(), serialize.TestNow)
+	}
+	for i, key := range want.TagKeys() {
+		if string(p.TagKeys()[i]) != string(key) || p.TagValues()[i] != want.TagValues()[i] {
+			t.Errorf("incorrect tag %d: got %s=%v", i, p.TagKeys()[i], p.TagValues()[i])
+		}
+	}
+	for i, key := range want.FieldKeys() {
+		if string(p.FieldKeys()[i]) != string(key) || p.FieldValues()[i] != want.FieldValues()[i] {
+			t.Errorf("incorrect field %d: got %s=%v want %s=%v", i, p.FieldKeys()[i], p.FieldValues()[i], key, want.FieldValues()[i])
+		}
+	}
+
+	if err := ParseLine([]byte("cpu usage_user=1"), p); err == nil {
+		t.Errorf("expected an error for a line without timestamp")
+	}
+	if err := ParseLine([]byte("cpu usage_user=abc 1451606400000000000"), p); err == nil {
+		t.Errorf("expected an error for an invalid field value")
+	}
+}
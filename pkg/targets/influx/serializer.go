@@ -83,6 +83,19 @@ func appendField(buf, key []byte, v interface{}) []byte {
 	buf = append(buf, key...)
 	buf = append(buf, '=')
 
+	// Influx expects string values in double quotes, with quotes and
+	// backslashes escaped:
+	if str, ok := v.(string); ok {
+		buf = append(buf, '"')
+		for i := 0; i < len(str); i++ {
+			if str[i] == '"' || str[i] == '\\' {
+				buf = append(buf, '\\')
+			}
+			buf = append(buf, str[i])
+		}
+		return append(buf, '"')
+	}
+
 	buf = serialize.FastFormatAppend(v, buf)
 
 	// Influx uses 'i' to indicate integers:
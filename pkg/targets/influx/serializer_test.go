@@ -22,6 +22,11 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointMultiField(),
 			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b big_usage_guest=5000000000i,usage_guest=38i,usage_guest_nice=38.24311829 1451606400000000000\n",
 		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b last_error=\"disk \\\"C:\\\\\\\" is 95% full, cleanup failed\",alerting=true,usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
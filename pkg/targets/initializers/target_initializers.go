@@ -8,10 +8,19 @@ import (
 	"github.com/timescale/tsbs/pkg/targets/clickhouse"
 	"github.com/timescale/tsbs/pkg/targets/constants"
 	"github.com/timescale/tsbs/pkg/targets/crate"
+	"github.com/timescale/tsbs/pkg/targets/elasticsearch"
+	"github.com/timescale/tsbs/pkg/targets/filesink"
+	"github.com/timescale/tsbs/pkg/targets/graphite"
 	"github.com/timescale/tsbs/pkg/targets/influx"
+	"github.com/timescale/tsbs/pkg/targets/kafka"
 	"github.com/timescale/tsbs/pkg/targets/mongo"
+	"github.com/timescale/tsbs/pkg/targets/mqtt"
+	"github.com/timescale/tsbs/pkg/targets/opentsdb"
+	"github.com/timescale/tsbs/pkg/targets/otlp"
 	"github.com/timescale/tsbs/pkg/targets/prometheus"
+	"github.com/timescale/tsbs/pkg/targets/questdb"
 	"github.com/timescale/tsbs/pkg/targets/siridb"
+	"github.com/timescale/tsbs/pkg/targets/tdengine"
 	"github.com/timescale/tsbs/pkg/targets/timescaledb"
 	"github.com/timescale/tsbs/pkg/targets/timestream"
 	"github.com/timescale/tsbs/pkg/targets/victoriametrics"
@@ -42,6 +51,24 @@ func GetTarget(format string) targets.ImplementedTarget {
 		return victoriametrics.NewTarget()
 	case constants.FormatTimestream:
 		return timestream.NewTarget()
+	case constants.FormatQuestDB:
+		return questdb.NewTarget()
+	case constants.FormatGraphite:
+		return graphite.NewTarget()
+	case constants.FormatOpenTSDB:
+		return opentsdb.NewTarget()
+	case constants.FormatElasticsearch:
+		return elasticsearch.NewTarget()
+	case constants.FormatOTLP:
+		return otlp.NewTarget()
+	case constants.FormatKafka:
+		return kafka.NewTarget()
+	case constants.FormatFileSink:
+		return filesink.NewTarget()
+	case constants.FormatTDengine:
+		return tdengine.NewTarget()
+	case constants.FormatMQTT:
+		return mqtt.NewTarget()
 	}
 
 	supportedFormatsStr := strings.Join(constants.SupportedFormats(), ",")
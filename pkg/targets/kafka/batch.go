@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"hash/fnv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// batch holds the lines published in a single round of produce requests,
+// one after the other in buf.
+type batch struct {
+	buf     []byte
+	ends    []int
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(len(b.ends))
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	line := item.Data.([]byte)
+	b.metrics += influx.CountFields(line)
+	b.buf = append(b.buf, line...)
+	b.ends = append(b.ends, len(b.buf))
+}
+
+// lines calls fn with every line of the batch.
+func (b *batch) lines(fn func(line []byte) error) error {
+	start := 0
+	for _, end := range b.ends {
+		if err := fn(b.buf[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (b *batch) reset() {
+	b.buf = b.buf[:0]
+	b.ends = b.ends[:0]
+	b.metrics = 0
+}
+
+// seriesHash hashes the series key of a line. Workers and partitions are
+// both chosen by it, so that a series is always published by the same
+// worker to the same partition.
+func seriesHash(line []byte) uint32 {
+	return keyHash(influx.SeriesKey(line))
+}
+
+func keyHash(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+// seriesPartitioner publishes the records to the partition chosen by the
+// hash of their key, i.e. their series, so the records of a series are in
+// the same partition and keep their order.
+type seriesPartitioner struct{}
+
+func (seriesPartitioner) ForTopic(string) kgo.TopicPartitioner {
+	return seriesPartitioner{}
+}
+
+// RequiresConsistency keeps the records on the partition of their series
+// even while it is unavailable.
+func (seriesPartitioner) RequiresConsistency(*kgo.Record) bool {
+	return true
+}
+
+func (seriesPartitioner) Partition(r *kgo.Record, n int) int {
+	return int(keyHash(r.Key) % uint32(n))
+}
+
+// seriesIndexer sends all the points of a series to the same worker.
+type seriesIndexer struct {
+	partitions uint
+}
+
+func (i *seriesIndexer) GetIndex(item data.LoadedPoint) uint {
+	return uint(seriesHash(item.Data.([]byte))) % i.partitions
+}
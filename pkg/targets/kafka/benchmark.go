@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the Kafka specific loading options.
+type SpecificConfig struct {
+	Brokers     []string      `yaml:"brokers" mapstructure:"brokers"`
+	Topic       string        `yaml:"topic" mapstructure:"topic"`
+	Format      string        `yaml:"format" mapstructure:"format"`
+	Acks        int16         `yaml:"acks" mapstructure:"acks"`
+	Compression string        `yaml:"compression" mapstructure:"compression"`
+	ClientID    string        `yaml:"client-id" mapstructure:"client-id"`
+	Timeout     time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	Retries     int           `yaml:"retries" mapstructure:"retries"`
+	Backoff     time.Duration `yaml:"backoff" mapstructure:"backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Brokers) == 0 {
+		return nil, errors.New("at least one broker is required")
+	}
+	if _, err := newRecordFormat(conf.Format); err != nil {
+		return nil, err
+	}
+	if _, ok := compressionCodecs[conf.Compression]; !ok {
+		return nil, fmt.Errorf("unknown compression %s, must be %s, %s or %s", conf.Compression, compressionNone, compressionGzip, compressionSnappy)
+	}
+	if _, ok := requiredAcks[conf.Acks]; !ok {
+		return nil, fmt.Errorf("invalid acks %d, must be 0, 1 or -1", conf.Acks)
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+	batchPool  *sync.Pool
+}
+
+func NewBenchmark(kafkaSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for Kafka")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	batchPool := &sync.Pool{New: func() interface{} {
+		return &batch{buf: make([]byte, 0, 4*1024*1024)}
+	}}
+	return &benchmark{
+		conf:       kafkaSpecificConfig,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br)},
+		batchPool:  batchPool,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &seriesIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{conf: b.conf, batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
+
+type factory struct {
+	batchPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return f.batchPool.Get().(*batch)
+}
@@ -0,0 +1,14 @@
+package kafka
+
+// The records are published to an existing topic, or to one created by the
+// brokers on first use if auto topic creation is enabled. Kafka has no
+// database to create.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool { return true }
+
+func (d *dbCreator) CreateDB(dbName string) error { return nil }
+
+func (d *dbCreator) RemoveOldDB(dbName string) error { return nil }
@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &kafkaTarget{}
+}
+
+type kafkaTarget struct {
+}
+
+func (t *kafkaTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	kafkaSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(kafkaSpecificConfig, dataSourceConfig)
+}
+
+// Serializer writes the data in the InfluxDB line protocol, the lines are
+// converted to the configured wire format when published.
+func (t *kafkaTarget) Serializer() serialize.PointSerializer {
+	return &influx.Serializer{}
+}
+
+func (t *kafkaTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"brokers", "localhost:9092", "Comma-separated list of bootstrap brokers")
+	flagSet.String(flagPrefix+"topic", "tsbs", "Topic the records are published to")
+	flagSet.String(flagPrefix+"format", formatILP, "Wire format of the record values: ilp, json or prometheus")
+	flagSet.Int16(flagPrefix+"acks", 1, "Acknowledgements required by the brokers: 0 (none), 1 (leader) or -1 (all in-sync replicas)")
+	flagSet.String(flagPrefix+"compression", compressionNone, "Compression of the record batches: none, gzip or snappy")
+	flagSet.String(flagPrefix+"client-id", "tsbs", "Client id sent with every request")
+	flagSet.Duration(flagPrefix+"timeout", 10*time.Second, "Timeout of connecting to the brokers, also sent as the produce timeout")
+	flagSet.Int(flagPrefix+"retries", 5, "Times to retry the partitions failing with a retriable error, e.g. after a leader change")
+	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to wait before retrying, at least 10ms")
+}
+
+func (t *kafkaTarget) TargetName() string {
+	return constants.FormatKafka
+}
@@ -0,0 +1,127 @@
+package kafka
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Compression codecs of the record batches.
+const (
+	compressionNone   = "none"
+	compressionGzip   = "gzip"
+	compressionSnappy = "snappy"
+)
+
+var compressionCodecs = map[string]kgo.CompressionCodec{
+	compressionNone:   kgo.NoCompression(),
+	compressionGzip:   kgo.GzipCompression(),
+	compressionSnappy: kgo.SnappyCompression(),
+}
+
+var requiredAcks = map[int16]kgo.Acks{
+	0:  kgo.NoAck(),
+	1:  kgo.LeaderAck(),
+	-1: kgo.AllISRAcks(),
+}
+
+// processor publishes the batches of a worker to the topic with a client of
+// its own. Records are keyed by their series and partitioned by its hash,
+// see seriesPartitioner.
+type processor struct {
+	conf      *SpecificConfig
+	batchPool *sync.Pool
+
+	format recordFormat
+	client *kgo.Client
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	var err error
+	p.format, err = newRecordFormat(p.conf.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !doLoad {
+		return
+	}
+	p.client, err = kgo.NewClient(p.clientOpts(workerNum)...)
+	if err != nil {
+		log.Fatalf("could not create client: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.conf.Timeout)
+	defer cancel()
+	if err := p.client.Ping(ctx); err != nil {
+		log.Fatalf("could not connect to brokers %v: %v", p.conf.Brokers, err)
+	}
+}
+
+// clientOpts returns the options of the client of a worker. The workers
+// start from different brokers to fetch the metadata of the topic.
+func (p *processor) clientOpts(workerNum int) []kgo.Opt {
+	seeds := make([]string, len(p.conf.Brokers))
+	for i := range seeds {
+		seeds[i] = p.conf.Brokers[(workerNum+i)%len(p.conf.Brokers)]
+	}
+	backoff := p.conf.Backoff
+	return []kgo.Opt{
+		kgo.SeedBrokers(seeds...),
+		kgo.ClientID(p.conf.ClientID),
+		kgo.DefaultProduceTopic(p.conf.Topic),
+		kgo.AllowAutoTopicCreation(),
+		kgo.RecordPartitioner(seriesPartitioner{}),
+		kgo.RequiredAcks(requiredAcks[p.conf.Acks]),
+		// idempotent writes require acks from all in-sync replicas
+		kgo.DisableIdempotentWrite(),
+		kgo.ProducerBatchCompression(compressionCodecs[p.conf.Compression]),
+		kgo.DialTimeout(p.conf.Timeout),
+		kgo.ProduceRequestTimeout(p.conf.Timeout),
+		// the first try counts as well
+		kgo.RecordRetries(p.conf.Retries + 1),
+		kgo.RetryBackoffFn(func(int) time.Duration { return backoff }),
+		// retries refresh the metadata, e.g. to find the new leader
+		kgo.MetadataMinAge(backoff),
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		if err := p.publish(batch); err != nil {
+			log.Fatalf("could not publish batch: %v", err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, uint64(batch.Len())
+	batch.reset()
+	p.batchPool.Put(batch)
+	return metricCount, rowCount
+}
+
+// publish converts the lines of the batch to records and waits for all of
+// them to be published, or for the first to fail after the retries.
+func (p *processor) publish(b *batch) error {
+	records := make([]*kgo.Record, 0, b.Len())
+	err := b.lines(func(line []byte) error {
+		value, err := p.format.value(line)
+		if err != nil {
+			return err
+		}
+		records = append(records, &kgo.Record{Key: influx.SeriesKey(line), Value: value})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return p.client.ProduceSync(context.Background(), records...).FirstErr()
+}
+
+func (p *processor) Close(doLoad bool) {
+	if p.client != nil {
+		p.client.Close()
+	}
+}
@@ -0,0 +1,274 @@
+package kafka
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const testTopic = "tsbs"
+
+func newTestCluster(t *testing.T, partitions int32) *kfake.Cluster {
+	c, err := kfake.NewCluster(kfake.NumBrokers(2), kfake.SeedTopics(partitions, testTopic))
+	if err != nil {
+		t.Fatalf("could not start cluster: %v", err)
+	}
+	return c
+}
+
+func newTestBatch(lines ...string) *batch {
+	b := &batch{}
+	for _, line := range lines {
+		b.Append(data.NewLoadedPoint([]byte(line)))
+	}
+	return b
+}
+
+func testLines(hosts int) []string {
+	var lines []string
+	for i := 0; i < hosts; i++ {
+		lines = append(lines, fmt.Sprintf("cpu,hostname=host_%d usage_user=%di,usage_system=1.5 1451606400000000000", i, i))
+	}
+	return lines
+}
+
+func newTestProcessor(c *kfake.Cluster, conf SpecificConfig) *processor {
+	conf.Brokers = c.ListenAddrs()
+	conf.Topic = testTopic
+	conf.ClientID = "tsbs"
+	conf.Timeout = 5 * time.Second
+	conf.Backoff = 10 * time.Millisecond
+	if conf.Format == "" {
+		conf.Format = formatILP
+	}
+	if conf.Compression == "" {
+		conf.Compression = compressionNone
+	}
+	p := &processor{conf: &conf, batchPool: &sync.Pool{}}
+	p.Init(0, true, false)
+	return p
+}
+
+// consume reads n records from the start of the topic.
+func consume(t *testing.T, c *kfake.Cluster, n int) []*kgo.Record {
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumeTopics(testTopic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	if err != nil {
+		t.Fatalf("could not create consumer: %v", err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < n {
+		fetches := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("consumed %d records, want %d: %v", len(records), n, err)
+		}
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+// checkRecords checks that every line was published once, keyed by its
+// series, to the partition of the series.
+func checkRecords(t *testing.T, records []*kgo.Record, lines []string, partitions int32) {
+	published := map[string]bool{}
+	for _, r := range records {
+		if published[string(r.Value)] {
+			t.Errorf("record published more than once: %s", r.Value)
+		}
+		published[string(r.Value)] = true
+		if want := string(influx.SeriesKey(r.Value)); string(r.Key) != want {
+			t.Errorf("incorrect key: got %s want %s", r.Key, want)
+		}
+		if want := int32(seriesHash(r.Value) % uint32(partitions)); r.Partition != want {
+			t.Errorf("series %s published to partition %d, want %d", r.Key, r.Partition, want)
+		}
+	}
+	for _, line := range lines {
+		if !published[line] {
+			t.Errorf("line not published: %s", line)
+		}
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	for _, compression := range []string{compressionNone, compressionGzip, compressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			c := newTestCluster(t, 4)
+			defer c.Close()
+			p := newTestProcessor(c, SpecificConfig{Acks: 1, Compression: compression})
+			defer p.Close(true)
+
+			lines := testLines(20)
+			metrics, rows := p.ProcessBatch(newTestBatch(lines...), true)
+			if metrics != 40 || rows != 20 {
+				t.Errorf("incorrect counts: got %d metrics %d rows, want 40 metrics 20 rows", metrics, rows)
+			}
+
+			records := consume(t, c, len(lines))
+			checkRecords(t, records, lines, 4)
+			partitions := map[int32]bool{}
+			for _, r := range records {
+				partitions[r.Partition] = true
+			}
+			if len(partitions) < 2 {
+				t.Errorf("records should be spread over the partitions, got %d partitions", len(partitions))
+			}
+		})
+	}
+}
+
+// failProduce makes the cluster answer the next produce requests with the
+// error code for all partitions, instead of storing the records.
+func failProduce(c *kfake.Cluster, requests int, code int16) *int {
+	failed := 0
+	c.ControlKey(int16(kmsg.Produce), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		failed++
+		if failed < requests {
+			c.KeepControl()
+		}
+		req := kreq.(*kmsg.ProduceRequest)
+		resp := req.ResponseKind().(*kmsg.ProduceResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewProduceResponseTopic()
+			st.Topic = rt.Topic
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewProduceResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+	})
+	return &failed
+}
+
+func TestProcessorRetriesRetriableErrors(t *testing.T) {
+	c := newTestCluster(t, 2)
+	defer c.Close()
+	p := newTestProcessor(c, SpecificConfig{Acks: -1, Retries: 2})
+	defer p.Close(true)
+	failed := failProduce(c, 1, kerr.NotLeaderForPartition.Code)
+
+	lines := testLines(10)
+	if err := p.publish(newTestBatch(lines...)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *failed != 1 {
+		t.Errorf("produce request should have failed once, failed %d times", *failed)
+	}
+	checkRecords(t, consume(t, c, len(lines)), lines, 2)
+}
+
+func TestProcessorProduceErrors(t *testing.T) {
+	c := newTestCluster(t, 1)
+	defer c.Close()
+	p := newTestProcessor(c, SpecificConfig{Acks: 1, Retries: 1})
+	defer p.Close(true)
+
+	failProduce(c, 1, kerr.MessageTooLarge.Code)
+	if err := p.publish(newTestBatch(testLines(1)...)); err == nil {
+		t.Errorf("expected an error for a non-retriable error code")
+	}
+
+	failed := failProduce(c, 10, kerr.NotLeaderForPartition.Code)
+	if err := p.publish(newTestBatch(testLines(1)...)); err == nil {
+		t.Errorf("expected an error when out of retries")
+	}
+	if *failed != 2 {
+		t.Errorf("incorrect number of produce requests: got %d want 2", *failed)
+	}
+}
+
+func TestProcessorAcksZero(t *testing.T) {
+	c := newTestCluster(t, 3)
+	defer c.Close()
+	p := newTestProcessor(c, SpecificConfig{Acks: 0, Format: formatJSON})
+
+	lines := testLines(10)
+	p.ProcessBatch(newTestBatch(lines...), true)
+	p.ProcessBatch(newTestBatch(lines...), true)
+	p.Close(true)
+
+	records := consume(t, c, 2*len(lines))
+	for _, r := range records {
+		if want := int32(keyHash(r.Key) % 3); r.Partition != want {
+			t.Errorf("series %s published to partition %d, want %d", r.Key, r.Partition, want)
+		}
+		if !bytes.HasPrefix(r.Value, []byte(`{"measurement":"cpu"`)) {
+			t.Errorf("value is not in the json format: %s", r.Value)
+		}
+	}
+}
+
+func TestSeriesPartitioner(t *testing.T) {
+	tp := seriesPartitioner{}.ForTopic(testTopic)
+	if !tp.RequiresConsistency(&kgo.Record{}) {
+		t.Errorf("the records of a series should stay on their partition")
+	}
+	key := influx.SeriesKey([]byte("cpu,hostname=host_1 usage_user=1i 1451606400000000000"))
+	first := tp.Partition(&kgo.Record{Key: key}, 4)
+	for n := 0; n < 10; n++ {
+		if got := tp.Partition(&kgo.Record{Key: key}, 4); got != first {
+			t.Errorf("records of a series should go to the same partition, got %d and %d", first, got)
+		}
+	}
+	if want := int(seriesHash([]byte("cpu,hostname=host_1 usage_user=2i 1451606410000000000")) % 4); first != want {
+		t.Errorf("partition should follow the series hash used for the workers, got %d want %d", first, want)
+	}
+}
+
+func TestSeriesIndexer(t *testing.T) {
+	i := &seriesIndexer{partitions: 4}
+	first := i.GetIndex(data.NewLoadedPoint([]byte("cpu,hostname=host_1 usage_user=1i 1451606400000000000")))
+	second := i.GetIndex(data.NewLoadedPoint([]byte("cpu,hostname=host_1 usage_user=2i,usage_system=3i 1451606410000000000")))
+	if first != second {
+		t.Errorf("points of a series should go to the same worker, got %d and %d", first, second)
+	}
+	if first >= 4 {
+		t.Errorf("index out of range: %d", first)
+	}
+}
+
+func TestFileDataSourceAndBatch(t *testing.T) {
+	lines := testLines(3)
+	file := ""
+	for _, line := range lines {
+		file += line + "\n"
+	}
+	ds := &fileDataSource{scanner: bufio.NewScanner(bytes.NewBufferString(file))}
+	b := &batch{}
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		b.Append(item)
+	}
+	if b.Len() != 3 || b.metrics != 6 {
+		t.Errorf("incorrect counts: got %d rows %d metrics, want 3 rows 6 metrics", b.Len(), b.metrics)
+	}
+	var got []string
+	b.lines(func(line []byte) error {
+		got = append(got, string(line))
+		return nil
+	})
+	if fmt.Sprint(got) != fmt.Sprint(lines) {
+		t.Errorf("incorrect lines:\ngot\n%v\nwant\n%v", got, lines)
+	}
+}
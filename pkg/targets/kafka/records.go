@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+	"github.com/timescale/tsbs/pkg/targets/prometheus"
+)
+
+// Wire formats of the record values.
+const (
+	formatILP        = "ilp"
+	formatJSON       = "json"
+	formatPrometheus = "prometheus"
+)
+
+// recordFormat converts a line of the data file, in the InfluxDB line
+// protocol, into the value of a record.
+type recordFormat interface {
+	value(line []byte) ([]byte, error)
+}
+
+func newRecordFormat(name string) (recordFormat, error) {
+	switch name {
+	case formatILP:
+		return ilpFormat{}, nil
+	case formatJSON:
+		return &jsonFormat{p: data.NewPoint()}, nil
+	case formatPrometheus:
+		return &prometheusFormat{p: data.NewPoint()}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %s, must be %s, %s or %s", name, formatILP, formatJSON, formatPrometheus)
+	}
+}
+
+// ilpFormat publishes the lines as they are.
+type ilpFormat struct{}
+
+func (ilpFormat) value(line []byte) ([]byte, error) {
+	return line, nil
+}
+
+// jsonFormat publishes every line as a JSON object, see serialize.AppendJSON.
+type jsonFormat struct {
+	p *data.Point
+}
+
+func (f *jsonFormat) value(line []byte) ([]byte, error) {
+	if err := influx.ParseLine(line, f.p); err != nil {
+		return nil, err
+	}
+	return serialize.AppendJSON(make([]byte, 0, 2*len(line)), f.p), nil
+}
+
+// prometheusFormat publishes every line as a remote write request, holding
+// the same time series as the Prometheus data files.
+type prometheusFormat struct {
+	p *data.Point
+}
+
+func (f *prometheusFormat) value(line []byte) ([]byte, error) {
+	if err := influx.ParseLine(line, f.p); err != nil {
+		return nil, err
+	}
+	req, err := prometheus.ConvertToWriteRequest(f.p)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(req)
+}
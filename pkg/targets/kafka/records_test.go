@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/timescale/promscale/pkg/prompb"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// serializeLine returns the line of the point as written to the data file,
+// without the trailing newline.
+func serializeLine(t *testing.T, p *data.Point) []byte {
+	b := new(bytes.Buffer)
+	if err := (&influx.Serializer{}).Serialize(p, b); err != nil {
+		t.Fatalf("could not serialize: %v", err)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+func TestRecordFormats(t *testing.T) {
+	line := serializeLine(t, serialize.TestPointMultiField())
+
+	f, err := newRecordFormat(formatILP)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := f.value(line); !bytes.Equal(got, line) {
+		t.Errorf("incorrect ilp value: got %s", got)
+	}
+
+	f, _ = newRecordFormat(formatJSON)
+	got, err := f.value(line)
+	want := `{"measurement":"cpu","timestamp":1451606400000000000,` +
+		`"tags":{"hostname":"host_0","region":"eu-west-1","datacenter":"eu-west-1b"},` +
+		`"fields":{"big_usage_guest":5000000000,"usage_guest":38,"usage_guest_nice":38.24311829}}`
+	if err != nil || string(got) != want {
+		t.Errorf("incorrect json value:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	f, _ = newRecordFormat(formatPrometheus)
+	got, err = f.value(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var req prompb.WriteRequest
+	if err := proto.Unmarshal(got, &req); err != nil {
+		t.Fatalf("value is not a write request: %v", err)
+	}
+	if len(req.Timeseries) != 3 {
+		t.Errorf("incorrect number of series: got %d want 3", len(req.Timeseries))
+	}
+
+	if _, err := newRecordFormat("avro"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+func TestJSONFormatNonNumeric(t *testing.T) {
+	f, _ := newRecordFormat(formatJSON)
+	got, err := f.value(serializeLine(t, serialize.TestPointNonNumeric()))
+	want := `{"measurement":"cpu","timestamp":1451606400000000000,` +
+		`"tags":{"hostname":"host_0","region":"eu-west-1","datacenter":"eu-west-1b"},` +
+		`"fields":{"last_error":"disk \"C:\\\" is 95% full, cleanup failed","alerting":true,"usage_guest_nice":38.24311829}}`
+	if err != nil || string(got) != want {
+		t.Errorf("incorrect json value:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
 	"io"
 	"sync"
 
@@ -19,7 +21,8 @@ var fbBuilderPool = &sync.Pool{
 // Serializer writes a Point in a serialized form for MongoDB
 type Serializer struct{}
 
-// Serialize writes Point data to the given Writer, using basic gob encoding
+// Serialize writes Point data to the given Writer, using basic gob encoding.
+// Readings are stored as float64, so non-numeric fields are left out.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 	b := fbBuilderPool.Get().(*flatbuffers.Builder)
 
@@ -61,6 +64,10 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
 		if val == nil {
 			continue
 		}
+		if !serialize.IsNumeric(val) {
+			serialize.WarnSkippedField(constants.FormatMongo, fieldKeys[i-1], val)
+			continue
+		}
 		newField := createField(b, fieldKeys[i-1], val)
 		fields = append(fields, newField)
 	}
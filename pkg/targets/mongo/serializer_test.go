@@ -76,6 +76,18 @@ func TestMongoSerializerSerialize(t *testing.T) {
 				readingVals: serialize.TestPointNoTags().FieldValues(),
 			},
 		},
+		{
+			desc:       "a Point with string and bool fields",
+			inputPoint: serialize.TestPointNonNumeric(),
+			want: output{
+				name:        string(serialize.TestMeasurement),
+				ts:          serialize.TestNow.UnixNano(),
+				tagKeys:     serialize.TestTagKeys,
+				tagVals:     serialize.TestTagVals,
+				readingKeys: [][]byte{serialize.TestPointDefault().FieldKeys()[0]},
+				readingVals: []interface{}{serialize.TestPointDefault().FieldValues()[0]},
+			},
+		},
 	}
 
 	ps := &Serializer{}
@@ -180,22 +192,13 @@ func deserializeMongo(r *bufio.Reader) *MongoPoint {
 }
 
 func TestMongoSerializerTypePanic(t *testing.T) {
-	testPanic := func() {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Errorf("did not panic when should")
-			}
-		}()
-		p := &data.Point{}
-		p.SetMeasurementName(serialize.TestMeasurement)
-		p.SetTimestamp(&serialize.TestNow)
-		p.AppendField([]byte("broken"), "a string?")
-		ps := &Serializer{}
-		b := new(bytes.Buffer)
-
-		ps.Serialize(p, b)
-	}
-	testPanic()
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("did not panic when should")
+		}
+	}()
+	b := flatbuffers.NewBuilder(0)
+	prependValue(b, "a string?")
 }
 
 func TestMongoSerializerSerializeErr(t *testing.T) {
@@ -0,0 +1,57 @@
+package mqtt
+
+import (
+	"hash/fnv"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// batch holds the lines published by a worker in one go, one after the
+// other in buf.
+type batch struct {
+	buf     []byte
+	ends    []int
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(len(b.ends))
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	line := item.Data.([]byte)
+	b.metrics += influx.CountFields(line)
+	b.buf = append(b.buf, line...)
+	b.ends = append(b.ends, len(b.buf))
+}
+
+// lines calls fn with every line of the batch.
+func (b *batch) lines(fn func(line []byte) error) error {
+	start := 0
+	for _, end := range b.ends {
+		if err := fn(b.buf[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+func (b *batch) reset() {
+	b.buf = b.buf[:0]
+	b.ends = b.ends[:0]
+	b.metrics = 0
+}
+
+// seriesIndexer sends all the points of a series to the same worker, so
+// that the messages of a topic are published in order by one client.
+type seriesIndexer struct {
+	partitions uint
+}
+
+func (i *seriesIndexer) GetIndex(item data.LoadedPoint) uint {
+	h := fnv.New32a()
+	h.Write(influx.SeriesKey(item.Data.([]byte)))
+	return uint(h.Sum32()) % i.partitions
+}
@@ -0,0 +1,104 @@
+package mqtt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the MQTT specific loading options.
+type SpecificConfig struct {
+	Brokers     []string      `yaml:"brokers" mapstructure:"brokers"`
+	Topic       string        `yaml:"topic" mapstructure:"topic"`
+	Format      string        `yaml:"format" mapstructure:"format"`
+	QoS         byte          `yaml:"qos" mapstructure:"qos"`
+	ClientID    string        `yaml:"client-id" mapstructure:"client-id"`
+	User        string        `yaml:"user" mapstructure:"user"`
+	Pass        string        `yaml:"pass" mapstructure:"pass"`
+	MaxInflight int           `yaml:"max-inflight" mapstructure:"max-inflight"`
+	Timeout     time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Brokers) == 0 {
+		return nil, errors.New("at least one broker is required")
+	}
+	if _, err := parseTopicTemplate(conf.Topic); err != nil {
+		return nil, err
+	}
+	if _, err := newPayloadFormat(conf.Format); err != nil {
+		return nil, err
+	}
+	if conf.QoS > 2 {
+		return nil, fmt.Errorf("invalid qos %d, must be 0, 1 or 2", conf.QoS)
+	}
+	if conf.MaxInflight < 1 {
+		return nil, fmt.Errorf("invalid max-inflight %d, must be at least 1", conf.MaxInflight)
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+	batchPool  *sync.Pool
+}
+
+func NewBenchmark(mqttSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for MQTT")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	batchPool := &sync.Pool{New: func() interface{} {
+		return &batch{buf: make([]byte, 0, 4*1024*1024)}
+	}}
+	return &benchmark{
+		conf:       mqttSpecificConfig,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br)},
+		batchPool:  batchPool,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &seriesIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{conf: b.conf, batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
+
+type factory struct {
+	batchPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return f.batchPool.Get().(*batch)
+}
@@ -0,0 +1,13 @@
+package mqtt
+
+// The messages are published to topics the broker creates on first use,
+// MQTT has no database to create.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool { return true }
+
+func (d *dbCreator) CreateDB(dbName string) error { return nil }
+
+func (d *dbCreator) RemoveOldDB(dbName string) error { return nil }
@@ -0,0 +1,51 @@
+package mqtt
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &mqttTarget{}
+}
+
+type mqttTarget struct {
+}
+
+func (t *mqttTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	mqttSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(mqttSpecificConfig, dataSourceConfig)
+}
+
+// Serializer writes the data in the InfluxDB line protocol, the lines are
+// converted to the configured payload format when published.
+func (t *mqttTarget) Serializer() serialize.PointSerializer {
+	return &influx.Serializer{}
+}
+
+func (t *mqttTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"brokers", "localhost:1883", "Comma-separated list of brokers, as host:port or URLs, workers connect to them in a round robin fashion")
+	flagSet.String(flagPrefix+"topic", "fleet/{fleet}/truck/{name}/{measurement}", "Template of the topics, {measurement} and {<tag>} are replaced by the measurement and tag values of a point")
+	flagSet.String(flagPrefix+"format", formatJSON, "Format of the message payloads: json or ilp")
+	flagSet.Uint8(flagPrefix+"qos", 1, "QoS level of the messages: 0, 1 or 2")
+	flagSet.String(flagPrefix+"client-id", "tsbs", "Prefix of the client ids, followed by the number of the worker")
+	flagSet.String(flagPrefix+"user", "", "User name to connect with")
+	flagSet.String(flagPrefix+"pass", "", "Password to connect with")
+	flagSet.Int(flagPrefix+"max-inflight", 100, "Messages a worker publishes before waiting for the oldest to be acknowledged")
+	flagSet.Duration(flagPrefix+"timeout", 10*time.Second, "Timeout of the connection and of the acknowledgements")
+}
+
+func (t *mqttTarget) TargetName() string {
+	return constants.FormatMQTT
+}
@@ -0,0 +1,47 @@
+package mqtt
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+// Formats of the message payloads.
+const (
+	formatILP  = "ilp"
+	formatJSON = "json"
+)
+
+// payloadFormat converts a line of the data file, in the InfluxDB line
+// protocol, and the point parsed from it into the payload of a message.
+type payloadFormat interface {
+	payload(line []byte, p *data.Point) []byte
+}
+
+func newPayloadFormat(name string) (payloadFormat, error) {
+	switch name {
+	case formatILP:
+		return ilpFormat{}, nil
+	case formatJSON:
+		return jsonFormat{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %s, must be %s or %s", name, formatILP, formatJSON)
+	}
+}
+
+// ilpFormat publishes the lines as they are.
+type ilpFormat struct{}
+
+func (ilpFormat) payload(line []byte, _ *data.Point) []byte {
+	return line
+}
+
+// jsonFormat publishes every point as a JSON object, see
+// serialize.AppendJSON. Every payload has a buffer of its own, since the
+// client writes it once the call has returned.
+type jsonFormat struct{}
+
+func (jsonFormat) payload(_ []byte, p *data.Point) []byte {
+	return serialize.AppendJSON(nil, p)
+}
@@ -0,0 +1,122 @@
+package mqtt
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// processor publishes every line of the batches of a worker as a message,
+// with a client of its own. A batch is processed once all its messages are
+// acknowledged, or written to the connection for QoS 0.
+type processor struct {
+	conf      *SpecificConfig
+	batchPool *sync.Pool
+
+	template *topicTemplate
+	format   payloadFormat
+	p        *data.Point
+	client   paho.Client
+	// pending holds the tokens of the messages not acknowledged yet, in
+	// the order they were published
+	pending []paho.Token
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	var err error
+	p.format, err = newPayloadFormat(p.conf.Format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p.template, err = parseTopicTemplate(p.conf.Topic)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p.p = data.NewPoint()
+	if !doLoad {
+		return
+	}
+	address := p.conf.Brokers[workerNum%len(p.conf.Brokers)]
+	p.client = paho.NewClient(p.clientOptions(address, workerNum))
+	if err := p.wait(p.client.Connect()); err != nil {
+		log.Fatalf("could not connect to broker %s: %v", address, err)
+	}
+}
+
+// clientOptions returns the options of the client of a worker. Brokers
+// without a scheme are reached over plain TCP.
+func (p *processor) clientOptions(address string, workerNum int) *paho.ClientOptions {
+	if !strings.Contains(address, "://") {
+		address = "tcp://" + address
+	}
+	return paho.NewClientOptions().
+		AddBroker(address).
+		SetClientID(fmt.Sprintf("%s-%d", p.conf.ClientID, workerNum)).
+		SetUsername(p.conf.User).
+		SetPassword(p.conf.Pass).
+		SetCleanSession(true).
+		SetConnectTimeout(p.conf.Timeout).
+		SetWriteTimeout(p.conf.Timeout).
+		// a lost connection fails the load rather than losing messages
+		SetAutoReconnect(false).
+		SetConnectRetry(false)
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		if err := p.publish(batch); err != nil {
+			log.Fatalf("could not publish batch: %v", err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, uint64(batch.Len())
+	batch.reset()
+	p.batchPool.Put(batch)
+	return metricCount, rowCount
+}
+
+// publish publishes the lines of the batch, waiting for the oldest message
+// whenever max-inflight messages are pending, and then for all of them.
+func (p *processor) publish(b *batch) error {
+	err := b.lines(func(line []byte) error {
+		if err := influx.ParseLine(line, p.p); err != nil {
+			return err
+		}
+		if len(p.pending) == p.conf.MaxInflight {
+			if err := p.wait(p.pending[0]); err != nil {
+				return err
+			}
+			p.pending = p.pending[1:]
+		}
+		token := p.client.Publish(p.template.topic(p.p), p.conf.QoS, false, p.format.payload(line, p.p))
+		p.pending = append(p.pending, token)
+		return nil
+	})
+	for _, token := range p.pending {
+		if err == nil {
+			err = p.wait(token)
+		}
+	}
+	p.pending = p.pending[:0]
+	return err
+}
+
+// wait waits for the token to complete, for at most the timeout.
+func (p *processor) wait(token paho.Token) error {
+	if !token.WaitTimeout(p.conf.Timeout) {
+		return fmt.Errorf("no acknowledgement within %v", p.conf.Timeout)
+	}
+	return token.Error()
+}
+
+func (p *processor) Close(doLoad bool) {
+	if p.client != nil {
+		p.client.Disconnect(uint(p.conf.Timeout.Milliseconds()))
+	}
+}
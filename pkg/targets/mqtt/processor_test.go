@@ -0,0 +1,374 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	mochipackets "github.com/mochi-mqtt/server/v2/packets"
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// trucks are readings and diagnostics of the iot use case, the last truck
+// does not report its name.
+const trucks = `readings,name=truck_0,fleet=South,driver=Trish velocity=58i,fuel_consumption=12.5 1451606400000000000
+diagnostics,name=truck_0,fleet=South,driver=Trish fuel_state=0.7,status=0i 1451606400000000000
+readings,name=truck_1,fleet=North/East,driver=Derek velocity=31i,fuel_consumption=9.8 1451606400000000000
+diagnostics,fleet=West current_load=1200i,status="ok" 1451606400000000000
+`
+
+// published is a message received by the broker.
+type published struct {
+	clientID string
+	topic    string
+	payload  string
+	qos      byte
+}
+
+// recorder records the messages published to the broker. If hold is set,
+// the broker does not acknowledge the first message until it is closed.
+type recorder struct {
+	mochi.HookBase
+	hold chan struct{}
+
+	mu        sync.Mutex
+	messages  []published
+	delivered chan struct{}
+}
+
+func (r *recorder) ID() string {
+	return "recorder"
+}
+
+func (r *recorder) Provides(b byte) bool {
+	return b == mochi.OnPublish
+}
+
+func (r *recorder) OnPublish(cl *mochi.Client, pk mochipackets.Packet) (mochipackets.Packet, error) {
+	if r.hold != nil {
+		<-r.hold
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, published{
+		clientID: cl.ID,
+		topic:    pk.TopicName,
+		payload:  string(pk.Payload),
+		qos:      pk.FixedHeader.Qos,
+	})
+	select {
+	case r.delivered <- struct{}{}:
+	default:
+	}
+	return pk, nil
+}
+
+// waitMessages waits for the broker to receive n messages, the messages of
+// QoS 0 arrive after they have been published.
+func (r *recorder) waitMessages(t *testing.T, n int) []published {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		r.mu.Lock()
+		messages := r.messages
+		r.mu.Unlock()
+		if len(messages) >= n {
+			return messages
+		}
+		select {
+		case <-r.delivered:
+		case <-deadline:
+			t.Fatalf("broker received %d messages, want %d", len(messages), n)
+		}
+	}
+}
+
+// startBroker starts an embedded broker with the given authentication
+// hook, listening on a random port, and returns its address.
+func startBroker(t *testing.T, authHook mochi.Hook, authConfig any, r *recorder) string {
+	t.Helper()
+	server := mochi.New(&mochi.Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := server.AddHook(authHook, authConfig); err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		r.delivered = make(chan struct{}, 1)
+		if err := server.AddHook(r, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := server.AddListener(tcp); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return tcp.Address()
+}
+
+// readBatch reads the lines with the data source of the target into a
+// batch.
+func readBatch(lines string) *batch {
+	ds := &fileDataSource{scanner: bufio.NewScanner(strings.NewReader(lines))}
+	b := &batch{}
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		b.Append(item)
+	}
+	return b
+}
+
+// connectProcessor starts a processor loading to the broker, with the
+// flag defaults unless conf overrides them.
+func connectProcessor(t *testing.T, address string, conf SpecificConfig) *processor {
+	t.Helper()
+	conf.Brokers = []string{address}
+	if conf.Topic == "" {
+		conf.Topic = "fleet/{fleet}/truck/{name}/{measurement}"
+	}
+	if conf.Format == "" {
+		conf.Format = formatJSON
+	}
+	if conf.ClientID == "" {
+		conf.ClientID = "tsbs"
+	}
+	if conf.MaxInflight == 0 {
+		conf.MaxInflight = 100
+	}
+	conf.Timeout = 5 * time.Second
+	p := &processor{conf: &conf, batchPool: &sync.Pool{}}
+	p.Init(3, true, false)
+	t.Cleanup(func() { p.Close(true) })
+	return p
+}
+
+func TestProcessorPublish(t *testing.T) {
+	topics := []string{
+		"fleet/South/truck/truck_0/readings",
+		"fleet/South/truck/truck_0/diagnostics",
+		"fleet/North_East/truck/truck_1/readings",
+		"fleet/West/truck/unknown/diagnostics",
+	}
+	lines := strings.Split(strings.TrimSpace(trucks), "\n")
+	for _, format := range []string{formatILP, formatJSON} {
+		for qos := byte(0); qos <= 2; qos++ {
+			t.Run(fmt.Sprintf("%s/qos%d", format, qos), func(t *testing.T) {
+				r := &recorder{}
+				address := startBroker(t, new(auth.AllowHook), nil, r)
+				p := connectProcessor(t, address, SpecificConfig{Format: format, QoS: qos})
+
+				metrics, rows := p.ProcessBatch(readBatch(trucks), true)
+				if metrics != 8 || rows != 4 {
+					t.Errorf("incorrect counts: got %d metrics and %d rows, want 8 and 4", metrics, rows)
+				}
+				messages := r.waitMessages(t, len(lines))
+				if len(messages) != len(lines) {
+					t.Fatalf("incorrect number of messages: got %d want %d", len(messages), len(lines))
+				}
+				for i, m := range messages {
+					if m.clientID != "tsbs-3" {
+						t.Errorf("incorrect client id: got %s want tsbs-3", m.clientID)
+					}
+					if m.qos != qos {
+						t.Errorf("incorrect qos: got %d want %d", m.qos, qos)
+					}
+					if m.topic != topics[i] {
+						t.Errorf("incorrect topic: got %s want %s", m.topic, topics[i])
+					}
+					if format == formatILP && m.payload != lines[i] {
+						t.Errorf("incorrect payload: got %s want %s", m.payload, lines[i])
+					}
+					if format == formatJSON && !json.Valid([]byte(m.payload)) {
+						t.Errorf("payload is not JSON: %s", m.payload)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestProcessorJSONPayload(t *testing.T) {
+	r := &recorder{}
+	address := startBroker(t, new(auth.AllowHook), nil, r)
+	p := connectProcessor(t, address, SpecificConfig{QoS: 1, Topic: "trucks/{name}"})
+	p.ProcessBatch(readBatch(`diagnostics,name=truck_7,fleet=East load=1500i,status="ok" 1451606410000000000`), true)
+
+	messages := r.waitMessages(t, 1)
+	if messages[0].topic != "trucks/truck_7" {
+		t.Errorf("incorrect topic: got %s want trucks/truck_7", messages[0].topic)
+	}
+	var got struct {
+		Measurement string
+		Timestamp   int64
+		Tags        map[string]string
+		Fields      map[string]interface{}
+	}
+	if err := json.Unmarshal([]byte(messages[0].payload), &got); err != nil {
+		t.Fatalf("payload is not JSON: %v: %s", err, messages[0].payload)
+	}
+	if got.Measurement != "diagnostics" || got.Timestamp != 1451606410000000000 {
+		t.Errorf("incorrect measurement or timestamp: %s", messages[0].payload)
+	}
+	if fmt.Sprint(got.Tags) != "map[fleet:East name:truck_7]" {
+		t.Errorf("incorrect tags: %v", got.Tags)
+	}
+	if fmt.Sprint(got.Fields) != "map[load:1500 status:ok]" {
+		t.Errorf("incorrect fields: %v", got.Fields)
+	}
+}
+
+// publishCounter counts the PUBLISH packets written by a client.
+type publishCounter struct {
+	net.Conn
+	publishes *int32
+}
+
+func (c publishCounter) Write(b []byte) (int, error) {
+	if len(b) > 0 && b[0]>>4 == packets.Publish {
+		atomic.AddInt32(c.publishes, 1)
+	}
+	return c.Conn.Write(b)
+}
+
+func TestProcessorMaxInflight(t *testing.T) {
+	r := &recorder{hold: make(chan struct{})}
+	address := startBroker(t, new(auth.AllowHook), nil, r)
+	conf := &SpecificConfig{Topic: "tsbs/{name}", Format: formatILP, QoS: 1, ClientID: "tsbs", MaxInflight: 2, Timeout: 5 * time.Second}
+	p := &processor{conf: conf}
+	p.Init(0, false, false)
+	var publishes int32
+	opts := p.clientOptions(address, 0).SetCustomOpenConnectionFn(func(uri *url.URL, _ paho.ClientOptions) (net.Conn, error) {
+		conn, err := net.Dial("tcp", uri.Host)
+		return publishCounter{Conn: conn, publishes: &publishes}, err
+	})
+	p.client = paho.NewClient(opts)
+	if err := p.wait(p.client.Connect()); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close(true)
+
+	var sb strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&sb, "readings,name=truck_%d velocity=%di 1451606400000000000\n", i%5, i)
+	}
+	done := make(chan error)
+	go func() { done <- p.publish(readBatch(sb.String())) }()
+
+	// the client has to wait for the acknowledgement of the first message
+	time.Sleep(200 * time.Millisecond)
+	if got := atomic.LoadInt32(&publishes); got != 2 {
+		t.Errorf("incorrect number of messages published before an acknowledgement: got %d want 2", got)
+	}
+	close(r.hold)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range r.waitMessages(t, 50) {
+		if want := fmt.Sprintf("velocity=%di", i); !strings.Contains(m.payload, want) {
+			t.Errorf("message %d out of order: %s", i, m.payload)
+		}
+	}
+}
+
+func TestProcessorConnect(t *testing.T) {
+	ledger := &auth.Options{Ledger: &auth.Ledger{Auth: auth.AuthRules{
+		{Username: "tsbs", Password: "secret", Allow: true},
+	}}}
+	address := startBroker(t, new(auth.Hook), ledger, nil)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := l.Addr().String()
+	l.Close()
+
+	cases := []struct {
+		desc    string
+		address string
+		pass    string
+		err     string
+	}{
+		{desc: "authenticated", address: address, pass: "secret"},
+		{desc: "with scheme", address: "tcp://" + address, pass: "secret"},
+		{desc: "wrong password", address: address, pass: "wrong", err: packets.ErrorRefusedNotAuthorised.Error()},
+		{desc: "no broker", address: closed, pass: "secret", err: "connection refused"},
+	}
+	for _, c := range cases {
+		p := &processor{conf: &SpecificConfig{ClientID: "tsbs", User: "tsbs", Pass: c.pass, Timeout: time.Second}}
+		client := paho.NewClient(p.clientOptions(c.address, 0))
+		err := p.wait(client.Connect())
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		} else if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: expected error %q, got %v", c.desc, c.err, err)
+		}
+		if err == nil {
+			client.Disconnect(100)
+		}
+	}
+}
+
+func TestTopicTemplate(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("diagnostics"))
+	p.AppendTag([]byte("name"), "truck/1+#")
+	p.AppendTag([]byte("fleet"), "")
+
+	cases := []struct {
+		template string
+		want     string
+		err      bool
+	}{
+		{template: "fleet/{fleet}/truck/{name}/{measurement}", want: "fleet/unknown/truck/truck_1__/diagnostics"},
+		{template: "tsbs/{driver}", want: "tsbs/unknown"},
+		{template: "tsbs", want: "tsbs"},
+		{template: "tsbs/{name", err: true},
+		{template: "tsbs/name}", err: true},
+		{template: "tsbs/{}", err: true},
+		{template: "tsbs/#", err: true},
+	}
+	for _, c := range cases {
+		template, err := parseTopicTemplate(c.template)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected error", c.template)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.template, err)
+			continue
+		}
+		if got := template.topic(p); got != c.want {
+			t.Errorf("%s: incorrect topic: got %s want %s", c.template, got, c.want)
+		}
+	}
+}
+
+func TestSeriesIndexer(t *testing.T) {
+	i := &seriesIndexer{partitions: 4}
+	first := i.GetIndex(data.NewLoadedPoint([]byte("readings,name=truck_1 velocity=1i 1451606400000000000")))
+	second := i.GetIndex(data.NewLoadedPoint([]byte("readings,name=truck_1 velocity=2i,fuel_state=3i 1451606410000000000")))
+	if first != second {
+		t.Errorf("points of a series should go to the same worker, got %d and %d", first, second)
+	}
+	if first >= 4 {
+		t.Errorf("index out of range: %d", first)
+	}
+}
@@ -0,0 +1,81 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// placeholderMeasurement is replaced by the measurement of a point, any
+// other placeholder by the value of the tag of the same name.
+const placeholderMeasurement = "measurement"
+
+// unknownTagValue replaces the tags a point does not have, e.g. the name of
+// a truck that is not reported.
+const unknownTagValue = "unknown"
+
+// topicTemplate derives the topic of a point from its measurement and tags,
+// e.g. fleet/{fleet}/truck/{name}/{measurement}.
+type topicTemplate struct {
+	// parts alternates literal text and placeholder names, starting with
+	// literal text
+	parts []string
+}
+
+func parseTopicTemplate(s string) (*topicTemplate, error) {
+	t := &topicTemplate{}
+	rest := s
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced '}' in topic template %s", s)
+			}
+			t.parts = append(t.parts, rest)
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed '{' in topic template %s", s)
+		}
+		name := rest[open+1 : open+end]
+		if name == "" || strings.ContainsAny(name, "{/") {
+			return nil, fmt.Errorf("invalid placeholder {%s} in topic template %s", name, s)
+		}
+		if strings.IndexByte(rest[:open], '}') >= 0 {
+			return nil, fmt.Errorf("unbalanced '}' in topic template %s", s)
+		}
+		t.parts = append(t.parts, rest[:open], name)
+		rest = rest[open+end+1:]
+	}
+	if strings.ContainsAny(strings.Join(t.parts, ""), "+#") {
+		return nil, fmt.Errorf("topic template %s must not contain wildcards", s)
+	}
+	return t, nil
+}
+
+// topic returns the topic of a point. Tag values are sanitized so they
+// stay a single topic level.
+func (t *topicTemplate) topic(p *data.Point) string {
+	var sb strings.Builder
+	for i, part := range t.parts {
+		if i%2 == 0 {
+			sb.WriteString(part)
+			continue
+		}
+		value := ""
+		if part == placeholderMeasurement {
+			value = string(p.MeasurementName())
+		} else if v := p.GetTagValue([]byte(part)); v != nil {
+			value = fmt.Sprint(v)
+		}
+		if value == "" {
+			value = unknownTagValue
+		}
+		sb.WriteString(levelEscaper.Replace(value))
+	}
+	return sb.String()
+}
+
+var levelEscaper = strings.NewReplacer("/", "_", "+", "_", "#", "_")
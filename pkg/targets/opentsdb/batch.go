@@ -0,0 +1,92 @@
+package opentsdb
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const errNotPutFmt = "parse error: line is not a put with a metric, timestamp and value: %s"
+
+var (
+	spaceSep = []byte(" ")
+	newLine  = []byte("\n")
+	putCmd   = []byte("put ")
+)
+
+// batch holds the telnet put lines sent to OpenTSDB in a single write.
+// Every line holds a single value, so the metric count equals the row count.
+type batch struct {
+	buf     *bytes.Buffer
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	that := item.Data.([]byte)
+	b.rows++
+	b.metrics++
+
+	// Each line is format "put metric timestamp value tags..."
+	if !bytes.HasPrefix(that, putCmd) || bytes.Count(that, spaceSep) < 3 {
+		log.Fatalf(errNotPutFmt, that)
+		return
+	}
+
+	b.buf.Write(that)
+	b.buf.Write(newLine)
+}
+
+// appendJSON appends the put lines of the batch as a JSON array of data
+// points for the HTTP /api/put endpoint, e.g.
+// [{"metric":"cpu.usage_user","timestamp":1451606400000,"value":38.24,"tags":{"hostname":"host_0"}}]
+func appendJSON(buf []byte, lines []byte) []byte {
+	buf = append(buf, '[')
+	first := true
+	for len(lines) > 0 {
+		var line []byte
+		if end := bytes.IndexByte(lines, '\n'); end >= 0 {
+			line, lines = lines[:end], lines[end+1:]
+		} else {
+			line, lines = lines, nil
+		}
+		if len(line) == 0 {
+			continue
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+
+		parts := bytes.Split(line[len(putCmd):], spaceSep)
+		buf = append(buf, `{"metric":`...)
+		buf = strconv.AppendQuote(buf, string(parts[0]))
+		buf = append(buf, `,"timestamp":`...)
+		buf = append(buf, parts[1]...)
+		buf = append(buf, `,"value":`...)
+		buf = append(buf, parts[2]...)
+		buf = append(buf, `,"tags":{`...)
+		firstTag := true
+		for _, tag := range parts[3:] {
+			eq := bytes.IndexByte(tag, '=')
+			if eq < 0 {
+				continue
+			}
+			if !firstTag {
+				buf = append(buf, ',')
+			}
+			firstTag = false
+			buf = strconv.AppendQuote(buf, string(tag[:eq]))
+			buf = append(buf, ':')
+			buf = strconv.AppendQuote(buf, string(tag[eq+1:]))
+		}
+		buf = append(buf, "}}"...)
+	}
+	return append(buf, ']')
+}
@@ -0,0 +1,92 @@
+package opentsdb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the OpenTSDB specific loading options.
+type SpecificConfig struct {
+	Protocol         string        `yaml:"protocol" mapstructure:"protocol"`
+	Address          string        `yaml:"address" mapstructure:"address"`
+	URL              string        `yaml:"url" mapstructure:"url"`
+	ReconnectRetries int           `yaml:"reconnect-retries" mapstructure:"reconnect-retries"`
+	ReconnectBackoff time.Duration `yaml:"reconnect-backoff" mapstructure:"reconnect-backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if conf.Protocol != protocolTelnet && conf.Protocol != protocolHTTP {
+		return nil, fmt.Errorf("invalid protocol %s, must be %s or %s", conf.Protocol, protocolTelnet, protocolHTTP)
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+}
+
+func NewBenchmark(openTSDBSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for OpenTSDB")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	return &benchmark{
+		conf:       openTSDBSpecificConfig,
+		dataSource: &fileDataSource{scanner: bufio.NewScanner(br)},
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &factory{bufPool: &bufPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		protocol: b.conf.Protocol,
+		address:  b.conf.Address,
+		url:      b.conf.URL,
+		retries:  b.conf.ReconnectRetries,
+		backoff:  b.conf.ReconnectBackoff,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
+
+type factory struct {
+	bufPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
+}
@@ -0,0 +1,14 @@
+package opentsdb
+
+// OpenTSDB doesn't have a database abstraction, metrics and tags are
+// assigned UIDs on the first write when tsd.core.auto_create_metrics is
+// enabled.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool { return true }
+
+func (d *dbCreator) CreateDB(dbName string) error { return nil }
+
+func (d *dbCreator) RemoveOldDB(dbName string) error { return nil }
@@ -0,0 +1,44 @@
+package opentsdb
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &openTSDBTarget{}
+}
+
+type openTSDBTarget struct {
+}
+
+func (t *openTSDBTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	openTSDBSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(openTSDBSpecificConfig, dataSourceConfig)
+}
+
+func (t *openTSDBTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *openTSDBTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"protocol", protocolTelnet, "Protocol to send the data points with: 'telnet' put lines over TCP or 'http' JSON")
+	flagSet.String(flagPrefix+"address", "localhost:4242", "OpenTSDB telnet TCP address, used with protocol 'telnet'")
+	flagSet.String(flagPrefix+"url", "http://localhost:4242/api/put", "OpenTSDB HTTP put URL, used with protocol 'http'")
+	flagSet.Int(flagPrefix+"reconnect-retries", 5, "Times to reconnect and resend a batch after a failed telnet write")
+	flagSet.Duration(flagPrefix+"reconnect-backoff", time.Second, "Time to wait before reconnecting, doubled with every retry")
+}
+
+func (t *openTSDBTarget) TargetName() string {
+	return constants.FormatOpenTSDB
+}
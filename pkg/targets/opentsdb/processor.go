@@ -0,0 +1,82 @@
+package opentsdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/common"
+)
+
+// Protocols the batches can be sent to OpenTSDB with.
+const (
+	protocolTelnet = "telnet"
+	protocolHTTP   = "http"
+)
+
+// processor sends the batches to OpenTSDB, either as put lines over a
+// telnet TCP connection per worker or as JSON to the HTTP /api/put
+// endpoint.
+type processor struct {
+	protocol string
+	address  string
+	url      string
+	retries  int
+	backoff  time.Duration
+
+	tcp    *common.TCPWriter
+	client *http.Client
+	json   []byte
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	if p.protocol == protocolHTTP {
+		p.client = &http.Client{}
+		return
+	}
+	p.tcp = &common.TCPWriter{Address: p.address, Retries: p.retries, Backoff: p.backoff}
+	if !doLoad {
+		return
+	}
+	if err := p.tcp.Connect(); err != nil {
+		log.Fatalf("worker %d could not connect to %s: %v", workerNum, p.address, err)
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		if p.protocol == protocolHTTP {
+			p.post(batch)
+		} else if err := p.tcp.Write(batch.buf.Bytes()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, batch.rows
+	batch.buf.Reset()
+	return metricCount, rowCount
+}
+
+// post sends the batch as JSON to the HTTP endpoint, which responds with
+// 204 No Content, or 200 OK when asked for details, on success.
+func (p *processor) post(b *batch) {
+	p.json = appendJSON(p.json[:0], b.buf.Bytes())
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(p.json))
+	if err != nil {
+		log.Fatalf("error while executing request: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		log.Fatalf("server returned HTTP status %d: %s", resp.StatusCode, body)
+	}
+}
+
+func (p *processor) Close(doLoad bool) {
+	if p.tcp != nil {
+		p.tcp.Close()
+	}
+}
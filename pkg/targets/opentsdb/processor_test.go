@@ -0,0 +1,148 @@
+package opentsdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+var testLines = []string{
+	"put cpu.usage_user 1451606400000 58.13 hostname=host_0 region=eu-west-1",
+	"put cpu.usage_system 1451606400000 2 hostname=host_0 region=eu-west-1",
+}
+
+func newTestBatch() *batch {
+	f := &factory{bufPool: &sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}}
+	b := f.New().(*batch)
+	for _, l := range testLines {
+		b.Append(data.NewLoadedPoint([]byte(l)))
+	}
+	return b
+}
+
+func TestAppendJSON(t *testing.T) {
+	got := string(appendJSON(nil, []byte(testLines[0]+"\n"+testLines[1]+"\n")))
+	want := `[{"metric":"cpu.usage_user","timestamp":1451606400000,"value":58.13,"tags":{"hostname":"host_0","region":"eu-west-1"}},` +
+		`{"metric":"cpu.usage_system","timestamp":1451606400000,"value":2,"tags":{"hostname":"host_0","region":"eu-west-1"}}]`
+	if got != want {
+		t.Errorf("incorrect JSON:\ngot\n%s\nwant\n%s", got, want)
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(got), &v); err != nil {
+		t.Errorf("invalid JSON: %v", err)
+	}
+}
+
+func TestProcessorTelnet(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		received <- lines
+	}()
+
+	p := &processor{protocol: protocolTelnet, address: ln.Addr().String(), retries: 1, backoff: time.Millisecond}
+	p.Init(0, true, false)
+	b := newTestBatch()
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 2 || rows != 2 {
+		t.Errorf("incorrect counts: got %d metrics and %d rows, want 2 and 2", metrics, rows)
+	}
+	p.Close(true)
+
+	select {
+	case lines := <-received:
+		if len(lines) != len(testLines) {
+			t.Fatalf("incorrect lines received: got %v want %v", lines, testLines)
+		}
+		for i := range testLines {
+			if lines[i] != testLines[i] {
+				t.Errorf("incorrect line %d: got %s want %s", i, lines[i], testLines[i])
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the lines")
+	}
+}
+
+func TestProcessorHTTP(t *testing.T) {
+	var received []map[string]interface{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/put" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer s.Close()
+
+	p := &processor{protocol: protocolHTTP, url: s.URL + "/api/put"}
+	p.Init(0, true, false)
+	b := newTestBatch()
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 2 || rows != 2 {
+		t.Errorf("incorrect counts: got %d metrics and %d rows, want 2 and 2", metrics, rows)
+	}
+	if b.buf.Len() != 0 {
+		t.Errorf("batch buffer not reset after processing")
+	}
+	p.Close(true)
+
+	if len(received) != 2 {
+		t.Fatalf("incorrect data points received: got %v", received)
+	}
+	if got := received[0]["metric"]; got != "cpu.usage_user" {
+		t.Errorf("incorrect metric: got %v want cpu.usage_user", got)
+	}
+	if got := received[0]["value"]; got != 58.13 {
+		t.Errorf("incorrect value: got %v want 58.13", got)
+	}
+	if got := received[1]["tags"].(map[string]interface{})["hostname"]; got != "host_0" {
+		t.Errorf("incorrect hostname tag: got %v want host_0", got)
+	}
+}
+
+func TestParseSpecificConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("protocol", protocolHTTP)
+	if _, err := parseSpecificConfig(v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v.Set("protocol", "udp")
+	if _, err := parseSpecificConfig(v); err == nil {
+		t.Errorf("expected an error for an invalid protocol")
+	}
+}
@@ -0,0 +1,92 @@
+package opentsdb
+
+import (
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+// Serializer writes a Point in the OpenTSDB telnet protocol. Every field
+// becomes a metric of its own, named <measurement>.<field>.
+type Serializer struct{}
+
+// Serialize writes Point data to the given writer.
+//
+// This function writes one line per field that looks like:
+// put <measurement>.<field> <timestamp> <field value> <tag key>=<tag value>\n
+//
+// For example:
+// put cpu.usage_user 1451606400000 38.24 hostname=host_0\n
+//
+// Timestamps are in milliseconds. Bools are written as 1 and 0, string
+// fields are left out since OpenTSDB only stores numbers.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	tags := make([]byte, 0, 256)
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	for i, key := range tagKeys {
+		if tagValues[i] == nil {
+			continue
+		}
+		tags = append(tags, ' ')
+		tags = appendSanitized(tags, key)
+		tags = append(tags, '=')
+		tags = appendSanitized(tags, serialize.FastFormatAppend(tagValues[i], nil))
+	}
+
+	buf := make([]byte, 0, 1024)
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	ts := p.Timestamp().UTC().UnixNano() / 1e6
+	for i, key := range fieldKeys {
+		v := fieldValues[i]
+		switch v.(type) {
+		case nil:
+			continue
+		case string:
+			serialize.WarnSkippedField(constants.FormatOpenTSDB, key, v)
+			continue
+		}
+
+		buf = append(buf, "put "...)
+		buf = appendSanitized(buf, p.MeasurementName())
+		buf = append(buf, '.')
+		buf = appendSanitized(buf, key)
+		buf = append(buf, ' ')
+		buf = serialize.FastFormatAppend(ts, buf)
+		buf = append(buf, ' ')
+		if b, ok := v.(bool); ok {
+			if b {
+				buf = append(buf, '1')
+			} else {
+				buf = append(buf, '0')
+			}
+		} else {
+			buf = serialize.FastFormatAppend(v, buf)
+		}
+		buf = append(buf, tags...)
+		buf = append(buf, '\n')
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendSanitized appends a metric name or tag, replacing the characters
+// OpenTSDB does not allow with underscores. Allowed are letters, digits,
+// '-', '_', '.' and '/'.
+func appendSanitized(buf, s []byte) []byte {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == '/':
+		case c >= 0x80: // part of a unicode letter
+		default:
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
@@ -0,0 +1,54 @@
+package opentsdb
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestOpenTSDBSerializerSerialize(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "put cpu.usage_guest_nice 1451606400000 38.24311829 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n",
+		},
+		{
+			Desc:       "a regular Point using int as value",
+			InputPoint: serialize.TestPointInt(),
+			Output:     "put cpu.usage_guest 1451606400000 38 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n",
+		},
+		{
+			Desc:       "a Point with multiple fields",
+			InputPoint: serialize.TestPointMultiField(),
+			Output: "put cpu.big_usage_guest 1451606400000 5000000000 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n" +
+				"put cpu.usage_guest 1451606400000 38 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n" +
+				"put cpu.usage_guest_nice 1451606400000 38.24311829 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n",
+		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output: "put cpu.alerting 1451606400000 1 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n" +
+				"put cpu.usage_guest_nice 1451606400000 38.24311829 hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\n",
+		},
+		{
+			Desc:       "a Point with a nil tag",
+			InputPoint: serialize.TestPointWithNilTag(),
+			Output:     "put cpu.usage_guest_nice 1451606400000 38.24311829\n",
+		},
+		{
+			Desc:       "a Point with a nil field",
+			InputPoint: serialize.TestPointWithNilField(),
+			Output:     "put cpu.usage_guest_nice 1451606400000 38.24311829\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
+
+func TestAppendSanitized(t *testing.T) {
+	got := string(appendSanitized(nil, []byte("Ubuntu 15.10:x86/64")))
+	if want := "Ubuntu_15.10_x86/64"; got != want {
+		t.Errorf("incorrect sanitized value: got %s want %s", got, want)
+	}
+}
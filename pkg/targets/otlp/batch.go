@@ -0,0 +1,53 @@
+package otlp
+
+import (
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// batch holds the ExportMetricsServiceRequest sent in a single request. A
+// request is the concatenation of its ResourceMetrics fields, so every
+// message is appended as it was read.
+type batch struct {
+	request []byte
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	msg := item.Data.([]byte)
+	points, err := countDataPoints(msg)
+	if err != nil {
+		log.Fatalf("parse error: invalid ResourceMetrics message: %v", err)
+	}
+	b.rows++
+	b.metrics += points
+	b.request = appendMessage(b.request, requestResourceMetrics, msg)
+}
+
+// countDataPoints counts the metrics of all scopes of a ResourceMetrics
+// message. The Serializer writes a single data point per metric.
+func countDataPoints(msg []byte) (uint64, error) {
+	var count uint64
+	var scopeErr error
+	err := forEachField(msg, func(num protowire.Number, _ protowire.Type, value []byte) {
+		if num != resourceMetricsScopeMetrics || scopeErr != nil {
+			return
+		}
+		scopeErr = forEachField(value, func(num protowire.Number, _ protowire.Type, _ []byte) {
+			if num == scopeMetricsMetrics {
+				count++
+			}
+		})
+	})
+	if err == nil {
+		err = scopeErr
+	}
+	return count, err
+}
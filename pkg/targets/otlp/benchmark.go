@@ -0,0 +1,106 @@
+package otlp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the OTLP specific loading options.
+type SpecificConfig struct {
+	URL     string        `yaml:"url" mapstructure:"url"`
+	Gzip    bool          `yaml:"gzip" mapstructure:"gzip"`
+	Headers []string      `yaml:"headers" mapstructure:"headers"`
+	Retries int           `yaml:"retries" mapstructure:"retries"`
+	Backoff time.Duration `yaml:"backoff" mapstructure:"backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if _, err := parseHeaders(conf.Headers); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// parseHeaders parses headers in the format of <name>=<value>.
+func parseHeaders(headers []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(headers))
+	for _, h := range headers {
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid header %s, must be <name>=<value>", h)
+		}
+		parsed[kv[0]] = kv[1]
+	}
+	return parsed, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+	batchPool  *sync.Pool
+}
+
+func NewBenchmark(otlpSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for OTLP")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	batchPool := &sync.Pool{New: func() interface{} {
+		return &batch{request: make([]byte, 0, 4*1024*1024)}
+	}}
+	return &benchmark{
+		conf:       otlpSpecificConfig,
+		dataSource: &fileDataSource{reader: br},
+		batchPool:  batchPool,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{batchPool: b.batchPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	headers, _ := parseHeaders(b.conf.Headers)
+	return &processor{
+		url:       b.conf.URL,
+		gzip:      b.conf.Gzip,
+		headers:   headers,
+		retries:   b.conf.Retries,
+		backoff:   b.conf.Backoff,
+		batchPool: b.batchPool,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{}
+}
+
+type factory struct {
+	batchPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return f.batchPool.Get().(*batch)
+}
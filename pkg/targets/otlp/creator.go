@@ -0,0 +1,13 @@
+package otlp
+
+// OTLP is an export protocol without a database abstraction, the backend
+// stores the metrics wherever it is configured to.
+type dbCreator struct{}
+
+func (d *dbCreator) Init() {}
+
+func (d *dbCreator) DBExists(dbName string) bool { return true }
+
+func (d *dbCreator) CreateDB(dbName string) error { return nil }
+
+func (d *dbCreator) RemoveOldDB(dbName string) error { return nil }
@@ -0,0 +1,35 @@
+package otlp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// fileDataSource reads the size-prefixed ResourceMetrics messages written
+// by the Serializer.
+type fileDataSource struct {
+	reader *bufio.Reader
+}
+
+func (f *fileDataSource) NextItem() data.LoadedPoint {
+	size, err := binary.ReadUvarint(f.reader)
+	if err == io.EOF {
+		return data.LoadedPoint{}
+	} else if err != nil {
+		log.Fatalf("read error: could not read message size: %v", err)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(f.reader, msg); err != nil {
+		log.Fatalf("read error: could not read message of %d bytes: %v", size, err)
+	}
+	return data.NewLoadedPoint(msg)
+}
+
+func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
@@ -0,0 +1,44 @@
+package otlp
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &otlpTarget{}
+}
+
+type otlpTarget struct {
+}
+
+func (t *otlpTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	otlpSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(otlpSpecificConfig, dataSourceConfig)
+}
+
+func (t *otlpTarget) Serializer() serialize.PointSerializer {
+	return &Serializer{}
+}
+
+func (t *otlpTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"url", "http://localhost:4318/v1/metrics", "OTLP/HTTP metrics endpoint")
+	flagSet.Bool(flagPrefix+"gzip", false, "Whether to compress the requests with gzip")
+	flagSet.String(flagPrefix+"headers", "", "Comma-separated headers sent with every request, as <name>=<value>, e.g. X-Scope-OrgID=tsbs")
+	flagSet.Int(flagPrefix+"retries", 5, "Times to resend a request rejected with a retryable status code, i.e. 429, 502, 503 or 504")
+	flagSet.Duration(flagPrefix+"backoff", time.Second, "Time to wait before resending a request, doubled with every retry")
+}
+
+func (t *otlpTarget) TargetName() string {
+	return constants.FormatOTLP
+}
@@ -0,0 +1,143 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const contentTypeProtobuf = "application/x-protobuf"
+
+// retryableStatus are the status codes OTLP/HTTP exporters retry on.
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// processor exports the batches of a worker over OTLP/HTTP.
+type processor struct {
+	url     string
+	gzip    bool
+	headers map[string]string
+	retries int
+	backoff time.Duration
+
+	batchPool *sync.Pool
+	client    *http.Client
+	buf       bytes.Buffer
+	zw        *gzip.Writer
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	p.client = &http.Client{}
+	if p.gzip {
+		p.zw = gzip.NewWriter(&p.buf)
+	}
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	if doLoad {
+		body := batch.request
+		if p.gzip {
+			body = p.compress(body)
+		}
+		if err := p.export(body); err != nil {
+			log.Fatalf("error exporting metrics: %v", err)
+		}
+	}
+	metricCount, rowCount = batch.metrics, batch.rows
+	batch.request = batch.request[:0]
+	batch.rows, batch.metrics = 0, 0
+	p.batchPool.Put(batch)
+	return metricCount, rowCount
+}
+
+func (p *processor) compress(body []byte) []byte {
+	p.buf.Reset()
+	p.zw.Reset(&p.buf)
+	if _, err := p.zw.Write(body); err != nil {
+		log.Fatalf("could not compress request: %v", err)
+	}
+	if err := p.zw.Close(); err != nil {
+		log.Fatalf("could not compress request: %v", err)
+	}
+	return p.buf.Bytes()
+}
+
+// export sends the request, retrying on the retryable status codes with a
+// backoff that doubles with every retry.
+func (p *processor) export(body []byte) error {
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		status, respBody, err := p.post(body)
+		if err != nil {
+			return err
+		}
+		if status == http.StatusOK {
+			logPartialSuccess(respBody)
+			return nil
+		}
+		if !retryableStatus[status] || attempt >= p.retries {
+			return fmt.Errorf("export returned code %d: %s", status, respBody)
+		}
+		log.Printf("export returned code %d. Retrying in %v", status, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (p *processor) post(body []byte) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", contentTypeProtobuf)
+	if p.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, respBody, err
+}
+
+// logPartialSuccess logs the data points the backend rejected from an
+// otherwise successful export, as reported in the ExportMetricsServiceResponse.
+func logPartialSuccess(resp []byte) {
+	var rejected uint64
+	var message []byte
+	forEachField(resp, func(num protowire.Number, _ protowire.Type, value []byte) {
+		if num != responsePartialSuccess {
+			return
+		}
+		forEachField(value, func(num protowire.Number, typ protowire.Type, value []byte) {
+			switch {
+			case num == partialSuccessRejectedPoints && typ == protowire.VarintType:
+				rejected, _ = protowire.ConsumeVarint(value)
+			case num == partialSuccessErrorMessage:
+				message = value
+			}
+		})
+	})
+	if rejected > 0 || len(message) > 0 {
+		log.Printf("export partially succeeded, %d data points rejected: %s", rejected, message)
+	}
+}
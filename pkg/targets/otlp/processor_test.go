@@ -0,0 +1,148 @@
+package otlp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// serializePoints returns the Serializer output for the points, as read
+// from a file.
+func serializePoints(t *testing.T, points ...*data.Point) []byte {
+	b := new(bytes.Buffer)
+	s := &Serializer{}
+	for _, p := range points {
+		if err := s.Serialize(p, b); err != nil {
+			t.Fatalf("could not serialize: %v", err)
+		}
+	}
+	return b.Bytes()
+}
+
+func newTestBatchPool() *sync.Pool {
+	return &sync.Pool{New: func() interface{} { return &batch{} }}
+}
+
+func TestFileDataSourceAndBatch(t *testing.T) {
+	file := serializePoints(t, serialize.TestPointMultiField(), serialize.TestPointDefault())
+	ds := &fileDataSource{reader: bufio.NewReader(bytes.NewReader(file))}
+	f := &factory{batchPool: newTestBatchPool()}
+	b := f.New().(*batch)
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		b.Append(item)
+	}
+	if b.Len() != 2 || b.metrics != 4 {
+		t.Errorf("incorrect counts: got %d rows %d metrics, want 2 rows 4 metrics", b.Len(), b.metrics)
+	}
+
+	var resourceMetrics int
+	err := forEachField(b.request, func(num protowire.Number, _ protowire.Type, _ []byte) {
+		if num == requestResourceMetrics {
+			resourceMetrics++
+		}
+	})
+	if err != nil || resourceMetrics != 2 {
+		t.Errorf("incorrect request: got %d ResourceMetrics, error %v", resourceMetrics, err)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders([]string{"X-Scope-OrgID=tsbs", "Authorization=Basic a2V5PQ=="})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if headers["X-Scope-OrgID"] != "tsbs" || headers["Authorization"] != "Basic a2V5PQ==" {
+		t.Errorf("incorrect headers: got %v", headers)
+	}
+	if _, err := parseHeaders([]string{"no-value"}); err == nil {
+		t.Errorf("expected an error for a header without value")
+	}
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	var requests [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/metrics" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != contentTypeProtobuf {
+			t.Errorf("incorrect content type: got %s", got)
+		}
+		if got := r.Header.Get("Content-Encoding"); got != "gzip" {
+			t.Errorf("incorrect content encoding: got %s", got)
+		}
+		if got := r.Header.Get("X-Scope-OrgID"); got != "tsbs" {
+			t.Errorf("incorrect tenant header: got %s", got)
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("request is not compressed: %v", err)
+		}
+		body, _ := ioutil.ReadAll(zr)
+		requests = append(requests, body)
+
+		if len(requests) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeProtobuf)
+	}))
+	defer server.Close()
+
+	pool := newTestBatchPool()
+	p := &processor{
+		url:       server.URL + "/v1/metrics",
+		gzip:      true,
+		headers:   map[string]string{"X-Scope-OrgID": "tsbs"},
+		retries:   1,
+		batchPool: pool,
+	}
+	p.Init(0, true, false)
+
+	b := (&factory{batchPool: pool}).New().(*batch)
+	msg := serializePoints(t, serialize.TestPointMultiField())
+	_, n := binary.Uvarint(msg)
+	b.Append(data.NewLoadedPoint(msg[n:]))
+	want := append([]byte(nil), b.request...)
+
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 3 || rows != 1 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 3 metrics 1 row", metrics, rows)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("incorrect number of requests: got %d want 2", len(requests))
+	}
+	for i, req := range requests {
+		if !bytes.Equal(req, want) {
+			t.Errorf("incorrect body of request %d", i)
+		}
+	}
+}
+
+func TestProcessorExportError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	p := &processor{url: server.URL, retries: 3}
+	p.Init(0, true, false)
+	if err := p.export([]byte{}); err == nil {
+		t.Errorf("expected an error for status 400")
+	}
+	if calls != 1 {
+		t.Errorf("non-retryable status should not be retried, got %d calls", calls)
+	}
+}
@@ -0,0 +1,99 @@
+package otlp
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the OTLP metrics protobuf messages, as defined in
+// opentelemetry/proto/collector/metrics/v1/metrics_service.proto and
+// opentelemetry/proto/metrics/v1/metrics.proto. The messages are encoded
+// by hand to avoid depending on the generated OTLP packages.
+const (
+	// ExportMetricsServiceRequest
+	requestResourceMetrics protowire.Number = 1
+
+	// ExportMetricsServiceResponse and ExportMetricsPartialSuccess
+	responsePartialSuccess       protowire.Number = 1
+	partialSuccessRejectedPoints protowire.Number = 1
+	partialSuccessErrorMessage   protowire.Number = 2
+
+	// ResourceMetrics
+	resourceMetricsResource     protowire.Number = 1
+	resourceMetricsScopeMetrics protowire.Number = 2
+
+	// Resource
+	resourceAttributes protowire.Number = 1
+
+	// ScopeMetrics
+	scopeMetricsScope   protowire.Number = 1
+	scopeMetricsMetrics protowire.Number = 2
+
+	// InstrumentationScope
+	scopeName protowire.Number = 1
+
+	// Metric
+	metricName  protowire.Number = 1
+	metricGauge protowire.Number = 5
+	metricSum   protowire.Number = 7
+
+	// Gauge and Sum
+	dataPoints            protowire.Number = 1
+	sumTemporality        protowire.Number = 2
+	sumIsMonotonic        protowire.Number = 3
+	temporalityCumulative                  = 2
+
+	// NumberDataPoint
+	pointTimeUnixNano protowire.Number = 3
+	pointAsDouble     protowire.Number = 4
+	pointAsInt        protowire.Number = 6
+	pointAttributes   protowire.Number = 7
+
+	// KeyValue
+	keyValueKey   protowire.Number = 1
+	keyValueValue protowire.Number = 2
+
+	// AnyValue
+	anyValueString protowire.Number = 1
+)
+
+// appendMessage appends the embedded message msg as field num.
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// appendStringAttribute appends a KeyValue with a string value as field num.
+func appendStringAttribute(b []byte, num protowire.Number, key, value []byte) []byte {
+	var anyValue []byte
+	anyValue = protowire.AppendTag(anyValue, anyValueString, protowire.BytesType)
+	anyValue = protowire.AppendBytes(anyValue, value)
+
+	var kv []byte
+	kv = protowire.AppendTag(kv, keyValueKey, protowire.BytesType)
+	kv = protowire.AppendBytes(kv, key)
+	kv = appendMessage(kv, keyValueValue, anyValue)
+	return appendMessage(b, num, kv)
+}
+
+// forEachField calls fn with every field of the message b, stopping at the
+// first malformed field.
+func forEachField(b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		value := b[:m]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		fn(num, typ, value)
+		b = b[m:]
+	}
+	return nil
+}
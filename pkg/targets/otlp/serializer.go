@@ -0,0 +1,163 @@
+package otlp
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// scope is the InstrumentationScope of all metrics, named tsbs.
+var scope = protowire.AppendString(protowire.AppendTag(nil, scopeName, protowire.BytesType), "tsbs")
+
+// datapointTags are the tags of the devops use case that tell apart the
+// instances of a sub-system on a host, e.g. the disks or the network
+// interfaces. They become attributes of the data points, while all other
+// tags identify the entity the readings are of and become attributes of
+// the resource.
+var datapointTags = map[string]bool{
+	"path":      true,
+	"fstype":    true,
+	"serial":    true,
+	"interface": true,
+	"port":      true,
+	"server":    true,
+}
+
+// cumulativeFields are the fields of the devops use case that are counters,
+// keyed by measurement. They become cumulative monotonic sums, as do the
+// fields named *_total, while all other fields become gauges.
+var cumulativeFields = map[string]map[string]bool{
+	"diskio": {"reads": true, "writes": true, "read_bytes": true, "write_bytes": true,
+		"read_time": true, "write_time": true, "io_time": true},
+	"kernel": {"interrupts": true, "context_switches": true, "processes_forked": true,
+		"disk_pages_in": true, "disk_pages_out": true},
+	"net":   {"bytes_sent": true, "bytes_recv": true, "packets_sent": true, "packets_recv": true},
+	"nginx": {"accepts": true, "handled": true, "requests": true},
+	"redis": {"total_connections_received": true, "expired_keys": true, "evicted_keys": true,
+		"keyspace_hits": true, "keyspace_misses": true},
+}
+
+// Serializer writes a Point as an OTLP ResourceMetrics protobuf message.
+type Serializer struct{}
+
+// Serialize writes Point data to the given writer as a ResourceMetrics
+// message, prefixed by its size as an uvarint since protobuf messages are
+// not self-delimiting.
+//
+// The tags become attributes of the resource, or of the data points for the
+// tags in datapointTags. Every field becomes a metric named
+// <measurement>.<field> with a single data point, in a scope named tsbs.
+// Floats are stored as doubles, integers and bools (as 1 and 0) as ints.
+// String fields are left out since OTLP metrics only store numbers.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	var resource, attributes []byte
+	tagKeys := p.TagKeys()
+	tagValues := p.TagValues()
+	for i, key := range tagKeys {
+		if tagValues[i] == nil {
+			continue
+		}
+		value := serialize.FastFormatAppend(tagValues[i], nil)
+		if datapointTags[string(key)] {
+			attributes = appendStringAttribute(attributes, pointAttributes, key, value)
+		} else {
+			resource = appendStringAttribute(resource, resourceAttributes, key, value)
+		}
+	}
+
+	scopeMetrics := appendMessage(nil, scopeMetricsScope, scope)
+
+	measurement := p.MeasurementName()
+	counters := cumulativeFields[string(measurement)]
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	ts := uint64(p.Timestamp().UTC().UnixNano())
+	for i, key := range fieldKeys {
+		if fieldValues[i] == nil {
+			continue
+		}
+		point, ok := appendValue(nil, fieldValues[i])
+		if !ok {
+			serialize.WarnSkippedField(constants.FormatOTLP, key, fieldValues[i])
+			continue
+		}
+		point = protowire.AppendTag(point, pointTimeUnixNano, protowire.Fixed64Type)
+		point = protowire.AppendFixed64(point, ts)
+		point = append(point, attributes...)
+
+		var metric []byte
+		metric = protowire.AppendTag(metric, metricName, protowire.BytesType)
+		metric = protowire.AppendVarint(metric, uint64(len(measurement)+1+len(key)))
+		metric = append(metric, measurement...)
+		metric = append(metric, '.')
+		metric = append(metric, key...)
+		if counters[string(key)] || isTotal(key) {
+			var sum []byte
+			sum = appendMessage(sum, dataPoints, point)
+			sum = protowire.AppendTag(sum, sumTemporality, protowire.VarintType)
+			sum = protowire.AppendVarint(sum, temporalityCumulative)
+			sum = protowire.AppendTag(sum, sumIsMonotonic, protowire.VarintType)
+			sum = protowire.AppendVarint(sum, protowire.EncodeBool(true))
+			metric = appendMessage(metric, metricSum, sum)
+		} else {
+			metric = appendMessage(metric, metricGauge, appendMessage(nil, dataPoints, point))
+		}
+		scopeMetrics = appendMessage(scopeMetrics, scopeMetricsMetrics, metric)
+	}
+
+	var msg []byte
+	msg = appendMessage(msg, resourceMetricsResource, resource)
+	msg = appendMessage(msg, resourceMetricsScopeMetrics, scopeMetrics)
+
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(msg)))
+	if _, err := w.Write(size[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// appendValue appends the value of a number data point, returning false
+// for values OTLP cannot store.
+func appendValue(b []byte, v interface{}) ([]byte, bool) {
+	var i int64
+	switch v := v.(type) {
+	case float64:
+		b = protowire.AppendTag(b, pointAsDouble, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(v)), true
+	case float32:
+		b = protowire.AppendTag(b, pointAsDouble, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(float64(v))), true
+	case int:
+		i = int64(v)
+	case int64:
+		i = v
+	case int32:
+		i = int64(v)
+	case uint:
+		i = int64(v)
+	case uint64:
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case bool:
+		if v {
+			i = 1
+		}
+	default:
+		return b, false
+	}
+	b = protowire.AppendTag(b, pointAsInt, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, uint64(i)), true
+}
+
+func isTotal(key []byte) bool {
+	const suffix = "_total"
+	return len(key) > len(suffix) && string(key[len(key)-len(suffix):]) == suffix
+}
@@ -0,0 +1,194 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// describe decodes a ResourceMetrics message into a readable form: the
+// resource attributes on the first line, followed by a line per metric with
+// its type, name, timestamp, value and data point attributes.
+func describe(t *testing.T, msg []byte) string {
+	var lines []string
+	attributes := func(kvs [][]byte) string {
+		var attrs []string
+		for _, kv := range kvs {
+			var key, value []byte
+			forEachField(kv, func(num protowire.Number, _ protowire.Type, v []byte) {
+				if num == keyValueKey {
+					key = v
+				} else if num == keyValueValue {
+					forEachField(v, func(_ protowire.Number, _ protowire.Type, s []byte) { value = s })
+				}
+			})
+			attrs = append(attrs, fmt.Sprintf("%s=%s", key, value))
+		}
+		return strings.Join(attrs, " ")
+	}
+
+	err := forEachField(msg, func(num protowire.Number, _ protowire.Type, v []byte) {
+		switch num {
+		case resourceMetricsResource:
+			var kvs [][]byte
+			forEachField(v, func(_ protowire.Number, _ protowire.Type, kv []byte) { kvs = append(kvs, kv) })
+			lines = append(lines, "resource "+attributes(kvs))
+		case resourceMetricsScopeMetrics:
+			forEachField(v, func(num protowire.Number, _ protowire.Type, v []byte) {
+				if num == scopeMetricsScope {
+					forEachField(v, func(_ protowire.Number, _ protowire.Type, name []byte) {
+						lines = append(lines, "scope "+string(name))
+					})
+					return
+				}
+				var line string
+				forEachField(v, func(num protowire.Number, typ protowire.Type, v []byte) {
+					switch num {
+					case metricName:
+						line += string(v)
+					case metricGauge, metricSum:
+						kind := "gauge"
+						if num == metricSum {
+							kind = "sum"
+						}
+						forEachField(v, func(num protowire.Number, _ protowire.Type, v []byte) {
+							switch num {
+							case sumTemporality:
+								kind += " cumulative"
+							case sumIsMonotonic:
+								kind += " monotonic"
+							case dataPoints:
+								var ts uint64
+								var value string
+								var kvs [][]byte
+								forEachField(v, func(num protowire.Number, _ protowire.Type, v []byte) {
+									switch num {
+									case pointTimeUnixNano:
+										ts, _ = protowire.ConsumeFixed64(v)
+									case pointAsDouble:
+										bits, _ := protowire.ConsumeFixed64(v)
+										value = fmt.Sprint(math.Float64frombits(bits))
+									case pointAsInt:
+										i, _ := protowire.ConsumeFixed64(v)
+										value = fmt.Sprint(int64(i))
+									case pointAttributes:
+										kvs = append(kvs, v)
+									}
+								})
+								line += fmt.Sprintf(" %d %s", ts, value)
+								if len(kvs) > 0 {
+									line += " " + attributes(kvs)
+								}
+							}
+						})
+						line = kind + " " + line
+					}
+				})
+				lines = append(lines, line)
+			})
+		}
+	})
+	if err != nil {
+		t.Fatalf("could not decode message: %v", err)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func serializeAndDescribe(t *testing.T, p *data.Point) string {
+	b := new(bytes.Buffer)
+	if err := (&Serializer{}).Serialize(p, b); err != nil {
+		t.Fatalf("could not serialize: %v", err)
+	}
+	size, n := binary.Uvarint(b.Bytes())
+	if n <= 0 || int(size) != b.Len()-n {
+		t.Fatalf("incorrect size prefix: got %d for a message of %d bytes", size, b.Len()-n)
+	}
+	return describe(t, b.Bytes()[n:])
+}
+
+func TestOTLPSerializerSerialize(t *testing.T) {
+	const (
+		resource = "resource hostname=host_0 region=eu-west-1 datacenter=eu-west-1b\nscope tsbs\n"
+		ts       = "1451606400000000000"
+	)
+	cases := []struct {
+		desc  string
+		point *data.Point
+		want  string
+	}{
+		{
+			desc:  "a regular Point",
+			point: serialize.TestPointDefault(),
+			want:  resource + "gauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+		{
+			desc:  "a regular Point using int as value",
+			point: serialize.TestPointInt(),
+			want:  resource + "gauge cpu.usage_guest " + ts + " 38",
+		},
+		{
+			desc:  "a Point with multiple fields",
+			point: serialize.TestPointMultiField(),
+			want: resource +
+				"gauge cpu.big_usage_guest " + ts + " 5000000000\n" +
+				"gauge cpu.usage_guest " + ts + " 38\n" +
+				"gauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+		{
+			desc:  "a Point with string and bool fields",
+			point: serialize.TestPointNonNumeric(),
+			want: resource +
+				"gauge cpu.alerting " + ts + " 1\n" +
+				"gauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+		{
+			desc:  "a Point with no tags",
+			point: serialize.TestPointNoTags(),
+			want:  "resource \nscope tsbs\ngauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+		{
+			desc:  "a Point with a nil tag",
+			point: serialize.TestPointWithNilTag(),
+			want:  "resource \nscope tsbs\ngauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+		{
+			desc:  "a Point with a nil field",
+			point: serialize.TestPointWithNilField(),
+			want:  "resource \nscope tsbs\ngauge cpu.usage_guest_nice " + ts + " 38.24311829",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			if got := serializeAndDescribe(t, c.point); got != c.want {
+				t.Errorf("incorrect message:\ngot\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestOTLPSerializerSumsAndDatapointAttributes(t *testing.T) {
+	p := data.NewPoint()
+	p.SetMeasurementName([]byte("diskio"))
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendTag([]byte("hostname"), "host_0")
+	p.AppendTag([]byte("serial"), "123-456")
+	p.AppendField([]byte("reads"), int64(12))
+	p.AppendField([]byte("weighted_io_time"), 2.5)
+	p.AppendField([]byte("queue_total"), int64(3))
+
+	want := "resource hostname=host_0\nscope tsbs\n" +
+		"sum cumulative monotonic diskio.reads 1451606400000000000 12 serial=123-456\n" +
+		"gauge diskio.weighted_io_time 1451606400000000000 2.5 serial=123-456\n" +
+		"sum cumulative monotonic diskio.queue_total 1451606400000000000 3 serial=123-456"
+	if got := serializeAndDescribe(t, p); got != want {
+		t.Errorf("incorrect message:\ngot\n%s\nwant\n%s", got, want)
+	}
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/timescale/promscale/pkg/prompb"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
 )
 
 const serializerVersion uint64 = 1
@@ -51,11 +53,11 @@ func (ps *Serializer) Serialize(p *data.Point, w io.Writer) error {
 		}
 	}
 	series := make([]prompb.TimeSeries, len(p.FieldKeys()))
-	err := convertToPromSeries(p, series)
+	n, err := convertToPromSeries(p, series)
 	if err != nil {
 		return fmt.Errorf("could not serialize point\n%v", err)
 	}
-	for _, ts := range series {
+	for _, ts := range series[:n] {
 		protoBytes, err := proto.Marshal(&ts)
 		if err != nil {
 			return err
@@ -74,12 +76,25 @@ func (ps *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	return nil
 }
 
-// Each point field will become a new TimeSeries with added field key as a label
-func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
+// ConvertToWriteRequest converts a Point into a remote write request holding
+// the same time series the Serializer writes for it.
+func ConvertToWriteRequest(p *data.Point) (*prompb.WriteRequest, error) {
+	series := make([]prompb.TimeSeries, len(p.FieldKeys()))
+	n, err := convertToPromSeries(p, series)
+	if err != nil {
+		return nil, err
+	}
+	return &prompb.WriteRequest{Timeseries: series[:n]}, nil
+}
+
+// Each point field will become a new TimeSeries with added field key as a label.
+// Prometheus only stores numbers, so bools become 1 and 0 and string fields are
+// left out. Returns the number of time series written to the buffer.
+func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) (int, error) {
 	bufLen := len(buffer)
 	requiredPlaces := len(p.FieldKeys())
 	if requiredPlaces > bufLen {
-		return fmt.Errorf("supplied buffer has insufficient space; need %d; got %d",
+		return 0, fmt.Errorf("supplied buffer has insufficient space; need %d; got %d",
 			requiredPlaces, bufLen,
 		)
 	}
@@ -108,7 +123,12 @@ func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
 	})
 
 	tsMs := p.TimestampInUnixMs()
+	n := 0
 	for i := range fieldKeys {
+		if _, ok := fieldValues[i].(string); ok {
+			serialize.WarnSkippedField(constants.FormatPrometheus, fieldKeys[i], fieldValues[i])
+			continue
+		}
 		myLabels := labels
 		if i+1 < len(fieldKeys) {
 			myLabels = make([]prompb.Label, len(labels))
@@ -122,9 +142,10 @@ func convertToPromSeries(p *data.Point, buffer []prompb.TimeSeries) error {
 			Labels:  myLabels,
 			Samples: []prompb.Sample{{Value: getFloat64(fieldValues[i]), Timestamp: tsMs}},
 		}
-		buffer[i] = ts
+		buffer[n] = ts
+		n++
 	}
-	return nil
+	return n, nil
 }
 
 func getFloat64(fieldValue interface{}) float64 {
@@ -135,6 +156,11 @@ func getFloat64(fieldValue interface{}) float64 {
 		return float64(fieldValue.(int64))
 	case float64:
 		return fieldValue.(float64)
+	case bool:
+		if t {
+			return 1
+		}
+		return 0
 	default:
 		panic(fmt.Sprintf("unsupported value type: %v", t))
 	}
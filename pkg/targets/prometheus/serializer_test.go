@@ -106,6 +106,15 @@ func TestConvertToPromSeries(t *testing.T) {
 		Samples: []prompb.Sample{{Value: 2, Timestamp: twoFieldPoint.Timestamp().UnixNano() / 1000000}},
 	}
 
+	nonNumericPoint := data.NewPoint()
+	nonNumericPoint.SetTimestamp(&someTimeAgo)
+	nonNumericPoint.AppendField([]byte("s"), "a string")
+	nonNumericPoint.AppendField([]byte("b"), true)
+	nnTS := prompb.TimeSeries{
+		Labels:  []prompb.Label{{Name: "__name__", Value: "b"}},
+		Samples: []prompb.Sample{{Value: 1, Timestamp: nonNumericPoint.Timestamp().UnixNano() / 1000000}},
+	}
+
 	testCases := []struct {
 		desc      string
 		expError  bool
@@ -124,12 +133,17 @@ func TestConvertToPromSeries(t *testing.T) {
 			inPoint:   twoFieldPoint,
 			inBuffer:  make([]prompb.TimeSeries, 2),
 			expBuffer: []prompb.TimeSeries{tfTS1, tfTS2},
+		}, {
+			desc:      "String field skipped, bool as number",
+			inPoint:   nonNumericPoint,
+			inBuffer:  make([]prompb.TimeSeries, 2),
+			expBuffer: []prompb.TimeSeries{nnTS},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := convertToPromSeries(tc.inPoint, tc.inBuffer)
+			n, err := convertToPromSeries(tc.inPoint, tc.inBuffer)
 			if tc.expError && err != nil {
 				return
 			} else if tc.expError {
@@ -139,6 +153,10 @@ func TestConvertToPromSeries(t *testing.T) {
 				t.Errorf("unexpected error: %v", err)
 				return
 			}
+			if n != len(tc.expBuffer) {
+				t.Errorf("wrong number of time-series; exp: %d; got %d", len(tc.expBuffer), n)
+				return
+			}
 
 			for i, ts := range tc.expBuffer {
 				returnedTS := tc.inBuffer[i]
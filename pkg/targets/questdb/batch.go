@@ -0,0 +1,71 @@
+package questdb
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
+
+var (
+	spaceSep = []byte(" ")
+	commaSep = []byte(",")
+	newLine  = []byte("\n")
+)
+
+// batch holds the ILP lines sent to QuestDB in a single write.
+type batch struct {
+	buf     *bytes.Buffer
+	rows    uint64
+	metrics uint64
+}
+
+func (b *batch) Len() uint {
+	return uint(b.rows)
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	that := item.Data.([]byte)
+	b.rows++
+
+	// Each ILP line is format "csv-tags csv-fields timestamp". Spaces may
+	// also appear inside of quoted string fields, so the fields are the part
+	// between the first and the last space.
+	fieldsPos := bytes.Index(that, spaceSep)
+	timestampPos := bytes.LastIndex(that, spaceSep)
+	if fieldsPos < 0 || fieldsPos == timestampPos {
+		log.Fatalf(errNotThreeTuplesFmt, bytes.Count(that, spaceSep)+1)
+		return
+	}
+	fields := that[fieldsPos+1 : timestampPos]
+	b.metrics += uint64(countFields(fields))
+
+	b.buf.Write(that)
+	b.buf.Write(newLine)
+}
+
+// countFields counts the comma-separated fields, skipping the commas in
+// quoted string values.
+func countFields(fields []byte) int {
+	if !bytes.ContainsRune(fields, '"') {
+		return bytes.Count(fields, commaSep) + 1
+	}
+
+	count := 1
+	quoted := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				count++
+			}
+		}
+	}
+	return count
+}
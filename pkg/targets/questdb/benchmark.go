@@ -0,0 +1,109 @@
+package questdb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/internal/inputs"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the QuestDB specific loading options.
+type SpecificConfig struct {
+	ILPAddress       string        `yaml:"ilp-address" mapstructure:"ilp-address"`
+	PGHost           string        `yaml:"pg-host" mapstructure:"pg-host"`
+	PGPort           string        `yaml:"pg-port" mapstructure:"pg-port"`
+	User             string        `yaml:"user" mapstructure:"user"`
+	Pass             string        `yaml:"pass" mapstructure:"pass"`
+	PartitionBy      string        `yaml:"partition-by" mapstructure:"partition-by"`
+	ReconnectRetries int           `yaml:"reconnect-retries" mapstructure:"reconnect-retries"`
+	ReconnectBackoff time.Duration `yaml:"reconnect-backoff" mapstructure:"reconnect-backoff"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	return &conf, nil
+}
+
+// connectString returns the connection string of the PostgreSQL wire
+// protocol endpoint, QuestDB serves a single database named qdb.
+func (c *SpecificConfig) connectString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=qdb sslmode=disable",
+		c.PGHost, c.PGPort, c.User, c.Pass)
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	conf       *SpecificConfig
+	dataSource targets.DataSource
+}
+
+func NewBenchmark(questSpecificConfig *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	var ds targets.DataSource
+	if dataSourceConfig.Type == source.FileDataSourceType {
+		br := load.GetBufferedReader(dataSourceConfig.File.Location)
+		ds = &fileDataSource{scanner: bufio.NewScanner(br)}
+	} else {
+		dataGenerator := &inputs.DataGenerator{}
+		simulator, err := dataGenerator.CreateSimulator(dataSourceConfig.Simulator)
+		if err != nil {
+			return nil, err
+		}
+		ds = newSimulationDataSource(simulator)
+	}
+
+	return &benchmark{
+		conf:       questSpecificConfig,
+		dataSource: ds,
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.dataSource
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	bufPool := sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 4*1024*1024))
+		},
+	}
+	return &factory{bufPool: &bufPool}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		address: b.conf.ILPAddress,
+		retries: b.conf.ReconnectRetries,
+		backoff: b.conf.ReconnectBackoff,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		ds:          b.dataSource,
+		connStr:     b.conf.connectString(),
+		partitionBy: b.conf.PartitionBy,
+	}
+}
+
+type factory struct {
+	bufPool *sync.Pool
+}
+
+func (f *factory) New() targets.Batch {
+	return &batch{buf: f.bufPool.Get().(*bytes.Buffer)}
+}
@@ -0,0 +1,137 @@
+package questdb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	pgxDriver = "pgx"
+
+	// timestampColumn is the designated timestamp column, named as QuestDB
+	// names it for tables created through ILP.
+	timestampColumn = "timestamp"
+)
+
+// dbCreator creates the tables of the benchmark through the PostgreSQL wire
+// protocol. QuestDB has no databases, so the tables of the generated
+// measurements stand in for the benchmark database. Without headers, i.e.
+// when loading ILP files, the tables are left for QuestDB to create from
+// the first written line.
+type dbCreator struct {
+	ds          targets.DataSource
+	connStr     string
+	partitionBy string
+	headers     *common.GeneratedDataHeaders
+}
+
+func (d *dbCreator) Init() {
+	d.headers = d.ds.Headers()
+}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	if d.headers == nil {
+		return false
+	}
+	db := d.mustConnect()
+	defer db.Close()
+	for table := range d.headers.FieldKeys {
+		var exists bool
+		err := db.QueryRow("SELECT count() > 0 FROM tables() WHERE table_name = $1", table).Scan(&exists)
+		if err != nil {
+			log.Fatalf("could not check if table %s exists: %v", table, err)
+		}
+		if exists {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	if d.headers == nil {
+		return nil
+	}
+	db := d.mustConnect()
+	defer db.Close()
+	for table := range d.headers.FieldKeys {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return fmt.Errorf("could not drop table %s: %v", table, err)
+		}
+	}
+	return nil
+}
+
+func (d *dbCreator) CreateDB(dbName string) error {
+	if d.headers == nil {
+		log.Println("no headers in the input, tables are created by QuestDB on the first write")
+		return nil
+	}
+	db := d.mustConnect()
+	defer db.Close()
+	for table := range d.headers.FieldKeys {
+		if _, err := db.Exec(createTableQuery(d.headers, table, d.partitionBy)); err != nil {
+			return fmt.Errorf("could not create table %s: %v", table, err)
+		}
+	}
+	return nil
+}
+
+func (d *dbCreator) mustConnect() *sql.DB {
+	db, err := sql.Open(pgxDriver, d.connStr)
+	if err != nil {
+		log.Fatalf("could not connect to QuestDB: %v", err)
+	}
+	return db
+}
+
+// createTableQuery returns the CREATE TABLE statement of the given
+// measurement. String tags become SYMBOL columns, the other tags are written
+// as fields by the influx serializer. Numeric fields are only declared when
+// their types are known, otherwise QuestDB adds them on write, as LONG for
+// integers and DOUBLE for floats.
+func createTableQuery(headers *common.GeneratedDataHeaders, table, partitionBy string) string {
+	columns := make([]string, 0, len(headers.TagKeys)+len(headers.FieldKeys[table])+1)
+	for i, key := range headers.TagKeys {
+		columns = append(columns, fmt.Sprintf("%s %s", key, tagTypeToQuestType(headers.TagTypes[i])))
+	}
+
+	knownTypes := len(headers.FieldTypes[table]) > 0
+	for i, key := range headers.FieldKeys[table] {
+		fieldType := headers.FieldType(table, i)
+		if !knownTypes && common.IsNumericFieldType(fieldType) {
+			continue
+		}
+		columns = append(columns, fmt.Sprintf("%s %s", key, fieldTypeToQuestType(fieldType)))
+	}
+	columns = append(columns, timestampColumn+" TIMESTAMP")
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) TIMESTAMP(%s) PARTITION BY %s",
+		table, strings.Join(columns, ", "), timestampColumn, partitionBy)
+}
+
+func tagTypeToQuestType(tagType string) string {
+	if tagType == "string" {
+		return "SYMBOL"
+	}
+	return fieldTypeToQuestType(tagType)
+}
+
+func fieldTypeToQuestType(fieldType string) string {
+	switch fieldType {
+	case common.FieldTypeString:
+		return "STRING"
+	case common.FieldTypeBool:
+		return "BOOLEAN"
+	case "int", "int32", "int64", "uint32", "uint64":
+		return "LONG"
+	default:
+		return "DOUBLE"
+	}
+}
@@ -0,0 +1,81 @@
+package questdb
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func TestCreateTableQuery(t *testing.T) {
+	cases := []struct {
+		desc    string
+		headers *common.GeneratedDataHeaders
+		table   string
+		want    string
+	}{
+		{
+			desc: "typed fields",
+			headers: &common.GeneratedDataHeaders{
+				TagKeys:  []string{"hostname", "rack"},
+				TagTypes: []string{"string", "string"},
+				FieldKeys: map[string][]string{
+					"cpu":    {"usage_user", "usage_system"},
+					"health": {"status", "healthy", "uptime"},
+				},
+				FieldTypes: map[string][]string{
+					"cpu":    {"float64", "float64"},
+					"health": {"string", "bool", "int64"},
+				},
+			},
+			table: "health",
+			want: "CREATE TABLE IF NOT EXISTS health (hostname SYMBOL, rack SYMBOL, " +
+				"status STRING, healthy BOOLEAN, uptime LONG, timestamp TIMESTAMP) " +
+				"TIMESTAMP(timestamp) PARTITION BY DAY",
+		},
+		{
+			desc: "numeric tags",
+			headers: &common.GeneratedDataHeaders{
+				TagKeys:    []string{"name", "load_capacity"},
+				TagTypes:   []string{"string", "float32"},
+				FieldKeys:  map[string][]string{"readings": {"latitude"}},
+				FieldTypes: map[string][]string{"readings": {"float64"}},
+			},
+			table: "readings",
+			want: "CREATE TABLE IF NOT EXISTS readings (name SYMBOL, load_capacity DOUBLE, " +
+				"latitude DOUBLE, timestamp TIMESTAMP) TIMESTAMP(timestamp) PARTITION BY DAY",
+		},
+		{
+			desc: "untyped fields are left to ILP",
+			headers: &common.GeneratedDataHeaders{
+				TagKeys:   []string{"symbol"},
+				TagTypes:  []string{"string"},
+				FieldKeys: map[string][]string{"trade": {"price", "size"}},
+			},
+			table: "trade",
+			want: "CREATE TABLE IF NOT EXISTS trade (symbol SYMBOL, timestamp TIMESTAMP) " +
+				"TIMESTAMP(timestamp) PARTITION BY DAY",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			if got := createTableQuery(c.headers, c.table, "DAY"); got != c.want {
+				t.Errorf("incorrect query:\ngot  %s\nwant %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDBCreatorWithoutHeaders(t *testing.T) {
+	// loading ILP files leaves the tables to QuestDB, so nothing is ever sent
+	d := &dbCreator{connStr: "host=localhost port=1"}
+	if d.DBExists("benchmark") {
+		t.Errorf("DBExists without headers: got true want false")
+	}
+	if err := d.RemoveOldDB("benchmark"); err != nil {
+		t.Errorf("RemoveOldDB without headers: unexpected error: %v", err)
+	}
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Errorf("CreateDB without headers: unexpected error: %v", err)
+	}
+}
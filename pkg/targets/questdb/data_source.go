@@ -0,0 +1,73 @@
+package questdb
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// fileDataSource reads ILP lines as written by the influx serializer. Such
+// files have no headers, so QuestDB creates the tables on the first write.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+}
+
+func (f *fileDataSource) NextItem() data.LoadedPoint {
+	ok := f.scanner.Scan()
+	if !ok && f.scanner.Err() == nil { // nothing scanned & no error = EOF
+		return data.LoadedPoint{}
+	} else if !ok {
+		log.Fatalf("scan error: %v", f.scanner.Err())
+	}
+	return data.NewLoadedPoint(f.scanner.Bytes())
+}
+
+func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	return nil
+}
+
+func newSimulationDataSource(sim common.Simulator) *simulationDataSource {
+	return &simulationDataSource{
+		simulator: sim,
+		headers:   sim.Headers(),
+	}
+}
+
+// simulationDataSource serializes the simulated points to ILP lines.
+type simulationDataSource struct {
+	simulator  common.Simulator
+	headers    *common.GeneratedDataHeaders
+	serializer influx.Serializer
+	buf        bytes.Buffer
+}
+
+func (d *simulationDataSource) Headers() *common.GeneratedDataHeaders {
+	return d.headers
+}
+
+func (d *simulationDataSource) NextItem() data.LoadedPoint {
+	newSimulatorPoint := data.NewPoint()
+	for !d.simulator.Finished() {
+		if !d.simulator.Next(newSimulatorPoint) {
+			newSimulatorPoint.Reset()
+			continue
+		}
+
+		d.buf.Reset()
+		if err := d.serializer.Serialize(newSimulatorPoint, &d.buf); err != nil {
+			log.Fatalf("could not serialize simulated point: %v", err)
+		}
+		// points without any field are not serialized
+		if d.buf.Len() == 0 {
+			newSimulatorPoint.Reset()
+			continue
+		}
+		line := bytes.TrimSuffix(d.buf.Bytes(), newLine)
+		return data.NewLoadedPoint(append([]byte(nil), line...))
+	}
+	return data.LoadedPoint{}
+}
@@ -0,0 +1,63 @@
+package questdb
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+)
+
+func TestDecode(t *testing.T) {
+	input := "cpu,tag1=tag1text col1=0.0,col2=0.0 140\nstatus,tag1=tag1text state=\"a b\" 150\n"
+	decoder := &fileDataSource{scanner: bufio.NewScanner(bytes.NewReader([]byte(input)))}
+	want := []string{
+		"cpu,tag1=tag1text col1=0.0,col2=0.0 140",
+		`status,tag1=tag1text state="a b" 150`,
+	}
+	for _, w := range want {
+		p := decoder.NextItem()
+		if got := string(p.Data.([]byte)); got != w {
+			t.Errorf("incorrect result: got\n%s\nwant\n%s", got, w)
+		}
+	}
+	// nothing left, should be EOF
+	if p := decoder.NextItem(); p.Data != nil {
+		t.Errorf("expected p.Data to be nil, got %v", p.Data)
+	}
+	if decoder.Headers() != nil {
+		t.Errorf("expected no headers for ILP files")
+	}
+}
+
+func TestSimulationDataSource(t *testing.T) {
+	start := time.Unix(0, 0)
+	conf := &devops.CPUOnlySimulatorConfig{
+		Start:           start,
+		End:             start.Add(time.Minute),
+		InitHostCount:   2,
+		HostCount:       2,
+		HostConstructor: devops.NewHostCPUOnly,
+	}
+	ds := newSimulationDataSource(conf.NewSimulator(10*time.Second, 0))
+	if fields := ds.Headers().FieldKeys["cpu"]; len(fields) != 10 {
+		t.Fatalf("expected 10 cpu fields in headers; got %v", fields)
+	}
+
+	rows := 0
+	for p := ds.NextItem(); p.Data != nil; p = ds.NextItem() {
+		line := p.Data.([]byte)
+		if !bytes.HasPrefix(line, []byte("cpu,hostname=host_")) {
+			t.Fatalf("unexpected line: %s", line)
+		}
+		if bytes.HasSuffix(line, newLine) {
+			t.Fatalf("line should not end with a new line: %q", line)
+		}
+		rows++
+	}
+	// 2 hosts reporting every 10 seconds for a minute
+	if rows != 12 {
+		t.Errorf("expected 12 rows; got %d", rows)
+	}
+}
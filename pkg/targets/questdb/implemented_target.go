@@ -0,0 +1,50 @@
+package questdb
+
+import (
+	"time"
+
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &questTarget{}
+}
+
+type questTarget struct {
+}
+
+func (t *questTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	questSpecificConfig, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBenchmark(questSpecificConfig, dataSourceConfig)
+}
+
+// Serializer returns the InfluxDB line protocol serializer, since QuestDB
+// ingests the same protocol (ILP) and stores strings and bools as they are.
+func (t *questTarget) Serializer() serialize.PointSerializer {
+	return &influx.Serializer{}
+}
+
+func (t *questTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"ilp-address", "localhost:9009", "QuestDB InfluxDB line protocol (ILP) TCP address")
+	flagSet.String(flagPrefix+"pg-host", "localhost", "Hostname of the QuestDB PostgreSQL wire protocol endpoint, used to create the tables")
+	flagSet.String(flagPrefix+"pg-port", "8812", "Port of the QuestDB PostgreSQL wire protocol endpoint")
+	flagSet.String(flagPrefix+"user", "admin", "User to connect to the PostgreSQL wire protocol endpoint as")
+	flagSet.String(flagPrefix+"pass", "quest", "Password for user connecting to the PostgreSQL wire protocol endpoint")
+	flagSet.String(flagPrefix+"partition-by", "DAY", "Partitioning of the created tables (NONE, HOUR, DAY, WEEK, MONTH or YEAR)")
+	flagSet.Int(flagPrefix+"reconnect-retries", 5, "Times to reconnect to the ILP endpoint and resend a batch after a failed write")
+	flagSet.Duration(flagPrefix+"reconnect-backoff", time.Second, "Time to wait before reconnecting to the ILP endpoint, doubled with every retry")
+}
+
+func (t *questTarget) TargetName() string {
+	return constants.FormatQuestDB
+}
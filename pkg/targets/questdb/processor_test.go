@@ -0,0 +1,188 @@
+package questdb
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestProcessorProcessBatch(t *testing.T) {
+	testCases := []struct {
+		doLoad        bool
+		points        []string
+		metrics, rows uint64
+	}{
+		{
+			doLoad: true,
+			points: []string{
+				"cpu,tag1=tag1val,tag2=tag2val col1=0.0,col2=0.0 140",
+			},
+			metrics: 2,
+			rows:    1,
+		},
+		{
+			doLoad: false,
+			points: []string{
+				"cpu,tag1=tag1val,tag2=tag2val col1=0.0,col2=0.0 140",
+			},
+			metrics: 2,
+			rows:    1,
+		},
+		{
+			doLoad: true,
+			points: []string{
+				"cpu,tag1=tag1val,tag2=tag2val col1=0.0,col2=0.0 140",
+				"cpu,tag1=tag1val,tag2=tag2val col3=1i,col4=1i 190",
+				`status,tag1=tag1val state="idle, waiting",ok=t 190`,
+				`status,tag1=tag1val state="a \"b\", c",ok=f 190`,
+			},
+			metrics: 8,
+			rows:    4,
+		},
+	}
+
+	f := &factory{bufPool: &sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}}
+	sink := startFakeILPSink(t)
+	defer sink.close()
+	for _, tc := range testCases {
+		name := fmt.Sprintf("%dmetrics %drows %dpoints load %v",
+			tc.metrics, tc.rows, len(tc.points), tc.doLoad)
+		t.Run(name, func(t *testing.T) {
+			b := f.New().(*batch)
+			for _, point := range tc.points {
+				b.Append(data.LoadedPoint{
+					Data: []byte(point),
+				})
+			}
+
+			p := &processor{address: sink.address()}
+			const ignored = false
+			p.Init(1, tc.doLoad, ignored)
+			metrics, rows := p.ProcessBatch(b, tc.doLoad)
+			p.Close(tc.doLoad)
+			if metrics != tc.metrics {
+				t.Fatalf("expected %d metrics; got %d", tc.metrics, metrics)
+			}
+			if rows != tc.rows {
+				t.Fatalf("expected %d rows; got %d", tc.rows, rows)
+			}
+
+			var want []string
+			if tc.doLoad {
+				want = tc.points
+			}
+			if got := sink.waitForLines(len(want)); !equalLines(got, want) {
+				t.Fatalf("incorrect lines received: got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCountFields(t *testing.T) {
+	cases := []struct {
+		fields string
+		want   int
+	}{
+		{fields: "a=1", want: 1},
+		{fields: "a=1,b=2i,c=t", want: 3},
+		{fields: `a="x,y",b=1`, want: 2},
+		{fields: `a="x\",y",b="",c=1`, want: 3},
+	}
+	for _, c := range cases {
+		if got := countFields([]byte(c.fields)); got != c.want {
+			t.Errorf("%s: got %d fields want %d", c.fields, got, c.want)
+		}
+	}
+}
+
+func equalLines(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeILPSink accepts ILP connections like QuestDB and collects the lines
+// written to them.
+type fakeILPSink struct {
+	t        *testing.T
+	listener net.Listener
+
+	mu       sync.Mutex
+	lines    []string
+	received chan struct{}
+}
+
+func startFakeILPSink(t *testing.T) *fakeILPSink {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not start fake ILP sink: %v", err)
+	}
+	s := &fakeILPSink{t: t, listener: l, received: make(chan struct{}, 1024)}
+	go s.accept()
+	return s
+}
+
+func (s *fakeILPSink) address() string { return s.listener.Addr().String() }
+
+func (s *fakeILPSink) close() { s.listener.Close() }
+
+func (s *fakeILPSink) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.read(conn)
+	}
+}
+
+func (s *fakeILPSink) read(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		s.mu.Lock()
+		s.lines = append(s.lines, scanner.Text())
+		s.mu.Unlock()
+		s.received <- struct{}{}
+	}
+}
+
+// waitForLines waits for n lines to be received and returns them, removing
+// them from the sink.
+func (s *fakeILPSink) waitForLines(n int) []string {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.received:
+		case <-time.After(5 * time.Second):
+			s.t.Fatalf("timed out waiting for %d lines", n)
+		}
+	}
+	// give unexpected lines the chance to arrive
+	select {
+	case <-s.received:
+		s.t.Fatalf("received more than %d lines", n)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := s.lines
+	s.lines = nil
+	return lines
+}
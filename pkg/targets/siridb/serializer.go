@@ -29,6 +29,8 @@ type Serializer struct{}
 //
 // The output looks like this:
 // <number of metrics> <length of name and tags> <name and tags> <length of field key_1> <length of timestamp_1 and field value_1> <field key_1> <packed timestamp_1 and value_1> <length of field key_2> <length of timestamp_1 and field value_2> <field key_2> <packed timestamp_1 and value_2>... etc.
+//
+// SiriDB has no boolean series, so bools are written as the integers 1 and 0.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	line := make([]byte, 8, 1024)
 	line = append(line, p.MeasurementName()...)
@@ -57,6 +59,9 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	fieldKeys := p.FieldKeys()
 	for i, value := range fieldValues {
+		if b, ok := value.(bool); ok {
+			value = boolToInt(b)
+		}
 
 		indexLenData := len(line) + 4
 
@@ -85,3 +90,10 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	_, err = w.Write(line)
 	return err
 }
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -55,6 +55,22 @@ func TestSiriDBSerializerSerialize(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:       "a Point with string and bool fields",
+			inputPoint: serialize.TestPointNonNumeric(),
+			want: output{
+				seriename: []string{
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|last_error",
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|alerting",
+					"cpu|hostname=host_0,region=eu-west-1,datacenter=eu-west-1b|usage_guest_nice",
+				},
+				value: [][]interface{}{
+					{1451606400000000000, serialize.TestString},
+					{1451606400000000000, 1},
+					{1451606400000000000, 38.24311829},
+				},
+			},
+		},
 		{
 			desc:       "a Point with no tags",
 			inputPoint: serialize.TestPointNoTags(),
@@ -0,0 +1,40 @@
+package tdengine
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+type batch struct {
+	points []*point
+}
+
+func (b *batch) Len() uint {
+	return uint(len(b.points))
+}
+
+func (b *batch) Append(item data.LoadedPoint) {
+	b.points = append(b.points, item.Data.(*point))
+}
+
+type factory struct{}
+
+func (f *factory) New() targets.Batch {
+	return &batch{}
+}
+
+// hostnameIndexer sends all the readings of a host or truck, identified by
+// the value of the first tag, to the same worker.
+type hostnameIndexer struct {
+	partitions uint
+}
+
+func (i *hostnameIndexer) GetIndex(item data.LoadedPoint) uint {
+	p := item.Data.(*point)
+	h := fnv.New32a()
+	h.Write([]byte(strings.SplitN(p.tags, ",", 2)[0]))
+	return uint(h.Sum32()) % i.partitions
+}
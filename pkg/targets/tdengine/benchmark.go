@@ -0,0 +1,84 @@
+package tdengine
+
+import (
+	"bufio"
+	"errors"
+
+	"github.com/blagojts/viper"
+	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// SpecificConfig holds the TDengine specific loading options.
+type SpecificConfig struct {
+	URL          string `yaml:"url" mapstructure:"url"`
+	User         string `yaml:"user" mapstructure:"user"`
+	Pass         string `yaml:"pass" mapstructure:"pass"`
+	MaxSQLLength int    `yaml:"max-sql-length" mapstructure:"max-sql-length"`
+}
+
+func parseSpecificConfig(v *viper.Viper) (*SpecificConfig, error) {
+	var conf SpecificConfig
+	if err := v.Unmarshal(&conf); err != nil {
+		return nil, err
+	}
+	if conf.URL == "" {
+		return nil, errors.New("the TDengine REST url is required")
+	}
+	if conf.MaxSQLLength <= 0 {
+		return nil, errors.New("max-sql-length must be positive")
+	}
+	return &conf, nil
+}
+
+// loader.Benchmark interface implementation
+type benchmark struct {
+	dbName string
+	conf   *SpecificConfig
+	ds     targets.DataSource
+}
+
+func NewBenchmark(dbName string, conf *SpecificConfig, dataSourceConfig *source.DataSourceConfig) (targets.Benchmark, error) {
+	if dataSourceConfig.Type != source.FileDataSourceType {
+		return nil, errors.New("only FILE data source type is supported for TDengine")
+	}
+
+	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	return &benchmark{
+		dbName: dbName,
+		conf:   conf,
+		ds:     &fileDataSource{scanner: bufio.NewScanner(br)},
+	}, nil
+}
+
+func (b *benchmark) GetDataSource() targets.DataSource {
+	return b.ds
+}
+
+func (b *benchmark) GetBatchFactory() targets.BatchFactory {
+	return &factory{}
+}
+
+func (b *benchmark) GetPointIndexer(maxPartitions uint) targets.PointIndexer {
+	if maxPartitions > 1 {
+		return &hostnameIndexer{partitions: maxPartitions}
+	}
+	return &targets.ConstantIndexer{}
+}
+
+func (b *benchmark) GetProcessor() targets.Processor {
+	return &processor{
+		c:            NewClient(b.conf.URL, b.conf.User, b.conf.Pass),
+		db:           b.dbName,
+		ds:           b.ds,
+		maxSQLLength: b.conf.MaxSQLLength,
+	}
+}
+
+func (b *benchmark) GetDBCreator() targets.DBCreator {
+	return &dbCreator{
+		c:  NewClient(b.conf.URL, b.conf.User, b.conf.Pass),
+		ds: b.ds,
+	}
+}
@@ -0,0 +1,88 @@
+package tdengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Client runs SQL statements over the REST interface of TDengine, served by
+// taosAdapter in TDengine 3.x and by taosd itself in 2.x.
+type Client struct {
+	url  string
+	user string
+	pass string
+	http *http.Client
+}
+
+// NewClient creates a Client of the REST interface at the given URL, e.g.
+// http://localhost:6041.
+func NewClient(url, user, pass string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), user: user, pass: pass, http: &http.Client{}}
+}
+
+// Result is the response to a statement. TDengine 3.x reports errors with a
+// non-zero code, 2.x with the status "error".
+type Result struct {
+	Status string          `json:"status"`
+	Code   int             `json:"code"`
+	Desc   string          `json:"desc"`
+	Data   [][]interface{} `json:"data"`
+	Rows   int             `json:"rows"`
+}
+
+// Exec runs the statement in the given database, or without a default
+// database if db is empty, and returns the decoded response.
+func (c *Client) Exec(db, sql string) (*Result, error) {
+	body, err := c.Do(db, sql)
+	if err != nil {
+		return nil, err
+	}
+	var res Result
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("could not decode response: %v: %s", err, body)
+	}
+	return &res, nil
+}
+
+// Do runs the statement in the given database and returns the body of the
+// response, after checking it for errors.
+func (c *Client) Do(db, sql string) ([]byte, error) {
+	path := "/rest/sql"
+	if db != "" {
+		path += "/" + db
+	}
+	req, err := http.NewRequest(http.MethodPost, c.url+path, strings.NewReader(sql))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.user, c.pass)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var status struct {
+		Status string `json:"status"`
+		Code   int    `json:"code"`
+		Desc   string `json:"desc"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("request returned code %d: %s", resp.StatusCode, body)
+		}
+		return nil, fmt.Errorf("could not decode response: %v: %s", err, body)
+	}
+	if status.Code != 0 || status.Status == "error" || resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request returned code %d, error %d: %s", resp.StatusCode, status.Code, status.Desc)
+	}
+	return body, nil
+}
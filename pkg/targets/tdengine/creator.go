@@ -0,0 +1,130 @@
+package tdengine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	// additionalTagsColumn holds the tags of a reading that are not in the
+	// header, e.g. the path and fstype of the disk measurement, as
+	// comma-separated <key>=<value> pairs.
+	additionalTagsColumn = "additional_tags"
+
+	tagLength            = 64
+	additionalTagsLength = 256
+	stringFieldLength    = 256
+)
+
+// dbCreator creates the database and one super table per measurement. The
+// sub-tables of the hosts or trucks are created by the loader when it first
+// inserts into them.
+type dbCreator struct {
+	c       *Client
+	ds      targets.DataSource
+	headers *common.GeneratedDataHeaders
+}
+
+func (d *dbCreator) Init() {
+	// fills dbCreator struct with data structure (super tables description)
+	// specified at the beginning of the data file
+	d.headers = d.ds.Headers()
+}
+
+func (d *dbCreator) DBExists(dbName string) bool {
+	res, err := d.c.Exec("", "SHOW DATABASES")
+	if err != nil {
+		fatal("could not list databases: %v", err)
+		return false
+	}
+	for _, row := range res.Data {
+		if len(row) > 0 && row[0] == dbName {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dbCreator) RemoveOldDB(dbName string) error {
+	_, err := d.c.Exec("", "DROP DATABASE IF EXISTS "+dbName)
+	return err
+}
+
+// CreateDB creates the database with nanosecond precision, so the timestamps
+// of the data file are stored as they are.
+func (d *dbCreator) CreateDB(dbName string) error {
+	if _, err := d.c.Exec("", fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s PRECISION 'ns'", dbName)); err != nil {
+		return err
+	}
+
+	measurements := make([]string, 0, len(d.headers.FieldKeys))
+	for measurement := range d.headers.FieldKeys {
+		measurements = append(measurements, measurement)
+	}
+	sort.Strings(measurements)
+	for _, measurement := range measurements {
+		sql, err := createSuperTableSQL(dbName, measurement, d.headers)
+		if err != nil {
+			return err
+		}
+		if _, err := d.c.Exec("", sql); err != nil {
+			return fmt.Errorf("could not create super table %s: %v", measurement, err)
+		}
+	}
+	return nil
+}
+
+// createSuperTableSQL returns the CREATE STABLE statement of the measurement,
+// with a column per field and a tag per tag of the header. Names are quoted,
+// since some of them, e.g. status, are keywords.
+func createSuperTableSQL(dbName, measurement string, headers *common.GeneratedDataHeaders) (string, error) {
+	columns := []string{"ts TIMESTAMP"}
+	for i, field := range headers.FieldKeys[measurement] {
+		columns = append(columns, fmt.Sprintf("`%s` %s", field, fieldTypeToTDengineType(headers.FieldType(measurement, i))))
+	}
+
+	tags := make([]string, 0, len(headers.TagKeys)+1)
+	for i, tag := range headers.TagKeys {
+		tagType, err := tagTypeToTDengineType(headers.TagTypes[i])
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, fmt.Sprintf("`%s` %s", tag, tagType))
+	}
+	tags = append(tags, fmt.Sprintf("`%s` BINARY(%d)", additionalTagsColumn, additionalTagsLength))
+
+	return fmt.Sprintf("CREATE STABLE IF NOT EXISTS %s.`%s` (%s) TAGS (%s)",
+		dbName, measurement, strings.Join(columns, ", "), strings.Join(tags, ", ")), nil
+}
+
+func fieldTypeToTDengineType(fieldType string) string {
+	switch fieldType {
+	case common.FieldTypeString:
+		return fmt.Sprintf("BINARY(%d)", stringFieldLength)
+	case common.FieldTypeBool:
+		return "BOOL"
+	default:
+		return "DOUBLE"
+	}
+}
+
+func tagTypeToTDengineType(tagType string) (string, error) {
+	switch tagType {
+	case "string":
+		return fmt.Sprintf("BINARY(%d)", tagLength), nil
+	case "float32":
+		return "FLOAT", nil
+	case "float64":
+		return "DOUBLE", nil
+	case "int32":
+		return "INT", nil
+	case "int64":
+		return "BIGINT", nil
+	default:
+		return "", fmt.Errorf("unrecognized tag type %s", tagType)
+	}
+}
@@ -0,0 +1,42 @@
+package tdengine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreatorCreateDB(t *testing.T) {
+	server, statements := newTestServer(t, `{"code":0,"column_meta":[["affected_rows","INT",4]],"data":[[0]],"rows":1}`)
+	defer server.Close()
+	p := newTestProcessor(server.URL, 1024)
+	d := &dbCreator{c: NewClient(server.URL, "root", "taosdata"), ds: p.ds}
+	d.Init()
+
+	if err := d.CreateDB("benchmark"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"/rest/sql CREATE DATABASE IF NOT EXISTS benchmark PRECISION 'ns'",
+		"/rest/sql CREATE STABLE IF NOT EXISTS benchmark.`cpu` (ts TIMESTAMP, `usage_user` DOUBLE, `usage_system` DOUBLE) " +
+			"TAGS (`hostname` BINARY(64), `region` BINARY(64), `load_capacity` FLOAT, `additional_tags` BINARY(256))",
+		"/rest/sql CREATE STABLE IF NOT EXISTS benchmark.`status` (ts TIMESTAMP, `state` BINARY(256), `ok` BOOL) " +
+			"TAGS (`hostname` BINARY(64), `region` BINARY(64), `load_capacity` FLOAT, `additional_tags` BINARY(256))",
+	}
+	if !reflect.DeepEqual(*statements, want) {
+		t.Errorf("incorrect statements:\ngot\n%s\nwant\n%s", strings.Join(*statements, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCreatorDBExists(t *testing.T) {
+	server, _ := newTestServer(t, `{"code":0,"column_meta":[["name","VARCHAR",64]],"data":[["information_schema"],["benchmark"]],"rows":2}`)
+	defer server.Close()
+	d := &dbCreator{c: NewClient(server.URL, "root", "taosdata")}
+
+	if !d.DBExists("benchmark") {
+		t.Errorf("expected database benchmark to exist")
+	}
+	if d.DBExists("other") {
+		t.Errorf("expected database other not to exist")
+	}
+}
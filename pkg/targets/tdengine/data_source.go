@@ -0,0 +1,125 @@
+package tdengine
+
+import (
+	"bufio"
+	"log"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const tagsPrefix = "tags"
+
+// allows for testing
+var fatal = log.Fatalf
+
+// point is a reading of the data file, a line of tags followed by a line of
+// field values:
+//
+// tags,hostname=host_0,region=eu-west-1,...,service_environment=production
+// cpu,1451606400000000000,58,2,24,61,22,63,6,44,80,38
+type point struct {
+	measurement string
+	tags        string
+	fields      string
+}
+
+// fileDataSource reads the data generated in the TimescaleDB format, i.e. a
+// header describing the tags and the fields of every measurement followed by
+// the readings.
+type fileDataSource struct {
+	scanner *bufio.Scanner
+	headers *common.GeneratedDataHeaders
+}
+
+func (d *fileDataSource) NextItem() data.LoadedPoint {
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			fatal("scan error: %v", err)
+		}
+		return data.LoadedPoint{}
+	}
+	parts := strings.SplitN(d.scanner.Text(), ",", 2)
+	if parts[0] != tagsPrefix {
+		fatal("data file in invalid format; got %s expected %s", parts[0], tagsPrefix)
+		return data.LoadedPoint{}
+	}
+	p := &point{}
+	if len(parts) == 2 {
+		p.tags = parts[1]
+	}
+
+	if !d.scanner.Scan() {
+		fatal("missing field line after tags: %v", d.scanner.Err())
+		return data.LoadedPoint{}
+	}
+	parts = strings.SplitN(d.scanner.Text(), ",", 2)
+	if len(parts) != 2 {
+		fatal("field line in invalid format: %s", d.scanner.Text())
+		return data.LoadedPoint{}
+	}
+	p.measurement, p.fields = parts[0], parts[1]
+	return data.NewLoadedPoint(p)
+}
+
+// Headers reads the header at the start of the file the first time it is
+// called:
+//
+// tags,hostname string,region string,...,service_environment string
+// cpu,usage_user,usage_system,...,usage_guest_nice
+// disk,total,free,used,used_percent,inodes_total,inodes_free,inodes_used
+// <blank line>
+func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
+	if d.headers != nil {
+		return d.headers
+	}
+
+	var lines []string
+	for {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				fatal("scan error: %v", err)
+			} else {
+				fatal("reached EOF while reading the header")
+			}
+			return nil
+		}
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		fatal("empty header")
+		return nil
+	}
+
+	tags := strings.Split(lines[0], ",")
+	if tags[0] != tagsPrefix {
+		fatal("input header in wrong format. got '%s', expected '%s'", tags[0], tagsPrefix)
+		return nil
+	}
+	headers := &common.GeneratedDataHeaders{
+		TagKeys:    make([]string, 0, len(tags)-1),
+		TagTypes:   make([]string, 0, len(tags)-1),
+		FieldKeys:  make(map[string][]string),
+		FieldTypes: make(map[string][]string),
+	}
+	for _, tag := range tags[1:] {
+		keyAndType := strings.Split(tag, " ")
+		if len(keyAndType) != 2 {
+			fatal("tag header has invalid format: %s", tag)
+			return nil
+		}
+		headers.TagKeys = append(headers.TagKeys, keyAndType[0])
+		headers.TagTypes = append(headers.TagTypes, keyAndType[1])
+	}
+	for _, line := range lines[1:] {
+		columns := strings.Split(line, ",")
+		headers.FieldKeys[columns[0]], headers.FieldTypes[columns[0]] = common.ParseFieldColumns(columns[1:])
+	}
+	d.headers = headers
+	return d.headers
+}
@@ -0,0 +1,50 @@
+package tdengine
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const testData = `tags,hostname string,region string,load_capacity float32
+cpu,usage_user,usage_system
+status,state string,ok bool
+
+tags,hostname=host_0,region=eu-west-1,load_capacity=1500
+cpu,1451606400000000000,58,2
+tags,hostname=host_1,region=us-west-1,load_capacity=,path=/dev/sda1
+status,1451606410000000000,it's,true
+`
+
+func TestFileDataSource(t *testing.T) {
+	ds := &fileDataSource{scanner: bufio.NewScanner(strings.NewReader(testData))}
+	want := &common.GeneratedDataHeaders{
+		TagKeys:    []string{"hostname", "region", "load_capacity"},
+		TagTypes:   []string{"string", "string", "float32"},
+		FieldKeys:  map[string][]string{"cpu": {"usage_user", "usage_system"}, "status": {"state", "ok"}},
+		FieldTypes: map[string][]string{"cpu": {"float64", "float64"}, "status": {"string", "bool"}},
+	}
+	if got := ds.Headers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("incorrect headers:\ngot\n%+v\nwant\n%+v", got, want)
+	}
+	if got := ds.Headers(); got != ds.headers {
+		t.Errorf("headers not cached")
+	}
+
+	wantPoints := []*point{
+		{measurement: "cpu", tags: "hostname=host_0,region=eu-west-1,load_capacity=1500", fields: "1451606400000000000,58,2"},
+		{measurement: "status", tags: "hostname=host_1,region=us-west-1,load_capacity=,path=/dev/sda1", fields: "1451606410000000000,it's,true"},
+	}
+	for _, want := range wantPoints {
+		item := ds.NextItem()
+		if got := item.Data.(*point); !reflect.DeepEqual(got, want) {
+			t.Errorf("incorrect point: got %+v want %+v", got, want)
+		}
+	}
+	if item := ds.NextItem(); item.Data != nil {
+		t.Errorf("expected no item at EOF, got %v", item.Data)
+	}
+}
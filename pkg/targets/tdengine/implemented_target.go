@@ -0,0 +1,42 @@
+package tdengine
+
+import (
+	"github.com/blagojts/viper"
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/timescaledb"
+)
+
+func NewTarget() targets.ImplementedTarget {
+	return &tdengineTarget{}
+}
+
+type tdengineTarget struct{}
+
+func (t *tdengineTarget) Benchmark(targetDB string, dataSourceConfig *source.DataSourceConfig, v *viper.Viper) (targets.Benchmark, error) {
+	conf, err := parseSpecificConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewBenchmark(targetDB, conf, dataSourceConfig)
+}
+
+// Serializer returns the TimescaleDB serializer, the header it is preceded
+// by describes the columns and tags of the super tables.
+func (t *tdengineTarget) Serializer() serialize.PointSerializer {
+	return &timescaledb.Serializer{}
+}
+
+func (t *tdengineTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
+	flagSet.String(flagPrefix+"url", "http://localhost:6041", "URL of the TDengine REST interface")
+	flagSet.String(flagPrefix+"user", "root", "User to connect to TDengine as")
+	flagSet.String(flagPrefix+"pass", "taosdata", "Password of the user")
+	flagSet.Int(flagPrefix+"max-sql-length", 1024*1024, "Maximum length in bytes of an INSERT statement, at most the maxSQLLength of the server")
+}
+
+func (t *tdengineTarget) TargetName() string {
+	return constants.FormatTDengine
+}
@@ -0,0 +1,203 @@
+package tdengine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/timescaledb"
+)
+
+// processor inserts the readings of a batch with as few statements as
+// possible, each of them inserting into many sub-tables:
+//
+//	INSERT INTO `cpu_host_0` USING `cpu` TAGS ('host_0', ...) VALUES (...) (...)
+//	            `cpu_host_1` USING `cpu` TAGS ('host_1', ...) VALUES (...)
+//
+// The USING clause creates the sub-table of a host or truck the first time
+// it is inserted into. Statements are split so they are not longer than the
+// maximum SQL length of the server.
+type processor struct {
+	c            *Client
+	db           string
+	ds           targets.DataSource
+	maxSQLLength int
+
+	headers *common.GeneratedDataHeaders
+	// clauses caches the INTO ... USING ... TAGS ... VALUES clause of a sub-table
+	// by the measurement and the tags of its readings
+	clauses map[string]string
+}
+
+// subTable holds the readings to insert into a sub-table.
+type subTable struct {
+	clause string
+	values []string
+}
+
+func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
+	p.headers = p.ds.Headers()
+	p.clauses = make(map[string]string)
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+	batch := b.(*batch)
+	tables := make(map[string]*subTable)
+	var order []*subTable
+	for _, pt := range batch.points {
+		fields := timescaledb.SplitFields(pt.fields)
+		metricCount += uint64(len(fields) - 1) // the first value is the timestamp
+		if !doLoad {
+			continue
+		}
+
+		key := pt.measurement + "\x00" + pt.tags
+		t, ok := tables[key]
+		if !ok {
+			t = &subTable{clause: p.clause(key, pt)}
+			tables[key] = t
+			order = append(order, t)
+		}
+		t.values = append(t.values, p.valuesSQL(pt.measurement, fields))
+	}
+	rowCount = uint64(len(batch.points))
+
+	if doLoad {
+		for _, sql := range insertStatements(order, p.maxSQLLength) {
+			if _, err := p.c.Do(p.db, sql); err != nil {
+				fatal("insert failed: %v", err)
+			}
+		}
+	}
+	batch.points = batch.points[:0]
+	return metricCount, rowCount
+}
+
+// insertStatements returns the INSERT statements inserting the readings of
+// the sub-tables, each of them at most maxLength bytes long unless a single
+// reading does not fit.
+func insertStatements(tables []*subTable, maxLength int) []string {
+	const insert = "INSERT INTO"
+	var statements []string
+	var sql strings.Builder
+	for _, t := range tables {
+		open := false
+		for _, v := range t.values {
+			n := 1 + len(v)
+			if !open {
+				n += 1 + len(t.clause)
+			}
+			if sql.Len() > 0 && sql.Len()+n > maxLength {
+				statements = append(statements, sql.String())
+				sql.Reset()
+				open = false
+			}
+			if sql.Len() == 0 {
+				sql.WriteString(insert)
+			}
+			if !open {
+				sql.WriteByte(' ')
+				sql.WriteString(t.clause)
+				open = true
+			}
+			sql.WriteByte(' ')
+			sql.WriteString(v)
+		}
+	}
+	if sql.Len() > 0 {
+		statements = append(statements, sql.String())
+	}
+	return statements
+}
+
+// clause returns the clause inserting into the sub-table of the reading, e.g.
+// `cpu_host_0` USING `cpu` TAGS ('host_0', ..., NULL) VALUES
+func (p *processor) clause(key string, pt *point) string {
+	if c, ok := p.clauses[key]; ok {
+		return c
+	}
+
+	tags := strings.Split(pt.tags, ",")
+	if pt.tags == "" {
+		tags = nil
+	}
+	tagValues := make([]string, len(p.headers.TagKeys)+1)
+	for i := range p.headers.TagKeys {
+		value := ""
+		if i < len(tags) {
+			value = tagValue(tags[i])
+		}
+		tagValues[i] = sqlValue(value, p.headers.TagTypes[i] == "string")
+	}
+	additional := ""
+	if len(tags) > len(p.headers.TagKeys) {
+		additional = strings.Join(tags[len(p.headers.TagKeys):], ",")
+	}
+	tagValues[len(p.headers.TagKeys)] = sqlValue(additional, true)
+
+	c := fmt.Sprintf("`%s` USING `%s` TAGS (%s) VALUES",
+		subTableName(pt.measurement, pt.tags, additional != ""), pt.measurement, strings.Join(tagValues, ", "))
+	p.clauses[key] = c
+	return c
+}
+
+// valuesSQL returns the values of a reading, e.g. (1451606400000000000, 58, 2)
+func (p *processor) valuesSQL(measurement string, fields []string) string {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	sb.WriteString(fields[0])
+	for i, v := range fields[1:] {
+		sb.WriteString(", ")
+		sb.WriteString(sqlValue(v, p.headers.FieldType(measurement, i) == common.FieldTypeString))
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// subTableName returns the name of the sub-table of a host or truck, made of
+// the measurement and the value of the first tag, e.g. cpu_host_0. Readings
+// with additional tags or without a value for the first tag get a hash of all
+// their tags appended, so that every series has a sub-table of its own.
+func subTableName(measurement, tags string, additional bool) string {
+	first := tagValue(strings.SplitN(tags, ",", 2)[0])
+	var sb strings.Builder
+	sb.WriteString(measurement)
+	sb.WriteByte('_')
+	for _, r := range first {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	if additional || first == "" {
+		h := fnv.New32a()
+		h.Write([]byte(tags))
+		fmt.Fprintf(&sb, "_%08x", h.Sum32())
+	}
+	return sb.String()
+}
+
+// tagValue returns the value of a <key>=<value> tag.
+func tagValue(tag string) string {
+	if i := strings.IndexByte(tag, '='); i >= 0 {
+		return tag[i+1:]
+	}
+	return ""
+}
+
+// sqlValue returns the literal of a tag or field value. Empty values are
+// NULL, strings are quoted.
+func sqlValue(value string, quote bool) string {
+	if value == "" {
+		return "NULL"
+	}
+	if !quote {
+		return value
+	}
+	return "'" + quoteEscaper.Replace(value) + "'"
+}
+
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
@@ -0,0 +1,151 @@
+package tdengine
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// newTestServer returns a server of the REST interface recording the
+// statements it is sent, answering them with the given body.
+func newTestServer(t *testing.T, response string) (*httptest.Server, *[]string) {
+	var statements []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, "/rest/sql") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "root" || pass != "taosdata" {
+			t.Errorf("incorrect basic auth: got %s %s %v", user, pass, ok)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		statements = append(statements, r.URL.Path+" "+string(body))
+		w.Write([]byte(response))
+	}))
+	return server, &statements
+}
+
+func newTestProcessor(url string, maxSQLLength int) *processor {
+	ds := &fileDataSource{scanner: bufio.NewScanner(strings.NewReader(testData))}
+	p := &processor{
+		c:            NewClient(url, "root", "taosdata"),
+		db:           "benchmark",
+		ds:           ds,
+		maxSQLLength: maxSQLLength,
+	}
+	p.Init(0, true, false)
+	return p
+}
+
+func TestProcessorProcessBatch(t *testing.T) {
+	server, statements := newTestServer(t, `{"code":0,"column_meta":[["affected_rows","INT",4]],"data":[[3]],"rows":1}`)
+	defer server.Close()
+	p := newTestProcessor(server.URL, 1024*1024)
+
+	b := (&factory{}).New()
+	b.Append(data.NewLoadedPoint(&point{measurement: "cpu", tags: "hostname=host_0,region=eu-west-1,load_capacity=1500", fields: "1451606400000000000,58,2"}))
+	b.Append(data.NewLoadedPoint(&point{measurement: "status", tags: "hostname=host_1,region=us-west-1,load_capacity=,path=/dev/sda1", fields: `1451606410000000000,it's a\,b,true`}))
+	b.Append(data.NewLoadedPoint(&point{measurement: "cpu", tags: "hostname=host_0,region=eu-west-1,load_capacity=1500", fields: "1451606410000000000,,3"}))
+
+	metrics, rows := p.ProcessBatch(b, true)
+	if metrics != 6 || rows != 3 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 6 metrics 3 rows", metrics, rows)
+	}
+	want := []string{"/rest/sql/benchmark INSERT INTO " +
+		"`cpu_host_0` USING `cpu` TAGS ('host_0', 'eu-west-1', 1500, NULL) VALUES " +
+		"(1451606400000000000, 58, 2) (1451606410000000000, NULL, 3) " +
+		"`status_host_1_f930d866` USING `status` TAGS ('host_1', 'us-west-1', NULL, 'path=/dev/sda1') VALUES " +
+		`(1451606410000000000, 'it\'s a,b', true)`,
+	}
+	if !reflect.DeepEqual(*statements, want) {
+		t.Errorf("incorrect statements:\ngot\n%s\nwant\n%s", strings.Join(*statements, "\n"), strings.Join(want, "\n"))
+	}
+	if b.Len() != 0 {
+		t.Errorf("batch not reset")
+	}
+}
+
+func TestProcessorProcessBatchNoLoad(t *testing.T) {
+	p := newTestProcessor("http://localhost:1", 1024)
+	b := (&factory{}).New()
+	b.Append(data.NewLoadedPoint(&point{measurement: "cpu", tags: "hostname=host_0", fields: "1451606400000000000,58,2"}))
+	if metrics, rows := p.ProcessBatch(b, false); metrics != 2 || rows != 1 {
+		t.Errorf("incorrect counts: got %d metrics %d rows, want 2 metrics 1 row", metrics, rows)
+	}
+}
+
+func TestInsertStatements(t *testing.T) {
+	tables := []*subTable{
+		{clause: "a USING m TAGS (1) VALUES", values: []string{"(1, 1)", "(2, 2)", "(3, 3)"}},
+		{clause: "b USING m TAGS (2) VALUES", values: []string{"(1, 1)"}},
+	}
+	cases := []struct {
+		maxLength int
+		want      []string
+	}{
+		{
+			maxLength: 1024,
+			want:      []string{"INSERT INTO a USING m TAGS (1) VALUES (1, 1) (2, 2) (3, 3) b USING m TAGS (2) VALUES (1, 1)"},
+		},
+		{
+			maxLength: 52,
+			want: []string{
+				"INSERT INTO a USING m TAGS (1) VALUES (1, 1) (2, 2)",
+				"INSERT INTO a USING m TAGS (1) VALUES (3, 3)",
+				"INSERT INTO b USING m TAGS (2) VALUES (1, 1)",
+			},
+		},
+		{
+			maxLength: 1,
+			want: []string{
+				"INSERT INTO a USING m TAGS (1) VALUES (1, 1)",
+				"INSERT INTO a USING m TAGS (1) VALUES (2, 2)",
+				"INSERT INTO a USING m TAGS (1) VALUES (3, 3)",
+				"INSERT INTO b USING m TAGS (2) VALUES (1, 1)",
+			},
+		},
+	}
+	for _, c := range cases {
+		if got := insertStatements(tables, c.maxLength); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("incorrect statements for max length %d:\ngot\n%s\nwant\n%s", c.maxLength, strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+		}
+	}
+}
+
+func TestSubTableName(t *testing.T) {
+	cases := []struct {
+		measurement string
+		tags        string
+		additional  bool
+		want        string
+	}{
+		{measurement: "cpu", tags: "hostname=host_0,region=eu-west-1", want: "cpu_host_0"},
+		{measurement: "readings", tags: "name=truck-1.a,fleet=East", want: "readings_truck_1_a"},
+		{measurement: "disk", tags: "hostname=host_0,path=/dev/sda1", additional: true, want: "disk_host_0_37599ee0"},
+		{measurement: "readings", tags: "name=,fleet=East", want: "readings__81155db9"},
+	}
+	for _, c := range cases {
+		if got := subTableName(c.measurement, c.tags, c.additional); got != c.want {
+			t.Errorf("incorrect name for %s %s: got %s want %s", c.measurement, c.tags, got, c.want)
+		}
+	}
+}
+
+func TestClientError(t *testing.T) {
+	for _, response := range []string{
+		`{"code":9826,"desc":"Database not exist"}`,
+		`{"status":"error","code":896,"desc":"Database not specified or available"}`,
+	} {
+		server, _ := newTestServer(t, response)
+		_, err := NewClient(server.URL, "root", "taosdata").Do("benchmark", "SELECT 1")
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), "not") {
+			t.Errorf("expected error for response %s, got %v", response, err)
+		}
+	}
+}
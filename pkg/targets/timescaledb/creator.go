@@ -3,6 +3,7 @@ package timescaledb
 import (
 	"database/sql"
 	"fmt"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
 	"log"
 	"regexp"
@@ -22,6 +23,10 @@ var fatal = log.Fatalf
 
 var tableCols = make(map[string][]string)
 
+// tableColTypes holds the type of each column in tableCols (e.g. float64,
+// string or bool), tables without types only have numeric columns.
+var tableColTypes = make(map[string][]string)
+
 type dbCreator struct {
 	driver  string
 	ds      targets.DataSource
@@ -98,6 +103,7 @@ func (d *dbCreator) PostCreateDB(dbName string) error {
 	for tableName, columns := range headers.FieldKeys {
 		// tableCols is a global map. Globally cache the available columns for the given table
 		tableCols[tableName] = columns
+		tableColTypes[tableName] = headers.FieldTypes[tableName]
 		fieldDefs, indexDefs := d.getFieldAndIndexDefinitions(tableName, columns)
 		if d.opts.CreateMetricsTable {
 			d.createTableAndIndexes(dbBench, tableName, fieldDefs, indexDefs)
@@ -122,11 +128,14 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 
 	allCols = append(allCols, columns...)
 	extraCols := 0 // set to 1 when hostname is kept in-table
+	if d.opts.InTableTag {
+		extraCols = 1
+	}
 	for idx, field := range allCols {
 		if len(field) == 0 {
 			continue
 		}
-		fieldType := "DOUBLE PRECISION"
+		fieldType := fieldTypeToPgType(tableName, idx-extraCols)
 		idxType := d.opts.FieldIndex
 		// This condition handles the case where we keep the primary tag key in the table
 		// and partition on it. Since under the current implementation this tag is always
@@ -134,7 +143,6 @@ func (d *dbCreator) getFieldAndIndexDefinitions(tableName string, columns []stri
 		if d.opts.InTableTag && idx == 0 {
 			fieldType = "TEXT"
 			idxType = ""
-			extraCols = 1
 		}
 
 		fieldDefs = append(fieldDefs, fmt.Sprintf("%s %s", field, fieldType))
@@ -266,6 +274,23 @@ func MustBegin(db *sql.DB) *sql.Tx {
 	return tx
 }
 
+// fieldTypeToPgType returns the type of the i-th field column of the table,
+// which is DOUBLE PRECISION unless the field is not numeric.
+func fieldTypeToPgType(tableName string, i int) string {
+	types := tableColTypes[tableName]
+	if i < 0 || i >= len(types) {
+		return "DOUBLE PRECISION"
+	}
+	switch types[i] {
+	case common.FieldTypeString:
+		return "TEXT"
+	case common.FieldTypeBool:
+		return "BOOLEAN"
+	default:
+		return "DOUBLE PRECISION"
+	}
+}
+
 func serializedTypeToPgType(serializedType string) string {
 	switch serializedType {
 	case "string":
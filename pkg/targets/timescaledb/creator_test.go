@@ -129,6 +129,7 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 		desc            string
 		tableName       string
 		columns         []string
+		columnTypes     []string
 		fieldIndexCount int
 		inTableTag      bool
 		wantFieldDefs   []string
@@ -179,6 +180,26 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 			wantFieldDefs:   []string{"usage_user DOUBLE PRECISION", "usage_system DOUBLE PRECISION", "usage_idle DOUBLE PRECISION", "usage_nice DOUBLE PRECISION"},
 			wantIndexDefs:   []string{"CREATE INDEX ON cpu (usage_user, time DESC)", "CREATE INDEX ON cpu (usage_system, time DESC)"},
 		},
+		{
+			desc:            "non-numeric fields",
+			tableName:       "health",
+			columns:         []string{"status", "alerting", "last_error", "uptime"},
+			columnTypes:     []string{"string", "bool", "string", "int64"},
+			fieldIndexCount: 1,
+			inTableTag:      false,
+			wantFieldDefs:   []string{"status TEXT", "alerting BOOLEAN", "last_error TEXT", "uptime DOUBLE PRECISION"},
+			wantIndexDefs:   []string{"CREATE INDEX ON health (status, time DESC)"},
+		},
+		{
+			desc:            "non-numeric fields, in table tag",
+			tableName:       "health",
+			columns:         []string{"status", "alerting"},
+			columnTypes:     []string{"string", "bool"},
+			fieldIndexCount: 0,
+			inTableTag:      true,
+			wantFieldDefs:   []string{"hostname TEXT", "status TEXT", "alerting BOOLEAN"},
+			wantIndexDefs:   []string{},
+		},
 	}
 
 	for _, c := range cases {
@@ -186,11 +207,15 @@ func TestDBCreatorGetFieldAndIndexDefinitions(t *testing.T) {
 		// Initialize global cache
 		tableCols[tagsKey] = []string{}
 		tableCols[tagsKey] = append(tableCols[tagsKey], "hostname")
+		tableColTypes[c.tableName] = c.columnTypes
 		dbc := &dbCreator{opts: &LoadingOptions{
 			InTableTag:      c.inTableTag,
 			FieldIndexCount: c.fieldIndexCount,
 		}}
 		fieldDefs, indexDefs := dbc.getFieldAndIndexDefinitions(c.tableName, c.columns)
+		if len(fieldDefs) != len(c.wantFieldDefs) {
+			t.Errorf("%s: incorrect number of fieldDefs: got %d want %d", c.desc, len(fieldDefs), len(c.wantFieldDefs))
+		}
 		for i, fieldDef := range fieldDefs {
 			if fieldDef != c.wantFieldDefs[i] {
 				t.Errorf("%s: incorrect fieldDef at idx %d: got %s want %s", c.desc, i, fieldDef, c.wantFieldDefs[i])
@@ -62,16 +62,17 @@ func (d *fileDataSource) Headers() *common.GeneratedDataHeaders {
 	}
 	tagNames, tagTypes := extractTagNamesAndTypes(tagsarr[1:])
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	for _, tableDef := range cols {
 		columns := strings.Split(tableDef, ",")
 		tableName := columns[0]
-		colNames := columns[1:]
-		fieldKeys[tableName] = colNames
+		fieldKeys[tableName], fieldTypes[tableName] = common.ParseFieldColumns(columns[1:])
 	}
 	d.headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tagNames,
-		FieldKeys: fieldKeys,
+		TagTypes:   tagTypes,
+		TagKeys:    tagNames,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return d.headers
 }
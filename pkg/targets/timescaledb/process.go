@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
 	"os"
 	"strconv"
@@ -120,7 +121,7 @@ func (p *processor) insertTags(db *sql.DB, tagRows [][]string, returnResults boo
 // divides the tags from data into appropriate slices that can then be used in
 // SQL queries to insert into their respective tables. Additionally, it also
 // returns the number of metrics (i.e., non-tag fields) for the data processed.
-func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]string, [][]interface{}, uint64) {
+func (p *processor) splitTagsAndMetrics(rows []*insertData, fieldTypes []string, dataCols int) ([][]string, [][]interface{}, uint64) {
 	tagRows := make([][]string, 0, len(rows))
 	dataRows := make([][]interface{}, 0, len(rows))
 	numMetrics := uint64(0)
@@ -141,7 +142,7 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 			json = subsystemTagsToJSON(strings.Split(tags[commonTagsLen], ","))
 		}
 
-		metrics := strings.Split(data.fields, ",")
+		metrics := SplitFields(data.fields)
 		numMetrics += uint64(len(metrics) - 1) // 1 field is timestamp
 
 		timeInt, err := strconv.ParseInt(metrics[0], 10, 64)
@@ -156,18 +157,13 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 		if p.opts.InTableTag {
 			r = append(r, tags[0])
 		}
-		for _, v := range metrics[1:] {
+		for i, v := range metrics[1:] {
 			if v == "" {
 				r = append(r, nil)
 				continue
 			}
 
-			num, err := strconv.ParseFloat(v, 64)
-			if err != nil {
-				panic(err)
-			}
-
-			r = append(r, num)
+			r = append(r, parseFieldValue(v, fieldTypes, i))
 		}
 
 		dataRows = append(dataRows, r)
@@ -177,12 +173,38 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 	return tagRows, dataRows, numMetrics
 }
 
+// parseFieldValue converts the value of the i-th field to the type of its
+// column, fields without a type are numeric.
+func parseFieldValue(v string, fieldTypes []string, i int) interface{} {
+	fieldType := ""
+	if i < len(fieldTypes) {
+		fieldType = fieldTypes[i]
+	}
+
+	switch fieldType {
+	case common.FieldTypeString:
+		return v
+	case common.FieldTypeBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			panic(err)
+		}
+		return b
+	default:
+		num, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			panic(err)
+		}
+		return num
+	}
+}
+
 func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 	colLen := len(tableCols[hypertable]) + numExtraCols
 	if p.opts.InTableTag {
 		colLen++
 	}
-	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, colLen)
+	tagRows, dataRows, numMetrics := p.splitTagsAndMetrics(rows, tableColTypes[hypertable], colLen)
 
 	// Check if any of these tags has yet to be inserted
 	newTags := make([][]string, 0, len(rows))
@@ -85,6 +85,7 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 	cases := []struct {
 		desc        string
 		rows        []*insertData
+		fieldTypes  []string
 		inTableTag  bool
 		wantMetrics uint64
 		wantTags    [][]string
@@ -182,6 +183,36 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 				[]interface{}{toTS("100"), nil, nil, nil, 5.0, 42.0},
 			},
 		},
+		{
+			desc: "string and bool field values",
+			rows: []*insertData{
+				{
+					tags:   "tag1=foo,tag2=bar",
+					fields: `100,disk "C:\\" is full\, cleanup failed,true,42`,
+				},
+			},
+			fieldTypes:  []string{"string", "bool", "float64"},
+			wantMetrics: 3,
+			wantTags:    [][]string{{"foo", "bar"}},
+			wantData: [][]interface{}{
+				[]interface{}{toTS("100"), nil, nil, `disk "C:\" is full, cleanup failed`, true, 42.0},
+			},
+		},
+		{
+			desc: "empty string field value",
+			rows: []*insertData{
+				{
+					tags:   "tag1=foo,tag2=bar",
+					fields: "100,,false,42",
+				},
+			},
+			fieldTypes:  []string{"string", "bool", "float64"},
+			wantMetrics: 3,
+			wantTags:    [][]string{{"foo", "bar"}},
+			wantData: [][]interface{}{
+				[]interface{}{toTS("100"), nil, nil, nil, false, 42.0},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -194,13 +225,13 @@ func TestSplitTagsAndMetrics(t *testing.T) {
 					t.Errorf("%s: did not panic when should", c.desc)
 				}
 			}()
-			p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+			p.splitTagsAndMetrics(c.rows, nil, numCols+numExtraCols)
 		}
 
 		oldInTableTag := p.opts.InTableTag
 		p.opts.InTableTag = c.inTableTag
 
-		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.rows, numCols+numExtraCols)
+		gotTags, gotData, numMetrics := p.splitTagsAndMetrics(c.rows, c.fieldTypes, numCols+numExtraCols)
 		if numMetrics != c.wantMetrics {
 			t.Errorf("%s: number of metrics incorrect: got %d want %d", c.desc, numMetrics, c.wantMetrics)
 		}
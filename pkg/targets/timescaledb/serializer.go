@@ -5,6 +5,7 @@ import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
 	"io"
+	"strings"
 )
 
 // Serializer writes a Point in a serialized form for TimescaleDB
@@ -17,6 +18,9 @@ type Serializer struct{}
 // e.g.,
 // tags,<tag1>,<tag2>,<tag3>,...
 // <measurement>,<timestamp>,<field1>,<field2>,<field3>,...
+//
+// Commas, newlines and backslashes in string field values are escaped with a
+// backslash, see SplitFields.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	// Tag row first, prefixed with name 'tags'
 	buf := make([]byte, 0, 256)
@@ -43,9 +47,59 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	for _, v := range fieldValues {
 		buf = append(buf, ',')
-		buf = serialize.FastFormatAppend(v, buf)
+		buf = appendFieldValue(v, buf)
 	}
 	buf = append(buf, '\n')
 	_, err = w.Write(buf)
 	return err
 }
+
+// appendFieldValue appends a field value to the field row, escaping string
+// values so they cannot break up the row.
+func appendFieldValue(v interface{}, buf []byte) []byte {
+	s, ok := v.(string)
+	if !ok {
+		return serialize.FastFormatAppend(v, buf)
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ',', '\\':
+			buf = append(buf, '\\', s[i])
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		default:
+			buf = append(buf, s[i])
+		}
+	}
+	return buf
+}
+
+// SplitFields splits a field row written by the Serializer into its values,
+// unescaping string values. Empty strings cannot be told apart from missing
+// values, so both are loaded as NULL.
+func SplitFields(row string) []string {
+	if strings.IndexByte(row, '\\') < 0 {
+		return strings.Split(row, ",")
+	}
+
+	values := make([]string, 0, strings.Count(row, ",")+1)
+	value := make([]byte, 0, len(row))
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row):
+			i++
+			if row[i] == 'n' {
+				value = append(value, '\n')
+			} else {
+				value = append(value, row[i])
+			}
+		case row[i] == ',':
+			values = append(values, string(value))
+			value = value[:0]
+		default:
+			value = append(value, row[i])
+		}
+	}
+	return append(values, string(value))
+}
@@ -1,7 +1,10 @@
 package timescaledb
 
 import (
+	"bytes"
 	"github.com/timescale/tsbs/pkg/data/serialize"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +25,11 @@ func TestTimescaleDBSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointMultiField(),
 			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,5000000000,38,38.24311829\n",
 		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     "tags,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b\ncpu,1451606400000000000,disk \"C:\\\\\" is 95% full\\, cleanup failed,true,38.24311829\n",
+		},
 		{
 			Desc:       "a Point with no tags",
 			InputPoint: serialize.TestPointNoTags(),
@@ -42,3 +50,41 @@ func TestTimescaleDBSerializerSerializeErr(t *testing.T) {
 		t.Errorf("unexpected writer error: %v", err)
 	}
 }
+
+func TestSplitFields(t *testing.T) {
+	cases := []struct {
+		desc string
+		row  string
+		want []string
+	}{
+		{
+			desc: "numeric fields",
+			row:  "1451606400000000000,38,,42.5",
+			want: []string{"1451606400000000000", "38", "", "42.5"},
+		},
+		{
+			desc: "escaped string fields",
+			row:  `1451606400000000000,a\,b,c\\d,line\nbreak,true`,
+			want: []string{"1451606400000000000", "a,b", `c\d`, "line\nbreak", "true"},
+		},
+	}
+
+	for _, c := range cases {
+		got := SplitFields(c.row)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: incorrect values: got %q want %q", c.desc, got, c.want)
+		}
+	}
+
+	// Values written by the serializer come back unchanged.
+	var buf bytes.Buffer
+	s := &Serializer{}
+	if err := s.Serialize(serialize.TestPointNonNumeric(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	values := SplitFields(strings.SplitN(rows[1], ",", 2)[1])
+	if values[1] != serialize.TestString || values[2] != "true" {
+		t.Errorf("string values do not round trip: got %q", values)
+	}
+}
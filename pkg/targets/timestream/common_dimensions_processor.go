@@ -57,7 +57,7 @@ func (c *commonDimensionsProcessor) writeToTable(table string, rows []deserializ
 	for _, row := range rows {
 		c.expandDimensionBuffer(len(row.tagKeys))
 		numDimensions := convertTagsToDimensions(row.tagKeys, row.tags, c._dimensionsBuffer)
-		numRecords := convertPointToRecords(&row, c.headers.FieldKeys[table], c._recordsBuffer)
+		numRecords := convertPointToRecords(&row, c.headers.FieldKeys[table], c.headers.FieldTypes[table], c._recordsBuffer)
 		writeRecordsInput := &timestreamwrite.WriteRecordsInput{
 			DatabaseName: &c.dbName,
 			TableName:    &table,
@@ -91,7 +91,7 @@ func convertTagsToDimensions(tagKeys, tagValues []string, buffer []*timestreamwr
 	return len(tagValues)
 }
 
-func convertPointToRecords(point *deserializedPoint, fieldKeys []string, buffer []*timestreamwrite.Record) (numFields int) {
+func convertPointToRecords(point *deserializedPoint, fieldKeys, fieldTypes []string, buffer []*timestreamwrite.Record) (numFields int) {
 	numFields = 0
 	for i, fieldVal := range point.fields {
 		if fieldVal == nil {
@@ -102,9 +102,25 @@ func convertPointToRecords(point *deserializedPoint, fieldKeys []string, buffer
 		}
 
 		buffer[numFields].SetMeasureName(fieldKeys[i])
-		buffer[numFields].SetMeasureValueType(timestreamwrite.MeasureValueTypeDouble)
+		buffer[numFields].SetMeasureValueType(measureValueType(fieldTypes, i))
 		buffer[numFields].SetMeasureValue(*fieldVal)
 		numFields++
 	}
 	return numFields
 }
+
+// measureValueType returns the Timestream type of the i-th field, which is
+// DOUBLE unless the field is not numeric.
+func measureValueType(fieldTypes []string, i int) string {
+	if i >= len(fieldTypes) {
+		return timestreamwrite.MeasureValueTypeDouble
+	}
+	switch fieldTypes[i] {
+	case common.FieldTypeString:
+		return timestreamwrite.MeasureValueTypeVarchar
+	case common.FieldTypeBool:
+		return timestreamwrite.MeasureValueTypeBoolean
+	default:
+		return timestreamwrite.MeasureValueTypeDouble
+	}
+}
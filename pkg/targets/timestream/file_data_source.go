@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets/timescaledb"
 	"log"
 	"strconv"
 	"strings"
@@ -67,16 +68,17 @@ func (f *fileDataSource) Headers() *common.GeneratedDataHeaders {
 		return nil
 	}
 	fieldKeys := make(map[string][]string)
+	fieldTypes := make(map[string][]string)
 	for _, tableDef := range cols {
 		columns := strings.Split(tableDef, ",")
 		tableName := columns[0]
-		colNames := columns[1:]
-		fieldKeys[tableName] = colNames
+		fieldKeys[tableName], fieldTypes[tableName] = common.ParseFieldColumns(columns[1:])
 	}
 	f._headers = &common.GeneratedDataHeaders{
-		TagTypes:  tagTypes,
-		TagKeys:   tagNames,
-		FieldKeys: fieldKeys,
+		TagTypes:   tagTypes,
+		TagKeys:    tagNames,
+		FieldKeys:  fieldKeys,
+		FieldTypes: fieldTypes,
 	}
 	return f._headers
 }
@@ -157,16 +159,16 @@ func tagsLineToTagValues(tagsLine string) (tagKeys, tagValues []string) {
 }
 
 func fieldsLineToFieldValues(fieldsLine string) (time string, fieldValues []*string) {
-	metrics := strings.Split(fieldsLine, ",")
+	metrics := timescaledb.SplitFields(fieldsLine)
 	fieldValues = make([]*string, len(metrics)-1)
 	// use nil at 2nd position as placeholder for tagKey
-	for i, v := range metrics[1:] {
-		if v == "" {
+	for i := range metrics[1:] {
+		if metrics[i+1] == "" {
 			fieldValues[i] = nil
 			continue
 		}
 
-		fieldValues[i] = &v
+		fieldValues[i] = &metrics[i+1]
 	}
 
 	return metrics[0], fieldValues
@@ -7,7 +7,6 @@ import (
 	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/targets"
 	"github.com/timescale/tsbs/pkg/targets/constants"
-	"github.com/timescale/tsbs/pkg/targets/influx"
 )
 
 func NewTarget() targets.ImplementedTarget {
@@ -27,7 +26,7 @@ func (vm vmTarget) Benchmark(_ string, dataSourceConfig *source.DataSourceConfig
 }
 
 func (vm vmTarget) Serializer() serialize.PointSerializer {
-	return &influx.Serializer{}
+	return &Serializer{}
 }
 
 func (vm vmTarget) TargetSpecificFlags(flagPrefix string, flagSet *pflag.FlagSet) {
@@ -0,0 +1,51 @@
+package victoriametrics
+
+import (
+	"io"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// Serializer writes a Point in the InfluxDB wire protocol accepted by
+// VictoriaMetrics. Since VictoriaMetrics only stores numbers, string fields
+// are left out; bools are kept and stored as 1 and 0.
+type Serializer struct {
+	influx   influx.Serializer
+	filtered *data.Point
+}
+
+// Serialize writes Point data to the given writer.
+func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	fieldKeys := p.FieldKeys()
+	fieldValues := p.FieldValues()
+	hasStrings := false
+	for i := range fieldValues {
+		if _, ok := fieldValues[i].(string); ok {
+			serialize.WarnSkippedField(constants.FormatVictoriaMetrics, fieldKeys[i], fieldValues[i])
+			hasStrings = true
+		}
+	}
+	if !hasStrings {
+		return s.influx.Serialize(p, w)
+	}
+
+	if s.filtered == nil {
+		s.filtered = data.NewPoint()
+	}
+	s.filtered.Reset()
+	s.filtered.SetMeasurementName(p.MeasurementName())
+	s.filtered.SetTimestamp(p.Timestamp())
+	tagValues := p.TagValues()
+	for i, key := range p.TagKeys() {
+		s.filtered.AppendTag(key, tagValues[i])
+	}
+	for i, key := range fieldKeys {
+		if _, ok := fieldValues[i].(string); !ok {
+			s.filtered.AppendField(key, fieldValues[i])
+		}
+	}
+	return s.influx.Serialize(s.filtered, w)
+}
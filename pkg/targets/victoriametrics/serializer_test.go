@@ -0,0 +1,24 @@
+package victoriametrics
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data/serialize"
+)
+
+func TestVictoriaMetricsSerializerSerialize(t *testing.T) {
+	cases := []serialize.SerializeCase{
+		{
+			Desc:       "a regular Point",
+			InputPoint: serialize.TestPointDefault(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+		{
+			Desc:       "a Point with string and bool fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b alerting=true,usage_guest_nice=38.24311829 1451606400000000000\n",
+		},
+	}
+
+	serialize.SerializerTest(t, cases, &Serializer{})
+}
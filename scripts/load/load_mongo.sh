@@ -12,6 +12,8 @@ DATA_FILE_NAME=${DATA_FILE_NAME:-mongo-data.gz}
 
 # Load parameters - personal
 PROGRESS_INTERVAL=${PROGRESS_INTERVAL:-10s}
+# Whether to store the data in a native time-series collection (MongoDB 5.0+)
+TIMESERIES=${TIMESERIES:-false}
 
 EXE_DIR=${EXE_DIR:-$(dirname $0)}
 source ${EXE_DIR}/load_common.sh
@@ -20,4 +22,5 @@ cat ${DATA_FILE} | gunzip | $EXE_FILE_NAME \
                                 --db-name=${DATABASE_NAME} \
                                 --batch-size=${BATCH_SIZE} \
                                 --workers=${NUM_WORKERS} \
-                                --reporting-period=${PROGRESS_INTERVAL}
+                                --reporting-period=${PROGRESS_INTERVAL} \
+                                --timeseries=${TIMESERIES}